@@ -3,29 +3,41 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"image"
 	"image/gif"
 	"image/jpeg"
 	"image/png"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"golang.org/x/image/bmp"
 	"golang.org/x/image/webp"
+	"winshot/internal/batch"
+	"winshot/internal/colors"
 	"winshot/internal/config"
 	"winshot/internal/hotkeys"
+	"winshot/internal/imagediff"
 	"winshot/internal/library"
+	"winshot/internal/metadata"
+	"winshot/internal/notify"
+	"winshot/internal/ocr"
 	"winshot/internal/overlay"
 	"winshot/internal/screenshot"
 	"winshot/internal/tray"
 	"winshot/internal/updater"
 	"winshot/internal/upload"
 	winEnum "winshot/internal/windows"
+	"winshot/internal/workflow"
 )
 
 // Version is set at build time via ldflags
@@ -35,8 +47,20 @@ var Version = "dev"
 type App struct {
 	ctx              context.Context
 	hotkeyManager    *hotkeys.HotkeyManager
+	hotkeyBindings   map[int]config.HotkeyBinding // Registered hotkey ID -> binding
+	hotkeyStatus     []hotkeys.Registration       // Registration result of each configured binding
+	hotkeyMu         sync.Mutex
+	hotkeysPaused    bool       // Hotkeys turned off from the tray, protected by hotkeyMu
+	workflowMu       sync.Mutex // Held while a headless hotkey workflow runs
+	handoffMu        sync.Mutex
+	handoffs         map[string][]workflow.Step // Steps waiting for the editor by token, protected by handoffMu
+	handoffOrder     []string                   // Handoff tokens, oldest first
 	overlayManager   *overlay.Manager
+	pinManager       *overlay.PinManager // Started on first use, protected by pinMu
+	pinMu            sync.Mutex
 	trayIcon         *tray.TrayIcon
+	trayRecent       []tray.RecentCapture // Recent captures in the open tray menu
+	notifier         *notify.Notifier
 	config           *config.Config
 	lastWidth        int
 	lastHeight       int
@@ -51,6 +75,20 @@ type App struct {
 	credManager    *upload.CredentialManager
 	r2Uploader     *upload.R2Uploader
 	gdriveUploader *upload.GDriveUploader
+
+	// OCR and library metadata
+	ocrEngine      ocr.OCREngine
+	libraryIndex   *library.Index
+	indexSyncMu    sync.Mutex
+	indexSynced    string // Folder and depth the index was last fully rescanned for, protected by indexSyncMu
+	thumbCache     *library.ThumbnailCache
+	libraryWatcher *library.Watcher
+	trashBin       *library.Trash
+	janitor        *library.Janitor
+	batchJobs      *batch.Manager
+	clipboardWatch *screenshot.ClipboardMonitor
+	lastCapture    library.CaptureSource // Source of the image currently in the editor
+	lastCaptureAt  time.Time             // When the image in the editor was captured
 }
 
 // NewApp creates a new App application struct
@@ -73,9 +111,9 @@ func (a *App) startup(ctx context.Context) {
 	a.hotkeyManager = hotkeys.NewHotkeyManager()
 	a.hotkeyManager.SetCallback(a.onHotkey)
 
-	// Register hotkeys from config
-	a.registerHotkeysFromConfig()
+	// Register hotkeys from config once the message loop runs, so conflicts are reported
 	a.hotkeyManager.Start()
+	a.registerHotkeysFromConfig()
 
 	// Initialize overlay manager for native region selection
 	a.overlayManager = overlay.NewManager()
@@ -93,8 +131,16 @@ func (a *App) startup(ctx context.Context) {
 		runtime.WindowSetAlwaysOnTop(a.ctx, true)
 		runtime.WindowSetAlwaysOnTop(a.ctx, false)
 	})
+	a.trayIcon.SetHotkeyWarning(hotkeyWarning(a.GetHotkeyStatus()))
+	a.trayIcon.SetMenuSource(a.fillTrayMenu)
 	a.trayIcon.Start()
 
+	// Notifications after save and upload, as balloons from the tray icon
+	a.notifier = notify.New(a.trayIcon.Notifications(), a.onNotificationAction)
+	a.trayIcon.SetNotificationHandler(func(id int, action string) {
+		a.notifier.Activate(id, action)
+	})
+
 	// Initialize window size tracking with config values
 	a.lastWidth = cfg.Window.Width
 	a.lastHeight = cfg.Window.Height
@@ -114,6 +160,44 @@ func (a *App) startup(ctx context.Context) {
 	a.gdriveUploader = upload.NewGDriveUploader(a.credManager, &upload.GDriveConfig{
 		FolderID: a.config.Cloud.GDrive.FolderID,
 	})
+
+	// Initialize OCR engine and library index
+	a.ocrEngine = ocr.NewTesseractEngine(a.config.OCR.TesseractPath, a.config.OCR.Language)
+	if indexPath, err := config.GetDataPath("library-index.json"); err == nil {
+		if idx, err := library.OpenIndex(indexPath); err == nil {
+			a.libraryIndex = idx
+			// Pick up files added while the app was closed
+			if folder, err := a.quickSaveFolder(); err == nil {
+				go a.syncLibraryIndex(folder)
+			}
+		} else {
+			println("Warning: failed to open library index:", err.Error())
+		}
+	}
+	if cacheDir, err := config.GetDataPath("thumbnails"); err == nil {
+		if cache, err := library.NewThumbnailCache(cacheDir); err == nil {
+			a.thumbCache = cache
+			// Drop thumbnails unused for a month (deleted or edited screenshots)
+			go cache.Prune(30 * 24 * time.Hour)
+		} else {
+			println("Warning: failed to create thumbnail cache:", err.Error())
+		}
+	}
+
+	// Watch the QuickSave folder for live library updates
+	a.startLibraryWatcher()
+
+	// Import images copied in other apps, if enabled
+	a.applyClipboardWatch()
+
+	// Bulk library operations report progress to the frontend
+	a.batchJobs = batch.NewManager(func(progress batch.Progress) {
+		runtime.EventsEmit(a.ctx, "batch:progress", progress)
+	})
+
+	// Purge expired trash and apply retention rules now and every few hours
+	a.janitor = library.NewJanitor(6*time.Hour, a.runLibraryHousekeeping)
+	a.janitor.Start()
 }
 
 // shutdown is called when the app is closing
@@ -133,21 +217,60 @@ func (a *App) shutdown(ctx context.Context) {
 	if a.overlayManager != nil {
 		a.overlayManager.Stop()
 	}
+	if pm := a.startedPins(); pm != nil {
+		pm.Stop()
+	}
 	if a.trayIcon != nil {
 		a.trayIcon.Stop()
 	}
+	if a.libraryWatcher != nil {
+		a.libraryWatcher.Stop()
+	}
+	if a.janitor != nil {
+		a.janitor.Stop()
+	}
+	if a.clipboardWatch != nil {
+		a.clipboardWatch.Stop()
+	}
+	if a.batchJobs != nil {
+		a.batchJobs.CancelAll()
+	}
+	// Write index changes still waiting for their batched save
+	if a.libraryIndex != nil {
+		if err := a.libraryIndex.Save(); err != nil {
+			println("Warning: failed to save library index:", err.Error())
+		}
+	}
 }
 
-// onHotkey handles global hotkey events
+// onHotkey handles global hotkey events by starting the bound capture workflow.
+// The frontend receives a handoff token for the steps after the editor with the
+// capture event, or an empty token if there are none.
 func (a *App) onHotkey(id int) {
-	switch id {
-	case hotkeys.HotkeyFullscreen:
-		runtime.EventsEmit(a.ctx, "hotkey:fullscreen")
-	case hotkeys.HotkeyRegion:
-		runtime.EventsEmit(a.ctx, "hotkey:region")
-	case hotkeys.HotkeyWindow:
-		runtime.EventsEmit(a.ctx, "hotkey:window")
+	a.hotkeyMu.Lock()
+	binding, ok := a.hotkeyBindings[id]
+	a.hotkeyMu.Unlock()
+	if !ok {
+		return
+	}
+
+	if binding.Capture == config.CaptureColor {
+		go a.pickColorHeadless()
+		return
 	}
+	if binding.Headless() {
+		go a.runHeadlessWorkflow(binding)
+		return
+	}
+
+	var token string
+	for i, step := range binding.Steps {
+		if step.Type == config.StepEditor {
+			token = a.handOffWorkflow(workflowSteps(binding.Steps[i+1:]))
+			break
+		}
+	}
+	runtime.EventsEmit(a.ctx, "hotkey:"+binding.Capture, token)
 }
 
 // onTrayMenu handles tray menu selections
@@ -159,12 +282,25 @@ func (a *App) onTrayMenu(menuID int) {
 		runtime.EventsEmit(a.ctx, "hotkey:region")
 	case tray.MenuWindow:
 		runtime.EventsEmit(a.ctx, "hotkey:window")
+	case tray.MenuSettings:
+		runtime.WindowShow(a.ctx)
+		a.isWindowHidden = false
+		runtime.EventsEmit(a.ctx, "tray:settings")
 	case tray.MenuLibrary:
 		// Show main window first so library modal has context
 		runtime.WindowShow(a.ctx)
 		a.isWindowHidden = false
 		// Emit event to open library window
 		runtime.EventsEmit(a.ctx, "tray:library")
+	case tray.MenuClipboard:
+		if err := a.SetClipboardWatch(!a.config.Library.WatchClipboard); err != nil {
+			println("Warning: failed to save clipboard watch setting:", err.Error())
+		}
+	case tray.MenuPauseHotkeys:
+		a.hotkeyMu.Lock()
+		paused := a.hotkeysPaused
+		a.hotkeyMu.Unlock()
+		a.setHotkeysPaused(!paused)
 	case tray.MenuQuit:
 		// Quit the application - use goroutine to avoid blocking tray menu
 		go func() {
@@ -176,6 +312,72 @@ func (a *App) onTrayMenu(menuID int) {
 			time.Sleep(500 * time.Millisecond)
 			os.Exit(0)
 		}()
+	default:
+		if i, action, ok := tray.RecentItem(menuID); ok && i < len(a.trayRecent) {
+			// Uploading can take a while; don't block the tray
+			go a.onTrayRecent(a.trayRecent[i], action)
+		} else if i, ok := tray.UploadItem(menuID); ok && i < len(a.uploadTargets()) {
+			a.config.Cloud.DefaultProvider = a.uploadTargets()[i].ID
+			if err := a.config.Save(); err != nil {
+				println("Warning: failed to save upload target:", err.Error())
+			}
+		}
+	}
+}
+
+// fillTrayMenu adds the newest library captures and the upload targets to the tray menu
+func (a *App) fillTrayMenu(state *tray.MenuState) {
+	a.trayRecent = nil
+	if a.libraryIndex != nil {
+		result := a.libraryIndex.Search(library.SearchQuery{
+			Sort:     library.SortNewest,
+			PageSize: tray.MaxRecentCaptures,
+		})
+		for _, img := range result.Images {
+			capture := tray.RecentCapture{Path: img.Filepath, Name: img.Filename}
+			if n := len(img.UploadURLs); n > 0 {
+				capture.URL = img.UploadURLs[n-1]
+			}
+			a.trayRecent = append(a.trayRecent, capture)
+		}
+	}
+
+	state.Recent = a.trayRecent
+	state.UploadTargets = a.uploadTargets()
+	state.UploadTarget = a.config.Cloud.DefaultProvider
+}
+
+// uploadTargets returns the upload providers in tray menu order
+func (a *App) uploadTargets() []tray.UploadTarget {
+	return []tray.UploadTarget{
+		{ID: string(upload.ProviderR2), Name: "Cloudflare R2", Configured: a.r2Uploader != nil && a.r2Uploader.IsConfigured()},
+		{ID: string(upload.ProviderGDrive), Name: "Google Drive", Configured: a.gdriveUploader != nil && a.gdriveUploader.IsConfigured()},
+	}
+}
+
+// onTrayRecent opens, copies or copies the link of a recent capture picked in the tray menu
+func (a *App) onTrayRecent(capture tray.RecentCapture, action tray.RecentAction) {
+	var err error
+	switch action {
+	case tray.RecentOpen:
+		a.ShowWindow()
+		runtime.EventsEmit(a.ctx, "tray:open", capture.Path)
+	case tray.RecentCopy:
+		err = a.CopyFileToClipboard(capture.Path)
+	case tray.RecentCopyURL:
+		url := capture.URL
+		if url == "" {
+			if url, err = a.workflowUpload(nil, a.config.Cloud.DefaultProvider, capture.Path); err != nil {
+				break
+			}
+			img, _ := a.loadLibraryImageFile(capture.Path)
+			a.showNotification(notify.Uploaded(capture.Path, url, img))
+		}
+		err = runtime.ClipboardSetText(a.ctx, url)
+	}
+	if err != nil {
+		println("Warning: tray action failed for", capture.Path+":", err.Error())
+		a.trayIcon.ShowBalloon("WinShot", err.Error(), true)
 	}
 }
 
@@ -261,31 +463,17 @@ func (a *App) PrepareRegionCapture() (*RegionCaptureData, error) {
 		time.Sleep(250 * time.Millisecond)
 	}
 
-	// Get the virtual screen bounds first
-	screenX, screenY, virtualWidth, virtualHeight := screenshot.GetVirtualScreenBounds()
-
-	// Capture raw RGBA (faster - no PNG encode)
-	rgbaImg, err := screenshot.CaptureVirtualScreenRaw()
+	sel, err := a.startRegionSelection()
 	if err != nil {
 		runtime.WindowShow(a.ctx)
 		a.isCapturing = false
 		return nil, err
 	}
 
-	// Calculate scale ratio between physical screenshot and logical window size
-	scaleRatio := float64(rgbaImg.Bounds().Dx()) / float64(virtualWidth)
-	if scaleRatio < 1.0 {
-		scaleRatio = 1.0
-	}
-
-	// Show native overlay and get result channel
-	bounds := image.Rect(screenX, screenY, screenX+virtualWidth, screenY+virtualHeight)
-	resultCh := a.overlayManager.Show(rgbaImg, bounds, scaleRatio)
-
 	// Wait for selection result in goroutine
 	go func() {
-		selResult := <-resultCh
-		if selResult.Cancelled {
+		croppedImg := a.waitRegionSelection(sel)
+		if croppedImg == nil {
 			// User cancelled - just show window
 			runtime.WindowShow(a.ctx)
 			a.isWindowHidden = false
@@ -293,15 +481,6 @@ func (a *App) PrepareRegionCapture() (*RegionCaptureData, error) {
 			return
 		}
 
-		// Scale coordinates to physical pixels
-		scaledX := int(float64(selResult.X) * scaleRatio)
-		scaledY := int(float64(selResult.Y) * scaleRatio)
-		scaledW := int(float64(selResult.Width) * scaleRatio)
-		scaledH := int(float64(selResult.Height) * scaleRatio)
-
-		// Crop to selected region before encoding (much faster - smaller image)
-		croppedImg := rgbaImg.SubImage(image.Rect(scaledX, scaledY, scaledX+scaledW, scaledY+scaledH))
-
 		var buf bytes.Buffer
 		if err := png.Encode(&buf, croppedImg); err != nil {
 			runtime.WindowShow(a.ctx)
@@ -312,8 +491,8 @@ func (a *App) PrepareRegionCapture() (*RegionCaptureData, error) {
 
 		// Emit cropped image directly - no need for frontend to crop again
 		runtime.EventsEmit(a.ctx, "region:selected", map[string]interface{}{
-			"width":      scaledW,
-			"height":     scaledH,
+			"width":      croppedImg.Bounds().Dx(),
+			"height":     croppedImg.Bounds().Dy(),
 			"screenshot": base64.StdEncoding.EncodeToString(buf.Bytes()),
 		})
 	}()
@@ -321,17 +500,84 @@ func (a *App) PrepareRegionCapture() (*RegionCaptureData, error) {
 	// Return minimal data (actual selection comes via event)
 	return &RegionCaptureData{
 		Screenshot:   nil, // Not needed - selection via event
-		ScreenX:      screenX,
-		ScreenY:      screenY,
-		Width:        virtualWidth,
-		Height:       virtualHeight,
-		ScaleRatio:   scaleRatio,
-		PhysicalW:    rgbaImg.Bounds().Dx(),
-		PhysicalH:    rgbaImg.Bounds().Dy(),
+		ScreenX:      sel.screenX,
+		ScreenY:      sel.screenY,
+		Width:        sel.width,
+		Height:       sel.height,
+		ScaleRatio:   sel.scaleRatio,
+		PhysicalW:    sel.shot.Bounds().Dx(),
+		PhysicalH:    sel.shot.Bounds().Dy(),
 		DisplayIndex: 0,
 	}, nil
 }
 
+// regionSelection is a native overlay region selection in progress
+type regionSelection struct {
+	shot             *image.RGBA // Virtual screen, physical pixels
+	screenX, screenY int         // Virtual screen origin
+	width, height    int         // Virtual screen size, logical pixels
+	scaleRatio       float64     // Physical/logical
+	resultCh         <-chan overlay.Result
+}
+
+// startRegionSelection captures the virtual screen and shows the native overlay
+// over it. The main window should already be hidden.
+func (a *App) startRegionSelection() (*regionSelection, error) {
+	return a.startOverlay(a.overlayManager.Show)
+}
+
+// startOverlay captures the virtual screen and shows it with show, the overlay
+// manager's Show or ShowPicker
+func (a *App) startOverlay(show func(*image.RGBA, image.Rectangle, float64) <-chan overlay.Result) (*regionSelection, error) {
+	// Get the virtual screen bounds first
+	screenX, screenY, virtualWidth, virtualHeight := screenshot.GetVirtualScreenBounds()
+
+	// Capture raw RGBA (faster - no PNG encode)
+	rgbaImg, err := screenshot.CaptureVirtualScreenRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate scale ratio between physical screenshot and logical window size
+	scaleRatio := float64(rgbaImg.Bounds().Dx()) / float64(virtualWidth)
+	if scaleRatio < 1.0 {
+		scaleRatio = 1.0
+	}
+
+	// Show native overlay and get result channel
+	bounds := image.Rect(screenX, screenY, screenX+virtualWidth, screenY+virtualHeight)
+	return &regionSelection{
+		shot:       rgbaImg,
+		screenX:    screenX,
+		screenY:    screenY,
+		width:      virtualWidth,
+		height:     virtualHeight,
+		scaleRatio: scaleRatio,
+		resultCh:   show(rgbaImg, bounds, scaleRatio),
+	}, nil
+}
+
+// waitRegionSelection blocks until the user finishes selecting and returns the selected part
+// of the screen, or nil if the selection was cancelled
+func (a *App) waitRegionSelection(sel *regionSelection) image.Image {
+	selResult := <-sel.resultCh
+	if selResult.Cancelled {
+		return nil
+	}
+
+	// Scale coordinates to physical pixels
+	scaledX := int(float64(selResult.X) * sel.scaleRatio)
+	scaledY := int(float64(selResult.Y) * sel.scaleRatio)
+	scaledW := int(float64(selResult.Width) * sel.scaleRatio)
+	scaledH := int(float64(selResult.Height) * sel.scaleRatio)
+
+	a.setLastCapture(displaySource(library.CaptureModeRegion,
+		sel.screenX+scaledX+scaledW/2, sel.screenY+scaledY+scaledH/2))
+
+	// Crop to selected region before encoding (much faster - smaller image)
+	return sel.shot.SubImage(image.Rect(scaledX, scaledY, scaledX+scaledW, scaledY+scaledH))
+}
+
 // imageToRGBA converts an image.Image to *image.RGBA
 func imageToRGBA(img image.Image) *image.RGBA {
 	if rgba, ok := img.(*image.RGBA); ok {
@@ -366,21 +612,28 @@ func (a *App) ShowWindow() {
 
 // CaptureFullscreen captures the display where the cursor is currently located
 func (a *App) CaptureFullscreen() (*screenshot.CaptureResult, error) {
+	cursorX, cursorY := screenshot.GetCursorPosition()
+	a.setLastCapture(displaySource(library.CaptureModeFullscreen, cursorX, cursorY))
 	return screenshot.CaptureFullscreen()
 }
 
 // CaptureRegion captures a specific region of the screen
 func (a *App) CaptureRegion(x, y, width, height int) (*screenshot.CaptureResult, error) {
+	a.setLastCapture(displaySource(library.CaptureModeRegion, x+width/2, y+height/2))
 	return screenshot.CaptureRegion(x, y, width, height)
 }
 
 // CaptureDisplay captures a specific display by index
 func (a *App) CaptureDisplay(displayIndex int) (*screenshot.CaptureResult, error) {
+	center := screenshot.GetDisplayBounds(displayIndex)
+	a.setLastCapture(displaySource(library.CaptureModeFullscreen,
+		(center.Min.X+center.Max.X)/2, (center.Min.Y+center.Max.Y)/2))
 	return screenshot.CaptureDisplay(displayIndex)
 }
 
 // CaptureWindow captures a specific window by handle
 func (a *App) CaptureWindow(hwnd int) (*screenshot.CaptureResult, error) {
+	a.setLastCapture(windowCaptureSource(uintptr(hwnd)))
 	result, err := screenshot.CaptureWindowByCoords(uintptr(hwnd))
 
 	// Bring WinShot back to front after capture
@@ -392,6 +645,71 @@ func (a *App) CaptureWindow(hwnd int) (*screenshot.CaptureResult, error) {
 	return result, err
 }
 
+// windowCaptureSource describes a window capture for the library index
+func windowCaptureSource(hwnd uintptr) library.CaptureSource {
+	source := library.CaptureSource{Mode: library.CaptureModeWindow}
+	if info, err := winEnum.GetWindowInfo(hwnd); err == nil && info != nil {
+		source = displaySource(library.CaptureModeWindow, info.X+info.Width/2, info.Y+info.Height/2)
+		source.WindowTitle = info.Title
+		source.WindowClass = info.ClassName
+	}
+	source.ProcessName = winEnum.GetWindowProcessName(hwnd)
+	return source
+}
+
+// displaySource describes a screen capture centered at (x, y): the monitor it
+// was taken on and that monitor's DPI scale
+func displaySource(mode string, x, y int) library.CaptureSource {
+	return library.CaptureSource{
+		Mode:     mode,
+		Monitor:  screenshot.GetMonitorAt(x, y) + 1,
+		DPIScale: screenshot.GetScaleAt(x, y),
+	}
+}
+
+// setLastCapture records the source of the image now in the editor
+func (a *App) setLastCapture(source library.CaptureSource) {
+	a.lastCapture = source
+	a.lastCaptureAt = time.Now()
+}
+
+// applyCaptureMetadata embeds the last capture's context into encoded PNG/JPEG
+// data, or strips all metadata when the privacy setting is on. Failures leave
+// the data unchanged.
+func (a *App) applyCaptureMetadata(data []byte) []byte {
+	var out []byte
+	var err error
+	if a.config.Export.StripMetadata {
+		out, err = metadata.Strip(data)
+	} else {
+		out, err = metadata.Embed(data, a.captureMetadata())
+	}
+	if err != nil {
+		println("Warning: failed to write image metadata:", err.Error())
+		return data
+	}
+	return out
+}
+
+// captureMetadata builds the metadata record for the image in the editor
+func (a *App) captureMetadata() metadata.Metadata {
+	capturedAt := a.lastCaptureAt
+	if capturedAt.IsZero() {
+		capturedAt = time.Now()
+	}
+	return metadata.Metadata{
+		CaptureMode: a.lastCapture.Mode,
+		CapturedAt:  capturedAt,
+		WindowTitle: a.lastCapture.WindowTitle,
+		WindowClass: a.lastCapture.WindowClass,
+		ProcessName: a.lastCapture.ProcessName,
+		Monitor:     a.lastCapture.Monitor,
+		DPIScale:    a.lastCapture.DPIScale,
+		AppVersion:  Version,
+		Author:      a.config.Export.MetadataAuthor,
+	}
+}
+
 // GetDisplayCount returns the number of active displays
 func (a *App) GetDisplayCount() int {
 	return screenshot.GetDisplayCount()
@@ -445,9 +763,10 @@ func (a *App) GetWindowInfo(hwnd int) (*winEnum.WindowInfo, error) {
 
 // SaveImageResult represents the result of saving an image
 type SaveImageResult struct {
-	Success  bool   `json:"success"`
-	FilePath string `json:"filePath"`
-	Error    string `json:"error,omitempty"`
+	Success     bool   `json:"success"`
+	FilePath    string `json:"filePath"`
+	Error       string `json:"error,omitempty"`
+	DuplicateOf string `json:"duplicateOf,omitempty"` // QuickSave: recent near-identical screenshot
 }
 
 // SaveImage saves a base64 encoded image to a file using a save dialog
@@ -491,6 +810,7 @@ func (a *App) SaveImage(imageData string, format string) SaveImageResult {
 	if err != nil {
 		return SaveImageResult{Success: false, Error: "Failed to decode image data: " + err.Error()}
 	}
+	data = a.applyCaptureMetadata(data)
 
 	// Write to file
 	err = os.WriteFile(filePath, data, 0644)
@@ -501,20 +821,29 @@ func (a *App) SaveImage(imageData string, format string) SaveImageResult {
 	return SaveImageResult{Success: true, FilePath: filePath}
 }
 
-// QuickSave saves a base64 encoded image to the configured directory
+// QuickSave saves a base64 encoded image to the configured directory and
+// shows a notification for it
 func (a *App) QuickSave(imageData string, format string) SaveImageResult {
+	result := a.quickSave(imageData, format)
+	if result.Success {
+		go func() {
+			img, _ := decodeBase64Image(imageData)
+			a.showNotification(notify.Saved(result.FilePath, img))
+		}()
+	}
+	return result
+}
+
+// quickSave saves a base64 encoded image to the configured directory
+func (a *App) quickSave(imageData string, format string) SaveImageResult {
 	// Get save directory from config (fallback to default)
-	saveDir := a.config.QuickSave.Folder
-	if saveDir == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return SaveImageResult{Success: false, Error: "Failed to get home directory: " + err.Error()}
-		}
-		saveDir = filepath.Join(homeDir, "Pictures", "WinShot")
+	saveDir, err := a.quickSaveFolder()
+	if err != nil {
+		return SaveImageResult{Success: false, Error: err.Error()}
 	}
 
 	// Create save directory if it doesn't exist
-	err := os.MkdirAll(saveDir, 0755)
+	err = os.MkdirAll(saveDir, 0755)
 	if err != nil {
 		return SaveImageResult{Success: false, Error: "Failed to create save directory: " + err.Error()}
 	}
@@ -576,29 +905,51 @@ func (a *App) QuickSave(imageData string, format string) SaveImageResult {
 	if err != nil {
 		return SaveImageResult{Success: false, Error: "Failed to decode image data: " + err.Error()}
 	}
+	data = a.applyCaptureMetadata(data)
 
 	err = os.WriteFile(filePath, data, 0644)
 	if err != nil {
 		return SaveImageResult{Success: false, Error: "Failed to save file: " + err.Error()}
 	}
 
-	return SaveImageResult{Success: true, FilePath: filePath}
-}
+	// Record capture source in the library index (non-fatal)
+	result := SaveImageResult{Success: true, FilePath: filePath}
+	if a.libraryIndex != nil {
+		a.libraryIndex.Record(filePath, a.lastCapture)
+		result.DuplicateOf = a.flagRecentDuplicate(filePath, data)
+	}
 
-// HotkeyConfig represents a hotkey configuration
-type HotkeyConfig struct {
-	Fullscreen string `json:"fullscreen"`
-	Region     string `json:"region"`
-	Window     string `json:"window"`
+	return result
 }
 
-// GetHotkeyConfig returns the current hotkey configuration
-func (a *App) GetHotkeyConfig() HotkeyConfig {
-	return HotkeyConfig{
-		Fullscreen: a.config.Hotkeys.Fullscreen,
-		Region:     a.config.Hotkeys.Region,
-		Window:     a.config.Hotkeys.Window,
+// recentDuplicateWindow is how many recent screenshots QuickSave compares against
+const recentDuplicateWindow = 10
+
+// flagRecentDuplicate hashes a freshly saved screenshot, stores the hash and returns
+// the path of a recent near-identical screenshot, if any
+func (a *App) flagRecentDuplicate(filePath string, data []byte) string {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	hash := library.HashImage(img)
+	if err := a.libraryIndex.SetHash(filePath, hash); err != nil {
+		println("Warning: failed to store image hash:", err.Error())
+	}
+
+	match, _, ok := a.libraryIndex.FindSimilarRecent(hash, filePath, recentDuplicateWindow, library.DefaultDuplicateThreshold)
+	if !ok {
+		return ""
 	}
+	if _, err := os.Stat(match.Path); err != nil {
+		return ""
+	}
+	return match.Path
+}
+
+// GetHotkeyConfig returns the current hotkey bindings
+func (a *App) GetHotkeyConfig() config.HotkeyConfig {
+	return a.config.Hotkeys
 }
 
 // GetConfig returns the current application configuration
@@ -608,6 +959,13 @@ func (a *App) GetConfig() *config.Config {
 
 // SaveConfig saves the application configuration
 func (a *App) SaveConfig(cfg *config.Config) error {
+	// Reject hotkeys that could never register before applying anything
+	if !reflect.DeepEqual(cfg.Hotkeys.Bindings, a.config.Hotkeys.Bindings) {
+		if err := validateHotkeyBindings(cfg.Hotkeys.Bindings); err != nil {
+			return err
+		}
+	}
+
 	// Update startup setting if changed
 	if cfg.Startup.LaunchOnStartup != a.config.Startup.LaunchOnStartup {
 		if err := config.SetStartupEnabled(cfg.Startup.LaunchOnStartup); err != nil {
@@ -616,20 +974,28 @@ func (a *App) SaveConfig(cfg *config.Config) error {
 	}
 
 	// Update hotkeys if changed
-	hotkeysChanged := cfg.Hotkeys.Fullscreen != a.config.Hotkeys.Fullscreen ||
-		cfg.Hotkeys.Region != a.config.Hotkeys.Region ||
-		cfg.Hotkeys.Window != a.config.Hotkeys.Window
+	hotkeysChanged := !reflect.DeepEqual(cfg.Hotkeys.Bindings, a.config.Hotkeys.Bindings)
 
 	// Store new config
 	a.config = cfg
 
+	// Pick up OCR settings changes
+	a.ocrEngine = ocr.NewTesseractEngine(cfg.OCR.TesseractPath, cfg.OCR.Language)
+
+	// Follow the QuickSave folder if it moved
+	a.startLibraryWatcher()
+	a.applyClipboardWatch()
+
 	// Save to disk
 	if err := cfg.Save(); err != nil {
 		return err
 	}
 
-	// Re-register hotkeys if they changed
-	if hotkeysChanged {
+	// Re-register hotkeys if they changed, unless paused from the tray
+	a.hotkeyMu.Lock()
+	paused := a.hotkeysPaused
+	a.hotkeyMu.Unlock()
+	if hotkeysChanged && !paused {
 		a.hotkeyManager.UnregisterAll()
 		a.registerHotkeysFromConfig()
 	}
@@ -644,22 +1010,126 @@ func (a *App) SelectFolder() (string, error) {
 	})
 }
 
-// registerHotkeysFromConfig registers hotkeys based on current config
+// registerHotkeysFromConfig registers every hotkey binding in the current config
+// and reports the ones that failed
 func (a *App) registerHotkeysFromConfig() {
-	// Parse and register fullscreen hotkey
-	if mods, key, ok := hotkeys.ParseHotkeyString(a.config.Hotkeys.Fullscreen); ok {
-		a.hotkeyManager.Register(hotkeys.HotkeyFullscreen, mods, key)
+	bindings := make(map[int]config.HotkeyBinding)
+	status := make([]hotkeys.Registration, 0, len(a.config.Hotkeys.Bindings))
+	for _, binding := range a.config.Hotkeys.Bindings {
+		reg := a.hotkeyManager.AddKeys(binding.Keys)
+		if reg.Status != hotkeys.StatusOK {
+			println("Warning: failed to register hotkey", binding.Keys+":", reg.Error)
+		} else {
+			bindings[reg.ID] = binding
+		}
+		status = append(status, reg)
+	}
+
+	a.hotkeyMu.Lock()
+	a.hotkeyBindings = bindings
+	a.hotkeyStatus = status
+	a.hotkeyMu.Unlock()
+
+	if a.trayIcon != nil {
+		a.trayIcon.SetHotkeyWarning(hotkeyWarning(status))
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "hotkeys:status", status)
 	}
+}
+
+// setHotkeysPaused unregisters every hotkey, so they reach other apps, or
+// registers them again from the config
+func (a *App) setHotkeysPaused(paused bool) {
+	a.hotkeyMu.Lock()
+	a.hotkeysPaused = paused
+	if paused {
+		a.hotkeyBindings = nil
+	}
+	a.hotkeyMu.Unlock()
+
+	if paused {
+		a.hotkeyManager.UnregisterAll()
+	} else {
+		a.registerHotkeysFromConfig()
+	}
+	if a.trayIcon != nil {
+		a.trayIcon.SetHotkeysPaused(paused)
+	}
+}
+
+// GetHotkeyStatus returns the registration result of each hotkey binding, in config order
+func (a *App) GetHotkeyStatus() []hotkeys.Registration {
+	a.hotkeyMu.Lock()
+	defer a.hotkeyMu.Unlock()
+	return append([]hotkeys.Registration(nil), a.hotkeyStatus...)
+}
+
+// CheckHotkey tells whether keys can be bound before saving: one of WinShot's own
+// bindings is fine, anything else must be valid and free. Failures come with
+// alternatives to try.
+func (a *App) CheckHotkey(keys string) hotkeys.Registration {
+	reg := hotkeys.Registration{Keys: keys, Status: hotkeys.StatusOK}
+
+	// Only the first combination of a sequence stays registered
+	seq, err := hotkeys.ValidateHotkeyString(keys)
+	if err == nil && !a.isOwnHotkey(seq[0]) {
+		err = a.hotkeyManager.Probe(seq[0].Modifiers, seq[0].KeyCode)
+	}
+	if err != nil {
+		reg.Status = hotkeys.StatusOf(err)
+		reg.Error = err.Error()
+		reg.Suggestions = a.hotkeyManager.Suggest(keys, 3)
+	}
+	return reg
+}
+
+// isOwnHotkey reports whether the first combination of one of the current
+// bindings registers the same way as combo
+func (a *App) isOwnHotkey(combo hotkeys.Combo) bool {
+	a.hotkeyMu.Lock()
+	defer a.hotkeyMu.Unlock()
+	for _, binding := range a.hotkeyBindings {
+		if seq, err := hotkeys.ParseSequence(binding.Keys); err == nil &&
+			seq[0].Modifiers == combo.Modifiers && seq[0].KeyCode == combo.KeyCode {
+			return true
+		}
+	}
+	return false
+}
 
-	// Parse and register region hotkey
-	if mods, key, ok := hotkeys.ParseHotkeyString(a.config.Hotkeys.Region); ok {
-		a.hotkeyManager.Register(hotkeys.HotkeyRegion, mods, key)
+// validateHotkeyBindings rejects bindings that could never register: unknown
+// keys, reserved combinations, and keys that are bound twice or start another
+// binding's sequence. Valid keys are rewritten in canonical form.
+func validateHotkeyBindings(bindings []config.HotkeyBinding) error {
+	seqs := make([]hotkeys.Sequence, len(bindings))
+	for i, binding := range bindings {
+		seq, err := hotkeys.ValidateHotkeyString(binding.Keys)
+		if err != nil {
+			return fmt.Errorf("hotkey %s: %w", binding.Keys, err)
+		}
+		for j, other := range seqs[:i] {
+			if seq.Conflicts(other) {
+				return fmt.Errorf("hotkey %s: conflicts with %s", binding.Keys, bindings[j].Keys)
+			}
+		}
+		seqs[i] = seq
 	}
+	for i, seq := range seqs {
+		bindings[i].Keys = seq.String()
+	}
+	return nil
+}
 
-	// Parse and register window hotkey
-	if mods, key, ok := hotkeys.ParseHotkeyString(a.config.Hotkeys.Window); ok {
-		a.hotkeyManager.Register(hotkeys.HotkeyWindow, mods, key)
+// hotkeyWarning summarizes failed registrations for the tray, or returns "" if all succeeded
+func hotkeyWarning(status []hotkeys.Registration) string {
+	var lines []string
+	for _, reg := range status {
+		if reg.Status != hotkeys.StatusOK {
+			lines = append(lines, reg.Keys+": "+reg.Error)
+		}
 	}
+	return strings.Join(lines, "\n")
 }
 
 // GetBackgroundImages returns the list of saved background images (base64 data URLs)
@@ -740,6 +1210,8 @@ func (a *App) OpenImage() (*screenshot.CaptureResult, error) {
 		return nil, err
 	}
 
+	a.setLastCapture(library.CaptureSource{Mode: library.CaptureModeFile})
+
 	// Return as base64 encoded PNG
 	return &screenshot.CaptureResult{
 		Width:  bounds.Dx(),
@@ -750,7 +1222,63 @@ func (a *App) OpenImage() (*screenshot.CaptureResult, error) {
 
 // GetClipboardImage reads an image from the Windows clipboard
 func (a *App) GetClipboardImage() (*screenshot.CaptureResult, error) {
-	return screenshot.GetClipboardImage()
+	result, err := screenshot.GetClipboardImage()
+	if err == nil {
+		a.setLastCapture(library.CaptureSource{Mode: library.CaptureModeClipboard})
+	}
+	return result, err
+}
+
+// GetClipboardImages reads every image from the clipboard, e.g. all image files
+// in a multi-file copy from File Explorer
+func (a *App) GetClipboardImages() ([]*screenshot.CaptureResult, error) {
+	results, err := screenshot.GetClipboardImages()
+	if err == nil {
+		a.setLastCapture(library.CaptureSource{Mode: library.CaptureModeClipboard})
+	}
+	return results, err
+}
+
+// CopyImageToClipboard places a base64 encoded image on the Windows clipboard as PNG,
+// DIBV5 and DIB plus an HTML fragment. Unlike the webview clipboard API this works
+// while the window is hidden or unfocused.
+func (a *App) CopyImageToClipboard(imageData string) error {
+	img, err := decodeBase64Image(imageData)
+	if err != nil {
+		return err
+	}
+
+	alt := a.lastCapture.WindowTitle
+	if alt == "" {
+		alt = "Screenshot"
+	}
+	return screenshot.SetClipboardImage(img, screenshot.ClipboardImageOptions{HTML: true, Alt: alt})
+}
+
+// CopyFileToClipboard places a library image file on the clipboard, both as a
+// file (pastes into File Explorer and chat apps) and as image data (pastes into editors)
+// Security: only files within the QuickSave folder can be copied
+func (a *App) CopyFileToClipboard(filePath string) error {
+	absPath, err := a.resolveLibraryPath(filePath)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	return screenshot.SetClipboardImage(img, screenshot.ClipboardImageOptions{
+		FilePath: absPath,
+		HTML:     true,
+		Alt:      filepath.Base(absPath),
+	})
 }
 
 // CheckForUpdate checks GitHub for a newer version
@@ -831,13 +1359,32 @@ func (a *App) TestR2Connection() error {
 	return a.r2Uploader.TestConnection()
 }
 
+// uploaderFor returns the uploader for a provider ("r2" or "gdrive") if it is configured
+func (a *App) uploaderFor(provider string) (upload.Uploader, error) {
+	var uploader upload.Uploader
+	switch upload.UploadProvider(provider) {
+	case upload.ProviderR2:
+		uploader = a.r2Uploader
+	case upload.ProviderGDrive:
+		uploader = a.gdriveUploader
+	default:
+		return nil, fmt.Errorf("unknown upload provider: %s", provider)
+	}
+	if !uploader.IsConfigured() {
+		return nil, fmt.Errorf("%s is not configured", provider)
+	}
+	return uploader, nil
+}
+
 // UploadToR2 uploads image to Cloudflare R2
 func (a *App) UploadToR2(imageData, filename string) (*upload.UploadResult, error) {
 	data, err := base64.StdEncoding.DecodeString(imageData)
 	if err != nil {
 		return &upload.UploadResult{Success: false, Error: "invalid image data"}, err
 	}
-	return a.r2Uploader.Upload(context.Background(), data, filename)
+	result, err := a.r2Uploader.Upload(context.Background(), data, filename)
+	a.notifyUpload(data, result, err)
+	return result, err
 }
 
 // ClearR2Credentials removes R2 credentials from Windows Credential Manager
@@ -933,7 +1480,9 @@ func (a *App) UploadToGDrive(imageData, filename string) (*upload.UploadResult,
 	if err != nil {
 		return &upload.UploadResult{Success: false, Error: "invalid image data"}, err
 	}
-	return a.gdriveUploader.Upload(context.Background(), data, filename)
+	result, err := a.gdriveUploader.Upload(context.Background(), data, filename)
+	a.notifyUpload(data, result, err)
+	return result, err
 }
 
 // ClearGDriveCredentials removes all GDrive credentials from Windows Credential Manager
@@ -946,70 +1495,460 @@ func (a *App) ClearGDriveCredentials() error {
 
 // ==================== Screenshot Library ====================
 
-// GetLibraryImages returns all screenshots from QuickSave folder
-func (a *App) GetLibraryImages() ([]library.LibraryImage, error) {
+// quickSaveFolder returns the configured QuickSave folder, falling back to Pictures\WinShot
+func (a *App) quickSaveFolder() (string, error) {
 	folder := a.config.QuickSave.Folder
 	if folder == "" {
-		// Fallback to default location
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
+			return "", fmt.Errorf("failed to get home directory: %w", err)
 		}
 		folder = filepath.Join(homeDir, "Pictures", "WinShot")
 	}
-
-	opts := library.DefaultScanOptions()
-	return library.ScanFolder(folder, opts)
+	return folder, nil
 }
 
-// OpenInEditor loads an image file into the editor
-// Security: validates path is within QuickSave folder
-func (a *App) OpenInEditor(imagePath string) (*screenshot.CaptureResult, error) {
-	// Validate path is within QuickSave folder (prevent directory traversal)
-	folder := a.config.QuickSave.Folder
-	if folder == "" {
-		homeDir, _ := os.UserHomeDir()
-		folder = filepath.Join(homeDir, "Pictures", "WinShot")
+// resolveLibraryPath returns the absolute path of a library file
+// Security: validates path is within QuickSave folder (prevent directory traversal)
+func (a *App) resolveLibraryPath(imagePath string) (string, error) {
+	folder, err := a.quickSaveFolder()
+	if err != nil {
+		return "", err
 	}
 
 	absPath, err := filepath.Abs(imagePath)
 	if err != nil {
-		return nil, fmt.Errorf("invalid path: %w", err)
+		return "", fmt.Errorf("invalid path: %w", err)
 	}
 
 	absFolder, err := filepath.Abs(folder)
 	if err != nil {
-		return nil, fmt.Errorf("invalid folder path: %w", err)
+		return "", fmt.Errorf("invalid folder path: %w", err)
 	}
 
 	// Security check: ensure file is within QuickSave folder
 	if !strings.HasPrefix(absPath, absFolder+string(filepath.Separator)) {
-		return nil, fmt.Errorf("access denied: file outside QuickSave folder")
+		return "", fmt.Errorf("access denied: file outside QuickSave folder")
 	}
 
-	// Read file
-	data, err := os.ReadFile(absPath)
+	return absPath, nil
+}
+
+// GetLibraryImages returns all screenshots from QuickSave folder
+func (a *App) GetLibraryImages() ([]library.LibraryImage, error) {
+	folder, err := a.quickSaveFolder()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, err
 	}
 
-	// Decode image
-	var img image.Image
-	ext := strings.ToLower(filepath.Ext(absPath))
-
-	switch ext {
-	case ".png":
-		img, err = png.Decode(bytes.NewReader(data))
-	case ".jpg", ".jpeg":
-		img, err = jpeg.Decode(bytes.NewReader(data))
-	default:
-		img, _, err = image.Decode(bytes.NewReader(data))
-	}
+	images, err := library.ScanFolder(folder, a.libraryScanOptions())
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode image: %w", err)
+		return nil, err
 	}
 
-	bounds := img.Bounds()
+	// Attach stored metadata (tags, source, OCR text) so the library can be searched
+	if a.libraryIndex != nil {
+		a.syncLibraryIndex(folder)
+		a.libraryIndex.Annotate(images)
+	}
+	return images, nil
+}
+
+// GetLibraryPage returns one page (1-based, newest first) of the QuickSave folder.
+// Only the page's thumbnails are generated, so the library can load lazily.
+func (a *App) GetLibraryPage(page, pageSize int) (*library.SearchResult, error) {
+	folder, err := a.quickSaveFolder()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := a.libraryScanOptions()
+	opts.MaxFiles = 0 // Paging replaces the scan limit
+	result, err := library.ScanFolderPage(folder, opts, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.libraryIndex != nil {
+		a.libraryIndex.Annotate(result.Images)
+	}
+	return result, nil
+}
+
+// startLibraryWatcher watches the QuickSave folder, restarting the watcher if the folder or depth changed
+func (a *App) startLibraryWatcher() {
+	folder, err := a.quickSaveFolder()
+	if err != nil {
+		return
+	}
+
+	depth := a.config.QuickSave.ScanDepth
+	if a.libraryWatcher != nil {
+		if a.libraryWatcher.Folder() == folder && a.libraryWatcher.MaxDepth() == depth {
+			return
+		}
+		a.libraryWatcher.Stop()
+		a.libraryWatcher = nil
+	}
+
+	opts := library.DefaultWatchOptions()
+	opts.MaxDepth = depth
+	watcher := library.NewWatcher(folder, opts, a.onLibraryChanges)
+	if err := watcher.Start(); err != nil {
+		println("Warning: failed to watch library folder:", err.Error())
+		return
+	}
+	a.libraryWatcher = watcher
+}
+
+// syncLibraryIndex rescans folder into the index unless the watcher already
+// keeps it current: once the index was rescanned for the folder and scan depth
+// the running watcher watches, the watcher's rescans pick up every change.
+func (a *App) syncLibraryIndex(folder string) error {
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return fmt.Errorf("invalid folder path: %w", err)
+	}
+	depth := a.config.QuickSave.ScanDepth
+	key := fmt.Sprintf("%s|%d", strings.ToLower(absFolder), depth)
+
+	a.indexSyncMu.Lock()
+	defer a.indexSyncMu.Unlock()
+	if a.indexSynced == key && a.libraryWatcher != nil {
+		return nil
+	}
+	if _, err := a.libraryIndex.Rescan(absFolder, depth); err != nil {
+		return err
+	}
+	a.indexSynced = key
+	return nil
+}
+
+// onLibraryChanges syncs the index and emits library:added/removed/changed events.
+// Added and changed events carry the full LibraryImage including its thumbnail.
+func (a *App) onLibraryChanges(events []library.FileEvent) {
+	if folder, err := a.quickSaveFolder(); err == nil && a.libraryIndex != nil {
+		a.libraryIndex.Rescan(folder, a.config.QuickSave.ScanDepth)
+	}
+
+	opts := a.libraryScanOptions()
+	for _, event := range events {
+		if event.Kind == library.FileRemoved {
+			runtime.EventsEmit(a.ctx, "library:removed", library.LibraryImage{
+				Filepath: event.Path,
+				Filename: filepath.Base(event.Path),
+			})
+			continue
+		}
+
+		img, err := library.LoadLibraryImage(event.Path, opts)
+		if err != nil {
+			continue // Still being written - the next change event will pick it up
+		}
+		if a.libraryIndex != nil {
+			images := []library.LibraryImage{img}
+			a.libraryIndex.Annotate(images)
+			img = images[0]
+		}
+		runtime.EventsEmit(a.ctx, "library:"+event.Kind, img)
+	}
+}
+
+// libraryScanOptions returns scan options backed by the thumbnail cache
+func (a *App) libraryScanOptions() library.ScanOptions {
+	opts := library.DefaultScanOptions()
+	opts.MaxDepth = a.config.QuickSave.ScanDepth
+	opts.Cache = a.thumbCache
+	return opts
+}
+
+// SearchLibrary queries the library index by text (filename, window title, tags, OCR text),
+// date range, tags and capture source. Pages are 1-based; thumbnails are only generated
+// for the returned page.
+func (a *App) SearchLibrary(query string, filters library.SearchFilters, sort string, page int) (*library.SearchResult, error) {
+	if a.libraryIndex == nil {
+		return nil, fmt.Errorf("library index not available")
+	}
+
+	folder, err := a.quickSaveFolder()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.syncLibraryIndex(folder); err != nil {
+		return nil, err
+	}
+
+	result := a.libraryIndex.Search(library.SearchQuery{
+		Text:    query,
+		Filters: filters,
+		Sort:    sort,
+		Page:    page,
+	})
+	library.LoadThumbnails(result.Images, a.libraryScanOptions())
+	return &result, nil
+}
+
+// SetLibraryTags replaces the tags of a library screenshot
+// Security: validates path is within QuickSave folder
+func (a *App) SetLibraryTags(imagePath string, tags []string) error {
+	absPath, err := a.resolveLibraryPath(imagePath)
+	if err != nil {
+		return err
+	}
+	if a.libraryIndex == nil {
+		return fmt.Errorf("library index not available")
+	}
+	return a.libraryIndex.SetTags(absPath, tags)
+}
+
+// RecordLibraryUpload remembers the public URL a library screenshot was uploaded to
+// Security: validates path is within QuickSave folder
+func (a *App) RecordLibraryUpload(imagePath, url string) error {
+	absPath, err := a.resolveLibraryPath(imagePath)
+	if err != nil {
+		return err
+	}
+	if a.libraryIndex == nil {
+		return fmt.Errorf("library index not available")
+	}
+	return a.libraryIndex.AddUploadURL(absPath, url)
+}
+
+// SetLibraryFavorite marks or unmarks a library screenshot as a favorite
+// Security: validates path is within QuickSave folder
+func (a *App) SetLibraryFavorite(imagePath string, favorite bool) error {
+	absPath, err := a.resolveLibraryPath(imagePath)
+	if err != nil {
+		return err
+	}
+	if a.libraryIndex == nil {
+		return fmt.Errorf("library index not available")
+	}
+	return a.libraryIndex.SetFavorite(absPath, favorite)
+}
+
+// GetCollections returns the saved library collections
+func (a *App) GetCollections() []library.Collection {
+	if a.libraryIndex == nil {
+		return []library.Collection{}
+	}
+	return a.libraryIndex.Collections()
+}
+
+// SaveCollection creates or replaces a collection (a saved library search)
+func (a *App) SaveCollection(collection library.Collection) error {
+	if a.libraryIndex == nil {
+		return fmt.Errorf("library index not available")
+	}
+	return a.libraryIndex.SaveCollection(collection)
+}
+
+// DeleteCollection removes a saved collection; its screenshots are not touched
+func (a *App) DeleteCollection(name string) error {
+	if a.libraryIndex == nil {
+		return fmt.Errorf("library index not available")
+	}
+	return a.libraryIndex.DeleteCollection(name)
+}
+
+// GetCollectionImages returns one page (1-based) of a collection's screenshots
+func (a *App) GetCollectionImages(name string, page int) (*library.SearchResult, error) {
+	if a.libraryIndex == nil {
+		return nil, fmt.Errorf("library index not available")
+	}
+	collection, ok := a.libraryIndex.GetCollection(name)
+	if !ok {
+		return nil, fmt.Errorf("collection not found: %s", name)
+	}
+	return a.SearchLibrary(collection.Query, collection.Filters, collection.Sort, page)
+}
+
+// GetLibraryFolders returns the subfolders of the QuickSave folder (relative paths)
+// that are within the configured scan depth
+func (a *App) GetLibraryFolders() ([]string, error) {
+	folder, err := a.quickSaveFolder()
+	if err != nil {
+		return nil, err
+	}
+	return library.ListSubfolders(folder, a.config.QuickSave.ScanDepth)
+}
+
+// FindDuplicates groups library screenshots that look the same or nearly the same.
+// threshold is the maximum perceptual hash distance (0-64); <= 0 uses the default.
+// Missing hashes are computed and cached in the index.
+func (a *App) FindDuplicates(threshold int) ([]library.DuplicateGroup, error) {
+	if a.libraryIndex == nil {
+		return nil, fmt.Errorf("library index not available")
+	}
+	if threshold <= 0 {
+		threshold = library.DefaultDuplicateThreshold
+	}
+
+	folder, err := a.quickSaveFolder()
+	if err != nil {
+		return nil, err
+	}
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return nil, fmt.Errorf("invalid folder path: %w", err)
+	}
+
+	depth := a.config.QuickSave.ScanDepth
+	if err := a.syncLibraryIndex(absFolder); err != nil {
+		return nil, err
+	}
+
+	entries := a.libraryIndex.EnsureHashes(a.libraryIndex.EntriesIn(absFolder, depth))
+	groups := library.GroupDuplicates(entries, threshold)
+	for i := range groups {
+		library.LoadThumbnails(groups[i].Images, a.libraryScanOptions())
+	}
+	return groups, nil
+}
+
+// MoveScreenshot moves a library screenshot into destFolder, given relative to the
+// QuickSave folder ("" for the top level). Returns the new path.
+// Security: both source and destination must be within QuickSave folder
+func (a *App) MoveScreenshot(imagePath, destFolder string) (string, error) {
+	absPath, err := a.resolveLibraryPath(imagePath)
+	if err != nil {
+		return "", err
+	}
+
+	absDest, err := a.resolveLibraryFolder(destFolder)
+	if err != nil {
+		return "", err
+	}
+	// The library would never list a file moved where its scan doesn't reach
+	newPath := filepath.Join(absDest, filepath.Base(absPath))
+	if root, err := a.quickSaveFolder(); err == nil {
+		if absRoot, err := filepath.Abs(root); err == nil && !library.InScan(absRoot, newPath, a.config.QuickSave.ScanDepth) {
+			return "", fmt.Errorf("can't move into a hidden folder or deeper than %d subfolder levels", a.config.QuickSave.ScanDepth)
+		}
+	}
+	if err := os.MkdirAll(absDest, 0755); err != nil {
+		return "", fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	return a.relocateScreenshot(absPath, newPath)
+}
+
+// RenameScreenshot renames a library screenshot in place, keeping its extension
+// if newName has none. Returns the new path.
+// Security: validates path is within QuickSave folder
+func (a *App) RenameScreenshot(imagePath, newName string) (string, error) {
+	absPath, err := a.resolveLibraryPath(imagePath)
+	if err != nil {
+		return "", err
+	}
+
+	newName = strings.TrimSpace(newName)
+	if newName == "" || newName != filepath.Base(newName) || newName == "." || newName == ".." {
+		return "", fmt.Errorf("invalid file name: %q", newName)
+	}
+	if filepath.Ext(newName) == "" {
+		newName += filepath.Ext(absPath)
+	}
+	switch strings.ToLower(filepath.Ext(newName)) {
+	case ".png", ".jpg", ".jpeg":
+	default:
+		return "", fmt.Errorf("unsupported image extension: %s", filepath.Ext(newName))
+	}
+
+	return a.relocateScreenshot(absPath, filepath.Join(filepath.Dir(absPath), newName))
+}
+
+// relocateScreenshot renames a validated library file without overwriting and
+// carries its index metadata along
+func (a *App) relocateScreenshot(absPath, newPath string) (string, error) {
+	newPath, err := a.resolveLibraryPath(newPath)
+	if err != nil {
+		return "", err
+	}
+	if newPath == absPath {
+		return newPath, nil
+	}
+
+	// Allow case-only renames of the same file; refuse to overwrite anything else
+	if info, err := os.Stat(newPath); err == nil {
+		if oldInfo, err := os.Stat(absPath); err != nil || !os.SameFile(info, oldInfo) {
+			return "", fmt.Errorf("a file named %s already exists", filepath.Base(newPath))
+		}
+	}
+
+	if err := os.Rename(absPath, newPath); err != nil {
+		return "", fmt.Errorf("failed to move file: %w", err)
+	}
+
+	if a.libraryIndex != nil {
+		a.libraryIndex.Move(absPath, newPath)
+	}
+	return newPath, nil
+}
+
+// resolveLibraryFolder returns the absolute path of a folder inside the QuickSave folder.
+// folder may be relative to the QuickSave folder or absolute; "" is the QuickSave folder itself.
+// Security: validates folder is within QuickSave folder (prevent directory traversal)
+func (a *App) resolveLibraryFolder(folder string) (string, error) {
+	root, err := a.quickSaveFolder()
+	if err != nil {
+		return "", err
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("invalid folder path: %w", err)
+	}
+
+	absFolder := folder
+	if !filepath.IsAbs(absFolder) {
+		absFolder = filepath.Join(absRoot, folder)
+	}
+	absFolder, err = filepath.Abs(absFolder)
+	if err != nil {
+		return "", fmt.Errorf("invalid folder path: %w", err)
+	}
+
+	// Security check: ensure folder is the QuickSave folder or inside it
+	if absFolder != absRoot && !strings.HasPrefix(absFolder, absRoot+string(filepath.Separator)) {
+		return "", fmt.Errorf("access denied: folder outside QuickSave folder")
+	}
+
+	return absFolder, nil
+}
+
+// OpenInEditor loads an image file into the editor
+// Security: validates path is within QuickSave folder
+func (a *App) OpenInEditor(imagePath string) (*screenshot.CaptureResult, error) {
+	absPath, err := a.resolveLibraryPath(imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read file
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Decode image
+	var img image.Image
+	ext := strings.ToLower(filepath.Ext(absPath))
+
+	switch ext {
+	case ".png":
+		img, err = png.Decode(bytes.NewReader(data))
+	case ".jpg", ".jpeg":
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	default:
+		img, _, err = image.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
 
 	// Re-encode as PNG for consistent handling in frontend
 	var buf bytes.Buffer
@@ -1017,6 +1956,8 @@ func (a *App) OpenInEditor(imagePath string) (*screenshot.CaptureResult, error)
 		return nil, fmt.Errorf("failed to encode image: %w", err)
 	}
 
+	a.setLastCapture(library.CaptureSource{Mode: library.CaptureModeFile})
+
 	return &screenshot.CaptureResult{
 		Width:  bounds.Dx(),
 		Height: bounds.Dy(),
@@ -1024,30 +1965,1380 @@ func (a *App) OpenInEditor(imagePath string) (*screenshot.CaptureResult, error)
 	}, nil
 }
 
-// DeleteScreenshot removes a screenshot file from disk
+// DeleteScreenshot moves a screenshot to the library trash, where it can be restored
 // Security: validates path is within QuickSave folder
 func (a *App) DeleteScreenshot(imagePath string) error {
-	// Validate path is within QuickSave folder (prevent directory traversal)
-	folder := a.config.QuickSave.Folder
-	if folder == "" {
-		homeDir, _ := os.UserHomeDir()
-		folder = filepath.Join(homeDir, "Pictures", "WinShot")
+	absPath, err := a.resolveLibraryPath(imagePath)
+	if err != nil {
+		return err
 	}
 
-	absPath, err := filepath.Abs(imagePath)
+	trash, err := a.libraryTrash()
 	if err != nil {
-		return fmt.Errorf("invalid path: %w", err)
+		return err
+	}
+
+	// Keep tags, favorites and OCR text so a restore brings them back
+	var entry *library.IndexEntry
+	if a.libraryIndex != nil {
+		if e, ok := a.libraryIndex.Get(absPath); ok {
+			entry = &e
+		}
+	}
+
+	if _, err := trash.Move(absPath, entry); err != nil {
+		return err
 	}
 
+	if a.libraryIndex != nil {
+		a.libraryIndex.Remove(absPath)
+	}
+	return nil
+}
+
+// ==================== Trash ====================
+
+// libraryTrash returns the trash of the current QuickSave folder
+func (a *App) libraryTrash() (*library.Trash, error) {
+	folder, err := a.quickSaveFolder()
+	if err != nil {
+		return nil, err
+	}
 	absFolder, err := filepath.Abs(folder)
 	if err != nil {
-		return fmt.Errorf("invalid folder path: %w", err)
+		return nil, fmt.Errorf("invalid folder path: %w", err)
 	}
 
-	// Security check: ensure file is within QuickSave folder
-	if !strings.HasPrefix(absPath, absFolder+string(filepath.Separator)) {
-		return fmt.Errorf("access denied: file outside QuickSave folder")
+	if a.trashBin == nil || a.trashBin.Root() != absFolder {
+		a.trashBin = library.NewTrash(absFolder)
+	}
+	return a.trashBin, nil
+}
+
+// purgeTrash permanently deletes trashed screenshots older than the configured retention
+func (a *App) purgeTrash() {
+	days := a.config.Library.TrashRetentionDays
+	if days <= 0 {
+		return
+	}
+	trash, err := a.libraryTrash()
+	if err != nil {
+		return
+	}
+	if _, err := trash.Purge(time.Duration(days) * 24 * time.Hour); err != nil {
+		println("Warning: failed to purge trash:", err.Error())
+	}
+}
+
+// GetTrashItems lists trashed screenshots, most recently deleted first, with thumbnails
+func (a *App) GetTrashItems() ([]library.TrashItem, error) {
+	a.purgeTrash()
+
+	trash, err := a.libraryTrash()
+	if err != nil {
+		return nil, err
+	}
+	items, err := trash.List()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := a.libraryScanOptions()
+	for i := range items {
+		if thumb, _, _, err := library.GenerateThumbnailCached(items[i].TrashPath, opts.ThumbnailWidth, opts.ThumbnailHeight, opts.Cache); err == nil {
+			items[i].Thumbnail = thumb
+		}
 	}
+	return items, nil
+}
+
+// RestoreFromTrash moves a trashed screenshot back to where it was deleted from.
+// Returns the restored path, which has a numbered suffix if the original name is taken.
+// Security: the restore target must be within QuickSave folder
+func (a *App) RestoreFromTrash(id string) (string, error) {
+	trash, err := a.libraryTrash()
+	if err != nil {
+		return "", err
+	}
+
+	items, err := trash.List()
+	if err != nil {
+		return "", err
+	}
+	for _, item := range items {
+		if item.ID != id {
+			continue
+		}
+		if _, err := a.resolveLibraryPath(item.OriginalPath); err != nil {
+			return "", err
+		}
+
+		item, restoredPath, err := trash.Restore(id)
+		if err != nil {
+			return "", err
+		}
+		if a.libraryIndex != nil && item.Entry != nil {
+			a.libraryIndex.Put(restoredPath, *item.Entry)
+		}
+		return restoredPath, nil
+	}
+
+	return "", fmt.Errorf("trash item not found: %s", id)
+}
+
+// DeleteFromTrash permanently deletes one trashed screenshot
+func (a *App) DeleteFromTrash(id string) error {
+	trash, err := a.libraryTrash()
+	if err != nil {
+		return err
+	}
+	return trash.Delete(id)
+}
+
+// EmptyTrash permanently deletes all trashed screenshots, returning how many were removed
+func (a *App) EmptyTrash() (int, error) {
+	trash, err := a.libraryTrash()
+	if err != nil {
+		return 0, err
+	}
+	return trash.Empty()
+}
+
+// ==================== Batch Operations ====================
+// Batch bindings validate every path, start a background job and return its ID.
+// Progress is emitted as "batch:progress" events (see batch.Progress); the final
+// event carries per-file results. An empty ID means the user cancelled a dialog.
+
+// resolveLibraryPaths validates a list of library paths
+// Security: every path must be within QuickSave folder
+func (a *App) resolveLibraryPaths(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files selected")
+	}
+
+	resolved := make([]string, 0, len(paths))
+	for _, path := range paths {
+		absPath, err := a.resolveLibraryPath(path)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, absPath)
+	}
+	return resolved, nil
+}
+
+// BatchExportZip asks for a destination and zips the given screenshots into it,
+// keeping their folder structure relative to the QuickSave folder
+func (a *App) BatchExportZip(paths []string) (string, error) {
+	files, err := a.resolveLibraryPaths(paths)
+	if err != nil {
+		return "", err
+	}
+
+	zipPath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Export Screenshots",
+		DefaultFilename: "winshot_" + time.Now().Format("2006-01-02") + ".zip",
+		Filters:         []runtime.FileFilter{{DisplayName: "Zip Archive", Pattern: "*.zip"}},
+	})
+	if err != nil || zipPath == "" {
+		return "", err
+	}
+	if filepath.Ext(zipPath) == "" {
+		zipPath += ".zip"
+	}
+
+	root, err := a.quickSaveFolder()
+	if err != nil {
+		return "", err
+	}
+	root, _ = filepath.Abs(root)
+
+	archive, err := library.CreateArchive(zipPath)
+	if err != nil {
+		return "", err
+	}
+
+	return a.batchJobs.Start("zip", files, func(ctx context.Context, file string) (string, error) {
+		return "", archive.Add(root, file)
+	}, func(completed bool) (string, error) {
+		if !completed {
+			archive.Abort()
+			return "", nil
+		}
+		return zipPath, archive.Close()
+	}), nil
+}
+
+// BatchConvert re-encodes and/or resizes screenshots into new files. opts.Folder,
+// if set, is relative to the QuickSave folder; by default files are written next to
+// their source. Each result's output is the new file's path.
+func (a *App) BatchConvert(paths []string, opts library.ConvertOptions) (string, error) {
+	files, err := a.resolveLibraryPaths(paths)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Folder != "" {
+		folder, err := a.resolveLibraryFolder(opts.Folder)
+		if err != nil {
+			return "", err
+		}
+		opts.Folder = folder
+	}
+
+	return a.batchJobs.Start("convert", files, func(ctx context.Context, file string) (string, error) {
+		return library.ConvertImage(file, opts)
+	}, nil), nil
+}
+
+// BatchUpload uploads screenshots to a configured provider ("r2" or "gdrive").
+// Each result's output is the public URL, which is also recorded in the library index.
+func (a *App) BatchUpload(paths []string, provider string) (string, error) {
+	files, err := a.resolveLibraryPaths(paths)
+	if err != nil {
+		return "", err
+	}
+
+	uploader, err := a.uploaderFor(provider)
+	if err != nil {
+		return "", err
+	}
+
+	return a.batchJobs.Start("upload", files, func(ctx context.Context, file string) (string, error) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		result, err := uploader.Upload(ctx, data, filepath.Base(file))
+		if err != nil {
+			return "", err
+		}
+		if !result.Success {
+			return "", fmt.Errorf("%s", result.Error)
+		}
+		if a.libraryIndex != nil {
+			a.libraryIndex.AddUploadURL(file, result.PublicURL)
+		}
+		return result.PublicURL, nil
+	}, nil), nil
+}
+
+// BatchCopy copies screenshots into destFolder (asking for one if empty).
+// Copies may go anywhere; existing files are never overwritten.
+func (a *App) BatchCopy(paths []string, destFolder string) (string, error) {
+	files, err := a.resolveLibraryPaths(paths)
+	if err != nil {
+		return "", err
+	}
+
+	if destFolder == "" {
+		destFolder, err = runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+			Title: "Copy Screenshots To",
+		})
+		if err != nil || destFolder == "" {
+			return "", err
+		}
+	}
+
+	return a.batchJobs.Start("copy", files, func(ctx context.Context, file string) (string, error) {
+		return library.CopyFile(file, destFolder)
+	}, nil), nil
+}
+
+// BatchDelete moves screenshots to the library trash
+func (a *App) BatchDelete(paths []string) (string, error) {
+	files, err := a.resolveLibraryPaths(paths)
+	if err != nil {
+		return "", err
+	}
+
+	return a.batchJobs.Start("delete", files, func(ctx context.Context, file string) (string, error) {
+		return "", a.DeleteScreenshot(file)
+	}, nil), nil
+}
+
+// CancelBatch stops a running batch job after its current file
+func (a *App) CancelBatch(jobID string) bool {
+	return a.batchJobs.Cancel(jobID)
+}
+
+// ==================== Retention ====================
+
+// runLibraryHousekeeping is the janitor task: purge the trash, then apply retention rules
+func (a *App) runLibraryHousekeeping() {
+	a.purgeTrash()
+	if err := a.applyRetention(); err != nil {
+		println("Warning: library retention failed:", err.Error())
+	}
+}
+
+// PreviewRetention returns what the configured retention rules would remove,
+// without removing anything (works even while the rules are disabled)
+func (a *App) PreviewRetention() (*library.RetentionPlan, error) {
+	plan, _, err := a.planRetention()
+	return plan, err
+}
+
+// planRetention rescans the QuickSave folder and applies the retention rules to it
+func (a *App) planRetention() (*library.RetentionPlan, string, error) {
+	if a.libraryIndex == nil {
+		return nil, "", fmt.Errorf("library index not available")
+	}
+
+	folder, err := a.quickSaveFolder()
+	if err != nil {
+		return nil, "", err
+	}
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid folder path: %w", err)
+	}
+
+	depth := a.config.QuickSave.ScanDepth
+	if _, err := a.libraryIndex.Rescan(absFolder, depth); err != nil {
+		return nil, "", err
+	}
+
+	rules := a.config.QuickSave.Retention
+	policy := library.RetentionPolicy{
+		MaxAge:       time.Duration(rules.MaxAgeDays) * 24 * time.Hour,
+		MaxCount:     rules.MaxCount,
+		MaxTotalSize: int64(rules.MaxTotalSizeMB) * 1024 * 1024,
+	}
+	plan := library.PlanRetention(a.libraryIndex.EntriesIn(absFolder, depth), policy, time.Now())
+	return &plan, absFolder, nil
+}
+
+// applyRetention removes the screenshots selected by the retention rules, either
+// through the trash or by zipping them into the archives data folder, and logs
+// each removal. Archives live outside the QuickSave folder and the trash counts
+// towards the size limit, so the limit actually frees space in the folder.
+func (a *App) applyRetention() error {
+	rules := a.config.QuickSave.Retention
+	if !rules.Enabled {
+		return nil
+	}
+
+	plan, folder, err := a.planRetention()
+	if err != nil || plan.IsEmpty() {
+		return err
+	}
+
+	var paths []string
+	for _, candidate := range plan.Remove {
+		paths = append(paths, candidate.Path)
+	}
+
+	var removed []library.RetentionCandidate
+	var log []string
+	if rules.Action == "archive" {
+		archiveDir, err := config.GetDataPath("archives")
+		if err != nil {
+			return err
+		}
+		zipPath := filepath.Join(archiveDir, "winshot_"+time.Now().Format("2006-01-02_15-04-05")+".zip")
+		if err := library.ArchiveFiles(zipPath, folder, paths); err != nil {
+			return err
+		}
+
+		for _, candidate := range plan.Remove {
+			if err := os.Remove(candidate.Path); err != nil {
+				log = append(log, fmt.Sprintf("failed to remove %s: %v", candidate.Path, err))
+				continue
+			}
+			a.libraryIndex.Remove(candidate.Path)
+			removed = append(removed, candidate)
+		}
+
+		switch {
+		case len(removed) == 0:
+			os.Remove(zipPath)
+			log = append(log, "archive discarded, no screenshot could be removed")
+		case len(removed) < len(plan.Remove):
+			log = append(log, fmt.Sprintf("archived %d of %d screenshots to %s (partial: the rest stay in the library)", len(removed), len(plan.Remove), zipPath))
+		default:
+			log = append(log, "archived to "+zipPath)
+		}
+	} else {
+		trash, err := a.libraryTrash()
+		if err != nil {
+			return err
+		}
+		for _, candidate := range plan.Remove {
+			entry, _ := a.libraryIndex.Get(candidate.Path)
+			if _, err := trash.Move(candidate.Path, &entry); err != nil {
+				log = append(log, fmt.Sprintf("failed to trash %s: %v", candidate.Path, err))
+				continue
+			}
+			a.libraryIndex.Remove(candidate.Path)
+			removed = append(removed, candidate)
+		}
+
+		// The trash is inside the QuickSave folder; purge its oldest items
+		// with whatever the screenshots left of the size limit
+		if maxSize := int64(rules.MaxTotalSizeMB) * 1024 * 1024; maxSize > 0 {
+			kept := plan.TotalSize
+			for _, candidate := range removed {
+				kept -= candidate.Size
+			}
+			if n, err := trash.PurgeToSize(max(maxSize-kept, 0)); err != nil {
+				log = append(log, fmt.Sprintf("failed to purge trash: %v", err))
+			} else if n > 0 {
+				log = append(log, fmt.Sprintf("purged %d item(s) from the trash to stay within %d MB", n, rules.MaxTotalSizeMB))
+			}
+		}
+	}
+
+	action := "trashed"
+	if rules.Action == "archive" {
+		action = "archived"
+	}
+	for _, candidate := range removed {
+		log = append(log, fmt.Sprintf("%s %s (%s, %d bytes)", action, candidate.Path, candidate.Reason, candidate.Size))
+	}
+	a.writeRetentionLog(log)
+
+	println("Library retention:", action, len(removed), "screenshot(s)")
+	return nil
+}
+
+// writeRetentionLog appends timestamped lines to retention.log in the config folder
+func (a *App) writeRetentionLog(lines []string) {
+	logPath, err := config.GetDataPath("retention.log")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	stamp := time.Now().Format(time.RFC3339)
+	for _, line := range lines {
+		fmt.Fprintf(f, "%s %s\n", stamp, line)
+	}
+}
+
+// ==================== OCR ====================
+
+// IsOCRAvailable checks if the OCR engine (Tesseract) is installed
+func (a *App) IsOCRAvailable() bool {
+	return a.ocrEngine != nil && a.ocrEngine.IsAvailable()
+}
+
+// OCRImage recognizes text in a base64 encoded image and copies it to the clipboard
+func (a *App) OCRImage(imageData string) (*ocr.Result, error) {
+	img, err := decodeBase64Image(imageData)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.ocrEngine.Recognize(context.Background(), img)
+	if err != nil {
+		return nil, err
+	}
+
+	a.copyOCRText(result)
+	return result, nil
+}
+
+// OCRRegion recognizes text in a region of a base64 encoded image and copies it to the clipboard
+func (a *App) OCRRegion(imageData string, x, y, width, height int) (*ocr.Result, error) {
+	img, err := decodeBase64Image(imageData)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := img.Bounds().Min
+	region := image.Rect(origin.X+x, origin.Y+y, origin.X+x+width, origin.Y+y+height)
+	result, err := ocr.RecognizeRegion(context.Background(), a.ocrEngine, img, region)
+	if err != nil {
+		return nil, err
+	}
+
+	a.copyOCRText(result)
+	return result, nil
+}
+
+// OCRLibraryImage recognizes text in a library screenshot, stores it in the library index
+// and copies it to the clipboard
+// Security: validates path is within QuickSave folder
+func (a *App) OCRLibraryImage(imagePath string) (*ocr.Result, error) {
+	absPath, err := a.resolveLibraryPath(imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	result, err := a.ocrEngine.Recognize(context.Background(), img)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.libraryIndex != nil {
+		if err := a.libraryIndex.SetOCRText(absPath, result.Text); err != nil {
+			return result, fmt.Errorf("failed to save OCR text: %w", err)
+		}
+	}
+
+	a.copyOCRText(result)
+	return result, nil
+}
+
+// copyOCRText places recognized text on the clipboard (skipped when nothing was found)
+func (a *App) copyOCRText(result *ocr.Result) {
+	if result == nil || result.Text == "" {
+		return
+	}
+	runtime.ClipboardSetText(a.ctx, result.Text)
+}
+
+// decodeBase64Image decodes a base64 encoded PNG/JPEG image
+func decodeBase64Image(imageData string) (image.Image, error) {
+	data, err := base64.StdEncoding.DecodeString(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image data: %w", err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// ==================== Notifications ====================
+
+// showNotification shows n unless notifications are turned off in settings
+func (a *App) showNotification(n notify.Notification) {
+	if a.notifier == nil || !a.config.Startup.ShowNotification {
+		return
+	}
+	if _, err := a.notifier.Show(n); err != nil {
+		println("Warning: failed to show notification:", err.Error())
+	}
+}
+
+// notifyUpload shows the outcome of uploading the image data from the editor
+func (a *App) notifyUpload(data []byte, result *upload.UploadResult, err error) {
+	go func() {
+		switch {
+		case err != nil:
+			a.showNotification(notify.Failed("Upload failed", err))
+		case !result.Success:
+			a.showNotification(notify.Failed("Upload failed", errors.New(result.Error)))
+		default:
+			img, _, _ := image.Decode(bytes.NewReader(data))
+			a.showNotification(notify.Uploaded("", result.PublicURL, img))
+		}
+	}()
+}
+
+// onNotificationAction runs an action clicked on a notification
+func (a *App) onNotificationAction(n notify.Notification, action string) {
+	var err error
+	switch action {
+	case notify.ActionOpen:
+		a.ShowWindow()
+		runtime.EventsEmit(a.ctx, "tray:open", n.Path)
+	case notify.ActionCopyURL:
+		err = runtime.ClipboardSetText(a.ctx, n.URL)
+	case notify.ActionShowInFolder:
+		err = showInFolder(n.Path)
+	}
+	if err != nil {
+		println("Warning: notification action", action, "failed:", err.Error())
+	}
+}
+
+// showInFolder opens File Explorer with the file at path selected
+func showInFolder(path string) error {
+	return exec.Command("explorer.exe", "/select,", path).Start()
+}
+
+// ==================== Compare ====================
+
+// CompareResult holds the metrics and renderings of an image comparison.
+// Renderings are base64 encoded PNGs.
+type CompareResult struct {
+	Diff       imagediff.Result `json:"diff"`
+	Overlay    string           `json:"overlay"`    // Second image faded with changes highlighted
+	SideBySide string           `json:"sideBySide"` // Both images next to each other
+	OnionSkin  string           `json:"onionSkin"`  // Second image blended over the first
+}
+
+// CompareImages compares two library screenshots (before/after) and renders the differences
+// Security: validates both paths are within QuickSave folder
+func (a *App) CompareImages(pathA, pathB string, opts imagediff.Options) (*CompareResult, error) {
+	imgA, err := a.loadLibraryImageFile(pathA)
+	if err != nil {
+		return nil, err
+	}
+	imgB, err := a.loadLibraryImageFile(pathB)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := imagediff.Compare(imgA, imgB, opts)
+	result := &CompareResult{Diff: diff.Result}
+
+	renderings := []struct {
+		img image.Image
+		dst *string
+	}{
+		{diff.Overlay(), &result.Overlay},
+		{diff.SideBySide(16), &result.SideBySide},
+		{diff.OnionSkin(), &result.OnionSkin},
+	}
+	for _, r := range renderings {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, r.img); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
+		*r.dst = base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+	return result, nil
+}
+
+// loadLibraryImageFile validates and decodes a library screenshot
+func (a *App) loadLibraryImageFile(imagePath string) (image.Image, error) {
+	absPath, err := a.resolveLibraryPath(imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// ==================== Clipboard History ====================
+
+// applyClipboardWatch starts or stops the clipboard watcher to match the config
+func (a *App) applyClipboardWatch() {
+	enabled := a.config.Library.WatchClipboard
+	if a.trayIcon != nil {
+		a.trayIcon.SetClipboardWatch(enabled)
+	}
+
+	if !enabled {
+		if a.clipboardWatch != nil {
+			a.clipboardWatch.Stop()
+		}
+		return
+	}
+
+	if a.clipboardWatch == nil {
+		a.clipboardWatch = screenshot.NewClipboardMonitor(a.importClipboardImage)
+	}
+	if err := a.clipboardWatch.Start(); err != nil {
+		println("Warning: failed to start clipboard watcher:", err.Error())
+		return
+	}
+	if a.libraryIndex != nil {
+		if err := a.libraryIndex.EnsureCollection(library.ClipboardCollection()); err != nil {
+			println("Warning: failed to create clipboard collection:", err.Error())
+		}
+	}
+}
+
+// SetClipboardWatch turns the clipboard history watcher on or off and saves the setting.
+// While on, every image copied in another app is saved to the Clipboard folder.
+func (a *App) SetClipboardWatch(enabled bool) error {
+	a.config.Library.WatchClipboard = enabled
+	a.applyClipboardWatch()
+	runtime.EventsEmit(a.ctx, "clipboard:watch", enabled)
+	return a.config.Save()
+}
+
+// importClipboardImage saves the image on the clipboard to the Clipboard folder,
+// unless the folder already holds the same image, then trims the folder to its cap.
+// Called by the clipboard watcher.
+func (a *App) importClipboardImage() {
+	if a.libraryIndex == nil {
+		return
+	}
+	folder, err := a.quickSaveFolder()
+	if err != nil {
+		return
+	}
+	clipFolder, err := filepath.Abs(filepath.Join(folder, library.ClipboardFolderName))
+	if err != nil {
+		return
+	}
+
+	result, err := screenshot.GetClipboardImage()
+	if err != nil || result.MimeType != "" {
+		return // Another app may still hold the clipboard; only PNG results are imported
+	}
+	data, err := base64.StdEncoding.DecodeString(result.Data)
+	if err != nil {
+		return
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+
+	// Exact duplicates only; the perceptual hash matches small edits too
+	hash := library.ContentHash(img)
+	if _, ok := a.libraryIndex.FindContentHashIn(clipFolder, hash); ok {
+		return
+	}
+
+	if err := os.MkdirAll(clipFolder, 0755); err != nil {
+		println("Warning: failed to create clipboard folder:", err.Error())
+		return
+	}
+	now := time.Now()
+	if !a.config.Export.StripMetadata {
+		if out, err := metadata.Embed(data, metadata.Metadata{
+			CaptureMode: library.CaptureModeClipboard,
+			CapturedAt:  now,
+			AppVersion:  Version,
+			Author:      a.config.Export.MetadataAuthor,
+		}); err == nil {
+			data = out
+		}
+	}
+
+	filePath := filepath.Join(clipFolder, library.ClipboardFilename(now))
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		println("Warning: failed to save clipboard image:", err.Error())
+		return
+	}
+	a.libraryIndex.Record(filePath, library.CaptureSource{Mode: library.CaptureModeClipboard})
+	if err := a.libraryIndex.SetContentHash(filePath, hash); err != nil {
+		println("Warning: failed to store image hash:", err.Error())
+	}
+
+	a.trimClipboardHistory(clipFolder)
+	runtime.EventsEmit(a.ctx, "clipboard:imported", filePath)
+}
+
+// trimClipboardHistory moves the oldest clipboard imports to the trash once the
+// folder holds more than the configured number. Favorites and tagged images are kept.
+func (a *App) trimClipboardHistory(clipFolder string) {
+	maxItems := a.config.Library.ClipboardMaxItems
+	if maxItems <= 0 {
+		maxItems = library.DefaultClipboardMaxItems
+	}
+
+	if _, err := a.libraryIndex.Rescan(clipFolder, 0); err != nil {
+		return
+	}
+	plan := library.PlanRetention(a.libraryIndex.EntriesIn(clipFolder, 0), library.RetentionPolicy{MaxCount: maxItems}, time.Now())
+	if plan.IsEmpty() {
+		return
+	}
+
+	trash, err := a.libraryTrash()
+	if err != nil {
+		return
+	}
+	for _, candidate := range plan.Remove {
+		entry, _ := a.libraryIndex.Get(candidate.Path)
+		if _, err := trash.Move(candidate.Path, &entry); err != nil {
+			println("Warning: failed to trash clipboard image:", err.Error())
+			continue
+		}
+		a.libraryIndex.Remove(candidate.Path)
+	}
+}
+
+// ==================== Pins ====================
+
+// pins returns the pin manager, starting it on first use
+func (a *App) pins() (*overlay.PinManager, error) {
+	a.pinMu.Lock()
+	defer a.pinMu.Unlock()
+	if a.pinManager == nil {
+		pm := overlay.NewPinManager()
+		pm.SetOnClosed(func(id int) {
+			runtime.EventsEmit(a.ctx, "pin:closed", id)
+		})
+		if err := pm.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start pin manager: %w", err)
+		}
+		a.pinManager = pm
+	}
+	return a.pinManager, nil
+}
+
+// startedPins returns the pin manager, or nil if nothing was pinned yet
+func (a *App) startedPins() *overlay.PinManager {
+	a.pinMu.Lock()
+	defer a.pinMu.Unlock()
+	return a.pinManager
+}
+
+// PinImage shows a base64 encoded image as an always-on-top window centred on the
+// mouse cursor and returns the pin ID. Drag to move, scroll to zoom, Ctrl+scroll
+// to change opacity, double-click for actual size, Esc to close.
+func (a *App) PinImage(imageData string) (int, error) {
+	img, err := decodeBase64Image(imageData)
+	if err != nil {
+		return 0, err
+	}
+	pm, err := a.pins()
+	if err != nil {
+		return 0, err
+	}
+	return pm.PinAtCursor(imageToRGBA(img))
+}
+
+// PinImageAt pins a base64 encoded image with its top-left corner at (x, y) in
+// virtual screen coordinates, e.g. over the region it was captured from
+func (a *App) PinImageAt(imageData string, x, y int) (int, error) {
+	img, err := decodeBase64Image(imageData)
+	if err != nil {
+		return 0, err
+	}
+	pm, err := a.pins()
+	if err != nil {
+		return 0, err
+	}
+	return pm.Pin(imageToRGBA(img), x, y)
+}
+
+// PinLibraryImage pins a screenshot from the library
+func (a *App) PinLibraryImage(imagePath string) (int, error) {
+	img, err := a.loadLibraryImageFile(imagePath)
+	if err != nil {
+		return 0, err
+	}
+	pm, err := a.pins()
+	if err != nil {
+		return 0, err
+	}
+	return pm.PinAtCursor(imageToRGBA(img))
+}
+
+// GetPins returns the open pins
+func (a *App) GetPins() []overlay.PinInfo {
+	pm := a.startedPins()
+	if pm == nil {
+		return []overlay.PinInfo{}
+	}
+	return pm.List()
+}
+
+// ClosePin closes one pin
+func (a *App) ClosePin(id int) error {
+	pm := a.startedPins()
+	if pm == nil {
+		return overlay.ErrPinNotFound
+	}
+	return pm.Close(id)
+}
+
+// CloseAllPins closes every pin
+func (a *App) CloseAllPins() {
+	if pm := a.startedPins(); pm != nil {
+		pm.CloseAll()
+	}
+}
+
+// SetPinZoom sets a pin's zoom factor (1 = actual size)
+func (a *App) SetPinZoom(id int, zoom float64) error {
+	pm := a.startedPins()
+	if pm == nil {
+		return overlay.ErrPinNotFound
+	}
+	return pm.SetZoom(id, zoom)
+}
+
+// SetPinOpacity sets a pin's opacity (0.1 to 1)
+func (a *App) SetPinOpacity(id int, opacity float64) error {
+	pm := a.startedPins()
+	if pm == nil {
+		return overlay.ErrPinNotFound
+	}
+	return pm.SetOpacity(id, opacity)
+}
+
+// SetPinClickThrough lets mouse clicks pass through a pin to the windows below it.
+// A click-through pin can only be changed or closed from WinShot.
+func (a *App) SetPinClickThrough(id int, enabled bool) error {
+	pm := a.startedPins()
+	if pm == nil {
+		return overlay.ErrPinNotFound
+	}
+	return pm.SetClickThrough(id, enabled)
+}
+
+// ==================== Color Picker ====================
+
+// PickedColor is a color picked from the screen in each notation
+type PickedColor struct {
+	Hex    string `json:"hex"`    // #RRGGBB
+	RGB    string `json:"rgb"`    // rgb(r, g, b)
+	HSL    string `json:"hsl"`    // hsl(h, s%, l%)
+	Copied string `json:"copied"` // The notation copied to the clipboard
+	X      int    `json:"x"`      // Screen position, physical pixels
+	Y      int    `json:"y"`
+}
+
+// PickColor hides the window, lets the user click a pixel on a frozen
+// screenshot of the screen and copies its color to the clipboard in the
+// configured notation. Returns nil if picking was cancelled.
+func (a *App) PickColor() (*PickedColor, error) {
+	var picked *PickedColor
+	var err error
+	a.whileHidden(func() {
+		picked, err = a.pickColor()
+	})
+	return picked, err
+}
+
+// whileHidden runs fn with the main window hidden, so that it stays out of a
+// screenshot fn takes, and shows the window again if it was visible
+func (a *App) whileHidden(fn func()) {
+	wasHidden := a.isWindowHidden
+	if !wasHidden {
+		runtime.WindowHide(a.ctx)
+		a.isWindowHidden = true
+		// Wait for window to fully hide (250ms for DWM compositor)
+		time.Sleep(250 * time.Millisecond)
+	}
+
+	fn()
+
+	if !wasHidden {
+		runtime.WindowShow(a.ctx)
+		a.isWindowHidden = false
+	}
+}
+
+// pickColorHeadless picks a color for a hotkey, confirming the copy in the tray
+func (a *App) pickColorHeadless() {
+	picked, err := a.PickColor()
+	if a.trayIcon == nil {
+		return
+	}
+	if err != nil {
+		println("Warning: color pick failed:", err.Error())
+		a.trayIcon.ShowBalloon("Color pick failed", err.Error(), true)
+	} else if picked != nil {
+		a.trayIcon.ShowBalloon("Color copied", picked.Copied, false)
+	}
+}
+
+// pickColor shows the overlay in color pick mode, then copies the picked color,
+// records it in the history and emits "color:picked". Returns nil if cancelled.
+func (a *App) pickColor() (*PickedColor, error) {
+	sel, err := a.startOverlay(a.overlayManager.ShowPicker)
+	if err != nil {
+		return nil, err
+	}
+	result := <-sel.resultCh
+	if result.Cancelled {
+		return nil, nil
+	}
+
+	c := colors.RGB{R: result.Color.R, G: result.Color.G, B: result.Color.B}
+	format := a.config.ColorPicker.Format
+	if !colors.ValidFormat(format) {
+		format = colors.FormatHex
+	}
+	picked := &PickedColor{
+		Hex:    c.Hex(),
+		RGB:    c.String(),
+		HSL:    c.HSL().String(),
+		Copied: c.Format(format),
+		X:      sel.screenX + int(float64(result.X)*sel.scaleRatio),
+		Y:      sel.screenY + int(float64(result.Y)*sel.scaleRatio),
+	}
+
+	if err := runtime.ClipboardSetText(a.ctx, picked.Copied); err != nil {
+		return picked, fmt.Errorf("failed to copy color: %w", err)
+	}
+
+	a.config.ColorPicker.AddColor(picked.Hex)
+	if err := a.config.Save(); err != nil {
+		println("Warning: failed to save color history:", err.Error())
+	}
+	runtime.EventsEmit(a.ctx, "color:picked", picked)
+	return picked, nil
+}
+
+// GetColorHistory returns the recently picked colors as "#RRGGBB", newest first
+func (a *App) GetColorHistory() []string {
+	if a.config == nil || a.config.ColorPicker.History == nil {
+		return []string{}
+	}
+	return a.config.ColorPicker.History
+}
+
+// ClearColorHistory forgets the recently picked colors
+func (a *App) ClearColorHistory() error {
+	a.config.ColorPicker.History = nil
+	return a.config.Save()
+}
+
+// ==================== Measure ====================
+
+// Measurement is a distance measured on the screen, in physical pixels
+type Measurement struct {
+	Width    int     `json:"width"`
+	Height   int     `json:"height"`
+	Distance float64 `json:"distance"` // Length of a dragged line; 0 for the gap between edges
+	Angle    float64 `json:"angle"`    // Degrees counterclockwise from pointing right
+	Text     string  `json:"text"`     // As copied to the clipboard, e.g. "120 x 48 px"
+}
+
+// MeasureScreen hides the window and shows a ruler over a frozen screenshot of
+// the screen: dragging measures a line and pointing finds the gap between the
+// edges around the cursor. The measurement confirmed with Enter is copied to
+// the clipboard. Returns nil if measuring was cancelled.
+func (a *App) MeasureScreen() (*Measurement, error) {
+	var measured *Measurement
+	var err error
+	a.whileHidden(func() {
+		measured, err = a.measureScreen()
+	})
+	return measured, err
+}
+
+func (a *App) measureScreen() (*Measurement, error) {
+	sel, err := a.startOverlay(a.overlayManager.ShowMeasure)
+	if err != nil {
+		return nil, err
+	}
+	result := <-sel.resultCh
+	if result.Cancelled {
+		return nil, nil
+	}
+
+	m := result.Measurement
+	measured := &Measurement{
+		Width:    m.Width,
+		Height:   m.Height,
+		Distance: m.Distance,
+		Angle:    m.Angle,
+		Text:     m.String(),
+	}
+	if err := runtime.ClipboardSetText(a.ctx, measured.Text); err != nil {
+		return measured, fmt.Errorf("failed to copy measurement: %w", err)
+	}
+	return measured, nil
+}
+
+// ==================== Workflows ====================
+
+// runHeadlessWorkflow captures with the binding's mode and runs its steps in the
+// background. The main window only appears if a step opens the editor.
+func (a *App) runHeadlessWorkflow(binding config.HotkeyBinding) {
+	if !a.workflowMu.TryLock() {
+		println("Warning: hotkey ignored, a capture workflow is already running")
+		return
+	}
+	defer a.workflowMu.Unlock()
+
+	// Hidden, the main window stays out of the capture and isn't the foreground window
+	var img image.Image
+	var err error
+	a.whileHidden(func() {
+		img, err = a.headlessCapture(binding.Capture)
+	})
+	if err != nil {
+		println("Warning: capture failed:", err.Error())
+		a.showNotification(notify.Failed("Capture failed", err))
+		return
+	}
+	if img == nil {
+		return // Region selection cancelled
+	}
+
+	steps := workflowSteps(binding.Steps)
+	a.finishWorkflow(steps, a.newWorkflowRunner().Run(context.Background(), img, steps))
+}
+
+// headlessCapture captures the cursor's display, a selected region or the
+// foreground window without showing the main window; callers hide it first.
+// A cancelled region selection returns a nil image.
+func (a *App) headlessCapture(mode string) (image.Image, error) {
+	switch mode {
+	case config.CaptureRegion:
+		sel, err := a.startRegionSelection()
+		if err != nil {
+			return nil, err
+		}
+		return a.waitRegionSelection(sel), nil
+	case config.CaptureWindow:
+		hwnd := winEnum.GetForegroundWindow()
+		if hwnd == 0 {
+			return nil, errors.New("no active window")
+		}
+		a.setLastCapture(windowCaptureSource(hwnd))
+		result, err := screenshot.CaptureWindowByCoords(hwnd)
+		if err != nil {
+			return nil, err
+		}
+		return decodeBase64Image(result.Data)
+	default:
+		result, err := a.CaptureFullscreen()
+		if err != nil {
+			return nil, err
+		}
+		return decodeBase64Image(result.Data)
+	}
+}
+
+// RunWorkflow runs the steps handed off under token on a base64 encoded image,
+// used by the editor for the steps that follow it in a hotkey's workflow
+func (a *App) RunWorkflow(imageData string, token string) (*workflow.Result, error) {
+	steps, ok := a.takeWorkflow(token)
+	if !ok {
+		return nil, errors.New("unknown or expired workflow")
+	}
+	img, err := decodeBase64Image(imageData)
+	if err != nil {
+		return nil, err
+	}
+	result := a.newWorkflowRunner().Run(context.Background(), img, steps)
+	a.finishWorkflow(steps, result)
+	return &result, nil
+}
+
+// maxWorkflowHandoffs is how many handed-off workflows wait for the editor at
+// once; older ones belong to captures that were cancelled
+const maxWorkflowHandoffs = 8
+
+// handOffWorkflow keeps steps until the editor runs them with RunWorkflow and
+// returns the token to run them by. The steps stay in the backend, so the
+// frontend can only run workflows the user configured. No steps hand off as "".
+func (a *App) handOffWorkflow(steps []workflow.Step) string {
+	if len(steps) == 0 {
+		return ""
+	}
+	token := rand.Text()
+
+	a.handoffMu.Lock()
+	defer a.handoffMu.Unlock()
+	if a.handoffs == nil {
+		a.handoffs = make(map[string][]workflow.Step)
+	}
+	if len(a.handoffOrder) == maxWorkflowHandoffs {
+		delete(a.handoffs, a.handoffOrder[0])
+		a.handoffOrder = a.handoffOrder[1:]
+	}
+	a.handoffs[token] = steps
+	a.handoffOrder = append(a.handoffOrder, token)
+	return token
+}
+
+// takeWorkflow returns and forgets the steps handed off under token
+func (a *App) takeWorkflow(token string) ([]workflow.Step, bool) {
+	a.handoffMu.Lock()
+	defer a.handoffMu.Unlock()
+	steps, ok := a.handoffs[token]
+	if !ok {
+		return nil, false
+	}
+	delete(a.handoffs, token)
+	for i, t := range a.handoffOrder {
+		if t == token {
+			a.handoffOrder = append(a.handoffOrder[:i], a.handoffOrder[i+1:]...)
+			break
+		}
+	}
+	return steps, true
+}
+
+// finishWorkflow logs failed steps, reports the result with the "workflow:done"
+// event and, if steps failed without a notify step to say so, shows a notification
+func (a *App) finishWorkflow(steps []workflow.Step, result workflow.Result) {
+	for _, step := range result.Steps {
+		if step.Error != "" {
+			println("Warning: workflow step", step.Type, "failed:", step.Error)
+		}
+	}
+	runtime.EventsEmit(a.ctx, "workflow:done", result)
+
+	if result.Failures == 0 {
+		return
+	}
+	for _, step := range steps {
+		if step.Type == workflow.StepNotify {
+			return
+		}
+	}
+	a.showNotification(workflowNotification(nil, result))
+}
+
+// workflowNotification returns the notification for the results of a workflow
+// so far, with the actions of a saved or uploaded capture
+func workflowNotification(img image.Image, result workflow.Result) notify.Notification {
+	title, message := result.Summary()
+	switch {
+	case result.Failures > 0:
+		return notify.Notification{Title: title, Message: message, Failed: true}
+	case result.URL != "":
+		return notify.Uploaded(result.Path, result.URL, img)
+	case result.Path != "":
+		return notify.Saved(result.Path, img)
+	}
+	return notify.Notification{Title: title, Message: message, Thumbnail: img}
+}
+
+// newWorkflowRunner returns a workflow runner acting through the app's services
+func (a *App) newWorkflowRunner() *workflow.Runner {
+	return &workflow.Runner{
+		Style: beautifyStyle(a.config.Editor),
+		Save:  a.workflowSave,
+		CopyImage: func(img image.Image, path string) error {
+			opts := screenshot.ClipboardImageOptions{FilePath: path, HTML: true, Alt: "Screenshot"}
+			if path != "" {
+				opts.Alt = filepath.Base(path)
+			}
+			return screenshot.SetClipboardImage(img, opts)
+		},
+		Upload: a.workflowUpload,
+		CopyText: func(text string) error {
+			return runtime.ClipboardSetText(a.ctx, text)
+		},
+		Notify: func(img image.Image, result workflow.Result) error {
+			a.showNotification(workflowNotification(img, result))
+			return nil
+		},
+		OpenEditor: a.openWorkflowEditor,
+	}
+}
+
+// workflowSave saves to the QuickSave folder in the default export format
+func (a *App) workflowSave(img image.Image) (string, error) {
+	format := strings.ToLower(a.config.Export.DefaultFormat)
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" || format == "jpg" {
+		format = "jpeg"
+		quality := a.config.Export.JpegQuality
+		if quality <= 0 || quality > 100 {
+			quality = 95
+		}
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	} else {
+		format = "png"
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	result := a.quickSave(base64.StdEncoding.EncodeToString(buf.Bytes()), format)
+	if !result.Success {
+		return "", errors.New(result.Error)
+	}
+	return result.FilePath, nil
+}
+
+// workflowUpload uploads the saved file if there is one, otherwise img as PNG,
+// and returns the public URL
+func (a *App) workflowUpload(img image.Image, provider, path string) (string, error) {
+	uploader, err := a.uploaderFor(provider)
+	if err != nil {
+		return "", err
+	}
+
+	var data []byte
+	filename := "winshot_" + time.Now().Format("2006-01-02_15-04-05") + ".png"
+	if path != "" {
+		if data, err = os.ReadFile(path); err != nil {
+			return "", err
+		}
+		filename = filepath.Base(path)
+	} else {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return "", fmt.Errorf("failed to encode image: %w", err)
+		}
+		data = buf.Bytes()
+	}
+
+	result, err := uploader.Upload(context.Background(), data, filename)
+	if err != nil {
+		return "", err
+	}
+	if !result.Success {
+		return "", fmt.Errorf("%s", result.Error)
+	}
+	if path != "" && a.libraryIndex != nil {
+		a.libraryIndex.AddUploadURL(path, result.PublicURL)
+	}
+	return result.PublicURL, nil
+}
+
+// openWorkflowEditor shows the capture in the editor, which runs the remaining
+// steps through RunWorkflow with their handoff token
+func (a *App) openWorkflowEditor(img image.Image, remaining []workflow.Step) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	runtime.EventsEmit(a.ctx, "workflow:editor", map[string]interface{}{
+		"capture": &screenshot.CaptureResult{
+			Width:  bounds.Dx(),
+			Height: bounds.Dy(),
+			Data:   base64.StdEncoding.EncodeToString(buf.Bytes()),
+		},
+		"workflow": a.handOffWorkflow(remaining),
+	})
+	a.ShowWindow()
+	return nil
+}
 
-	return os.Remove(absPath)
+// workflowSteps converts configured steps for the workflow runner
+func workflowSteps(steps []config.WorkflowStep) []workflow.Step {
+	converted := make([]workflow.Step, len(steps))
+	for i, step := range steps {
+		converted[i] = workflow.Step{Type: step.Type, Provider: step.Provider, Command: step.Command, Args: step.Args}
+	}
+	return converted
+}
+
+// beautifyStyle returns the editor's saved look as a beautify style. With the
+// background hidden the editor exports the bare screenshot.
+func beautifyStyle(editor config.EditorConfig) workflow.Style {
+	style := workflow.Style{
+		Padding:         editor.Padding,
+		CornerRadius:    editor.CornerRadius,
+		ShadowSize:      editor.ShadowSize,
+		Background:      editor.BackgroundColor,
+		AutoBackground:  editor.AutoBackground,
+		OutputRatio:     editor.OutputRatio,
+		Inset:           editor.Inset,
+		InsetBackground: editor.InsetBackgroundColor,
+		BorderEnabled:   editor.BorderEnabled,
+		BorderWeight:    editor.BorderWeight,
+		BorderColor:     editor.BorderColor,
+		BorderOpacity:   editor.BorderOpacity,
+		BorderType:      editor.BorderType,
+	}
+	if !editor.ShowBackground {
+		style.Padding, style.CornerRadius, style.OutputRatio = 0, 0, "auto"
+	}
+	return style
 }
@@ -28,6 +28,8 @@ import {
   SaveEditorConfig,
   OpenImage,
   GetClipboardImage,
+  CopyImageToClipboard,
+  PinImage,
   CheckForUpdate,
   GetSkippedVersion,
   IsR2Configured,
@@ -35,10 +37,14 @@ import {
   UploadToR2,
   UploadToGDrive,
   OpenInEditor,
+  RunWorkflow,
+  PickColor,
+  MeasureScreen,
 } from '../wailsjs/go/main/App';
 import { updater } from '../wailsjs/go/models';
 import { EventsOn, EventsOff, WindowGetSize } from '../wailsjs/runtime/runtime';
 import { extractDominantEdgeColor } from './utils/extract-edge-color';
+import { rasterizeSvg } from './utils/rasterize-svg';
 
 // Default editor settings (used before Go config loads)
 const DEFAULT_EDITOR_SETTINGS = {
@@ -219,6 +225,12 @@ function App() {
   // Auto-copy state: tracks when a fresh capture needs auto-copy after canvas renders
   const [pendingAutoCopy, setPendingAutoCopy] = useState(false);
 
+  // Hotkey workflow state: handoff token of the steps that follow the editor in
+  // the workflow of the hotkey that started the current capture, moved to
+  // pendingWorkflow once the capture lands and run after the canvas renders
+  const hotkeyWorkflowRef = useRef<string | null>(null);
+  const [pendingWorkflow, setPendingWorkflow] = useState<string | null>(null);
+
   // Settings modal state
   const [showSettings, setShowSettings] = useState(false);
 
@@ -482,6 +494,8 @@ function App() {
 
       // Trigger auto-copy of styled canvas (handled by useEffect)
       setPendingAutoCopy(true);
+      setPendingWorkflow(hotkeyWorkflowRef.current);
+      hotkeyWorkflowRef.current = null;
     } catch (error) {
       console.error('Capture failed:', error);
       setStatusMessage('Capture failed');
@@ -511,6 +525,8 @@ function App() {
 
       // Trigger auto-copy of styled canvas (handled by useEffect)
       setPendingAutoCopy(true);
+      setPendingWorkflow(hotkeyWorkflowRef.current);
+      hotkeyWorkflowRef.current = null;
     } catch (error) {
       console.error('Window capture failed:', error);
       setStatusMessage('Capture failed');
@@ -540,6 +556,8 @@ function App() {
 
     // Trigger auto-copy of styled canvas (handled by useEffect)
     setPendingAutoCopy(true);
+    setPendingWorkflow(hotkeyWorkflowRef.current);
+    hotkeyWorkflowRef.current = null;
   }, [resetAnnotations]);
 
   const handleClear = useCallback(() => {
@@ -606,7 +624,12 @@ function App() {
         return;
       }
 
-      setScreenshot(result as CaptureResult);
+      // SVG markup is passed through unrendered; rasterize it for the editor
+      const image = result.mimeType === 'image/svg+xml'
+        ? await rasterizeSvg(result as CaptureResult)
+        : result as CaptureResult;
+
+      setScreenshot(image);
       // Reset annotations and crop state for clipboard image (clears history)
       resetAnnotations([]);
       setSelectedAnnotationId(null);
@@ -629,6 +652,32 @@ function App() {
     }
   }, [resetAnnotations]);
 
+  const handleColorPick = useCallback(async () => {
+    try {
+      const picked = await PickColor();
+      if (!picked) return; // Cancelled
+      setStatusMessage(`Copied ${picked.copied}`);
+      setTimeout(() => setStatusMessage(undefined), 2000);
+    } catch (error) {
+      console.error('Color pick failed:', error);
+      setStatusMessage('Failed to pick color');
+      setTimeout(() => setStatusMessage(undefined), 3000);
+    }
+  }, []);
+
+  const handleMeasure = useCallback(async () => {
+    try {
+      const measured = await MeasureScreen();
+      if (!measured) return; // Cancelled
+      setStatusMessage(`Copied ${measured.text}`);
+      setTimeout(() => setStatusMessage(undefined), 2000);
+    } catch (error) {
+      console.error('Measure failed:', error);
+      setStatusMessage('Failed to measure');
+      setTimeout(() => setStatusMessage(undefined), 3000);
+    }
+  }, []);
+
   // Drag & drop state
   const [isDragging, setIsDragging] = useState(false);
 
@@ -712,10 +761,14 @@ function App() {
 
   // Listen for global hotkey events and native overlay events from backend
   useEffect(() => {
-    const handleFullscreen = () => {
+    // Hotkey events carry a token for the steps after the editor in the bound
+    // workflow, empty if there are none; tray events carry nothing
+    const handleFullscreen = (workflow?: string) => {
+      hotkeyWorkflowRef.current = workflow || null;
       handleCapture('fullscreen');
     };
-    const handleRegion = async () => {
+    const handleRegion = async (workflow?: string) => {
+      hotkeyWorkflowRef.current = workflow || null;
       // Native overlay handles region capture - just trigger it
       try {
         await PrepareRegionCapture();
@@ -726,10 +779,22 @@ function App() {
         setTimeout(() => setStatusMessage(undefined), 3000);
       }
     };
-    const handleWindow = () => {
+    const handleWindow = (workflow?: string) => {
+      hotkeyWorkflowRef.current = workflow || null;
       setShowWindowPicker(true);
     };
 
+    // A background workflow reached its editor step: show the capture and
+    // run the rest of the workflow once it renders
+    const handleWorkflowEditor = (data: { capture: CaptureResult; workflow: string }) => {
+      setScreenshot(data.capture);
+      resetAnnotations([]);
+      setSelectedAnnotationId(null);
+      setActiveTool('select');
+      setLastSavedPath(null);
+      setPendingWorkflow(data.workflow || null);
+    };
+
     // Handle native overlay selection result (already cropped)
     const handleRegionSelected = (data: {
       width: number;
@@ -744,20 +809,29 @@ function App() {
       setShowLibrary(true);
     };
 
+    // Handle tray "Fix Hotkeys..." (shown when some hotkeys failed to register)
+    const handleTraySettings = () => {
+      setShowSettings(true);
+    };
+
     EventsOn('hotkey:fullscreen', handleFullscreen);
     EventsOn('hotkey:region', handleRegion);
     EventsOn('hotkey:window', handleWindow);
     EventsOn('region:selected', handleRegionSelected);
+    EventsOn('workflow:editor', handleWorkflowEditor);
     EventsOn('tray:library', handleTrayLibrary);
+    EventsOn('tray:settings', handleTraySettings);
 
     return () => {
       EventsOff('hotkey:fullscreen');
       EventsOff('hotkey:region');
       EventsOff('hotkey:window');
       EventsOff('region:selected');
+      EventsOff('workflow:editor');
       EventsOff('tray:library');
+      EventsOff('tray:settings');
     };
-  }, [handleCapture, handleNativeRegionSelect]);
+  }, [handleCapture, handleNativeRegionSelect, resetAnnotations]);
 
   // Handle minimize to tray
   const handleMinimizeToTray = useCallback(() => {
@@ -803,6 +877,18 @@ function App() {
     }
   }, [resetAnnotations]);
 
+  // Open a capture picked from the tray menu or a notification
+  useEffect(() => {
+    const handleTrayOpen = (filepath: string) => {
+      handleLibraryEdit({ filepath } as LibraryImage);
+    };
+
+    EventsOn('tray:open', handleTrayOpen);
+    return () => {
+      EventsOff('tray:open');
+    };
+  }, [handleLibraryEdit]);
+
   const handleLibraryCapture = useCallback(() => {
     setShowLibrary(false);
     // Small delay ensures library closes before capture UI appears
@@ -1299,6 +1385,10 @@ function App() {
           // Clipboard write may fail in some contexts, still show save success
           setStatusMessage(`Saved to ${result.filePath}`);
         }
+        if (result.duplicateOf) {
+          const original = result.duplicateOf.split(/[\\/]/).pop();
+          setStatusMessage(`Saved to ${result.filePath} (looks like a duplicate of ${original})`);
+        }
       } else {
         setStatusMessage(result.error || 'Quick save failed');
       }
@@ -1432,15 +1522,9 @@ function App() {
       // Restore Transformer visibility
       transformers.forEach((tr) => tr.show());
 
-      const blob = await new Promise<Blob | null>((resolve) => {
-        canvas.toBlob(resolve, 'image/png');
-      });
-
-      if (!blob) return false;
-
-      await navigator.clipboard.write([
-        new ClipboardItem({ 'image/png': blob }),
-      ]);
+      // Written natively (PNG + DIB formats) so it works while the window is hidden
+      const base64Data = getBase64FromDataUrl(canvas.toDataURL('image/png'));
+      await CopyImageToClipboard(base64Data);
       return true;
     } catch (error) {
       console.error('Failed to copy styled canvas:', error);
@@ -1470,6 +1554,24 @@ function App() {
     setTimeout(() => setStatusMessage(undefined), 2000);
   }, [screenshot, copyStyledCanvasToClipboard]);
 
+  // Pin the styled canvas to the screen as an always-on-top window
+  const handlePin = useCallback(async () => {
+    const dataUrl = getCanvasDataUrl('png');
+    if (!dataUrl) {
+      setStatusMessage('Pin failed: No canvas available');
+      return;
+    }
+
+    try {
+      await PinImage(getBase64FromDataUrl(dataUrl));
+      setStatusMessage('Pinned to screen (Esc closes the pin)');
+    } catch (error) {
+      console.error('Pin failed:', error);
+      setStatusMessage('Failed to pin screenshot');
+    }
+    setTimeout(() => setStatusMessage(undefined), 2000);
+  }, [getCanvasDataUrl]);
+
   // Auto-copy styled canvas to clipboard after capture completes
   useEffect(() => {
     if (!pendingAutoCopy || !screenshot) return;
@@ -1514,6 +1616,47 @@ function App() {
     };
   }, [pendingAutoCopy, screenshot, copyStyledCanvasToClipboard]);
 
+  // Run the workflow steps that follow the editor once the canvas has rendered.
+  // The backend runs them on the styled canvas, as if exported by hand.
+  useEffect(() => {
+    if (!pendingWorkflow || !screenshot) return;
+
+    let cancelled = false;
+    const workflow = pendingWorkflow;
+
+    const runSteps = async () => {
+      // Wait for canvas to render with new screenshot (see auto-copy above)
+      await new Promise(resolve => setTimeout(resolve, 300));
+      if (cancelled) return;
+      setPendingWorkflow(null);
+
+      const dataUrl = getCanvasDataUrl('png');
+      if (!dataUrl) return;
+
+      try {
+        const result = await RunWorkflow(getBase64FromDataUrl(dataUrl), workflow);
+        if (result.path) {
+          setLastSavedPath(result.path);
+        }
+        if (result.failures) {
+          const failed = result.steps.filter(step => step.error).map(step => `${step.type}: ${step.error}`);
+          setToast({ message: `Workflow failed: ${failed.join('; ')}`, type: 'error' });
+        } else if (result.url) {
+          setToast({ message: `Uploaded! ${result.url}`, type: 'success' });
+        }
+      } catch (err) {
+        console.error('Workflow failed:', err);
+        setToast({ message: 'Workflow failed', type: 'error' });
+      }
+    };
+
+    runSteps();
+
+    return () => {
+      cancelled = true;
+    };
+  }, [pendingWorkflow, screenshot, getCanvasDataUrl]);
+
   // Keyboard shortcuts for export, import, and clipboard paste
   useEffect(() => {
     const handleExportKeyDown = (e: KeyboardEvent) => {
@@ -1574,7 +1717,10 @@ function App() {
       )}
       <TitleBar onMinimize={handleMinimizeToTray} />
       <CaptureToolbar
-        onCapture={handleCapture}
+        onCapture={(mode) => {
+          hotkeyWorkflowRef.current = null;
+          handleCapture(mode);
+        }}
         isCapturing={isCapturing}
         hasScreenshot={!!screenshot}
         onClear={handleClear}
@@ -1582,6 +1728,8 @@ function App() {
         onOpenSettings={() => setShowSettings(true)}
         onImportImage={handleImportImage}
         onClipboardCapture={handleClipboardCapture}
+        onPickColor={handleColorPick}
+        onMeasure={handleMeasure}
       />
 
       {screenshot && !cropMode && (
@@ -1709,6 +1857,7 @@ function App() {
           onSave={handleSave}
           onQuickSave={handleQuickSave}
           onCopyToClipboard={handleCopyToClipboard}
+          onPin={handlePin}
           onCopyPath={handleCopyPath}
           onOpenLibrary={() => setShowLibrary(true)}
           onCloudUpload={handleCloudUpload}
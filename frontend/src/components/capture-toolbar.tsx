@@ -1,5 +1,5 @@
 import { CaptureMode } from '../types';
-import { Monitor, Scan, AppWindow, Settings, ChevronDown, FolderOpen, Clipboard } from 'lucide-react';
+import { Monitor, Scan, AppWindow, Settings, ChevronDown, FolderOpen, Clipboard, Pipette, Ruler } from 'lucide-react';
 
 interface CaptureToolbarProps {
   onCapture: (mode: CaptureMode) => void;
@@ -10,9 +10,11 @@ interface CaptureToolbarProps {
   onOpenSettings?: () => void;
   onImportImage?: () => void;
   onClipboardCapture?: () => void;
+  onPickColor?: () => void;
+  onMeasure?: () => void;
 }
 
-export function CaptureToolbar({ onCapture, isCapturing, hasScreenshot, onClear, onMinimize, onOpenSettings, onImportImage, onClipboardCapture }: CaptureToolbarProps) {
+export function CaptureToolbar({ onCapture, isCapturing, hasScreenshot, onClear, onMinimize, onOpenSettings, onImportImage, onClipboardCapture, onPickColor, onMeasure }: CaptureToolbarProps) {
   return (
     <div className="flex items-center gap-4 px-4 py-3 glass">
       <div className="flex gap-2">
@@ -94,6 +96,34 @@ export function CaptureToolbar({ onCapture, isCapturing, hasScreenshot, onClear,
             Import
           </button>
         )}
+
+        {/* Color picker - icon only, it doesn't produce a screenshot */}
+        {onPickColor && (
+          <button
+            onClick={onPickColor}
+            disabled={isCapturing}
+            className="p-2.5 rounded-xl transition-all duration-200 text-slate-300 hover:text-white
+                       bg-white/5 hover:bg-white/10 border border-white/10 hover:border-white/20
+                       disabled:opacity-50"
+            title="Pick a color from the screen"
+          >
+            <Pipette className="w-5 h-5" />
+          </button>
+        )}
+
+        {/* Measure - icon only, like the color picker */}
+        {onMeasure && (
+          <button
+            onClick={onMeasure}
+            disabled={isCapturing}
+            className="p-2.5 rounded-xl transition-all duration-200 text-slate-300 hover:text-white
+                       bg-white/5 hover:bg-white/10 border border-white/10 hover:border-white/20
+                       disabled:opacity-50"
+            title="Measure distances on the screen"
+          >
+            <Ruler className="w-5 h-5" />
+          </button>
+        )}
       </div>
 
       {hasScreenshot && (
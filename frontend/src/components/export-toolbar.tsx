@@ -1,10 +1,12 @@
 import { useState, useEffect } from 'react';
-import { ClipboardCopy, Download, Save, Link, Cloud, ChevronUp, Image } from 'lucide-react';
+import { ClipboardCopy, Download, Save, Link, Cloud, ChevronUp, Image, Pin } from 'lucide-react';
+import { PinsMenu } from './pins-menu';
 
 interface ExportToolbarProps {
   onSave: (format: 'png' | 'jpeg') => void;
   onQuickSave: (format: 'png' | 'jpeg') => void;
   onCopyToClipboard: () => void;
+  onPin: () => void;
   onCopyPath: () => void;
   onOpenLibrary: () => void;
   onCloudUpload: (provider: 'r2' | 'gdrive') => void;
@@ -19,6 +21,7 @@ export function ExportToolbar({
   onSave,
   onQuickSave,
   onCopyToClipboard,
+  onPin,
   onCopyPath,
   onOpenLibrary,
   onCloudUpload,
@@ -91,6 +94,23 @@ export function ExportToolbar({
           Copy
         </button>
 
+        {/* Pin to screen */}
+        <div className="flex items-center gap-1">
+          <button
+            onClick={onPin}
+            disabled={isExporting}
+            className="flex items-center gap-1.5 px-3 py-1.5 text-sm rounded-lg font-medium transition-all duration-200
+                       bg-white/5 hover:bg-white/10 border border-white/10 hover:border-white/20
+                       text-slate-300 hover:text-white
+                       disabled:opacity-50 disabled:cursor-not-allowed"
+            title="Pin to screen (scroll to zoom, Ctrl+scroll for opacity, Esc to close)"
+          >
+            <Pin className="w-4 h-4" />
+            Pin
+          </button>
+          <PinsMenu />
+        </div>
+
         {/* Copy Path - only show after save */}
         {lastSavedPath && (
           <button
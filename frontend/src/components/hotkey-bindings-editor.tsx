@@ -0,0 +1,231 @@
+import { useEffect, useState } from 'react';
+import { AlertTriangle, Plus, Trash2 } from 'lucide-react';
+import { HotkeyInput } from './hotkey-input';
+import { CheckHotkey } from '../../wailsjs/go/main/App';
+import { hotkeys } from '../../wailsjs/go/models';
+
+export interface WorkflowStepValue {
+  type: string;
+  provider?: string;
+  command?: string;
+  args?: string[];
+}
+
+export interface HotkeyBindingValue {
+  keys: string;
+  capture: string;
+  steps: WorkflowStepValue[];
+  name?: string;
+}
+
+interface HotkeyBindingsEditorProps {
+  bindings: HotkeyBindingValue[];
+  onChange: (bindings: HotkeyBindingValue[]) => void;
+}
+
+const CAPTURE_MODES = [
+  { value: 'fullscreen', label: 'Fullscreen' },
+  { value: 'region', label: 'Region' },
+  { value: 'window', label: 'Window' },
+  { value: 'color', label: 'Color picker' },
+] as const;
+
+// Toggleable after-capture steps, in the order they run. Without the editor the
+// capture is processed in the background and the window stays hidden.
+const STEP_OPTIONS: { label: string; step: WorkflowStepValue }[] = [
+  { label: 'Open editor', step: { type: 'editor' } },
+  { label: 'Beautify', step: { type: 'beautify' } },
+  { label: 'Save', step: { type: 'save' } },
+  { label: 'Copy', step: { type: 'copy' } },
+  { label: 'Upload to R2', step: { type: 'upload', provider: 'r2' } },
+  { label: 'Upload to Drive', step: { type: 'upload', provider: 'gdrive' } },
+  { label: 'Copy link', step: { type: 'copyUrl' } },
+  { label: 'Notify', step: { type: 'notify' } },
+  { label: 'Run command', step: { type: 'command' } },
+];
+
+// splitArgs splits a command line into arguments, keeping "quoted parts" together
+const splitArgs = (line: string): string[] =>
+  Array.from(line.matchAll(/"([^"]*)"|(\S+)/g), (m) => m[1] ?? m[2]);
+
+const joinArgs = (args?: string[]): string =>
+  (args ?? []).map((arg) => (/\s/.test(arg) || arg === '' ? `"${arg}"` : arg)).join(' ');
+
+const sameStep = (a: WorkflowStepValue, b: WorkflowStepValue) =>
+  a.type === b.type && (a.provider || '') === (b.provider || '');
+
+const normalizeKeys = (keys: string) => keys.replace(/\s/g, '').toUpperCase();
+
+export function HotkeyBindingsEditor({ bindings, onChange }: HotkeyBindingsEditorProps) {
+  // Registration check per key combination, so conflicts show up before saving
+  const [checks, setChecks] = useState<Record<string, hotkeys.Registration>>({});
+  const keysList = Array.from(new Set(bindings.map((b) => b.keys).filter(Boolean)));
+
+  useEffect(() => {
+    let cancelled = false;
+    Promise.all(keysList.map((keys) => CheckHotkey(keys)))
+      .then((results) => {
+        if (!cancelled) {
+          setChecks(Object.fromEntries(results.map((r) => [r.keys, r])));
+        }
+      })
+      .catch((err) => console.error('Failed to check hotkeys:', err));
+    return () => {
+      cancelled = true;
+    };
+  }, [keysList.join('\n')]);
+
+  const isDuplicate = (index: number) =>
+    bindings[index].keys !== '' &&
+    bindings.some((b, i) => i !== index && normalizeKeys(b.keys) === normalizeKeys(bindings[index].keys));
+
+  const updateBinding = (index: number, patch: Partial<HotkeyBindingValue>) => {
+    onChange(bindings.map((b, i) => (i === index ? { ...b, ...patch } : b)));
+  };
+
+  const toggleStep = (index: number, step: WorkflowStepValue, enabled: boolean) => {
+    // Rebuild from STEP_OPTIONS so steps always run in a predictable order,
+    // keeping the settings of existing steps (e.g. the command line)
+    const current = bindings[index].steps;
+    const steps = STEP_OPTIONS
+      .map((option) => current.find((c) => sameStep(c, option.step)) ?? option.step)
+      .filter((s) => (sameStep(s, step) ? enabled : current.some((c) => sameStep(c, s))));
+    updateBinding(index, { steps });
+  };
+
+  const updateCommand = (index: number, patch: Partial<WorkflowStepValue>) => {
+    const steps = bindings[index].steps.map((s) => (s.type === 'command' ? { ...s, ...patch } : s));
+    updateBinding(index, { steps });
+  };
+
+  const addBinding = () => {
+    onChange([...bindings, { keys: '', capture: 'region', steps: [{ type: 'editor' }] }]);
+  };
+
+  const removeBinding = (index: number) => {
+    onChange(bindings.filter((_, i) => i !== index));
+  };
+
+  return (
+    <div className="space-y-3">
+      {bindings.map((binding, index) => (
+        <div key={index} className="p-3 rounded-lg bg-white/5 border border-white/5">
+          <div className="flex items-start gap-2">
+            <div className="flex-1">
+              <HotkeyInput
+                label={binding.name || `Hotkey ${index + 1}`}
+                value={binding.keys}
+                onChange={(keys) => updateBinding(index, { keys })}
+              />
+            </div>
+            <button
+              type="button"
+              onClick={() => removeBinding(index)}
+              className="mt-7 p-2.5 rounded-xl transition-all duration-200
+                         bg-white/5 hover:bg-rose-500/20 border border-white/10 hover:border-rose-500/30
+                         text-slate-400 hover:text-rose-400"
+              title="Remove hotkey"
+            >
+              <Trash2 className="w-4 h-4" />
+            </button>
+          </div>
+
+          {isDuplicate(index) ? (
+            <p className="flex items-center gap-1.5 -mt-1 mb-3 text-xs text-rose-400">
+              <AlertTriangle className="w-3.5 h-3.5" />
+              Same keys as another hotkey
+            </p>
+          ) : (
+            checks[binding.keys] && checks[binding.keys].status !== 'ok' && (
+              <div className="-mt-1 mb-3">
+                <p className="flex items-center gap-1.5 text-xs text-rose-400">
+                  <AlertTriangle className="w-3.5 h-3.5" />
+                  {checks[binding.keys].error}
+                </p>
+                {(checks[binding.keys].suggestions ?? []).length > 0 && (
+                  <div className="flex flex-wrap items-center gap-2 mt-2 text-xs text-slate-400">
+                    Try:
+                    {checks[binding.keys].suggestions!.map((keys) => (
+                      <button
+                        key={keys}
+                        type="button"
+                        onClick={() => updateBinding(index, { keys })}
+                        className="px-2 py-1 rounded-lg bg-white/5 hover:bg-violet-500/20 border border-white/10 hover:border-violet-500/30 text-slate-200"
+                      >
+                        {keys}
+                      </button>
+                    ))}
+                  </div>
+                )}
+              </div>
+            )
+          )}
+
+          <label className="block text-sm text-slate-300 font-medium mb-2">Capture</label>
+          <select
+            value={binding.capture}
+            onChange={(e) => updateBinding(index, { capture: e.target.value })}
+            className="w-full px-4 py-2.5 mb-3 bg-white/5 border border-white/10 rounded-xl text-slate-200 focus:outline-none focus:border-violet-500/50"
+          >
+            {CAPTURE_MODES.map((mode) => (
+              <option key={mode.value} value={mode.value}>{mode.label}</option>
+            ))}
+          </select>
+
+          {binding.capture === 'color' ? (
+            <p className="text-xs text-slate-500">Copies the picked color to the clipboard without showing WinShot.</p>
+          ) : (
+            <>
+              <label className="block text-sm text-slate-300 font-medium mb-2">Then</label>
+              <div className="flex flex-wrap gap-x-4 gap-y-2">
+                {STEP_OPTIONS.map((option) => (
+                  <label key={option.label} className="flex items-center gap-2 cursor-pointer text-sm text-slate-200">
+                    <input
+                      type="checkbox"
+                      checked={binding.steps.some((s) => sameStep(s, option.step))}
+                      onChange={(e) => toggleStep(index, option.step, e.target.checked)}
+                    />
+                    {option.label}
+                  </label>
+                ))}
+              </div>
+
+              {binding.steps.filter((s) => s.type === 'command').map((step) => (
+                <div key="command" className="mt-3 grid grid-cols-2 gap-2">
+                  <input
+                    type="text"
+                    value={step.command ?? ''}
+                    onChange={(e) => updateCommand(index, { command: e.target.value })}
+                    placeholder={'Program, e.g. C:\\Tools\\optimize.exe'}
+                    className="px-3 py-2 bg-white/5 border border-white/10 rounded-xl text-sm text-slate-200 focus:outline-none focus:border-violet-500/50"
+                  />
+                  <input
+                    type="text"
+                    defaultValue={joinArgs(step.args)}
+                    onBlur={(e) => updateCommand(index, { args: splitArgs(e.target.value) })}
+                    placeholder="Arguments: {file} {url}"
+                    className="px-3 py-2 bg-white/5 border border-white/10 rounded-xl text-sm text-slate-200 focus:outline-none focus:border-violet-500/50"
+                  />
+                </div>
+              ))}
+
+              {binding.steps.length > 0 && binding.steps[0].type !== 'editor' && (
+                <p className="mt-3 text-xs text-slate-500">Runs in the background without showing WinShot.</p>
+              )}
+            </>
+          )}
+        </div>
+      ))}
+
+      <button
+        type="button"
+        onClick={addBinding}
+        className="flex items-center gap-2 px-4 py-2.5 rounded-xl text-sm transition-all duration-200
+                   bg-white/5 text-slate-300 hover:bg-white/10 border border-white/10 hover:border-white/20"
+      >
+        <Plus className="w-4 h-4" />
+        Add hotkey
+      </button>
+    </div>
+  );
+}
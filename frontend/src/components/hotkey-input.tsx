@@ -8,11 +8,15 @@ interface HotkeyInputProps {
   disabled?: boolean;
 }
 
-// Map browser key codes to readable names
+// Map browser key codes to the canonical names the backend writes
 const keyCodeToName: Record<string, string> = {
   PrintScreen: 'PrintScreen',
+  Pause: 'Pause',
+  ContextMenu: 'Apps',
   F1: 'F1', F2: 'F2', F3: 'F3', F4: 'F4', F5: 'F5', F6: 'F6',
   F7: 'F7', F8: 'F8', F9: 'F9', F10: 'F10', F11: 'F11', F12: 'F12',
+  F13: 'F13', F14: 'F14', F15: 'F15', F16: 'F16', F17: 'F17', F18: 'F18',
+  F19: 'F19', F20: 'F20', F21: 'F21', F22: 'F22', F23: 'F23', F24: 'F24',
   Space: 'Space',
   Enter: 'Enter',
   Tab: 'Tab',
@@ -28,8 +32,57 @@ const keyCodeToName: Record<string, string> = {
   ArrowDown: 'Down',
   ArrowLeft: 'Left',
   ArrowRight: 'Right',
+  Semicolon: 'Semicolon',
+  Equal: 'Equals',
+  Comma: 'Comma',
+  Minus: 'Minus',
+  Period: 'Period',
+  Slash: 'Slash',
+  Backquote: 'Backquote',
+  BracketLeft: 'BracketLeft',
+  Backslash: 'Backslash',
+  BracketRight: 'BracketRight',
+  Quote: 'Quote',
+  IntlBackslash: 'IntlBackslash',
+  NumpadMultiply: 'NumMultiply',
+  NumpadAdd: 'NumAdd',
+  NumpadSubtract: 'NumSubtract',
+  NumpadDecimal: 'NumDecimal',
+  NumpadDivide: 'NumDivide',
+  NumpadEnter: 'Enter',
+  BrowserBack: 'BrowserBack',
+  BrowserForward: 'BrowserForward',
+  BrowserRefresh: 'BrowserRefresh',
+  BrowserStop: 'BrowserStop',
+  BrowserSearch: 'BrowserSearch',
+  BrowserFavorites: 'BrowserFavorites',
+  BrowserHome: 'BrowserHome',
+  AudioVolumeMute: 'VolumeMute',
+  AudioVolumeDown: 'VolumeDown',
+  AudioVolumeUp: 'VolumeUp',
+  MediaTrackNext: 'MediaNext',
+  MediaTrackPrevious: 'MediaPrev',
+  MediaStop: 'MediaStop',
+  MediaPlayPause: 'MediaPlayPause',
+  LaunchMail: 'LaunchMail',
+  LaunchMediaPlayer: 'LaunchMedia',
+  LaunchApp1: 'LaunchApp1',
+  LaunchApp2: 'LaunchApp2',
 };
 
+// keyName names the key of a keyboard event by its physical position, so
+// Shift+1 records "1" rather than "!"
+const keyName = (e: KeyboardEvent): string => {
+  if (keyCodeToName[e.code]) return keyCodeToName[e.code];
+  const match = /^(?:Key([A-Z])|Digit(\d)|Numpad(\d))$/.exec(e.code);
+  if (match) return match[1] ?? match[2] ?? `Num${match[3]}`;
+  return e.key.length === 1 ? e.key.toUpperCase() : e.key;
+};
+
+// How long recording waits for the next combination of a sequence like "Ctrl+Shift+S, R"
+const SEQUENCE_WAIT_MS = 1000;
+const MAX_SEQUENCE_LENGTH = 3;
+
 // Keys that shouldn't be used as main hotkey
 const blockedKeys = new Set(['Control', 'Alt', 'Shift', 'Meta', 'CapsLock', 'NumLock', 'ScrollLock']);
 
@@ -43,8 +96,32 @@ const HOTKEY_PRESETS = [
 export function HotkeyInput({ value, onChange, label, disabled = false }: HotkeyInputProps) {
   const [isRecording, setIsRecording] = useState(false);
   const [currentKeys, setCurrentKeys] = useState<string[]>([]);
+  // Combinations recorded so far; another may follow within SEQUENCE_WAIT_MS
+  const [recorded, setRecorded] = useState<string[]>([]);
+  const sequenceTimer = useRef<number | null>(null);
   const inputRef = useRef<HTMLButtonElement>(null);
 
+  const clearSequenceTimer = () => {
+    if (sequenceTimer.current !== null) {
+      window.clearTimeout(sequenceTimer.current);
+      sequenceTimer.current = null;
+    }
+  };
+
+  const finishRecording = useCallback((combos: string[]) => {
+    clearSequenceTimer();
+    onChange(combos.join(', '));
+    setIsRecording(false);
+    setCurrentKeys([]);
+    setRecorded([]);
+    inputRef.current?.blur();
+  }, [onChange]);
+
+  const waitForNextCombo = useCallback((combos: string[]) => {
+    clearSequenceTimer();
+    sequenceTimer.current = window.setTimeout(() => finishRecording(combos), SEQUENCE_WAIT_MS);
+  }, [finishRecording]);
+
   // Format hotkey for display
   const formatHotkey = (hotkey: string): string => {
     if (!hotkey) return 'Click to set';
@@ -56,6 +133,7 @@ export function HotkeyInput({ value, onChange, label, disabled = false }: Hotkey
 
     e.preventDefault();
     e.stopPropagation();
+    clearSequenceTimer();
 
     const parts: string[] = [];
 
@@ -65,24 +143,13 @@ export function HotkeyInput({ value, onChange, label, disabled = false }: Hotkey
     if (e.shiftKey) parts.push('Shift');
     if (e.metaKey) parts.push('Win');
 
-    // Get the main key
-    let keyName = e.key;
-
     // Skip if it's just a modifier key
-    if (blockedKeys.has(keyName)) {
+    if (blockedKeys.has(e.key)) {
       setCurrentKeys(parts);
       return;
     }
 
-    // Map special keys
-    if (keyCodeToName[e.code]) {
-      keyName = keyCodeToName[e.code];
-    } else if (e.key.length === 1) {
-      // Single character (letter or number)
-      keyName = e.key.toUpperCase();
-    }
-
-    parts.push(keyName);
+    parts.push(keyName(e));
     setCurrentKeys(parts);
   }, [isRecording]);
 
@@ -97,14 +164,22 @@ export function HotkeyInput({ value, onChange, label, disabled = false }: Hotkey
       const lastKey = currentKeys[currentKeys.length - 1];
       // Check if last item is not a modifier
       if (!['Ctrl', 'Alt', 'Shift', 'Win'].includes(lastKey)) {
-        const hotkeyStr = currentKeys.join('+');
-        onChange(hotkeyStr);
-        setIsRecording(false);
+        const combos = [...recorded, currentKeys.join('+')];
         setCurrentKeys([]);
-        inputRef.current?.blur();
+        if (combos.length >= MAX_SEQUENCE_LENGTH) {
+          finishRecording(combos);
+        } else {
+          setRecorded(combos);
+          waitForNextCombo(combos);
+        }
+        return;
       }
     }
-  }, [isRecording, currentKeys, onChange]);
+    // Released modifiers without a key: keep waiting for the rest of a sequence
+    if (recorded.length > 0) {
+      waitForNextCombo(recorded);
+    }
+  }, [isRecording, currentKeys, recorded, finishRecording, waitForNextCombo]);
 
   useEffect(() => {
     if (isRecording) {
@@ -117,15 +192,24 @@ export function HotkeyInput({ value, onChange, label, disabled = false }: Hotkey
     }
   }, [isRecording, handleKeyDown, handleKeyUp]);
 
+  useEffect(() => clearSequenceTimer, []);
+
   const startRecording = () => {
     if (disabled) return;
     setIsRecording(true);
     setCurrentKeys([]);
+    setRecorded([]);
   };
 
   const cancelRecording = () => {
+    // Losing focus while waiting for the rest of a sequence keeps what was recorded
+    if (sequenceTimer.current !== null) {
+      finishRecording(recorded);
+      return;
+    }
     setIsRecording(false);
     setCurrentKeys([]);
+    setRecorded([]);
   };
 
   const clearHotkey = (e: React.MouseEvent) => {
@@ -149,9 +233,11 @@ export function HotkeyInput({ value, onChange, label, disabled = false }: Hotkey
           } ${disabled ? 'opacity-50 cursor-not-allowed' : 'cursor-pointer'}`}
         >
           {isRecording
-            ? currentKeys.length > 0
-              ? currentKeys.join('+')
-              : 'Press keys...'
+            ? recorded.length > 0
+              ? `${[...recorded, currentKeys.join('+')].filter(Boolean).join(', ')}, …`
+              : currentKeys.length > 0
+                ? currentKeys.join('+')
+                : 'Press keys...'
             : formatHotkey(value)}
         </button>
         {value && !isRecording && (
@@ -1,8 +1,12 @@
 import { useState, useEffect, useCallback, useRef } from 'react';
 import { LibraryImage } from '../types';
-import { GetLibraryImages, DeleteScreenshot } from '../../wailsjs/go/main/App';
+import { GetLibraryPage, DeleteScreenshot } from '../../wailsjs/go/main/App';
+import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
 import { X, Camera, Edit, Trash2, RefreshCw, Image, Calendar } from 'lucide-react';
 
+// Thumbnails are generated per page as the grid is scrolled
+const PAGE_SIZE = 40;
+
 interface LibraryWindowProps {
   isOpen: boolean;
   onClose: () => void;
@@ -15,7 +19,12 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
   const [selectedIndex, setSelectedIndex] = useState<number>(-1);
   const [isLoading, setIsLoading] = useState(false);
   const [isDeleting, setIsDeleting] = useState(false);
+  const [isLoadingMore, setIsLoadingMore] = useState(false);
+  const [page, setPage] = useState(0);
+  const [total, setTotal] = useState(0);
   const containerRef = useRef<HTMLDivElement>(null);
+  const imagesRef = useRef<LibraryImage[]>([]);
+  imagesRef.current = images;
 
   // Derived state for selected image
   const selectedImage = selectedIndex >= 0 && selectedIndex < images.length
@@ -31,13 +40,49 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
     }
   }, [isOpen]);
 
-  // Reset selection when images change
+  const hasMore = images.length < total;
+
+  // Live updates from the QuickSave folder watcher
   useEffect(() => {
-    if (images.length > 0) {
-      setSelectedIndex(0);
-    } else {
-      setSelectedIndex(-1);
-    }
+    if (!isOpen) return;
+
+    const isShown = (image: LibraryImage) =>
+      imagesRef.current.some(img => img.filepath === image.filepath);
+
+    const handleAdded = (image: LibraryImage) => {
+      if (isShown(image)) return;
+      setImages(prev => [image, ...prev.filter(img => img.filepath !== image.filepath)]);
+      setTotal(t => t + 1);
+    };
+
+    const handleChanged = (image: LibraryImage) => {
+      setImages(prev => prev.map(img => (img.filepath === image.filepath ? image : img)));
+    };
+
+    const handleRemoved = (image: LibraryImage) => {
+      if (!isShown(image)) return;
+      setImages(prev => prev.filter(img => img.filepath !== image.filepath));
+      setTotal(t => Math.max(t - 1, 0));
+    };
+
+    EventsOn('library:added', handleAdded);
+    EventsOn('library:changed', handleChanged);
+    EventsOn('library:removed', handleRemoved);
+
+    return () => {
+      EventsOff('library:added');
+      EventsOff('library:changed');
+      EventsOff('library:removed');
+    };
+  }, [isOpen]);
+
+  // Keep selection valid when images change (appending a page keeps it in place)
+  useEffect(() => {
+    setSelectedIndex(prev => {
+      if (images.length === 0) return -1;
+      if (prev < 0) return 0;
+      return Math.min(prev, images.length - 1);
+    });
   }, [images]);
 
   // Keyboard navigation
@@ -114,16 +159,49 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
 
   const loadImages = async () => {
     setIsLoading(true);
+    setSelectedIndex(-1);
     try {
-      const list = await GetLibraryImages();
-      setImages((list as LibraryImage[]) || []);
+      const result = await GetLibraryPage(1, PAGE_SIZE);
+      setImages((result?.images as LibraryImage[]) || []);
+      setTotal(result?.total || 0);
+      setPage(1);
     } catch (error) {
       console.error('Failed to load library images:', error);
       setImages([]);
+      setTotal(0);
+      setPage(0);
     }
     setIsLoading(false);
   };
 
+  const loadMore = async () => {
+    if (isLoading || isLoadingMore || !hasMore) return;
+
+    setIsLoadingMore(true);
+    try {
+      const result = await GetLibraryPage(page + 1, PAGE_SIZE);
+      const next = (result?.images as LibraryImage[]) || [];
+      setImages(prev => {
+        // Skip files already shown (folder may have changed between pages)
+        const known = new Set(prev.map(img => img.filepath));
+        return [...prev, ...next.filter(img => !known.has(img.filepath))];
+      });
+      setTotal(result?.total || 0);
+      setPage(page + 1);
+    } catch (error) {
+      console.error('Failed to load more library images:', error);
+    }
+    setIsLoadingMore(false);
+  };
+
+  // Load the next page when scrolled near the bottom of the grid
+  const handleScroll = (e: React.UIEvent<HTMLDivElement>) => {
+    const el = e.currentTarget;
+    if (el.scrollHeight - el.scrollTop - el.clientHeight < 300) {
+      loadMore();
+    }
+  };
+
   const handleSelect = useCallback((index: number) => {
     setSelectedIndex(index);
   }, []);
@@ -135,7 +213,7 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
   const handleDelete = async () => {
     if (!selectedImage) return;
 
-    const confirmed = window.confirm('Delete this screenshot? This cannot be undone.');
+    const confirmed = window.confirm('Move this screenshot to the trash? You can restore it later.');
     if (!confirmed) return;
 
     setIsDeleting(true);
@@ -149,6 +227,7 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
         }
         return newImages;
       });
+      setTotal(prev => Math.max(prev - 1, 0));
     } catch (error) {
       console.error('Failed to delete screenshot:', error);
     }
@@ -171,6 +250,21 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
     });
   };
 
+  // Tooltip summarizing the capture metadata embedded in the file
+  const describeMetadata = (image: LibraryImage) => {
+    const md = image.metadata;
+    if (!md) return image.filename;
+    const lines = [image.filename];
+    if (md.windowTitle) lines.push(md.windowTitle);
+    if (md.processName) lines.push(`App: ${md.processName}`);
+    if (md.captureMode) lines.push(`Capture: ${md.captureMode}`);
+    if (md.monitor) {
+      lines.push(`Monitor ${md.monitor}${md.dpiScale ? ` @ ${Math.round(md.dpiScale * 100)}%` : ''}`);
+    }
+    if (md.author) lines.push(`Author: ${md.author}`);
+    return lines.join('\n');
+  };
+
   if (!isOpen) return null;
 
   return (
@@ -186,7 +280,7 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
             <Image className="w-5 h-5 text-violet-400" />
             <h2 className="text-lg font-bold text-gradient">Screenshot Library</h2>
             <span className="text-sm text-slate-400">
-              {images.length} {images.length === 1 ? 'image' : 'images'}
+              {total} {total === 1 ? 'image' : 'images'}
             </span>
           </div>
           <button
@@ -198,7 +292,7 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
         </div>
 
         {/* Grid */}
-        <div className="flex-1 overflow-y-auto p-4">
+        <div className="flex-1 overflow-y-auto p-4" onScroll={handleScroll}>
           {isLoading ? (
             <div className="flex items-center justify-center py-20 text-slate-400">
               <div className="flex flex-col items-center gap-3">
@@ -232,6 +326,7 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
                   data-index={index}
                   onClick={() => handleSelect(index)}
                   onDoubleClick={() => handleDoubleClick(image)}
+                  title={describeMetadata(image)}
                   className={`group relative rounded-xl overflow-hidden transition-all duration-200
                               border-2 ${selectedIndex === index
                                 ? 'border-violet-500 ring-2 ring-violet-500/30'
@@ -240,11 +335,15 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
                 >
                   {/* Thumbnail */}
                   <div className="aspect-[4/3] bg-slate-900/50 flex items-center justify-center">
-                    <img
-                      src={`data:image/png;base64,${image.thumbnail}`}
-                      alt={image.filename}
-                      className="max-w-full max-h-full object-contain"
-                    />
+                    {image.thumbnail ? (
+                      <img
+                        src={`data:image/png;base64,${image.thumbnail}`}
+                        alt={image.filename}
+                        className="max-w-full max-h-full object-contain"
+                      />
+                    ) : (
+                      <Image className="w-8 h-8 text-slate-600" />
+                    )}
                   </div>
 
                   {/* Info overlay */}
@@ -264,7 +363,7 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
                   {/* Selection indicator */}
                   {selectedIndex === index && (
                     <div className="absolute top-2 left-2 bg-violet-500 text-white text-[10px] px-1.5 py-0.5 rounded font-medium">
-                      {index + 1}/{images.length}
+                      {index + 1}/{total}
                     </div>
                   )}
 
@@ -272,6 +371,11 @@ export function LibraryWindow({ isOpen, onClose, onCapture, onEdit }: LibraryWin
                   <div className="absolute inset-0 bg-violet-500/0 group-hover:bg-violet-500/10 transition-all duration-200 pointer-events-none" />
                 </button>
               ))}
+              {isLoadingMore && (
+                <div className="col-span-4 flex justify-center py-4 text-sm text-slate-400">
+                  Loading more...
+                </div>
+              )}
             </div>
           )}
         </div>
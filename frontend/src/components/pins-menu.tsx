@@ -0,0 +1,115 @@
+import { useState, useEffect, useCallback } from 'react';
+import { ChevronUp, X, MousePointerClick } from 'lucide-react';
+import { GetPins, ClosePin, CloseAllPins, SetPinOpacity, SetPinClickThrough } from '../../wailsjs/go/main/App';
+import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
+import { overlay } from '../../wailsjs/go/models';
+
+/**
+ * Dropdown listing pinned screenshots, with opacity, click-through and close controls.
+ * Click-through pins ignore the mouse, so this menu is the only way to change them.
+ */
+export function PinsMenu() {
+  const [isOpen, setIsOpen] = useState(false);
+  const [pins, setPins] = useState<overlay.PinInfo[]>([]);
+
+  const refresh = useCallback(async () => {
+    try {
+      setPins((await GetPins()) || []);
+    } catch (error) {
+      console.error('Failed to list pins:', error);
+    }
+  }, []);
+
+  useEffect(() => {
+    if (!isOpen) return;
+    refresh();
+    EventsOn('pin:closed', refresh);
+    return () => EventsOff('pin:closed');
+  }, [isOpen, refresh]);
+
+  const handleOpacity = async (id: number, opacity: number) => {
+    setPins(prev => prev.map(p => (p.id === id ? { ...p, opacity } : p)));
+    await SetPinOpacity(id, opacity).catch(console.error);
+  };
+
+  const handleClickThrough = async (id: number, enabled: boolean) => {
+    await SetPinClickThrough(id, enabled).catch(console.error);
+    refresh();
+  };
+
+  const handleClose = async (id: number) => {
+    await ClosePin(id).catch(console.error);
+    refresh();
+  };
+
+  const handleCloseAll = async () => {
+    await CloseAllPins();
+    setPins([]);
+    setIsOpen(false);
+  };
+
+  return (
+    <div className="relative">
+      <button
+        onClick={() => setIsOpen(!isOpen)}
+        className="flex items-center px-1.5 py-1.5 text-sm rounded-lg transition-all duration-200
+                   bg-white/5 hover:bg-white/10 border border-white/10 hover:border-white/20
+                   text-slate-300 hover:text-white"
+        title="Manage pinned screenshots"
+      >
+        <ChevronUp className="w-3 h-3" />
+      </button>
+
+      {isOpen && (
+        <div className="absolute bottom-full left-0 mb-1 p-2 w-[260px] rounded-lg bg-slate-800/95 border border-white/10 shadow-xl z-50">
+          {pins.length === 0 ? (
+            <p className="px-1 py-2 text-xs text-slate-400">No pinned screenshots</p>
+          ) : (
+            <>
+              {pins.map(pin => (
+                <div key={pin.id} className="px-1 py-1.5 border-b border-white/5 last:border-b-0">
+                  <div className="flex items-center justify-between text-xs text-slate-300">
+                    <span>
+                      Pin {pin.id} · {pin.width}×{pin.height} · {Math.round(pin.zoom * 100)}%
+                    </span>
+                    <div className="flex items-center gap-1">
+                      <button
+                        onClick={() => handleClickThrough(pin.id, !pin.clickThrough)}
+                        className={`p-1 rounded ${pin.clickThrough ? 'bg-violet-500/30 text-violet-200' : 'text-slate-400 hover:bg-white/10'}`}
+                        title={pin.clickThrough ? 'Click-through on' : 'Click-through off'}
+                      >
+                        <MousePointerClick className="w-3.5 h-3.5" />
+                      </button>
+                      <button
+                        onClick={() => handleClose(pin.id)}
+                        className="p-1 rounded text-slate-400 hover:bg-white/10 hover:text-white"
+                        title="Close pin"
+                      >
+                        <X className="w-3.5 h-3.5" />
+                      </button>
+                    </div>
+                  </div>
+                  <input
+                    type="range"
+                    min={10}
+                    max={100}
+                    value={Math.round(pin.opacity * 100)}
+                    onChange={(e) => handleOpacity(pin.id, parseInt(e.target.value, 10) / 100)}
+                    className="w-full mt-1"
+                    title="Opacity"
+                  />
+                </div>
+              ))}
+              <button
+                onClick={handleCloseAll}
+                className="w-full mt-1 px-2 py-1.5 text-xs rounded text-slate-200 hover:bg-white/10"
+              >
+                Close all pins
+              </button>
+            </>
+          )}
+        </div>
+      )}
+    </div>
+  );
+}
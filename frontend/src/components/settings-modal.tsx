@@ -1,5 +1,5 @@
 import { useState, useEffect } from 'react';
-import { HotkeyInput } from './hotkey-input';
+import { HotkeyBindingsEditor, HotkeyBindingValue } from './hotkey-bindings-editor';
 import {
   GetConfig,
   SaveConfig,
@@ -31,9 +31,7 @@ type SettingsTab = 'hotkeys' | 'startup' | 'quicksave' | 'export' | 'updates' |
 // Local interface for easier state management
 interface LocalConfig {
   hotkeys: {
-    fullscreen: string;
-    region: string;
-    window: string;
+    bindings: HotkeyBindingValue[];
   };
   startup: {
     launchOnStartup: boolean;
@@ -44,16 +42,27 @@ interface LocalConfig {
   quickSave: {
     folder: string;
     pattern: string;
+    scanDepth: number;
   };
   export: {
     defaultFormat: string;
     jpegQuality: number;
     includeBackground: boolean;
     autoCopyToClipboard: boolean;
+    stripMetadata: boolean;
+    metadataAuthor: string;
   };
   update: {
     checkOnStartup: boolean;
   };
+  library: {
+    trashRetentionDays: number;
+    watchClipboard: boolean;
+    clipboardMaxItems: number;
+  };
+  colorPicker: {
+    format: string;
+  };
 }
 
 // Cloud config local state
@@ -91,9 +100,11 @@ const defaultCloudConfig: CloudLocalConfig = {
 
 const defaultConfig: LocalConfig = {
   hotkeys: {
-    fullscreen: 'PrintScreen',
-    region: 'Ctrl+PrintScreen',
-    window: 'Ctrl+Shift+PrintScreen',
+    bindings: [
+      { keys: 'PrintScreen', capture: 'fullscreen', steps: [{ type: 'editor' }] },
+      { keys: 'Ctrl+PrintScreen', capture: 'region', steps: [{ type: 'editor' }] },
+      { keys: 'Ctrl+Shift+PrintScreen', capture: 'window', steps: [{ type: 'editor' }] },
+    ],
   },
   startup: {
     launchOnStartup: false,
@@ -104,16 +115,27 @@ const defaultConfig: LocalConfig = {
   quickSave: {
     folder: '',
     pattern: 'timestamp',
+    scanDepth: 3,
   },
   export: {
     defaultFormat: 'png',
     jpegQuality: 95,
     includeBackground: true,
     autoCopyToClipboard: true,
+    stripMetadata: false,
+    metadataAuthor: '',
   },
   update: {
     checkOnStartup: true,
   },
+  library: {
+    trashRetentionDays: 30,
+    watchClipboard: false,
+    clipboardMaxItems: 200,
+  },
+  colorPicker: {
+    format: 'hex',
+  },
 };
 
 export function SettingsModal({ isOpen, onClose }: SettingsModalProps) {
@@ -167,9 +189,12 @@ export function SettingsModal({ isOpen, onClose }: SettingsModalProps) {
       const cfg = await GetConfig();
       const local: LocalConfig = {
         hotkeys: {
-          fullscreen: cfg.hotkeys?.fullscreen || defaultConfig.hotkeys.fullscreen,
-          region: cfg.hotkeys?.region || defaultConfig.hotkeys.region,
-          window: cfg.hotkeys?.window || defaultConfig.hotkeys.window,
+          bindings: (cfg.hotkeys?.bindings ?? defaultConfig.hotkeys.bindings).map((b) => ({
+            keys: b.keys,
+            capture: b.capture,
+            steps: b.steps ?? [],
+            name: b.name,
+          })),
         },
         startup: {
           launchOnStartup: cfg.startup?.launchOnStartup || false,
@@ -180,16 +205,27 @@ export function SettingsModal({ isOpen, onClose }: SettingsModalProps) {
         quickSave: {
           folder: cfg.quickSave?.folder || '',
           pattern: cfg.quickSave?.pattern || 'timestamp',
+          scanDepth: cfg.quickSave?.scanDepth ?? 3,
         },
         export: {
           defaultFormat: cfg.export?.defaultFormat || 'png',
           jpegQuality: cfg.export?.jpegQuality || 95,
           includeBackground: cfg.export?.includeBackground ?? true,
           autoCopyToClipboard: cfg.export?.autoCopyToClipboard ?? true,
+          stripMetadata: cfg.export?.stripMetadata ?? false,
+          metadataAuthor: cfg.export?.metadataAuthor || '',
         },
         update: {
           checkOnStartup: cfg.update?.checkOnStartup ?? true,
         },
+        library: {
+          trashRetentionDays: cfg.library?.trashRetentionDays ?? 30,
+          watchClipboard: cfg.library?.watchClipboard ?? false,
+          clipboardMaxItems: cfg.library?.clipboardMaxItems || 200,
+        },
+        colorPicker: {
+          format: cfg.colorPicker?.format || 'hex',
+        },
       };
       setLocalConfig(local);
       setOriginalConfig(local);
@@ -299,13 +335,21 @@ export function SettingsModal({ isOpen, onClose }: SettingsModalProps) {
     setError(null);
 
     try {
-      // Convert LocalConfig to config.Config for the backend
+      // Convert LocalConfig to config.Config for the backend, keeping the
+      // sections not edited here (OCR, library, ...) as they are now
+      const current = await GetConfig();
       const cfg = new config.Config({
-        hotkeys: new config.HotkeyConfig(localConfig.hotkeys),
+        ...current,
+        hotkeys: new config.HotkeyConfig({
+          // Unset keys can't be registered, so drop them instead of saving
+          bindings: localConfig.hotkeys.bindings.filter((b) => b.keys !== ''),
+        }),
         startup: new config.StartupConfig(localConfig.startup),
-        quickSave: new config.QuickSaveConfig(localConfig.quickSave),
+        quickSave: new config.QuickSaveConfig({ ...current.quickSave, ...localConfig.quickSave }),
         export: new config.ExportConfig(localConfig.export),
         update: new config.UpdateConfig(localConfig.update),
+        library: new config.LibraryConfig({ ...current.library, ...localConfig.library }),
+        colorPicker: new config.ColorConfig({ ...current.colorPicker, ...localConfig.colorPicker }),
       });
       await SaveConfig(cfg);
       setOriginalConfig(localConfig);
@@ -388,35 +432,15 @@ export function SettingsModal({ isOpen, onClose }: SettingsModalProps) {
           {activeTab === 'hotkeys' && (
             <div>
               <p className="text-sm text-slate-400 mb-4 p-3 rounded-lg bg-white/5 border border-white/5">
-                Click on a field and press your desired key combination.
+                Click on a field and press your desired key combination, then choose what
+                the hotkey captures and what happens with the screenshot.
               </p>
-              <HotkeyInput
-                label="Fullscreen Capture"
-                value={localConfig.hotkeys.fullscreen}
-                onChange={(value) =>
-                  setLocalConfig((prev) => ({
-                    ...prev,
-                    hotkeys: { ...prev.hotkeys, fullscreen: value },
-                  }))
-                }
-              />
-              <HotkeyInput
-                label="Region Capture"
-                value={localConfig.hotkeys.region}
-                onChange={(value) =>
-                  setLocalConfig((prev) => ({
-                    ...prev,
-                    hotkeys: { ...prev.hotkeys, region: value },
-                  }))
-                }
-              />
-              <HotkeyInput
-                label="Window Capture"
-                value={localConfig.hotkeys.window}
-                onChange={(value) =>
+              <HotkeyBindingsEditor
+                bindings={localConfig.hotkeys.bindings}
+                onChange={(bindings) =>
                   setLocalConfig((prev) => ({
                     ...prev,
-                    hotkeys: { ...prev.hotkeys, window: value },
+                    hotkeys: { bindings },
                   }))
                 }
               />
@@ -464,7 +488,7 @@ export function SettingsModal({ isOpen, onClose }: SettingsModalProps) {
                     }))
                   }
                 />
-                <span className="text-slate-200">Show notification on capture</span>
+                <span className="text-slate-200">Show notification after saving or uploading</span>
               </label>
 
               <label className="flex items-center gap-3 cursor-pointer p-3 rounded-lg bg-white/5 hover:bg-white/8 border border-white/5 transition-all duration-200">
@@ -529,6 +553,89 @@ export function SettingsModal({ isOpen, onClose }: SettingsModalProps) {
                   <option value="increment">winshot_001, winshot_002...</option>
                 </select>
               </div>
+
+              <div>
+                <label className="block text-sm text-slate-300 font-medium mb-2">Library Subfolders</label>
+                <select
+                  value={localConfig.quickSave.scanDepth}
+                  onChange={(e) =>
+                    setLocalConfig((prev) => ({
+                      ...prev,
+                      quickSave: {
+                        ...prev.quickSave,
+                        scanDepth: parseInt(e.target.value, 10),
+                      },
+                    }))
+                  }
+                  className="w-full px-4 py-2.5 bg-white/5 border border-white/10 rounded-xl text-slate-200 focus:outline-none focus:border-violet-500/50"
+                >
+                  <option value={0}>Top folder only</option>
+                  <option value={1}>1 level of subfolders</option>
+                  <option value={3}>3 levels of subfolders</option>
+                  <option value={10}>All subfolders (up to 10 levels)</option>
+                </select>
+              </div>
+
+              <div>
+                <label className="block text-sm text-slate-300 font-medium mb-2">Empty Trash Automatically</label>
+                <select
+                  value={localConfig.library.trashRetentionDays}
+                  onChange={(e) =>
+                    setLocalConfig((prev) => ({
+                      ...prev,
+                      library: {
+                        ...prev.library,
+                        trashRetentionDays: parseInt(e.target.value, 10),
+                      },
+                    }))
+                  }
+                  className="w-full px-4 py-2.5 bg-white/5 border border-white/10 rounded-xl text-slate-200 focus:outline-none focus:border-violet-500/50"
+                >
+                  <option value={7}>After 7 days</option>
+                  <option value={30}>After 30 days</option>
+                  <option value={90}>After 90 days</option>
+                  <option value={0}>Never</option>
+                </select>
+              </div>
+
+              <label className="flex items-center gap-3 cursor-pointer p-3 rounded-lg bg-white/5 hover:bg-white/8 border border-white/5 transition-all duration-200">
+                <input
+                  type="checkbox"
+                  checked={localConfig.library.watchClipboard}
+                  onChange={(e) =>
+                    setLocalConfig((prev) => ({
+                      ...prev,
+                      library: { ...prev.library, watchClipboard: e.target.checked },
+                    }))
+                  }
+                />
+                <div>
+                  <span className="text-slate-200">Watch clipboard</span>
+                  <p className="text-xs text-slate-400 mt-0.5">Save images copied in other apps to the Clipboard folder</p>
+                </div>
+              </label>
+
+              <div>
+                <label className="block text-sm text-slate-300 font-medium mb-2">Clipboard History Size</label>
+                <input
+                  type="number"
+                  min={10}
+                  max={5000}
+                  value={localConfig.library.clipboardMaxItems}
+                  disabled={!localConfig.library.watchClipboard}
+                  onChange={(e) =>
+                    setLocalConfig((prev) => ({
+                      ...prev,
+                      library: {
+                        ...prev.library,
+                        clipboardMaxItems: Math.max(10, parseInt(e.target.value, 10) || 200),
+                      },
+                    }))
+                  }
+                  className="w-full px-4 py-2.5 bg-white/5 border border-white/10 rounded-xl text-slate-200 focus:outline-none focus:border-violet-500/50 disabled:opacity-50"
+                />
+                <p className="text-xs text-slate-400 mt-1.5">Oldest clipboard images move to the trash past this count</p>
+              </div>
             </div>
           )}
 
@@ -629,6 +736,58 @@ export function SettingsModal({ isOpen, onClose }: SettingsModalProps) {
                   <p className="text-xs text-slate-400 mt-0.5">Uses your default export format (PNG/JPEG)</p>
                 </div>
               </label>
+
+              <label className="flex items-center gap-3 cursor-pointer p-3 rounded-lg bg-white/5 hover:bg-white/8 border border-white/5 transition-all duration-200">
+                <input
+                  type="checkbox"
+                  checked={localConfig.export.stripMetadata}
+                  onChange={(e) =>
+                    setLocalConfig((prev) => ({
+                      ...prev,
+                      export: { ...prev.export, stripMetadata: e.target.checked },
+                    }))
+                  }
+                />
+                <div>
+                  <span className="text-slate-200">Strip metadata from saved files</span>
+                  <p className="text-xs text-slate-400 mt-0.5">Don't record window title, app, monitor or time in PNG/JPEG files</p>
+                </div>
+              </label>
+
+              <div>
+                <label className="block text-sm text-slate-300 font-medium mb-2">Author</label>
+                <input
+                  type="text"
+                  value={localConfig.export.metadataAuthor}
+                  onChange={(e) =>
+                    setLocalConfig((prev) => ({
+                      ...prev,
+                      export: { ...prev.export, metadataAuthor: e.target.value },
+                    }))
+                  }
+                  disabled={localConfig.export.stripMetadata}
+                  className="w-full px-3 py-2 bg-white/5 border border-white/10 rounded-lg text-slate-200 text-sm placeholder:text-slate-500 focus:outline-none focus:border-violet-500/50 disabled:opacity-50"
+                  placeholder="Optional - embedded in saved files"
+                />
+              </div>
+
+              <div>
+                <label className="block text-sm text-slate-300 font-medium mb-2">Color Picker Copies</label>
+                <select
+                  value={localConfig.colorPicker.format}
+                  onChange={(e) =>
+                    setLocalConfig((prev) => ({
+                      ...prev,
+                      colorPicker: { ...prev.colorPicker, format: e.target.value },
+                    }))
+                  }
+                  className="w-full px-4 py-2.5 bg-white/5 border border-white/10 rounded-xl text-slate-200 focus:outline-none focus:border-violet-500/50"
+                >
+                  <option value="hex">HEX - #12ABEF</option>
+                  <option value="rgb">RGB - rgb(18, 171, 239)</option>
+                  <option value="hsl">HSL - hsl(198, 87%, 50%)</option>
+                </select>
+              </div>
             </div>
           )}
 
@@ -2,6 +2,7 @@ export interface CaptureResult {
   width: number;
   height: number;
   data: string;
+  mimeType?: string; // Set when data is not PNG (e.g. "image/svg+xml")
 }
 
 export interface WindowInfo {
@@ -106,6 +107,17 @@ export interface StartupConfig {
 export interface QuickSaveConfig {
   folder: string;
   pattern: 'timestamp' | 'date' | 'increment';
+  scanDepth: number; // Subfolder levels shown in the library (0 = top folder only)
+  retention?: RetentionConfig;
+}
+
+// Automatic QuickSave cleanup; favorites and tagged screenshots are exempt
+export interface RetentionConfig {
+  enabled: boolean;
+  maxAgeDays: number; // 0 = no limit
+  maxTotalSizeMb: number; // 0 = no limit
+  maxCount: number; // 0 = no limit
+  action: 'trash' | 'archive';
 }
 
 export interface ExportConfig {
@@ -129,4 +141,20 @@ export interface LibraryImage {
   thumbnail: string; // Base64 PNG
   width: number;
   height: number;
+  favorite?: boolean;
+  tags?: string[];
+  metadata?: CaptureMetadata;
+}
+
+// Capture context embedded in saved PNG/JPEG files
+export interface CaptureMetadata {
+  captureMode?: string;
+  capturedAt: string;
+  windowTitle?: string;
+  windowClass?: string;
+  processName?: string;
+  monitor?: number;
+  dpiScale?: number;
+  appVersion?: string;
+  author?: string;
 }
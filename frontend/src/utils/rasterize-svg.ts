@@ -0,0 +1,38 @@
+/**
+ * Rasterizes SVG markup to a PNG capture result.
+ * Used for clipboard SVGs, which the backend passes through unrendered.
+ */
+
+import { CaptureResult } from '../types';
+
+const MAX_SVG_DIM = 8192; // Cap huge intrinsic sizes to keep the canvas allocatable
+
+/**
+ * Render base64 SVG markup to PNG at its intrinsic size
+ * @param result - Capture result with mimeType "image/svg+xml"
+ * @returns Capture result holding base64 PNG data
+ */
+export function rasterizeSvg(result: CaptureResult): Promise<CaptureResult> {
+  const scale = Math.min(1, MAX_SVG_DIM / Math.max(result.width, result.height, 1));
+  const width = Math.max(1, Math.round(result.width * scale));
+  const height = Math.max(1, Math.round(result.height * scale));
+
+  return new Promise((resolve, reject) => {
+    const img = new Image();
+    img.onload = () => {
+      const canvas = document.createElement('canvas');
+      canvas.width = width;
+      canvas.height = height;
+      const ctx = canvas.getContext('2d');
+      if (!ctx) {
+        reject(new Error('Canvas 2D context unavailable'));
+        return;
+      }
+      ctx.drawImage(img, 0, 0, width, height);
+      const data = canvas.toDataURL('image/png').split(',')[1];
+      resolve({ width, height, data });
+    };
+    img.onerror = () => reject(new Error('Failed to render SVG'));
+    img.src = `data:image/svg+xml;base64,${result.data}`;
+  });
+}
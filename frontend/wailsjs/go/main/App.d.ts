@@ -1,13 +1,30 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {library} from '../models';
 import {screenshot} from '../models';
 import {updater} from '../models';
-import {config} from '../models';
+import {hotkeys} from '../models';
+import {imagediff} from '../models';
 import {main} from '../models';
-import {library} from '../models';
+import {config} from '../models';
+import {overlay} from '../models';
 import {windows} from '../models';
+import {ocr} from '../models';
+import {workflow} from '../models';
 import {upload} from '../models';
 
+export function BatchConvert(arg1:Array<string>,arg2:library.ConvertOptions):Promise<string>;
+
+export function BatchCopy(arg1:Array<string>,arg2:string):Promise<string>;
+
+export function BatchDelete(arg1:Array<string>):Promise<string>;
+
+export function BatchExportZip(arg1:Array<string>):Promise<string>;
+
+export function BatchUpload(arg1:Array<string>,arg2:string):Promise<string>;
+
+export function CancelBatch(arg1:string):Promise<boolean>;
+
 export function CaptureDisplay(arg1:number):Promise<screenshot.CaptureResult>;
 
 export function CaptureFullscreen():Promise<screenshot.CaptureResult>;
@@ -18,14 +35,36 @@ export function CaptureWindow(arg1:number):Promise<screenshot.CaptureResult>;
 
 export function CheckForUpdate(arg1:string):Promise<updater.UpdateInfo>;
 
+export function CheckHotkey(arg1:string):Promise<hotkeys.Registration>;
+
+export function ClearColorHistory():Promise<void>;
+
 export function ClearGDriveCredentials():Promise<void>;
 
 export function ClearR2Credentials():Promise<void>;
 
+export function CloseAllPins():Promise<void>;
+
+export function ClosePin(arg1:number):Promise<void>;
+
+export function CompareImages(arg1:string,arg2:string,arg3:imagediff.Options):Promise<main.CompareResult>;
+
+export function CopyFileToClipboard(arg1:string):Promise<void>;
+
+export function CopyImageToClipboard(arg1:string):Promise<void>;
+
+export function DeleteCollection(arg1:string):Promise<void>;
+
+export function DeleteFromTrash(arg1:string):Promise<void>;
+
 export function DeleteScreenshot(arg1:string):Promise<void>;
 
 export function DisconnectGDrive():Promise<void>;
 
+export function EmptyTrash():Promise<number>;
+
+export function FindDuplicates(arg1:number):Promise<Array<library.DuplicateGroup>>;
+
 export function FinishRegionCapture():Promise<void>;
 
 export function GetActiveDisplayIndex():Promise<number>;
@@ -34,6 +73,14 @@ export function GetBackgroundImages():Promise<Array<string>>;
 
 export function GetClipboardImage():Promise<screenshot.CaptureResult>;
 
+export function GetClipboardImages():Promise<Array<screenshot.CaptureResult>>;
+
+export function GetCollectionImages(arg1:string,arg2:number):Promise<library.SearchResult>;
+
+export function GetCollections():Promise<Array<library.Collection>>;
+
+export function GetColorHistory():Promise<Array<string>>;
+
 export function GetConfig():Promise<config.Config>;
 
 export function GetDisplayBounds(arg1:number):Promise<main.DisplayBounds>;
@@ -46,14 +93,24 @@ export function GetGDriveConfig():Promise<config.GDriveConfig>;
 
 export function GetGDriveStatus():Promise<main.GDriveStatus>;
 
-export function GetHotkeyConfig():Promise<main.HotkeyConfig>;
+export function GetHotkeyConfig():Promise<config.HotkeyConfig>;
+
+export function GetHotkeyStatus():Promise<Array<hotkeys.Registration>>;
+
+export function GetLibraryFolders():Promise<Array<string>>;
 
 export function GetLibraryImages():Promise<Array<library.LibraryImage>>;
 
+export function GetLibraryPage(arg1:number,arg2:number):Promise<library.SearchResult>;
+
+export function GetPins():Promise<Array<overlay.PinInfo>>;
+
 export function GetR2Config():Promise<config.R2Config>;
 
 export function GetSkippedVersion():Promise<string>;
 
+export function GetTrashItems():Promise<Array<library.TrashItem>>;
+
 export function GetVirtualScreenBounds():Promise<main.VirtualScreenBounds>;
 
 export function GetWindowInfo(arg1:number):Promise<windows.WindowInfo>;
@@ -66,22 +123,54 @@ export function HasGDriveCredentials():Promise<boolean>;
 
 export function IsGDriveConnected():Promise<boolean>;
 
+export function IsOCRAvailable():Promise<boolean>;
+
 export function IsR2Configured():Promise<boolean>;
 
+export function MeasureScreen():Promise<main.Measurement>;
+
 export function MinimizeToTray():Promise<void>;
 
+export function MoveScreenshot(arg1:string,arg2:string):Promise<string>;
+
+export function OCRImage(arg1:string):Promise<ocr.Result>;
+
+export function OCRLibraryImage(arg1:string):Promise<ocr.Result>;
+
+export function OCRRegion(arg1:string,arg2:number,arg3:number,arg4:number,arg5:number):Promise<ocr.Result>;
+
 export function OpenImage():Promise<screenshot.CaptureResult>;
 
 export function OpenInEditor(arg1:string):Promise<screenshot.CaptureResult>;
 
 export function OpenURL(arg1:string):Promise<void>;
 
+export function PickColor():Promise<main.PickedColor>;
+
+export function PinImage(arg1:string):Promise<number>;
+
+export function PinImageAt(arg1:string,arg2:number,arg3:number):Promise<number>;
+
+export function PinLibraryImage(arg1:string):Promise<number>;
+
 export function PrepareRegionCapture():Promise<main.RegionCaptureData>;
 
+export function PreviewRetention():Promise<library.RetentionPlan>;
+
 export function QuickSave(arg1:string,arg2:string):Promise<main.SaveImageResult>;
 
+export function RecordLibraryUpload(arg1:string,arg2:string):Promise<void>;
+
+export function RenameScreenshot(arg1:string,arg2:string):Promise<string>;
+
+export function RestoreFromTrash(arg1:string):Promise<string>;
+
+export function RunWorkflow(arg1:string,arg2:string):Promise<workflow.Result>;
+
 export function SaveBackgroundImages(arg1:Array<string>):Promise<void>;
 
+export function SaveCollection(arg1:library.Collection):Promise<void>;
+
 export function SaveConfig(arg1:config.Config):Promise<void>;
 
 export function SaveEditorConfig(arg1:config.EditorConfig):Promise<void>;
@@ -96,8 +185,22 @@ export function SaveR2Config(arg1:string,arg2:string,arg3:string,arg4:string):Pr
 
 export function SaveR2Credentials(arg1:string,arg2:string):Promise<void>;
 
+export function SearchLibrary(arg1:string,arg2:library.SearchFilters,arg3:string,arg4:number):Promise<library.SearchResult>;
+
 export function SelectFolder():Promise<string>;
 
+export function SetClipboardWatch(arg1:boolean):Promise<void>;
+
+export function SetLibraryFavorite(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetLibraryTags(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SetPinClickThrough(arg1:number,arg2:boolean):Promise<void>;
+
+export function SetPinOpacity(arg1:number,arg2:number):Promise<void>;
+
+export function SetPinZoom(arg1:number,arg2:number):Promise<void>;
+
 export function SetSkippedVersion(arg1:string):Promise<void>;
 
 export function ShowWindow():Promise<void>;
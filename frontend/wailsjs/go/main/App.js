@@ -2,6 +2,30 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function BatchConvert(arg1, arg2) {
+  return window['go']['main']['App']['BatchConvert'](arg1, arg2);
+}
+
+export function BatchCopy(arg1, arg2) {
+  return window['go']['main']['App']['BatchCopy'](arg1, arg2);
+}
+
+export function BatchDelete(arg1) {
+  return window['go']['main']['App']['BatchDelete'](arg1);
+}
+
+export function BatchExportZip(arg1) {
+  return window['go']['main']['App']['BatchExportZip'](arg1);
+}
+
+export function BatchUpload(arg1, arg2) {
+  return window['go']['main']['App']['BatchUpload'](arg1, arg2);
+}
+
+export function CancelBatch(arg1) {
+  return window['go']['main']['App']['CancelBatch'](arg1);
+}
+
 export function CaptureDisplay(arg1) {
   return window['go']['main']['App']['CaptureDisplay'](arg1);
 }
@@ -22,6 +46,14 @@ export function CheckForUpdate(arg1) {
   return window['go']['main']['App']['CheckForUpdate'](arg1);
 }
 
+export function CheckHotkey(arg1) {
+  return window['go']['main']['App']['CheckHotkey'](arg1);
+}
+
+export function ClearColorHistory() {
+  return window['go']['main']['App']['ClearColorHistory']();
+}
+
 export function ClearGDriveCredentials() {
   return window['go']['main']['App']['ClearGDriveCredentials']();
 }
@@ -30,6 +62,34 @@ export function ClearR2Credentials() {
   return window['go']['main']['App']['ClearR2Credentials']();
 }
 
+export function CloseAllPins() {
+  return window['go']['main']['App']['CloseAllPins']();
+}
+
+export function ClosePin(arg1) {
+  return window['go']['main']['App']['ClosePin'](arg1);
+}
+
+export function CompareImages(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CompareImages'](arg1, arg2, arg3);
+}
+
+export function CopyFileToClipboard(arg1) {
+  return window['go']['main']['App']['CopyFileToClipboard'](arg1);
+}
+
+export function CopyImageToClipboard(arg1) {
+  return window['go']['main']['App']['CopyImageToClipboard'](arg1);
+}
+
+export function DeleteCollection(arg1) {
+  return window['go']['main']['App']['DeleteCollection'](arg1);
+}
+
+export function DeleteFromTrash(arg1) {
+  return window['go']['main']['App']['DeleteFromTrash'](arg1);
+}
+
 export function DeleteScreenshot(arg1) {
   return window['go']['main']['App']['DeleteScreenshot'](arg1);
 }
@@ -38,6 +98,14 @@ export function DisconnectGDrive() {
   return window['go']['main']['App']['DisconnectGDrive']();
 }
 
+export function EmptyTrash() {
+  return window['go']['main']['App']['EmptyTrash']();
+}
+
+export function FindDuplicates(arg1) {
+  return window['go']['main']['App']['FindDuplicates'](arg1);
+}
+
 export function FinishRegionCapture() {
   return window['go']['main']['App']['FinishRegionCapture']();
 }
@@ -54,6 +122,22 @@ export function GetClipboardImage() {
   return window['go']['main']['App']['GetClipboardImage']();
 }
 
+export function GetClipboardImages() {
+  return window['go']['main']['App']['GetClipboardImages']();
+}
+
+export function GetCollectionImages(arg1, arg2) {
+  return window['go']['main']['App']['GetCollectionImages'](arg1, arg2);
+}
+
+export function GetCollections() {
+  return window['go']['main']['App']['GetCollections']();
+}
+
+export function GetColorHistory() {
+  return window['go']['main']['App']['GetColorHistory']();
+}
+
 export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
@@ -82,10 +166,26 @@ export function GetHotkeyConfig() {
   return window['go']['main']['App']['GetHotkeyConfig']();
 }
 
+export function GetHotkeyStatus() {
+  return window['go']['main']['App']['GetHotkeyStatus']();
+}
+
+export function GetLibraryFolders() {
+  return window['go']['main']['App']['GetLibraryFolders']();
+}
+
 export function GetLibraryImages() {
   return window['go']['main']['App']['GetLibraryImages']();
 }
 
+export function GetLibraryPage(arg1, arg2) {
+  return window['go']['main']['App']['GetLibraryPage'](arg1, arg2);
+}
+
+export function GetPins() {
+  return window['go']['main']['App']['GetPins']();
+}
+
 export function GetR2Config() {
   return window['go']['main']['App']['GetR2Config']();
 }
@@ -94,6 +194,10 @@ export function GetSkippedVersion() {
   return window['go']['main']['App']['GetSkippedVersion']();
 }
 
+export function GetTrashItems() {
+  return window['go']['main']['App']['GetTrashItems']();
+}
+
 export function GetVirtualScreenBounds() {
   return window['go']['main']['App']['GetVirtualScreenBounds']();
 }
@@ -118,14 +222,38 @@ export function IsGDriveConnected() {
   return window['go']['main']['App']['IsGDriveConnected']();
 }
 
+export function IsOCRAvailable() {
+  return window['go']['main']['App']['IsOCRAvailable']();
+}
+
 export function IsR2Configured() {
   return window['go']['main']['App']['IsR2Configured']();
 }
 
+export function MeasureScreen() {
+  return window['go']['main']['App']['MeasureScreen']();
+}
+
 export function MinimizeToTray() {
   return window['go']['main']['App']['MinimizeToTray']();
 }
 
+export function MoveScreenshot(arg1, arg2) {
+  return window['go']['main']['App']['MoveScreenshot'](arg1, arg2);
+}
+
+export function OCRImage(arg1) {
+  return window['go']['main']['App']['OCRImage'](arg1);
+}
+
+export function OCRLibraryImage(arg1) {
+  return window['go']['main']['App']['OCRLibraryImage'](arg1);
+}
+
+export function OCRRegion(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['OCRRegion'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function OpenImage() {
   return window['go']['main']['App']['OpenImage']();
 }
@@ -138,18 +266,58 @@ export function OpenURL(arg1) {
   return window['go']['main']['App']['OpenURL'](arg1);
 }
 
+export function PickColor() {
+  return window['go']['main']['App']['PickColor']();
+}
+
+export function PinImage(arg1) {
+  return window['go']['main']['App']['PinImage'](arg1);
+}
+
+export function PinImageAt(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PinImageAt'](arg1, arg2, arg3);
+}
+
+export function PinLibraryImage(arg1) {
+  return window['go']['main']['App']['PinLibraryImage'](arg1);
+}
+
 export function PrepareRegionCapture() {
   return window['go']['main']['App']['PrepareRegionCapture']();
 }
 
+export function PreviewRetention() {
+  return window['go']['main']['App']['PreviewRetention']();
+}
+
 export function QuickSave(arg1, arg2) {
   return window['go']['main']['App']['QuickSave'](arg1, arg2);
 }
 
+export function RecordLibraryUpload(arg1, arg2) {
+  return window['go']['main']['App']['RecordLibraryUpload'](arg1, arg2);
+}
+
+export function RenameScreenshot(arg1, arg2) {
+  return window['go']['main']['App']['RenameScreenshot'](arg1, arg2);
+}
+
+export function RestoreFromTrash(arg1) {
+  return window['go']['main']['App']['RestoreFromTrash'](arg1);
+}
+
+export function RunWorkflow(arg1, arg2) {
+  return window['go']['main']['App']['RunWorkflow'](arg1, arg2);
+}
+
 export function SaveBackgroundImages(arg1) {
   return window['go']['main']['App']['SaveBackgroundImages'](arg1);
 }
 
+export function SaveCollection(arg1) {
+  return window['go']['main']['App']['SaveCollection'](arg1);
+}
+
 export function SaveConfig(arg1) {
   return window['go']['main']['App']['SaveConfig'](arg1);
 }
@@ -178,10 +346,38 @@ export function SaveR2Credentials(arg1, arg2) {
   return window['go']['main']['App']['SaveR2Credentials'](arg1, arg2);
 }
 
+export function SearchLibrary(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SearchLibrary'](arg1, arg2, arg3, arg4);
+}
+
 export function SelectFolder() {
   return window['go']['main']['App']['SelectFolder']();
 }
 
+export function SetClipboardWatch(arg1) {
+  return window['go']['main']['App']['SetClipboardWatch'](arg1);
+}
+
+export function SetLibraryFavorite(arg1, arg2) {
+  return window['go']['main']['App']['SetLibraryFavorite'](arg1, arg2);
+}
+
+export function SetLibraryTags(arg1, arg2) {
+  return window['go']['main']['App']['SetLibraryTags'](arg1, arg2);
+}
+
+export function SetPinClickThrough(arg1, arg2) {
+  return window['go']['main']['App']['SetPinClickThrough'](arg1, arg2);
+}
+
+export function SetPinOpacity(arg1, arg2) {
+  return window['go']['main']['App']['SetPinOpacity'](arg1, arg2);
+}
+
+export function SetPinZoom(arg1, arg2) {
+  return window['go']['main']['App']['SetPinZoom'](arg1, arg2);
+}
+
 export function SetSkippedVersion(arg1) {
   return window['go']['main']['App']['SetSkippedVersion'](arg1);
 }
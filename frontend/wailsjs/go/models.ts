@@ -33,6 +33,7 @@ export namespace config {
 	export class CloudConfig {
 	    r2?: R2Config;
 	    gdrive?: GDriveConfig;
+	    defaultProvider?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new CloudConfig(source);
@@ -42,6 +43,7 @@ export namespace config {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.r2 = this.convertValues(source["r2"], R2Config);
 	        this.gdrive = this.convertValues(source["gdrive"], GDriveConfig);
+	        this.defaultProvider = source["defaultProvider"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -62,6 +64,50 @@ export namespace config {
 		    return a;
 		}
 	}
+	export class ColorConfig {
+	    format: string;
+	    history?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ColorConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.history = source["history"];
+	    }
+	}
+	export class LibraryConfig {
+	    trashRetentionDays: number;
+	    watchClipboard: boolean;
+	    clipboardMaxItems: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LibraryConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.trashRetentionDays = source["trashRetentionDays"];
+	        this.watchClipboard = source["watchClipboard"];
+	        this.clipboardMaxItems = source["clipboardMaxItems"];
+	    }
+	}
+	export class OCRConfig {
+	    tesseractPath?: string;
+	    language: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OCRConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tesseractPath = source["tesseractPath"];
+	        this.language = source["language"];
+	    }
+	}
 	export class UpdateConfig {
 	    checkOnStartup: boolean;
 	    skippedVersion?: string;
@@ -135,6 +181,8 @@ export namespace config {
 	    jpegQuality: number;
 	    includeBackground: boolean;
 	    autoCopyToClipboard: boolean;
+	    stripMetadata: boolean;
+	    metadataAuthor?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new ExportConfig(source);
@@ -146,11 +194,35 @@ export namespace config {
 	        this.jpegQuality = source["jpegQuality"];
 	        this.includeBackground = source["includeBackground"];
 	        this.autoCopyToClipboard = source["autoCopyToClipboard"];
+	        this.stripMetadata = source["stripMetadata"];
+	        this.metadataAuthor = source["metadataAuthor"];
+	    }
+	}
+	export class RetentionConfig {
+	    enabled: boolean;
+	    maxAgeDays: number;
+	    maxTotalSizeMb: number;
+	    maxCount: number;
+	    action: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RetentionConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.maxAgeDays = source["maxAgeDays"];
+	        this.maxTotalSizeMb = source["maxTotalSizeMb"];
+	        this.maxCount = source["maxCount"];
+	        this.action = source["action"];
 	    }
 	}
 	export class QuickSaveConfig {
 	    folder: string;
 	    pattern: string;
+	    scanDepth: number;
+	    retention: RetentionConfig;
 	
 	    static createFrom(source: any = {}) {
 	        return new QuickSaveConfig(source);
@@ -160,7 +232,27 @@ export namespace config {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.folder = source["folder"];
 	        this.pattern = source["pattern"];
+	        this.scanDepth = source["scanDepth"];
+	        this.retention = this.convertValues(source["retention"], RetentionConfig);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class StartupConfig {
 	    launchOnStartup: boolean;
@@ -180,48 +272,661 @@ export namespace config {
 	        this.closeToTray = source["closeToTray"];
 	    }
 	}
-	export class HotkeyConfig {
-	    fullscreen: string;
-	    region: string;
-	    window: string;
+	export class WorkflowStep {
+	    type: string;
+	    provider?: string;
+	    command?: string;
+	    args?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.provider = source["provider"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	    }
+	}
+	export class HotkeyBinding {
+	    keys: string;
+	    capture: string;
+	    steps?: WorkflowStep[];
+	    name?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HotkeyBinding(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.keys = source["keys"];
+	        this.capture = source["capture"];
+	        this.steps = this.convertValues(source["steps"], WorkflowStep);
+	        this.name = source["name"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HotkeyConfig {
+	    bindings: HotkeyBinding[];
+	    fullscreen?: string;
+	    region?: string;
+	    window?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HotkeyConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bindings = this.convertValues(source["bindings"], HotkeyBinding);
+	        this.fullscreen = source["fullscreen"];
+	        this.region = source["region"];
+	        this.window = source["window"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Config {
+	    hotkeys: HotkeyConfig;
+	    startup: StartupConfig;
+	    quickSave: QuickSaveConfig;
+	    export: ExportConfig;
+	    window: WindowConfig;
+	    editor: EditorConfig;
+	    update: UpdateConfig;
+	    cloud?: CloudConfig;
+	    ocr: OCRConfig;
+	    library: LibraryConfig;
+	    colorPicker: ColorConfig;
+	    backgroundImages?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hotkeys = this.convertValues(source["hotkeys"], HotkeyConfig);
+	        this.startup = this.convertValues(source["startup"], StartupConfig);
+	        this.quickSave = this.convertValues(source["quickSave"], QuickSaveConfig);
+	        this.export = this.convertValues(source["export"], ExportConfig);
+	        this.window = this.convertValues(source["window"], WindowConfig);
+	        this.editor = this.convertValues(source["editor"], EditorConfig);
+	        this.update = this.convertValues(source["update"], UpdateConfig);
+	        this.cloud = this.convertValues(source["cloud"], CloudConfig);
+	        this.ocr = this.convertValues(source["ocr"], OCRConfig);
+	        this.library = this.convertValues(source["library"], LibraryConfig);
+	        this.colorPicker = this.convertValues(source["colorPicker"], ColorConfig);
+	        this.backgroundImages = source["backgroundImages"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+	
+	
+	
+	
+	
+	
+	
+	
+	
+
+}
+
+export namespace hotkeys {
+	
+	export class Registration {
+	    id: number;
+	    keys: string;
+	    status: string;
+	    error?: string;
+	    suggestions?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Registration(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.keys = source["keys"];
+	        this.status = source["status"];
+	        this.error = source["error"];
+	        this.suggestions = source["suggestions"];
+	    }
+	}
+
+}
+
+export namespace imagediff {
+	
+	export class Options {
+	    tolerance: number;
+	    maxOffset: number;
+	    mergeDistance: number;
+	    onionOpacity: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tolerance = source["tolerance"];
+	        this.maxOffset = source["maxOffset"];
+	        this.mergeDistance = source["mergeDistance"];
+	        this.onionOpacity = source["onionOpacity"];
+	    }
+	}
+	export class Rect {
+	    x: number;
+	    y: number;
+	    width: number;
+	    height: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Rect(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	    }
+	}
+	export class Result {
+	    width: number;
+	    height: number;
+	    offsetX: number;
+	    offsetY: number;
+	    changedPixels: number;
+	    totalPixels: number;
+	    similarity: number;
+	    regions: Rect[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.offsetX = source["offsetX"];
+	        this.offsetY = source["offsetY"];
+	        this.changedPixels = source["changedPixels"];
+	        this.totalPixels = source["totalPixels"];
+	        this.similarity = source["similarity"];
+	        this.regions = this.convertValues(source["regions"], Rect);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace library {
+	
+	export class CaptureSource {
+	    mode?: string;
+	    windowTitle?: string;
+	    windowClass?: string;
+	    processName?: string;
+	    monitor?: number;
+	    dpiScale?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.windowTitle = source["windowTitle"];
+	        this.windowClass = source["windowClass"];
+	        this.processName = source["processName"];
+	        this.monitor = source["monitor"];
+	        this.dpiScale = source["dpiScale"];
+	    }
+	}
+	export class SearchFilters {
+	    from?: string;
+	    to?: string;
+	    tags?: string[];
+	    mode?: string;
+	    process?: string;
+	    favoritesOnly?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchFilters(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.tags = source["tags"];
+	        this.mode = source["mode"];
+	        this.process = source["process"];
+	        this.favoritesOnly = source["favoritesOnly"];
+	    }
+	}
+	export class Collection {
+	    name: string;
+	    query?: string;
+	    filters: SearchFilters;
+	    sort?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Collection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.query = source["query"];
+	        this.filters = this.convertValues(source["filters"], SearchFilters);
+	        this.sort = source["sort"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ConvertOptions {
+	    format: string;
+	    quality: number;
+	    maxWidth: number;
+	    maxHeight: number;
+	    folder: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConvertOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.quality = source["quality"];
+	        this.maxWidth = source["maxWidth"];
+	        this.maxHeight = source["maxHeight"];
+	        this.folder = source["folder"];
+	    }
+	}
+	export class LibraryImage {
+	    filepath: string;
+	    filename: string;
+	    modifiedDate: string;
+	    thumbnail: string;
+	    width: number;
+	    height: number;
+	    tags?: string[];
+	    source: CaptureSource;
+	    uploadUrls?: string[];
+	    favorite?: boolean;
+	    ocrText?: string;
+	    metadata?: metadata.Metadata;
+	
+	    static createFrom(source: any = {}) {
+	        return new LibraryImage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filepath = source["filepath"];
+	        this.filename = source["filename"];
+	        this.modifiedDate = source["modifiedDate"];
+	        this.thumbnail = source["thumbnail"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.tags = source["tags"];
+	        this.source = this.convertValues(source["source"], CaptureSource);
+	        this.uploadUrls = source["uploadUrls"];
+	        this.favorite = source["favorite"];
+	        this.ocrText = source["ocrText"];
+	        this.metadata = this.convertValues(source["metadata"], metadata.Metadata);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DuplicateGroup {
+	    images: LibraryImage[];
+	    maxDistance: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DuplicateGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.images = this.convertValues(source["images"], LibraryImage);
+	        this.maxDistance = source["maxDistance"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class IndexEntry {
+	    path: string;
+	    filename: string;
+	    size: number;
+	    // Go type: time
+	    modTime: any;
+	    width: number;
+	    height: number;
+	    source: CaptureSource;
+	    tags?: string[];
+	    uploadUrls?: string[];
+	    favorite?: boolean;
+	    ocrText?: string;
+	    hash?: string;
+	    contentHash?: string;
+	    metadata?: metadata.Metadata;
+	
+	    static createFrom(source: any = {}) {
+	        return new IndexEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.filename = source["filename"];
+	        this.size = source["size"];
+	        this.modTime = this.convertValues(source["modTime"], null);
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.source = this.convertValues(source["source"], CaptureSource);
+	        this.tags = source["tags"];
+	        this.uploadUrls = source["uploadUrls"];
+	        this.favorite = source["favorite"];
+	        this.ocrText = source["ocrText"];
+	        this.hash = source["hash"];
+	        this.contentHash = source["contentHash"];
+	        this.metadata = this.convertValues(source["metadata"], metadata.Metadata);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class RetentionCandidate {
+	    path: string;
+	    filename: string;
+	    size: number;
+	    // Go type: time
+	    modTime: any;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RetentionCandidate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.filename = source["filename"];
+	        this.size = source["size"];
+	        this.modTime = this.convertValues(source["modTime"], null);
+	        this.reason = source["reason"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RetentionPlan {
+	    remove: RetentionCandidate[];
+	    kept: number;
+	    exempt: number;
+	    totalSize: number;
+	    freedSize: number;
+	    overLimits: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RetentionPlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.remove = this.convertValues(source["remove"], RetentionCandidate);
+	        this.kept = source["kept"];
+	        this.exempt = source["exempt"];
+	        this.totalSize = source["totalSize"];
+	        this.freedSize = source["freedSize"];
+	        this.overLimits = source["overLimits"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SearchResult {
+	    images: LibraryImage[];
+	    total: number;
+	    page: number;
+	    pageSize: number;
+	    totalPages: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new HotkeyConfig(source);
+	        return new SearchResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.fullscreen = source["fullscreen"];
-	        this.region = source["region"];
-	        this.window = source["window"];
+	        this.images = this.convertValues(source["images"], LibraryImage);
+	        this.total = source["total"];
+	        this.page = source["page"];
+	        this.pageSize = source["pageSize"];
+	        this.totalPages = source["totalPages"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class Config {
-	    hotkeys: HotkeyConfig;
-	    startup: StartupConfig;
-	    quickSave: QuickSaveConfig;
-	    export: ExportConfig;
-	    window: WindowConfig;
-	    editor: EditorConfig;
-	    update: UpdateConfig;
-	    cloud?: CloudConfig;
-	    backgroundImages?: string[];
+	export class TrashItem {
+	    id: string;
+	    filename: string;
+	    originalPath: string;
+	    trashPath: string;
+	    // Go type: time
+	    deletedAt: any;
+	    size: number;
+	    entry?: IndexEntry;
+	    thumbnail?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Config(source);
+	        return new TrashItem(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.hotkeys = this.convertValues(source["hotkeys"], HotkeyConfig);
-	        this.startup = this.convertValues(source["startup"], StartupConfig);
-	        this.quickSave = this.convertValues(source["quickSave"], QuickSaveConfig);
-	        this.export = this.convertValues(source["export"], ExportConfig);
-	        this.window = this.convertValues(source["window"], WindowConfig);
-	        this.editor = this.convertValues(source["editor"], EditorConfig);
-	        this.update = this.convertValues(source["update"], UpdateConfig);
-	        this.cloud = this.convertValues(source["cloud"], CloudConfig);
-	        this.backgroundImages = source["backgroundImages"];
+	        this.id = source["id"];
+	        this.filename = source["filename"];
+	        this.originalPath = source["originalPath"];
+	        this.trashPath = source["trashPath"];
+	        this.deletedAt = this.convertValues(source["deletedAt"], null);
+	        this.size = source["size"];
+	        this.entry = this.convertValues(source["entry"], IndexEntry);
+	        this.thumbnail = source["thumbnail"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -242,46 +947,47 @@ export namespace config {
 		    return a;
 		}
 	}
-	
-	
-	
-	
-	
-	
-	
-	
 
 }
 
-export namespace library {
+export namespace main {
 	
-	export class LibraryImage {
-	    filepath: string;
-	    filename: string;
-	    modifiedDate: string;
-	    thumbnail: string;
-	    width: number;
-	    height: number;
+	export class CompareResult {
+	    diff: imagediff.Result;
+	    overlay: string;
+	    sideBySide: string;
+	    onionSkin: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new LibraryImage(source);
+	        return new CompareResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.filepath = source["filepath"];
-	        this.filename = source["filename"];
-	        this.modifiedDate = source["modifiedDate"];
-	        this.thumbnail = source["thumbnail"];
-	        this.width = source["width"];
-	        this.height = source["height"];
+	        this.diff = this.convertValues(source["diff"], imagediff.Result);
+	        this.overlay = source["overlay"];
+	        this.sideBySide = source["sideBySide"];
+	        this.onionSkin = source["onionSkin"];
 	    }
-	}
-
-}
-
-export namespace main {
 	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class DisplayBounds {
 	    x: number;
 	    y: number;
@@ -314,20 +1020,46 @@ export namespace main {
 	        this.email = source["email"];
 	    }
 	}
-	export class HotkeyConfig {
-	    fullscreen: string;
-	    region: string;
-	    window: string;
+	export class Measurement {
+	    width: number;
+	    height: number;
+	    distance: number;
+	    angle: number;
+	    text: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new HotkeyConfig(source);
+	        return new Measurement(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.fullscreen = source["fullscreen"];
-	        this.region = source["region"];
-	        this.window = source["window"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.distance = source["distance"];
+	        this.angle = source["angle"];
+	        this.text = source["text"];
+	    }
+	}
+	export class PickedColor {
+	    hex: string;
+	    rgb: string;
+	    hsl: string;
+	    copied: string;
+	    x: number;
+	    y: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PickedColor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hex = source["hex"];
+	        this.rgb = source["rgb"];
+	        this.hsl = source["hsl"];
+	        this.copied = source["copied"];
+	        this.x = source["x"];
+	        this.y = source["y"];
 	    }
 	}
 	export class RegionCaptureData {
@@ -380,6 +1112,7 @@ export namespace main {
 	    success: boolean;
 	    filePath: string;
 	    error?: string;
+	    duplicateOf?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new SaveImageResult(source);
@@ -390,6 +1123,7 @@ export namespace main {
 	        this.success = source["success"];
 	        this.filePath = source["filePath"];
 	        this.error = source["error"];
+	        this.duplicateOf = source["duplicateOf"];
 	    }
 	}
 	export class VirtualScreenBounds {
@@ -413,12 +1147,157 @@ export namespace main {
 
 }
 
+export namespace metadata {
+	
+	export class Metadata {
+	    captureMode?: string;
+	    // Go type: time
+	    capturedAt: any;
+	    windowTitle?: string;
+	    windowClass?: string;
+	    processName?: string;
+	    monitor?: number;
+	    dpiScale?: number;
+	    appVersion?: string;
+	    author?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Metadata(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.captureMode = source["captureMode"];
+	        this.capturedAt = this.convertValues(source["capturedAt"], null);
+	        this.windowTitle = source["windowTitle"];
+	        this.windowClass = source["windowClass"];
+	        this.processName = source["processName"];
+	        this.monitor = source["monitor"];
+	        this.dpiScale = source["dpiScale"];
+	        this.appVersion = source["appVersion"];
+	        this.author = source["author"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace ocr {
+	
+	export class Word {
+	    text: string;
+	    x: number;
+	    y: number;
+	    width: number;
+	    height: number;
+	    confidence: number;
+	    line: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Word(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.confidence = source["confidence"];
+	        this.line = source["line"];
+	    }
+	}
+	export class Result {
+	    text: string;
+	    words: Word[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.words = this.convertValues(source["words"], Word);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace overlay {
+	
+	export class PinInfo {
+	    id: number;
+	    x: number;
+	    y: number;
+	    width: number;
+	    height: number;
+	    zoom: number;
+	    opacity: number;
+	    clickThrough: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PinInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.zoom = source["zoom"];
+	        this.opacity = source["opacity"];
+	        this.clickThrough = source["clickThrough"];
+	    }
+	}
+
+}
+
 export namespace screenshot {
 	
 	export class CaptureResult {
 	    width: number;
 	    height: number;
 	    data: string;
+	    mimeType?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new CaptureResult(source);
@@ -429,6 +1308,7 @@ export namespace screenshot {
 	        this.width = source["width"];
 	        this.height = source["height"];
 	        this.data = source["data"];
+	        this.mimeType = source["mimeType"];
 	    }
 	}
 
@@ -539,3 +1419,62 @@ export namespace windows {
 
 }
 
+export namespace workflow {
+	
+	export class StepResult {
+	    type: string;
+	    output?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StepResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.output = source["output"];
+	        this.error = source["error"];
+	    }
+	}
+	export class Result {
+	    steps: StepResult[];
+	    path?: string;
+	    url?: string;
+	    handoff?: boolean;
+	    failures?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.steps = this.convertValues(source["steps"], StepResult);
+	        this.path = source["path"];
+	        this.url = source["url"];
+	        this.handoff = source["handoff"];
+	        this.failures = source["failures"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
@@ -0,0 +1,175 @@
+// Package batch runs cancellable multi-file jobs with progress reporting
+package batch
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+)
+
+// Job states reported in Progress.State
+const (
+	StateRunning   = "running"
+	StateCompleted = "completed"
+	StateCancelled = "cancelled"
+	StateFailed    = "failed" // Finish step failed; per-item errors are in Results
+)
+
+// Task processes one item and returns its output (e.g. a new path or a URL)
+type Task func(ctx context.Context, item string) (string, error)
+
+// Finish runs after the last item (or on cancel) and returns the job's overall output,
+// e.g. the path of a zip. completed is false when the job was cancelled.
+type Finish func(completed bool) (string, error)
+
+// ItemResult is the outcome of one item
+type ItemResult struct {
+	Item   string `json:"item"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Progress is reported after every item and once more when the job ends
+type Progress struct {
+	JobID     string       `json:"jobId"`
+	Kind      string       `json:"kind"`
+	State     string       `json:"state"`
+	Total     int          `json:"total"`
+	Completed int          `json:"completed"` // Items processed, including failures
+	Failed    int          `json:"failed"`
+	Current   string       `json:"current,omitempty"` // Item just processed
+	Output    string       `json:"output,omitempty"`  // Result of Finish, set when the job ends
+	Error     string       `json:"error,omitempty"`
+	Results   []ItemResult `json:"results,omitempty"` // Set when the job ends
+}
+
+// Manager runs jobs in the background and tracks them for cancellation
+type Manager struct {
+	onProgress func(Progress)
+	jobs       map[string]context.CancelFunc
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+}
+
+// NewManager creates a job manager. onProgress is called from job goroutines.
+func NewManager(onProgress func(Progress)) *Manager {
+	return &Manager{
+		onProgress: onProgress,
+		jobs:       make(map[string]context.CancelFunc),
+	}
+}
+
+// Start runs task for each item in order in a new background job and returns its ID.
+// finish may be nil.
+func (m *Manager) Start(kind string, items []string, task Task, finish Finish) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	id := newJobID()
+
+	m.mu.Lock()
+	m.jobs[id] = cancel
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() {
+			m.mu.Lock()
+			delete(m.jobs, id)
+			m.mu.Unlock()
+			cancel()
+		}()
+		m.run(ctx, id, kind, items, task, finish)
+	}()
+
+	return id
+}
+
+// run processes items and reports progress
+func (m *Manager) run(ctx context.Context, id, kind string, items []string, task Task, finish Finish) {
+	progress := Progress{
+		JobID: id,
+		Kind:  kind,
+		State: StateRunning,
+		Total: len(items),
+	}
+	results := make([]ItemResult, 0, len(items))
+	m.report(progress)
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+
+		output, err := task(ctx, item)
+		result := ItemResult{Item: item, Output: output}
+		if err != nil {
+			if ctx.Err() != nil {
+				break // Interrupted by cancel - not a failure of the item
+			}
+			result.Error = err.Error()
+			progress.Failed++
+		}
+		results = append(results, result)
+
+		progress.Completed++
+		progress.Current = item
+		m.report(progress)
+	}
+
+	completed := ctx.Err() == nil
+	progress.State = StateCompleted
+	if !completed {
+		progress.State = StateCancelled
+	}
+
+	if finish != nil {
+		output, err := finish(completed)
+		progress.Output = output
+		if err != nil {
+			progress.State = StateFailed
+			progress.Error = err.Error()
+		}
+	}
+
+	progress.Current = ""
+	progress.Results = results
+	m.report(progress)
+}
+
+// report forwards progress to the callback
+func (m *Manager) report(progress Progress) {
+	if m.onProgress != nil {
+		m.onProgress(progress)
+	}
+}
+
+// Cancel stops a running job after its current item. Returns false if no such job is running.
+func (m *Manager) Cancel(id string) bool {
+	m.mu.Lock()
+	cancel, ok := m.jobs[id]
+	m.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// CancelAll cancels every running job and waits for them to end
+func (m *Manager) CancelAll() {
+	m.mu.Lock()
+	for _, cancel := range m.jobs {
+		cancel()
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// newJobID returns a random job identifier
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
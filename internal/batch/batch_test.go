@@ -0,0 +1,143 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector records progress reports and signals when a job ends
+type collector struct {
+	mu      sync.Mutex
+	reports []Progress
+	done    chan Progress
+}
+
+func newCollector() *collector {
+	return &collector{done: make(chan Progress, 1)}
+}
+
+func (c *collector) onProgress(p Progress) {
+	c.mu.Lock()
+	c.reports = append(c.reports, p)
+	c.mu.Unlock()
+	if p.State != StateRunning {
+		c.done <- p
+	}
+}
+
+func (c *collector) wait(t *testing.T) Progress {
+	t.Helper()
+	select {
+	case p := <-c.done:
+		return p
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for job to finish")
+		return Progress{}
+	}
+}
+
+func TestManager_RunsItemsAndReportsProgress(t *testing.T) {
+	c := newCollector()
+	m := NewManager(c.onProgress)
+
+	task := func(ctx context.Context, item string) (string, error) {
+		if item == "bad" {
+			return "", errors.New("boom")
+		}
+		return item + ".out", nil
+	}
+	var finishedCompleted bool
+	finish := func(completed bool) (string, error) {
+		finishedCompleted = completed
+		return "archive.zip", nil
+	}
+
+	id := m.Start("zip", []string{"a", "bad", "c"}, task, finish)
+	final := c.wait(t)
+
+	if final.JobID != id || final.Kind != "zip" {
+		t.Errorf("final = %+v, want job %s of kind zip", final, id)
+	}
+	if final.State != StateCompleted || !finishedCompleted {
+		t.Errorf("State = %s, finish completed = %v, want completed", final.State, finishedCompleted)
+	}
+	if final.Total != 3 || final.Completed != 3 || final.Failed != 1 {
+		t.Errorf("Total/Completed/Failed = %d/%d/%d, want 3/3/1", final.Total, final.Completed, final.Failed)
+	}
+	if final.Output != "archive.zip" {
+		t.Errorf("Output = %q, want archive.zip", final.Output)
+	}
+	if len(final.Results) != 3 || final.Results[0].Output != "a.out" || final.Results[1].Error != "boom" {
+		t.Errorf("Results = %+v", final.Results)
+	}
+
+	// Start report, one per item, and the final report
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.reports) != 5 {
+		t.Errorf("got %d progress reports, want 5", len(c.reports))
+	}
+	for i, p := range c.reports[:4] {
+		if p.Completed != i {
+			t.Errorf("report %d Completed = %d, want %d", i, p.Completed, i)
+		}
+	}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	c := newCollector()
+	m := NewManager(c.onProgress)
+
+	started := make(chan struct{})
+	task := func(ctx context.Context, item string) (string, error) {
+		if item == "block" {
+			close(started)
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return item, nil
+	}
+	var finishedCompleted = true
+	finish := func(completed bool) (string, error) {
+		finishedCompleted = completed
+		return "", nil
+	}
+
+	id := m.Start("copy", []string{"a", "block", "never"}, task, finish)
+	<-started
+	if !m.Cancel(id) {
+		t.Fatal("Cancel() = false for a running job")
+	}
+
+	final := c.wait(t)
+	if final.State != StateCancelled || finishedCompleted {
+		t.Errorf("State = %s, finish completed = %v, want cancelled", final.State, finishedCompleted)
+	}
+	if final.Completed != 1 || len(final.Results) != 1 {
+		t.Errorf("Completed = %d, Results = %d, want 1 item processed", final.Completed, len(final.Results))
+	}
+
+	m.CancelAll()
+	if m.Cancel(id) {
+		t.Error("Cancel() = true for a finished job")
+	}
+}
+
+func TestManager_FinishError(t *testing.T) {
+	c := newCollector()
+	m := NewManager(c.onProgress)
+
+	m.Start("zip", []string{"a"}, func(ctx context.Context, item string) (string, error) {
+		return "", nil
+	}, func(completed bool) (string, error) {
+		return "", errors.New("disk full")
+	})
+
+	final := c.wait(t)
+	if final.State != StateFailed || final.Error != "disk full" {
+		t.Errorf("final = %+v, want failed with disk full", final)
+	}
+}
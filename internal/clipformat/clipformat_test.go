@@ -0,0 +1,164 @@
+package clipformat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// testImage is 3x2: top row red, green, transparent; bottom row blue, half-transparent black, white
+func testImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255})
+	img.SetNRGBA(1, 0, color.NRGBA{0, 255, 0, 255})
+	img.SetNRGBA(2, 0, color.NRGBA{0, 0, 0, 0})
+	img.SetNRGBA(0, 1, color.NRGBA{0, 0, 255, 255})
+	img.SetNRGBA(1, 1, color.NRGBA{0, 0, 0, 128})
+	img.SetNRGBA(2, 1, color.NRGBA{255, 255, 255, 255})
+	return img
+}
+
+func TestEncodeDIB(t *testing.T) {
+	data := EncodeDIB(testImage())
+
+	rowSize := 12 // 3 pixels * 3 bytes = 9, padded to 12
+	if len(data) != bitmapInfoHeaderSize+rowSize*2 {
+		t.Fatalf("len = %d, want %d", len(data), bitmapInfoHeaderSize+rowSize*2)
+	}
+	le := binary.LittleEndian
+	if got := le.Uint32(data[0:]); got != bitmapInfoHeaderSize {
+		t.Errorf("biSize = %d", got)
+	}
+	if w, h := int32(le.Uint32(data[4:])), int32(le.Uint32(data[8:])); w != 3 || h != 2 {
+		t.Errorf("size = %dx%d, want 3x2 (bottom-up)", w, h)
+	}
+	if bits := le.Uint16(data[14:]); bits != 24 {
+		t.Errorf("biBitCount = %d, want 24", bits)
+	}
+
+	// Rows are bottom-up BGR; alpha is composited over white
+	pixels := data[bitmapInfoHeaderSize:]
+	want := []struct {
+		row, x  int
+		b, g, r byte
+	}{
+		{0, 0, 255, 0, 0},     // Bottom row: blue
+		{0, 1, 127, 127, 127}, // Half-transparent black over white
+		{0, 2, 255, 255, 255}, // White
+		{1, 0, 0, 0, 255},     // Top row: red
+		{1, 1, 0, 255, 0},     // Green
+		{1, 2, 255, 255, 255}, // Transparent becomes white
+	}
+	for _, p := range want {
+		px := pixels[p.row*rowSize+p.x*3:]
+		if px[0] != p.b || px[1] != p.g || px[2] != p.r {
+			t.Errorf("row %d x %d = %v, want BGR %d,%d,%d", p.row, p.x, px[:3], p.b, p.g, p.r)
+		}
+	}
+}
+
+func TestEncodeDIBV5(t *testing.T) {
+	data := EncodeDIBV5(testImage())
+
+	if len(data) != bitmapV5HeaderSize+3*4*2 {
+		t.Fatalf("len = %d, want %d", len(data), bitmapV5HeaderSize+3*4*2)
+	}
+	le := binary.LittleEndian
+	checks := []struct {
+		name   string
+		offset int
+		want   uint32
+	}{
+		{"bV5Size", 0, bitmapV5HeaderSize},
+		{"bV5Compression", 16, biBitfields},
+		{"bV5RedMask", 40, 0x00FF0000},
+		{"bV5GreenMask", 44, 0x0000FF00},
+		{"bV5BlueMask", 48, 0x000000FF},
+		{"bV5AlphaMask", 52, 0xFF000000},
+		{"bV5CSType", 56, lcsSRGB},
+		{"bV5Intent", 108, lcsGMImages},
+	}
+	for _, c := range checks {
+		if got := le.Uint32(data[c.offset:]); got != c.want {
+			t.Errorf("%s = %#x, want %#x", c.name, got, c.want)
+		}
+	}
+	if bits := le.Uint16(data[14:]); bits != 32 {
+		t.Errorf("bV5BitCount = %d, want 32", bits)
+	}
+
+	// Bottom-up BGRA with straight alpha
+	pixels := data[bitmapV5HeaderSize:]
+	if got := pixels[4:8]; got[0] != 0 || got[1] != 0 || got[2] != 0 || got[3] != 128 {
+		t.Errorf("half-transparent pixel = %v, want [0 0 0 128]", got)
+	}
+	if got := pixels[12:16]; got[0] != 0 || got[1] != 0 || got[2] != 255 || got[3] != 255 {
+		t.Errorf("top-left pixel = %v, want red [0 0 255 255]", got)
+	}
+	if got := pixels[20:24]; got[3] != 0 {
+		t.Errorf("transparent pixel alpha = %d, want 0", got[3])
+	}
+}
+
+func TestEncodeDropFiles(t *testing.T) {
+	paths := []string{`C:\Shots\a.png`, `C:\Shots\é b.jpg`}
+	data := EncodeDropFiles(paths)
+
+	le := binary.LittleEndian
+	if got := le.Uint32(data[0:]); got != dropFilesHeaderSize {
+		t.Errorf("pFiles = %d, want %d", got, dropFilesHeaderSize)
+	}
+	if got := le.Uint32(data[16:]); got != 1 {
+		t.Errorf("fWide = %d, want 1", got)
+	}
+
+	units := make([]uint16, (len(data)-dropFilesHeaderSize)/2)
+	for i := range units {
+		units[i] = le.Uint16(data[dropFilesHeaderSize+i*2:])
+	}
+	if units[len(units)-1] != 0 || units[len(units)-2] != 0 {
+		t.Fatal("file list is not double-NUL terminated")
+	}
+	got := strings.Split(string(utf16.Decode(units[:len(units)-2])), "\x00")
+	if len(got) != 2 || got[0] != paths[0] || got[1] != paths[1] {
+		t.Errorf("paths = %q, want %q", got, paths)
+	}
+}
+
+func TestEncodeHTML(t *testing.T) {
+	fragment := ImageFragment(FileURL(`C:\My Shots\shot #1.png`), 640, 480, `Build "failed"`)
+	data := string(EncodeHTML(fragment, "https://example.com/"))
+
+	offsets := map[string]int{}
+	for _, key := range []string{"StartHTML", "EndHTML", "StartFragment", "EndFragment"} {
+		var v int
+		i := strings.Index(data, key+":")
+		if i < 0 {
+			t.Fatalf("missing %s", key)
+		}
+		if _, err := fmt.Sscanf(data[i+len(key)+1:], "%d", &v); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		offsets[key] = v
+	}
+
+	if got := data[offsets["StartFragment"]:offsets["EndFragment"]]; got != fragment {
+		t.Errorf("fragment = %q, want %q", got, fragment)
+	}
+	if !strings.HasPrefix(data[offsets["StartHTML"]:], "<html>") || offsets["EndHTML"] != len(data) {
+		t.Errorf("StartHTML/EndHTML don't frame the document: %v (len %d)", offsets, len(data))
+	}
+	if !strings.Contains(data, "SourceURL:https://example.com/\r\n") {
+		t.Error("missing SourceURL")
+	}
+	if want := `src="file:///C:/My%20Shots/shot%20%231.png"`; !strings.Contains(fragment, want) {
+		t.Errorf("fragment %q does not contain %q", fragment, want)
+	}
+	if !strings.Contains(fragment, `alt="Build &#34;failed&#34;"`) {
+		t.Errorf("alt not escaped: %q", fragment)
+	}
+}
@@ -0,0 +1,421 @@
+package clipformat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// dibFixture builds a packed DIB. rows are listed top to bottom without padding;
+// a positive height stores them bottom-up as Windows does by default.
+type dibFixture struct {
+	headerSize  int
+	width       int
+	height      int // Negative = top-down
+	bitCount    int
+	compression uint32
+	colorsUsed  int
+	headerMasks [4]uint32 // V4/V5 header masks
+	extra       []byte    // Masks and/or palette after the header
+	rows        [][]byte
+}
+
+func (f dibFixture) bytes() []byte {
+	header := make([]byte, f.headerSize)
+	le := binary.LittleEndian
+	le.PutUint32(header[0:], uint32(f.headerSize))
+	le.PutUint32(header[4:], uint32(int32(f.width)))
+	le.PutUint32(header[8:], uint32(int32(f.height)))
+	le.PutUint16(header[12:], 1)
+	le.PutUint16(header[14:], uint16(f.bitCount))
+	le.PutUint32(header[16:], f.compression)
+	le.PutUint32(header[32:], uint32(f.colorsUsed))
+	if f.headerSize >= 56 {
+		for i, m := range f.headerMasks {
+			le.PutUint32(header[40+i*4:], m)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.Write(header)
+	buf.Write(f.extra)
+
+	rowSize := ((f.width*f.bitCount + 31) / 32) * 4
+	rows := f.rows
+	if f.height > 0 {
+		rows = make([][]byte, len(f.rows))
+		for i := range f.rows {
+			rows[len(rows)-1-i] = f.rows[i]
+		}
+	}
+	for _, row := range rows {
+		padded := make([]byte, rowSize)
+		copy(padded, row)
+		buf.Write(padded)
+	}
+	return buf.Bytes()
+}
+
+func u32s(values ...uint32) []byte {
+	b := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(b[i*4:], v)
+	}
+	return b
+}
+
+func u16s(values ...uint16) []byte {
+	b := make([]byte, 2*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(b[i*2:], v)
+	}
+	return b
+}
+
+// palette builds RGBQUAD entries from RGB colors
+func palette(colors ...color.NRGBA) []byte {
+	var b []byte
+	for _, c := range colors {
+		b = append(b, c.B, c.G, c.R, 0)
+	}
+	return b
+}
+
+var (
+	red   = color.NRGBA{255, 0, 0, 255}
+	green = color.NRGBA{0, 255, 0, 255}
+	blue  = color.NRGBA{0, 0, 255, 255}
+	white = color.NRGBA{255, 255, 255, 255}
+	black = color.NRGBA{0, 0, 0, 255}
+)
+
+func TestDecodeDIB(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		width   int
+		height  int
+		samples map[image.Point]color.NRGBA
+	}{
+		{
+			name: "1-bit palette with row padding",
+			data: dibFixture{
+				headerSize: 40, width: 10, height: 2, bitCount: 1,
+				extra: palette(black, white),
+				rows:  [][]byte{{0b10100000, 0b01000000}, {0xFF, 0xC0}},
+			}.bytes(),
+			width: 10, height: 2,
+			samples: map[image.Point]color.NRGBA{{0, 0}: white, {1, 0}: black, {2, 0}: white, {9, 0}: white, {8, 0}: black, {5, 1}: white},
+		},
+		{
+			name: "4-bit palette with ClrUsed",
+			data: dibFixture{
+				headerSize: 40, width: 3, height: 1, bitCount: 4, colorsUsed: 3,
+				extra: palette(red, green, blue),
+				rows:  [][]byte{{0x01, 0x20}},
+			}.bytes(),
+			width: 3, height: 1,
+			samples: map[image.Point]color.NRGBA{{0, 0}: red, {1, 0}: green, {2, 0}: blue},
+		},
+		{
+			name: "8-bit palette top-down",
+			data: dibFixture{
+				headerSize: 40, width: 2, height: -2, bitCount: 8, colorsUsed: 4,
+				extra: palette(red, green, blue, white),
+				rows:  [][]byte{{0, 1}, {2, 3}},
+			}.bytes(),
+			width: 2, height: 2,
+			samples: map[image.Point]color.NRGBA{{0, 0}: red, {1, 0}: green, {0, 1}: blue, {1, 1}: white},
+		},
+		{
+			name: "16-bit 5-5-5",
+			data: dibFixture{
+				headerSize: 40, width: 2, height: 1, bitCount: 16,
+				rows: [][]byte{u16s(0x7C00, 0x001F)},
+			}.bytes(),
+			width: 2, height: 1,
+			samples: map[image.Point]color.NRGBA{{0, 0}: red, {1, 0}: blue},
+		},
+		{
+			name: "16-bit 5-6-5 bitfields",
+			data: dibFixture{
+				headerSize: 40, width: 2, height: 1, bitCount: 16, compression: biBitfields,
+				extra: u32s(0xF800, 0x07E0, 0x001F),
+				rows:  [][]byte{u16s(0x07E0, 0xFFFF)},
+			}.bytes(),
+			width: 2, height: 1,
+			samples: map[image.Point]color.NRGBA{{0, 0}: green, {1, 0}: white},
+		},
+		{
+			name: "32-bit BI_RGB with zero alpha is opaque",
+			data: dibFixture{
+				headerSize: 40, width: 2, height: 1, bitCount: 32,
+				rows: [][]byte{{0, 0, 255, 0, 255, 0, 0, 0}},
+			}.bytes(),
+			width: 2, height: 1,
+			samples: map[image.Point]color.NRGBA{{0, 0}: red, {1, 0}: blue},
+		},
+		{
+			name: "32-bit BI_RGB with alpha",
+			data: dibFixture{
+				headerSize: 40, width: 2, height: 1, bitCount: 32,
+				rows: [][]byte{{0, 0, 255, 255, 255, 0, 0, 64}},
+			}.bytes(),
+			width: 2, height: 1,
+			samples: map[image.Point]color.NRGBA{{0, 0}: red, {1, 0}: {0, 0, 255, 64}},
+		},
+		{
+			name: "32-bit bitfields RGBA order",
+			data: dibFixture{
+				headerSize: 40, width: 1, height: 1, bitCount: 32, compression: biAlphaBitfields,
+				extra: u32s(0x000000FF, 0x0000FF00, 0x00FF0000, 0xFF000000),
+				rows:  [][]byte{{255, 0, 0, 128}},
+			}.bytes(),
+			width: 1, height: 1,
+			samples: map[image.Point]color.NRGBA{{0, 0}: {255, 0, 0, 128}},
+		},
+		{
+			name: "V5 with masks repeated after the header",
+			data: func() []byte {
+				f := dibFixture{
+					headerSize: 124, width: 1, height: 1, bitCount: 32, compression: biBitfields,
+					headerMasks: [4]uint32{0x00FF0000, 0x0000FF00, 0x000000FF, 0xFF000000},
+					extra:       u32s(0x00FF0000, 0x0000FF00, 0x000000FF),
+					rows:        [][]byte{{0, 255, 0, 200}},
+				}
+				return f.bytes()
+			}(),
+			width: 1, height: 1,
+			samples: map[image.Point]color.NRGBA{{0, 0}: {0, 255, 0, 200}},
+		},
+		{
+			name: "core header 24-bit",
+			data: func() []byte {
+				header := append(u32s(12), u16s(2, 1, 1, 24)...)
+				return append(header, 0, 0, 255, 255, 255, 255, 0, 0)
+			}(),
+			width: 2, height: 1,
+			samples: map[image.Point]color.NRGBA{{0, 0}: red, {1, 0}: white},
+		},
+		{
+			name: "core header 1-bit RGBTRIPLE palette",
+			data: func() []byte {
+				header := append(u32s(12), u16s(2, 1, 1, 1)...)
+				header = append(header, 0, 0, 255, 0, 255, 0) // red, green
+				return append(header, 0b01000000, 0, 0, 0)
+			}(),
+			width: 2, height: 1,
+			samples: map[image.Point]color.NRGBA{{0, 0}: red, {1, 0}: green},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := DecodeDIB(tt.data)
+			if err != nil {
+				t.Fatalf("DecodeDIB() error = %v", err)
+			}
+			if img.Bounds().Dx() != tt.width || img.Bounds().Dy() != tt.height {
+				t.Fatalf("size = %v, want %dx%d", img.Bounds().Size(), tt.width, tt.height)
+			}
+			for p, want := range tt.samples {
+				if got := img.NRGBAAt(p.X, p.Y); got != want {
+					t.Errorf("pixel %v = %v, want %v", p, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeDIBRoundTrip(t *testing.T) {
+	src := testImage()
+
+	v5, err := DecodeDIB(EncodeDIBV5(src))
+	if err != nil {
+		t.Fatalf("DecodeDIB(V5) error = %v", err)
+	}
+	if !bytes.Equal(v5.Pix, src.Pix) {
+		t.Errorf("DIBV5 round trip = %v, want %v", v5.Pix, src.Pix)
+	}
+
+	dib, err := DecodeDIB(EncodeDIB(src))
+	if err != nil {
+		t.Fatalf("DecodeDIB(DIB) error = %v", err)
+	}
+	if got := dib.NRGBAAt(0, 0); got != red {
+		t.Errorf("DIB top-left = %v, want red", got)
+	}
+	if got := dib.NRGBAAt(2, 0); got != white {
+		t.Errorf("DIB transparent pixel = %v, want white", got)
+	}
+}
+
+func TestDecodeDIBEmbeddedPNG(t *testing.T) {
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, testImage()); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	data := dibFixture{headerSize: 40, width: 3, height: 2, compression: biPNG}.bytes()
+	data = append(data, pngData.Bytes()...)
+
+	img, err := DecodeDIB(data)
+	if err != nil {
+		t.Fatalf("DecodeDIB() error = %v", err)
+	}
+	if got := img.NRGBAAt(1, 1); got != (color.NRGBA{0, 0, 0, 128}) {
+		t.Errorf("pixel = %v, want half-transparent black", got)
+	}
+}
+
+func TestDecodeDIBErrors(t *testing.T) {
+	valid := dibFixture{headerSize: 40, width: 4, height: 4, bitCount: 24, rows: make([][]byte, 4)}.bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated header", valid[:20]},
+		{"truncated pixels", valid[:len(valid)-1]},
+		{"unknown header size", append(u32s(64), valid[4:]...)},
+		{"zero width", dibFixture{headerSize: 40, width: 0, height: 1, bitCount: 24}.bytes()},
+		{"RLE8", dibFixture{headerSize: 40, width: 1, height: 1, bitCount: 8, compression: 1}.bytes()},
+		{"2-bit", dibFixture{headerSize: 40, width: 1, height: 1, bitCount: 2}.bytes()},
+		{"huge", dibFixture{headerSize: 40, width: 1 << 15, height: 1 << 15, bitCount: 24}.bytes()},
+		{"truncated palette", dibFixture{headerSize: 40, width: 1, height: 1, bitCount: 8}.bytes()[:60]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if img, err := DecodeDIB(tt.data); err == nil {
+				t.Errorf("DecodeDIB() = %v, want error", img.Bounds())
+			}
+		})
+	}
+}
+
+func TestDecodeDropFiles(t *testing.T) {
+	paths := []string{`C:\Shots\a.png`, `D:\Ünïcode\b.jpg`, `\\server\share\c.bmp`}
+	got, err := DecodeDropFiles(EncodeDropFiles(paths))
+	if err != nil {
+		t.Fatalf("DecodeDropFiles() error = %v", err)
+	}
+	if len(got) != len(paths) {
+		t.Fatalf("DecodeDropFiles() = %q, want %q", got, paths)
+	}
+	for i := range paths {
+		if got[i] != paths[i] {
+			t.Errorf("path %d = %q, want %q", i, got[i], paths[i])
+		}
+	}
+
+	// ANSI list
+	ansi := append(u32s(20, 0, 0, 0, 0), []byte("C:\\x.png\x00C:\\y.png\x00\x00")...)
+	if got, err := DecodeDropFiles(ansi); err != nil || len(got) != 2 || got[1] != `C:\y.png` {
+		t.Errorf("DecodeDropFiles(ansi) = %q, %v", got, err)
+	}
+
+	if _, err := DecodeDropFiles([]byte{1, 2, 3}); err == nil {
+		t.Error("DecodeDropFiles(truncated) want error")
+	}
+}
+
+func TestHTMLImageSources(t *testing.T) {
+	fragment := `<p>Chart</p><img alt="a" src="data:image/png;base64,AAAA"><IMG SRC='https://x.test/b.png?a=1&amp;b=2'> <img src=plain.png>`
+	data := EncodeHTML(fragment, "")
+	data = append([]byte{}, data...)
+
+	got := HTMLImageSources(data)
+	want := []string{"data:image/png;base64,AAAA", "https://x.test/b.png?a=1&b=2", "plain.png"}
+	if len(got) != len(want) {
+		t.Fatalf("HTMLImageSources() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("source %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	// Images outside the marked fragment are ignored
+	outside := bytes.Replace(data, []byte("<html><body>"), []byte(`<html><body><img src="outside.png">`), 1)
+	if got := HTMLImageSources(outside); len(got) != 0 && got[0] == "outside.png" {
+		t.Errorf("HTMLImageSources() included image outside fragment: %q", got)
+	}
+}
+
+func TestDecodeDataURI(t *testing.T) {
+	tests := []struct {
+		uri       string
+		wantData  string
+		wantMedia string
+		wantErr   bool
+	}{
+		{"data:image/png;base64,aGVsbG8=", "hello", "image/png", false},
+		{"data:image/svg+xml;charset=utf-8,%3Csvg%2F%3E", "<svg/>", "image/svg+xml", false},
+		{"data:IMAGE/JPEG;base64,aGV\nsbG8=", "hello", "image/jpeg", false},
+		{"data:,plain", "plain", "text/plain", false},
+		{"https://x.test/a.png", "", "", true},
+		{"data:image/png;base64", "", "", true},
+		{"data:image/png;base64,!!!", "", "", true},
+	}
+
+	for _, tt := range tests {
+		data, media, err := DecodeDataURI(tt.uri)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("DecodeDataURI(%q) error = %v, wantErr %v", tt.uri, err, tt.wantErr)
+			continue
+		}
+		if string(data) != tt.wantData || media != tt.wantMedia {
+			t.Errorf("DecodeDataURI(%q) = %q, %q, want %q, %q", tt.uri, data, media, tt.wantData, tt.wantMedia)
+		}
+	}
+}
+
+func TestSVGSize(t *testing.T) {
+	tests := []struct {
+		svg    string
+		w, h   int
+		wantOK bool
+	}{
+		{`<svg xmlns="http://www.w3.org/2000/svg" width="120" height="80"/>`, 120, 80, true},
+		{`<?xml version="1.0"?><svg width="64px" height="32.4px"></svg>`, 64, 32, true},
+		{`<svg viewBox="0 0 300 150"></svg>`, 300, 150, true},
+		{`<svg width="200" viewBox="0,0,100,50"></svg>`, 200, 100, true},
+		{`<svg width="100%" height="100%" viewBox="0 0 40 30"></svg>`, 40, 30, true},
+		{`<!-- comment --><svg width="10em"></svg>`, 0, 0, false},
+		{`<html><svg width="10" height="10"/></html>`, 0, 0, false},
+		{`not xml`, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		w, h, ok := SVGSize([]byte(tt.svg))
+		if ok != tt.wantOK || w != tt.w || h != tt.h {
+			t.Errorf("SVGSize(%q) = %d, %d, %v, want %d, %d, %v", tt.svg, w, h, ok, tt.w, tt.h, tt.wantOK)
+		}
+	}
+}
+
+func TestFilePathFromURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		want   string
+		wantOK bool
+	}{
+		{FileURL(`C:\Shots\my shot.png`), `C:\Shots\my shot.png`, true},
+		{"file:///D:/a%23b.jpg", `D:\a#b.jpg`, true},
+		{"file://server/share/c.png", `\\server\share\c.png`, true},
+		{"file://localhost/C:/x.png", `C:\x.png`, true},
+		{"https://x.test/a.png", "", false},
+		{"data:image/png;base64,AAAA", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := FilePathFromURL(tt.url)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("FilePathFromURL(%q) = %q, %v, want %q, %v", tt.url, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
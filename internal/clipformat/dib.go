@@ -0,0 +1,434 @@
+// Package clipformat encodes and decodes the Windows clipboard formats WinShot
+// exchanges with other applications: device-independent bitmaps (CF_DIB,
+// CF_DIBV5), file lists (CF_HDROP) and HTML fragments ("HTML Format").
+// It is pure Go so the byte layouts can be tested on any platform.
+package clipformat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg" // Register decoders for BI_JPEG / BI_PNG bitmaps
+	_ "image/png"
+	"math/bits"
+)
+
+// Bitmap header sizes and field values
+const (
+	bitmapInfoHeaderSize = 40
+	bitmapV5HeaderSize   = 124
+
+	bitmapCoreHeaderSize = 12
+
+	biRGB            = 0
+	biBitfields      = 3
+	biJPEG           = 4
+	biPNG            = 5
+	biAlphaBitfields = 6
+
+	lcsSRGB     = 0x73524742 // 'sRGB'
+	lcsGMImages = 4          // LCS_GM_IMAGES rendering intent
+
+	pixelsPerMeter = 3780 // 96 DPI
+)
+
+// bitmapInfoHeader mirrors BITMAPINFOHEADER
+type bitmapInfoHeader struct {
+	Size          uint32
+	Width         int32
+	Height        int32
+	Planes        uint16
+	BitCount      uint16
+	Compression   uint32
+	SizeImage     uint32
+	XPelsPerMeter int32
+	YPelsPerMeter int32
+	ClrUsed       uint32
+	ClrImportant  uint32
+}
+
+// bitmapV5Header mirrors BITMAPV5HEADER
+type bitmapV5Header struct {
+	bitmapInfoHeader
+	RedMask     uint32
+	GreenMask   uint32
+	BlueMask    uint32
+	AlphaMask   uint32
+	CSType      uint32
+	Endpoints   [9]int32 // CIEXYZTRIPLE, unused with sRGB
+	GammaRed    uint32
+	GammaGreen  uint32
+	GammaBlue   uint32
+	Intent      uint32
+	ProfileData uint32
+	ProfileSize uint32
+	Reserved    uint32
+}
+
+// EncodeDIB encodes img as a CF_DIB: a BITMAPINFOHEADER followed by bottom-up
+// 24-bit BGR rows. CF_DIB has no alpha, so transparent pixels are composited
+// over white.
+func EncodeDIB(img image.Image) []byte {
+	rgba := toNRGBA(img)
+	width, height := rgba.Rect.Dx(), rgba.Rect.Dy()
+	rowSize := (width*3 + 3) &^ 3
+
+	header := bitmapInfoHeader{
+		Size:          bitmapInfoHeaderSize,
+		Width:         int32(width),
+		Height:        int32(height), // Positive = bottom-up
+		Planes:        1,
+		BitCount:      24,
+		Compression:   biRGB,
+		SizeImage:     uint32(rowSize * height),
+		XPelsPerMeter: pixelsPerMeter,
+		YPelsPerMeter: pixelsPerMeter,
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(bitmapInfoHeaderSize + rowSize*height)
+	binary.Write(&buf, binary.LittleEndian, header)
+
+	row := make([]byte, rowSize)
+	for y := height - 1; y >= 0; y-- {
+		src := rgba.Pix[y*rgba.Stride:]
+		for x := 0; x < width; x++ {
+			r, g, b, a := src[x*4], src[x*4+1], src[x*4+2], src[x*4+3]
+			row[x*3+0] = overWhite(b, a)
+			row[x*3+1] = overWhite(g, a)
+			row[x*3+2] = overWhite(r, a)
+		}
+		buf.Write(row)
+	}
+	return buf.Bytes()
+}
+
+// EncodeDIBV5 encodes img as a CF_DIBV5: a BITMAPV5HEADER (sRGB, BI_BITFIELDS
+// with an alpha mask) followed by bottom-up 32-bit BGRA rows with straight
+// (non-premultiplied) alpha
+func EncodeDIBV5(img image.Image) []byte {
+	rgba := toNRGBA(img)
+	width, height := rgba.Rect.Dx(), rgba.Rect.Dy()
+	rowSize := width * 4
+
+	header := bitmapV5Header{
+		bitmapInfoHeader: bitmapInfoHeader{
+			Size:          bitmapV5HeaderSize,
+			Width:         int32(width),
+			Height:        int32(height),
+			Planes:        1,
+			BitCount:      32,
+			Compression:   biBitfields,
+			SizeImage:     uint32(rowSize * height),
+			XPelsPerMeter: pixelsPerMeter,
+			YPelsPerMeter: pixelsPerMeter,
+		},
+		RedMask:   0x00FF0000,
+		GreenMask: 0x0000FF00,
+		BlueMask:  0x000000FF,
+		AlphaMask: 0xFF000000,
+		CSType:    lcsSRGB,
+		Intent:    lcsGMImages,
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(bitmapV5HeaderSize + rowSize*height)
+	binary.Write(&buf, binary.LittleEndian, header)
+
+	row := make([]byte, rowSize)
+	for y := height - 1; y >= 0; y-- {
+		src := rgba.Pix[y*rgba.Stride:]
+		for x := 0; x < width; x++ {
+			row[x*4+0] = src[x*4+2]
+			row[x*4+1] = src[x*4+1]
+			row[x*4+2] = src[x*4+0]
+			row[x*4+3] = src[x*4+3]
+		}
+		buf.Write(row)
+	}
+	return buf.Bytes()
+}
+
+// toNRGBA returns img as non-premultiplied RGBA with bounds starting at (0, 0)
+func toNRGBA(img image.Image) *image.NRGBA {
+	bounds := img.Bounds()
+	if nrgba, ok := img.(*image.NRGBA); ok && bounds.Min == (image.Point{}) {
+		return nrgba
+	}
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	return nrgba
+}
+
+// overWhite composites a straight-alpha channel value over white
+func overWhite(c, a uint8) uint8 {
+	return uint8((int(c)*int(a) + 255*(255-int(a)) + 127) / 255)
+}
+
+// maxDIBPixels bounds decoded bitmaps (about 16K x 16K)
+const maxDIBPixels = 1 << 28
+
+// ErrTruncatedDIB is returned when a bitmap is shorter than its header claims
+var ErrTruncatedDIB = errors.New("truncated bitmap data")
+
+// dibInfo is the decoded bitmap header, normalized across header versions
+type dibInfo struct {
+	headerSize  int
+	width       int
+	height      int
+	topDown     bool
+	bitCount    int
+	compression uint32
+	colorsUsed  int
+	masks       [4]uint32 // Red, green, blue, alpha
+	hasMasks    bool      // Masks came from the header or BI_BITFIELDS
+	paletteSize int       // Bytes per palette entry (3 for core headers, else 4)
+}
+
+// DecodeDIB decodes a packed device-independent bitmap as found in CF_DIB and
+// CF_DIBV5: BITMAPCOREHEADER, BITMAPINFOHEADER or BITMAPV4/V5HEADER, followed
+// by optional masks and palette and the pixel rows. It supports 1, 4 and
+// 8-bit palettes, 16 and 32-bit BI_RGB/BI_BITFIELDS, 24-bit, and embedded
+// PNG/JPEG. Bitmaps with an alpha channel that is zero everywhere (common for
+// 32-bit BI_RGB) are treated as opaque.
+func DecodeDIB(data []byte) (*image.NRGBA, error) {
+	info, err := parseDIBHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	offset := info.headerSize
+
+	// BITMAPINFOHEADER with bitfields: masks follow the header
+	if info.headerSize == bitmapInfoHeaderSize {
+		switch info.compression {
+		case biBitfields:
+			if len(data) < offset+12 {
+				return nil, ErrTruncatedDIB
+			}
+			for i := 0; i < 3; i++ {
+				info.masks[i] = binary.LittleEndian.Uint32(data[offset+i*4:])
+			}
+			info.masks[3] = 0
+			info.hasMasks = true
+			offset += 12
+		case biAlphaBitfields:
+			if len(data) < offset+16 {
+				return nil, ErrTruncatedDIB
+			}
+			for i := 0; i < 4; i++ {
+				info.masks[i] = binary.LittleEndian.Uint32(data[offset+i*4:])
+			}
+			info.hasMasks = true
+			offset += 16
+		}
+	}
+
+	if info.compression == biPNG || info.compression == biJPEG {
+		img, _, err := image.Decode(bytes.NewReader(data[offset:]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode embedded image: %w", err)
+		}
+		return toNRGBA(img), nil
+	}
+
+	// Palette
+	var palette [][3]uint8 // RGB
+	if info.bitCount <= 8 {
+		count := info.colorsUsed
+		if count == 0 || count > 1<<info.bitCount {
+			count = 1 << info.bitCount
+		}
+		if len(data) < offset+count*info.paletteSize {
+			return nil, ErrTruncatedDIB
+		}
+		palette = make([][3]uint8, count)
+		for i := range palette {
+			entry := data[offset+i*info.paletteSize:]
+			palette[i] = [3]uint8{entry[2], entry[1], entry[0]}
+		}
+		offset += count * info.paletteSize
+	}
+
+	rowSize := ((info.width*info.bitCount + 31) / 32) * 4
+	pixelBytes := rowSize * info.height
+
+	// Some writers append BI_BITFIELDS masks after a V4/V5 header as well,
+	// although the header already holds them; skip them when they match
+	if info.headerSize > bitmapInfoHeaderSize && info.compression == biBitfields &&
+		len(data) >= offset+12+pixelBytes && matchesMasks(data[offset:], info.masks) {
+		offset += 12
+	}
+
+	if len(data) < offset+pixelBytes {
+		return nil, ErrTruncatedDIB
+	}
+	return decodeDIBPixels(data[offset:], info, palette, rowSize)
+}
+
+// parseDIBHeader reads the bitmap header and validates it
+func parseDIBHeader(data []byte) (dibInfo, error) {
+	le := binary.LittleEndian
+	if len(data) < 4 {
+		return dibInfo{}, ErrTruncatedDIB
+	}
+
+	info := dibInfo{headerSize: int(le.Uint32(data)), paletteSize: 4}
+	switch {
+	case info.headerSize == bitmapCoreHeaderSize:
+		if len(data) < bitmapCoreHeaderSize {
+			return dibInfo{}, ErrTruncatedDIB
+		}
+		info.width = int(le.Uint16(data[4:]))
+		info.height = int(le.Uint16(data[6:]))
+		info.bitCount = int(le.Uint16(data[10:]))
+		info.paletteSize = 3
+	case info.headerSize >= bitmapInfoHeaderSize && info.headerSize <= bitmapV5HeaderSize:
+		if len(data) < info.headerSize {
+			return dibInfo{}, ErrTruncatedDIB
+		}
+		info.width = int(int32(le.Uint32(data[4:])))
+		info.height = int(int32(le.Uint32(data[8:])))
+		info.bitCount = int(le.Uint16(data[14:]))
+		info.compression = le.Uint32(data[16:])
+		info.colorsUsed = int(le.Uint32(data[32:]))
+
+		// V2+ headers carry RGB masks, V3+ an alpha mask
+		if info.headerSize >= 52 {
+			for i := 0; i < 3; i++ {
+				info.masks[i] = le.Uint32(data[40+i*4:])
+			}
+			info.hasMasks = info.compression == biBitfields || info.compression == biAlphaBitfields
+		}
+		if info.headerSize >= 56 {
+			info.masks[3] = le.Uint32(data[52:])
+		}
+	default:
+		return dibInfo{}, fmt.Errorf("unsupported bitmap header size %d", info.headerSize)
+	}
+
+	if info.height < 0 {
+		info.height = -info.height
+		info.topDown = true
+	}
+	if info.width <= 0 || info.height <= 0 {
+		return dibInfo{}, errors.New("invalid bitmap dimensions")
+	}
+	if info.width*info.height > maxDIBPixels {
+		return dibInfo{}, errors.New("bitmap too large")
+	}
+
+	switch info.compression {
+	case biRGB, biBitfields, biAlphaBitfields:
+	case biPNG, biJPEG:
+		return info, nil
+	default:
+		return dibInfo{}, fmt.Errorf("unsupported bitmap compression %d", info.compression)
+	}
+
+	switch info.bitCount {
+	case 1, 4, 8, 24:
+	case 16, 32:
+		if !info.hasMasks {
+			// Default layouts: 16-bit is 5-5-5, 32-bit is BGRX with a possible alpha byte
+			if info.bitCount == 16 {
+				info.masks = [4]uint32{0x7C00, 0x03E0, 0x001F, 0}
+			} else {
+				info.masks = [4]uint32{0x00FF0000, 0x0000FF00, 0x000000FF, 0xFF000000}
+			}
+		}
+	default:
+		return dibInfo{}, fmt.Errorf("unsupported bit depth %d", info.bitCount)
+	}
+	return info, nil
+}
+
+// decodeDIBPixels converts the pixel rows to NRGBA
+func decodeDIBPixels(pixels []byte, info dibInfo, palette [][3]uint8, rowSize int) (*image.NRGBA, error) {
+	img := image.NewNRGBA(image.Rect(0, 0, info.width, info.height))
+	shifts, widths := maskLayout(info.masks)
+	sawAlpha := false
+
+	for y := 0; y < info.height; y++ {
+		srcY := y
+		if !info.topDown {
+			srcY = info.height - 1 - y
+		}
+		row := pixels[srcY*rowSize : srcY*rowSize+rowSize]
+		dst := img.Pix[y*img.Stride:]
+
+		for x := 0; x < info.width; x++ {
+			var r, g, b, a uint8 = 0, 0, 0, 255
+			switch info.bitCount {
+			case 1, 4, 8:
+				perByte := 8 / info.bitCount
+				shift := uint(8 - info.bitCount*(x%perByte+1))
+				index := int(row[x/perByte]>>shift) & (1<<info.bitCount - 1)
+				if index < len(palette) {
+					r, g, b = palette[index][0], palette[index][1], palette[index][2]
+				}
+			case 24:
+				b, g, r = row[x*3], row[x*3+1], row[x*3+2]
+			case 16, 32:
+				var v uint32
+				if info.bitCount == 16 {
+					v = uint32(binary.LittleEndian.Uint16(row[x*2:]))
+				} else {
+					v = binary.LittleEndian.Uint32(row[x*4:])
+				}
+				r = maskValue(v, info.masks[0], shifts[0], widths[0])
+				g = maskValue(v, info.masks[1], shifts[1], widths[1])
+				b = maskValue(v, info.masks[2], shifts[2], widths[2])
+				if info.masks[3] != 0 {
+					a = maskValue(v, info.masks[3], shifts[3], widths[3])
+					if a != 0 {
+						sawAlpha = true
+					}
+				}
+			}
+			dst[x*4], dst[x*4+1], dst[x*4+2], dst[x*4+3] = r, g, b, a
+		}
+	}
+
+	// An alpha channel that is zero everywhere means "no alpha"
+	if info.masks[3] != 0 && info.bitCount >= 16 && !sawAlpha {
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 255
+		}
+	}
+	return img, nil
+}
+
+// maskLayout returns the shift and bit width of each channel mask
+func maskLayout(masks [4]uint32) (shifts, widths [4]int) {
+	for i, m := range masks {
+		if m == 0 {
+			continue
+		}
+		shifts[i] = bits.TrailingZeros32(m)
+		widths[i] = bits.OnesCount32(m)
+	}
+	return shifts, widths
+}
+
+// maskValue extracts a channel and scales it to 8 bits
+func maskValue(v, mask uint32, shift, width int) uint8 {
+	if mask == 0 || width == 0 {
+		return 0
+	}
+	raw := uint64((v & mask) >> uint(shift))
+	maxValue := uint64(1)<<uint(width) - 1
+	return uint8((raw*255 + maxValue/2) / maxValue)
+}
+
+// matchesMasks reports whether b starts with the RGB masks
+func matchesMasks(b []byte, masks [4]uint32) bool {
+	for i := 0; i < 3; i++ {
+		if binary.LittleEndian.Uint32(b[i*4:]) != masks[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package clipformat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"unicode/utf16"
+)
+
+// dropFilesHeaderSize is sizeof(DROPFILES)
+const dropFilesHeaderSize = 20
+
+// dropFiles mirrors DROPFILES
+type dropFiles struct {
+	Files     uint32 // Offset of the file list
+	PointX    int32
+	PointY    int32
+	NonClient int32 // fNC
+	WideChar  int32 // fWide: file names are UTF-16
+}
+
+// EncodeDropFiles encodes paths as a CF_HDROP: a DROPFILES header followed by
+// NUL-terminated UTF-16 paths and a final NUL
+func EncodeDropFiles(paths []string) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, dropFiles{Files: dropFilesHeaderSize, WideChar: 1})
+	for _, path := range paths {
+		binary.Write(&buf, binary.LittleEndian, utf16.Encode([]rune(path)))
+		binary.Write(&buf, binary.LittleEndian, uint16(0))
+	}
+	binary.Write(&buf, binary.LittleEndian, uint16(0))
+	return buf.Bytes()
+}
+
+// DecodeDropFiles parses a CF_HDROP (DROPFILES header and file list). Both
+// UTF-16 and ANSI lists are supported; ANSI names are read as Latin-1.
+func DecodeDropFiles(data []byte) ([]string, error) {
+	if len(data) < dropFilesHeaderSize {
+		return nil, errors.New("truncated file list")
+	}
+	le := binary.LittleEndian
+	offset := int(le.Uint32(data))
+	wide := le.Uint32(data[16:]) != 0
+	if offset < dropFilesHeaderSize || offset > len(data) {
+		return nil, errors.New("invalid file list offset")
+	}
+
+	var paths []string
+	list := data[offset:]
+	if wide {
+		var name []uint16
+		for i := 0; i+1 < len(list); i += 2 {
+			c := le.Uint16(list[i:])
+			if c != 0 {
+				name = append(name, c)
+				continue
+			}
+			if len(name) == 0 {
+				break // Double NUL ends the list
+			}
+			paths = append(paths, string(utf16.Decode(name)))
+			name = name[:0]
+		}
+	} else {
+		for _, name := range bytes.Split(list, []byte{0}) {
+			if len(name) == 0 {
+				break
+			}
+			paths = append(paths, latin1(name))
+		}
+	}
+	return paths, nil
+}
+
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
@@ -0,0 +1,157 @@
+package clipformat
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HTMLFormatName is the registered clipboard format name for HTML fragments
+const HTMLFormatName = "HTML Format"
+
+// cfHTMLHeader is the CF_HTML description header. Offsets are zero-padded to a
+// fixed width so the header length doesn't depend on them.
+const cfHTMLHeader = "Version:0.9\r\n" +
+	"StartHTML:%010d\r\n" +
+	"EndHTML:%010d\r\n" +
+	"StartFragment:%010d\r\n" +
+	"EndFragment:%010d\r\n"
+
+const (
+	fragmentStart = "<!--StartFragment-->"
+	fragmentEnd   = "<!--EndFragment-->"
+)
+
+// EncodeHTML wraps an HTML fragment in the CF_HTML clipboard format. sourceURL
+// is optional. Offsets in the header are byte offsets into the UTF-8 result.
+func EncodeHTML(fragment, sourceURL string) []byte {
+	var extra string
+	if sourceURL != "" {
+		extra = "SourceURL:" + sourceURL + "\r\n"
+	}
+	headerLen := len(fmt.Sprintf(cfHTMLHeader, 0, 0, 0, 0)) + len(extra)
+
+	prefix := "<html><body>\r\n" + fragmentStart
+	suffix := fragmentEnd + "\r\n</body></html>"
+
+	startHTML := headerLen
+	startFragment := startHTML + len(prefix)
+	endFragment := startFragment + len(fragment)
+	endHTML := endFragment + len(suffix)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, cfHTMLHeader, startHTML, endHTML, startFragment, endFragment)
+	b.WriteString(extra)
+	b.WriteString(prefix)
+	b.WriteString(fragment)
+	b.WriteString(suffix)
+	return []byte(b.String())
+}
+
+// ImageFragment returns an <img> element for src (a data: or file: URL)
+func ImageFragment(src string, width, height int, alt string) string {
+	return fmt.Sprintf(`<img src="%s" width="%d" height="%d" alt="%s">`,
+		html.EscapeString(src), width, height, html.EscapeString(alt))
+}
+
+// FileURL converts a Windows path to a file:/// URL
+func FileURL(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// FilePathFromURL converts a file: URL back to a Windows path. UNC hosts
+// become \\host\share paths. ok is false for any other scheme.
+func FilePathFromURL(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.EqualFold(u.Scheme, "file") || u.Path == "" {
+		return "", false
+	}
+	path := u.Path
+	if len(path) >= 3 && path[0] == '/' && path[2] == ':' {
+		path = path[1:] // "/C:/x" -> "C:/x"
+	}
+	path = strings.ReplaceAll(path, "/", `\`)
+	if u.Host != "" && !strings.EqualFold(u.Host, "localhost") {
+		path = `\\` + u.Host + path
+	}
+	return path, true
+}
+
+// imgSrcPattern matches the src attribute of <img> elements
+var imgSrcPattern = regexp.MustCompile(`(?is)<img\b[^>]*?\ssrc\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+
+// HTMLImageSources returns the src of every <img> in CF_HTML data, in document
+// order. Only the fragment is searched when the header marks one.
+func HTMLImageSources(data []byte) []string {
+	doc := string(data)
+	start, okStart := htmlHeaderOffset(doc, "StartFragment")
+	end, okEnd := htmlHeaderOffset(doc, "EndFragment")
+	if okStart && okEnd && start <= end && end <= len(doc) {
+		doc = doc[start:end]
+	}
+
+	var sources []string
+	for _, m := range imgSrcPattern.FindAllStringSubmatch(doc, -1) {
+		src := m[1] + m[2] + m[3] // Only one alternative matches
+		if src != "" {
+			sources = append(sources, html.UnescapeString(src))
+		}
+	}
+	return sources
+}
+
+// htmlHeaderOffset reads a numeric CF_HTML header field
+func htmlHeaderOffset(doc, key string) (int, bool) {
+	i := strings.Index(doc, key+":")
+	if i < 0 {
+		return 0, false
+	}
+	value := doc[i+len(key)+1:]
+	if end := strings.IndexAny(value, "\r\n"); end >= 0 {
+		value = value[:end]
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	return n, err == nil && n >= 0
+}
+
+// DecodeDataURI decodes a data: URI and returns its payload and media type
+func DecodeDataURI(uri string) ([]byte, string, error) {
+	rest, ok := strings.CutPrefix(uri, "data:")
+	if !ok {
+		return nil, "", errors.New("not a data URI")
+	}
+	meta, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return nil, "", errors.New("malformed data URI")
+	}
+
+	mediaType, isBase64 := strings.CutSuffix(meta, ";base64")
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i] // Drop parameters like charset
+	}
+	if mediaType == "" {
+		mediaType = "text/plain"
+	}
+
+	if isBase64 {
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(payload), ""))
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid base64 in data URI: %w", err)
+		}
+		return data, strings.ToLower(mediaType), nil
+	}
+	text, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid data URI: %w", err)
+	}
+	return []byte(text), strings.ToLower(mediaType), nil
+}
@@ -0,0 +1,75 @@
+package clipformat
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// SVGFormatName is the registered clipboard format name for SVG markup
+const SVGFormatName = "image/svg+xml"
+
+// SVGSize returns the intrinsic size of an SVG document from the root element's
+// width/height (px or unitless) or, failing that, its viewBox. ok is false if
+// data is not SVG or has no usable size.
+func SVGSize(data []byte) (width, height int, ok bool) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, 0, false
+		}
+		start, isStart := token.(xml.StartElement)
+		if !isStart {
+			continue
+		}
+		if start.Name.Local != "svg" {
+			return 0, 0, false
+		}
+
+		var w, h float64
+		var viewBox string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "width":
+				w = svgLength(attr.Value)
+			case "height":
+				h = svgLength(attr.Value)
+			case "viewBox":
+				viewBox = attr.Value
+			}
+		}
+
+		if fields := strings.Fields(strings.ReplaceAll(viewBox, ",", " ")); len(fields) == 4 {
+			vw, errW := strconv.ParseFloat(fields[2], 64)
+			vh, errH := strconv.ParseFloat(fields[3], 64)
+			if errW == nil && errH == nil && vw > 0 && vh > 0 {
+				// Fill in a missing dimension from the aspect ratio
+				switch {
+				case w <= 0 && h <= 0:
+					w, h = vw, vh
+				case w <= 0:
+					w = h * vw / vh
+				case h <= 0:
+					h = w * vh / vw
+				}
+			}
+		}
+		if w <= 0 || h <= 0 {
+			return 0, 0, false
+		}
+		return int(w + 0.5), int(h + 0.5), true
+	}
+}
+
+// svgLength parses a width/height in px or user units; other units give 0
+func svgLength(value string) float64 {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "px")
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return n
+}
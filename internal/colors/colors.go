@@ -0,0 +1,146 @@
+// Package colors converts picked screen colors between the HEX, RGB and HSL
+// notations designers paste into CSS and design tools
+package colors
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Formats a picked color can be copied in
+const (
+	FormatHex = "hex" // #12ABEF
+	FormatRGB = "rgb" // rgb(18, 171, 239)
+	FormatHSL = "hsl" // hsl(198, 87%, 50%)
+)
+
+// RGB is an opaque 8-bit color
+type RGB struct {
+	R, G, B uint8
+}
+
+// HSL is a color as hue in degrees [0, 360) and saturation and lightness in
+// percent [0, 100]
+type HSL struct {
+	H, S, L float64
+}
+
+// ValidFormat reports whether format is one of the Format constants
+func ValidFormat(format string) bool {
+	return format == FormatHex || format == FormatRGB || format == FormatHSL
+}
+
+// Hex returns the color as "#RRGGBB"
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// String returns the color in CSS rgb() notation
+func (c RGB) String() string {
+	return fmt.Sprintf("rgb(%d, %d, %d)", c.R, c.G, c.B)
+}
+
+// Format returns the color in one of the Format notations, or as HEX for an
+// unknown one
+func (c RGB) Format(format string) string {
+	switch format {
+	case FormatRGB:
+		return c.String()
+	case FormatHSL:
+		return c.HSL().String()
+	default:
+		return c.Hex()
+	}
+}
+
+// HSL converts the color to hue, saturation and lightness
+func (c RGB) HSL() HSL {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l := (max + min) / 2
+	if max == min {
+		return HSL{L: l * 100} // Gray
+	}
+
+	d := max - min
+	s := math.Min(d/(1-math.Abs(2*l-1)), 1) // Rounding can take it just past 1
+
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return HSL{H: h, S: s * 100, L: l * 100}
+}
+
+// RGB converts the color back to 8-bit RGB
+func (c HSL) RGB() RGB {
+	h := math.Mod(c.H, 360)
+	if h < 0 {
+		h += 360
+	}
+	s := clamp(c.S, 0, 100) / 100
+	l := clamp(c.L, 0, 100) / 100
+
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - chroma/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return RGB{R: channel(r + m), G: channel(g + m), B: channel(b + m)}
+}
+
+// String returns the color in CSS hsl() notation, rounded to whole numbers
+func (c HSL) String() string {
+	h := int(math.Round(c.H)) % 360
+	return fmt.Sprintf("hsl(%d, %d%%, %d%%)", h, int(math.Round(c.S)), int(math.Round(c.L)))
+}
+
+// ParseHex parses "#RRGGBB" or "#RGB", with or without the "#"
+func ParseHex(s string) (RGB, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return RGB{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	return RGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil
+}
+
+func channel(v float64) uint8 {
+	return uint8(math.Round(clamp(v, 0, 1) * 255))
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
@@ -0,0 +1,114 @@
+package colors
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		c                  RGB
+		hex, rgb, hslValue string
+	}{
+		{RGB{0, 0, 0}, "#000000", "rgb(0, 0, 0)", "hsl(0, 0%, 0%)"},
+		{RGB{255, 255, 255}, "#FFFFFF", "rgb(255, 255, 255)", "hsl(0, 0%, 100%)"},
+		{RGB{255, 0, 0}, "#FF0000", "rgb(255, 0, 0)", "hsl(0, 100%, 50%)"},
+		{RGB{0, 255, 0}, "#00FF00", "rgb(0, 255, 0)", "hsl(120, 100%, 50%)"},
+		{RGB{0, 0, 255}, "#0000FF", "rgb(0, 0, 255)", "hsl(240, 100%, 50%)"},
+		{RGB{128, 128, 128}, "#808080", "rgb(128, 128, 128)", "hsl(0, 0%, 50%)"},
+		{RGB{0x12, 0xAB, 0xEF}, "#12ABEF", "rgb(18, 171, 239)", "hsl(198, 87%, 50%)"},
+		{RGB{0x66, 0x7E, 0xEA}, "#667EEA", "rgb(102, 126, 234)", "hsl(229, 76%, 66%)"},
+		{RGB{255, 0, 128}, "#FF0080", "rgb(255, 0, 128)", "hsl(330, 100%, 50%)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hex, func(t *testing.T) {
+			if got := tt.c.Format(FormatHex); got != tt.hex {
+				t.Errorf("hex = %q, want %q", got, tt.hex)
+			}
+			if got := tt.c.Format(FormatRGB); got != tt.rgb {
+				t.Errorf("rgb = %q, want %q", got, tt.rgb)
+			}
+			if got := tt.c.Format(FormatHSL); got != tt.hslValue {
+				t.Errorf("hsl = %q, want %q", got, tt.hslValue)
+			}
+		})
+	}
+}
+
+// Converting to HSL and back gives the same color
+func TestHSL_RoundTrip(t *testing.T) {
+	for r := 0; r < 256; r += 15 {
+		for g := 0; g < 256; g += 15 {
+			for b := 0; b < 256; b += 15 {
+				c := RGB{uint8(r), uint8(g), uint8(b)}
+				h := c.HSL()
+				if h.H < 0 || h.H >= 360 || h.S < 0 || h.S > 100 || h.L < 0 || h.L > 100 {
+					t.Fatalf("%v.HSL() = %.2f, %.2f, %.2f out of range", c, h.H, h.S, h.L)
+				}
+				if got := h.RGB(); got != c {
+					t.Fatalf("%v.HSL().RGB() = %v", c, got)
+				}
+			}
+		}
+	}
+}
+
+func TestHSL_RGB(t *testing.T) {
+	tests := []struct {
+		hsl  HSL
+		want RGB
+	}{
+		{HSL{0, 100, 50}, RGB{255, 0, 0}},
+		{HSL{360, 100, 50}, RGB{255, 0, 0}},
+		{HSL{-120, 100, 50}, RGB{0, 0, 255}},
+		{HSL{60, 100, 25}, RGB{128, 128, 0}},
+		{HSL{200, 0, 40}, RGB{102, 102, 102}},
+		{HSL{90, 150, -10}, RGB{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		if got := tt.hsl.RGB(); got != tt.want {
+			t.Errorf("%+v.RGB() = %v, want %v", tt.hsl, got, tt.want)
+		}
+	}
+}
+
+func TestHSL_Saturation(t *testing.T) {
+	h := RGB{0x12, 0xAB, 0xEF}.HSL()
+	if math.Abs(h.H-198.5) > 0.1 || math.Abs(h.S-87.4) > 0.1 || math.Abs(h.L-50.4) > 0.1 {
+		t.Errorf("HSL() = %.2f, %.2f, %.2f", h.H, h.S, h.L)
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    RGB
+		wantErr bool
+	}{
+		{"#12ABEF", RGB{0x12, 0xAB, 0xEF}, false},
+		{"12abef", RGB{0x12, 0xAB, 0xEF}, false},
+		{" #fff ", RGB{255, 255, 255}, false},
+		{"#0a0", RGB{0, 0xAA, 0}, false},
+		{"", RGB{}, true},
+		{"#12345", RGB{}, true},
+		{"#GG0000", RGB{}, true},
+		{"#-12345", RGB{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseHex(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseHex(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidFormat(t *testing.T) {
+	for _, f := range []string{FormatHex, FormatRGB, FormatHSL} {
+		if !ValidFormat(f) {
+			t.Errorf("ValidFormat(%q) = false", f)
+		}
+	}
+	if ValidFormat("cmyk") {
+		t.Error(`ValidFormat("cmyk") = true`)
+	}
+}
@@ -4,15 +4,9 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
-// HotkeyConfig holds hotkey settings
-type HotkeyConfig struct {
-	Fullscreen string `json:"fullscreen"`
-	Region     string `json:"region"`
-	Window     string `json:"window"`
-}
-
 // StartupConfig holds startup-related settings
 type StartupConfig struct {
 	LaunchOnStartup  bool `json:"launchOnStartup"`
@@ -29,8 +23,20 @@ type UpdateConfig struct {
 
 // QuickSaveConfig holds quick save settings
 type QuickSaveConfig struct {
-	Folder  string `json:"folder"`
-	Pattern string `json:"pattern"` // "timestamp", "date", "increment"
+	Folder    string          `json:"folder"`
+	Pattern   string          `json:"pattern"`   // "timestamp", "date", "increment"
+	ScanDepth int             `json:"scanDepth"` // Subfolder levels shown in the library (0 = top folder only)
+	Retention RetentionConfig `json:"retention"`
+}
+
+// RetentionConfig holds automatic cleanup rules for the QuickSave folder.
+// Favorites and tagged screenshots are never removed. Zero limits are disabled.
+type RetentionConfig struct {
+	Enabled        bool   `json:"enabled"`
+	MaxAgeDays     int    `json:"maxAgeDays"`
+	MaxTotalSizeMB int    `json:"maxTotalSizeMb"`
+	MaxCount       int    `json:"maxCount"`
+	Action         string `json:"action"` // "trash" or "archive" (zip into the archives data folder, then delete)
 }
 
 // ExportConfig holds export default settings
@@ -39,6 +45,8 @@ type ExportConfig struct {
 	JpegQuality        int    `json:"jpegQuality"`        // 0-100
 	IncludeBackground  bool   `json:"includeBackground"`
 	AutoCopyToClipboard bool  `json:"autoCopyToClipboard"`
+	StripMetadata      bool   `json:"stripMetadata"`            // Privacy: save files without capture metadata
+	MetadataAuthor     string `json:"metadataAuthor,omitempty"` // Author embedded in saved files
 }
 
 // WindowConfig holds window size and position settings
@@ -79,10 +87,47 @@ type GDriveConfig struct {
 	FolderID string `json:"folderId,omitempty"` // Optional upload folder ID
 }
 
+// LibraryConfig holds screenshot library housekeeping settings
+type LibraryConfig struct {
+	TrashRetentionDays int  `json:"trashRetentionDays"` // Purge trashed screenshots after N days (0 = keep until emptied)
+	WatchClipboard     bool `json:"watchClipboard"`     // Import images copied in other apps into the Clipboard folder
+	ClipboardMaxItems  int  `json:"clipboardMaxItems"`  // Oldest clipboard imports are trashed past this count (0 = 200)
+}
+
+// OCRConfig holds offline text recognition settings
+type OCRConfig struct {
+	TesseractPath string `json:"tesseractPath,omitempty"` // Empty = search PATH and default install folders
+	Language      string `json:"language"`                // Tesseract language code(s), e.g. "eng" or "eng+deu"
+}
+
+// ColorConfig holds color picker settings and the colors picked recently
+type ColorConfig struct {
+	Format  string   `json:"format"`            // Notation copied to the clipboard: "hex", "rgb" or "hsl"
+	History []string `json:"history,omitempty"` // "#RRGGBB", newest first
+}
+
+// MaxColorHistory is how many picked colors are remembered
+const MaxColorHistory = 16
+
+// AddColor puts a picked color first in the history, dropping an earlier pick
+// of the same color and the oldest past MaxColorHistory
+func (c *ColorConfig) AddColor(hex string) {
+	hex = strings.ToUpper(hex)
+	history := []string{hex}
+	for _, h := range c.History {
+		if h != hex && len(history) < MaxColorHistory {
+			history = append(history, h)
+		}
+	}
+	c.History = history
+}
+
 // CloudConfig holds cloud upload provider settings
 type CloudConfig struct {
 	R2     R2Config     `json:"r2,omitempty"`
 	GDrive GDriveConfig `json:"gdrive,omitempty"`
+
+	DefaultProvider string `json:"defaultProvider,omitempty"` // "r2" or "gdrive"; used by the tray menu
 }
 
 // Config holds all application settings
@@ -95,6 +140,9 @@ type Config struct {
 	Editor           EditorConfig    `json:"editor"`
 	Update           UpdateConfig    `json:"update"`
 	Cloud            CloudConfig     `json:"cloud,omitempty"`
+	OCR              OCRConfig       `json:"ocr"`
+	Library          LibraryConfig   `json:"library"`
+	ColorPicker      ColorConfig     `json:"colorPicker"`
 	BackgroundImages []string        `json:"backgroundImages,omitempty"`
 }
 
@@ -105,9 +153,7 @@ func Default() *Config {
 
 	return &Config{
 		Hotkeys: HotkeyConfig{
-			Fullscreen: "PrintScreen",
-			Region:     "Ctrl+PrintScreen",
-			Window:     "Ctrl+Shift+PrintScreen",
+			Bindings: DefaultHotkeyBindings(),
 		},
 		Startup: StartupConfig{
 			LaunchOnStartup:  false,
@@ -116,8 +162,12 @@ func Default() *Config {
 			CloseToTray:      true,
 		},
 		QuickSave: QuickSaveConfig{
-			Folder:  defaultFolder,
-			Pattern: "timestamp",
+			Folder:    defaultFolder,
+			Pattern:   "timestamp",
+			ScanDepth: 3,
+			Retention: RetentionConfig{
+				Action: "trash",
+			},
 		},
 		Export: ExportConfig{
 			DefaultFormat:       "png",
@@ -153,16 +203,31 @@ func Default() *Config {
 			R2:     R2Config{},
 			GDrive: GDriveConfig{},
 		},
+		OCR: OCRConfig{
+			Language: "eng",
+		},
+		Library: LibraryConfig{
+			TrashRetentionDays: 30,
+			ClipboardMaxItems:  200,
+		},
+		ColorPicker: ColorConfig{
+			Format: "hex",
+		},
 	}
 }
 
 // GetConfigPath returns the path to the config file
 func GetConfigPath() (string, error) {
+	return GetDataPath("config.json")
+}
+
+// GetDataPath returns the path to a file stored next to the config file
+func GetDataPath(name string) (string, error) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(configDir, "WinShot", "config.json"), nil
+	return filepath.Join(configDir, "WinShot", name), nil
 }
 
 // Load reads config from disk, returns default if not found
@@ -184,13 +249,31 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	cfg, migrated, err := parse(data)
+	if err != nil {
 		// Invalid JSON, return defaults
 		return Default(), nil
 	}
+	if migrated {
+		cfg.Save()
+	}
+
+	return cfg, nil
+}
+
+// parse decodes a saved config over the defaults, so settings added since it
+// was saved start at their default instead of zero. Reports whether the
+// config was migrated and needs saving.
+func parse(data []byte) (*Config, bool, error) {
+	cfg := Default()
+	// Hotkeys migrate from the legacy fields only when "bindings" is missing
+	cfg.Hotkeys = HotkeyConfig{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, false, err
+	}
 
-	return &cfg, nil
+	// Move pre-bindings hotkeys into the bindings list
+	return cfg, cfg.Hotkeys.migrate(), nil
 }
 
 // Save writes config to disk
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestColorConfig_AddColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []string
+		add     string
+		want    []string
+	}{
+		{"first pick", nil, "#12abef", []string{"#12ABEF"}},
+		{"newest first", []string{"#000000"}, "#FFFFFF", []string{"#FFFFFF", "#000000"}},
+		{"repeat moves to front", []string{"#111111", "#222222", "#333333"}, "#333333", []string{"#333333", "#111111", "#222222"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ColorConfig{History: tt.history}
+			c.AddColor(tt.add)
+			if !reflect.DeepEqual(c.History, tt.want) {
+				t.Errorf("History = %v, want %v", c.History, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorConfig_AddColorLimit(t *testing.T) {
+	var c ColorConfig
+	for i := 0; i < MaxColorHistory+5; i++ {
+		c.AddColor(fmt.Sprintf("#0000%02X", i))
+	}
+	if len(c.History) != MaxColorHistory {
+		t.Fatalf("len(History) = %d, want %d", len(c.History), MaxColorHistory)
+	}
+	if want := fmt.Sprintf("#0000%02X", MaxColorHistory+4); c.History[0] != want {
+		t.Errorf("History[0] = %q, want %q", c.History[0], want)
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import "testing"
+
+// A config.json saved before scan depth, retention, OCR and the library settings existed
+const preLibraryConfig = `{
+  "hotkeys": {
+    "fullscreen": "PrintScreen",
+    "region": "Ctrl+PrintScreen",
+    "window": "Ctrl+Shift+PrintScreen"
+  },
+  "startup": {"launchOnStartup": false, "minimizeToTray": false, "showNotification": true, "closeToTray": true},
+  "quickSave": {"folder": "D:\\Shots", "pattern": "date"},
+  "export": {"defaultFormat": "jpeg", "jpegQuality": 80, "includeBackground": true, "autoCopyToClipboard": false}
+}`
+
+func TestParse_BackfillsNewSettings(t *testing.T) {
+	cfg, migrated, err := parse([]byte(preLibraryConfig))
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if !migrated {
+		t.Error("legacy hotkeys not reported as migrated")
+	}
+
+	// Saved settings are kept
+	if cfg.QuickSave.Folder != `D:\Shots` || cfg.QuickSave.Pattern != "date" || cfg.Export.JpegQuality != 80 {
+		t.Errorf("saved settings lost: quickSave = %+v, export = %+v", cfg.QuickSave, cfg.Export)
+	}
+	if len(cfg.Hotkeys.Bindings) != 3 || cfg.Hotkeys.Bindings[1].Keys != "Ctrl+PrintScreen" {
+		t.Errorf("bindings = %+v, want the legacy hotkeys", cfg.Hotkeys.Bindings)
+	}
+
+	// Settings the file predates get their defaults
+	want := Default()
+	if cfg.QuickSave.ScanDepth != want.QuickSave.ScanDepth {
+		t.Errorf("ScanDepth = %d, want %d", cfg.QuickSave.ScanDepth, want.QuickSave.ScanDepth)
+	}
+	if cfg.Library != want.Library {
+		t.Errorf("Library = %+v, want %+v", cfg.Library, want.Library)
+	}
+	if cfg.QuickSave.Retention != want.QuickSave.Retention || cfg.OCR != want.OCR || cfg.ColorPicker.Format != want.ColorPicker.Format {
+		t.Errorf("retention = %+v, ocr = %+v, color format = %q; want defaults", cfg.QuickSave.Retention, cfg.OCR, cfg.ColorPicker.Format)
+	}
+}
+
+func TestParse_KeepsExplicitZeros(t *testing.T) {
+	cfg, migrated, err := parse([]byte(`{
+  "hotkeys": {"bindings": []},
+  "quickSave": {"folder": "D:\\Shots", "scanDepth": 0},
+  "library": {"trashRetentionDays": 0, "clipboardMaxItems": 50}
+}`))
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if migrated {
+		t.Error("current config reported as migrated")
+	}
+	if cfg.QuickSave.ScanDepth != 0 || cfg.Library.TrashRetentionDays != 0 || cfg.Library.ClipboardMaxItems != 50 {
+		t.Errorf("quickSave = %+v, library = %+v; want the saved values", cfg.QuickSave, cfg.Library)
+	}
+	if cfg.Hotkeys.Bindings == nil || len(cfg.Hotkeys.Bindings) != 0 {
+		t.Errorf("bindings = %#v, want the saved empty list", cfg.Hotkeys.Bindings)
+	}
+}
+
+func TestParse_InvalidJSON(t *testing.T) {
+	if _, _, err := parse([]byte(`{"quickSave":`)); err == nil {
+		t.Error("parse() accepted truncated JSON")
+	}
+}
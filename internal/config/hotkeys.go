@@ -0,0 +1,113 @@
+package config
+
+// Capture modes a hotkey binding can start
+const (
+	CaptureFullscreen = "fullscreen"
+	CaptureRegion     = "region"
+	CaptureWindow     = "window"
+	CaptureColor      = "color" // Pick a pixel's color instead of capturing an image
+)
+
+// After-capture workflow step types
+const (
+	StepEditor   = "editor"   // Open the capture in the editor; later steps run on the edited image
+	StepBeautify = "beautify" // Apply the editor's background, padding, shadow and border
+	StepSave     = "save"     // Save to the QuickSave folder
+	StepCopy     = "copy"     // Copy the image to the clipboard
+	StepUpload   = "upload"   // Upload to the cloud provider in WorkflowStep.Provider
+	StepCopyURL  = "copyUrl"  // Copy the uploaded image's URL to the clipboard
+	StepNotify   = "notify"   // Show a notification with the result
+	StepCommand  = "command"  // Run WorkflowStep.Command
+)
+
+// WorkflowStep is one action run after a capture
+type WorkflowStep struct {
+	Type     string   `json:"type"`
+	Provider string   `json:"provider,omitempty"` // Upload: "r2" or "gdrive"
+	Command  string   `json:"command,omitempty"`  // Command: executable to run
+	Args     []string `json:"args,omitempty"`     // Command: arguments; "{file}" and "{url}" are replaced
+}
+
+// HotkeyBinding maps a key combination to a capture mode and the steps run on the result
+type HotkeyBinding struct {
+	Keys    string         `json:"keys"`    // e.g. "Ctrl+Shift+PrintScreen"
+	Capture string         `json:"capture"` // "fullscreen", "region", "window" or "color"
+	Steps   []WorkflowStep `json:"steps,omitempty"`
+	Name    string         `json:"name,omitempty"` // Optional label shown in settings
+}
+
+// HotkeyConfig holds hotkey settings
+type HotkeyConfig struct {
+	Bindings []HotkeyBinding `json:"bindings"`
+
+	// Deprecated: one fixed hotkey per capture mode, from before bindings.
+	// Load moves these into Bindings.
+	Fullscreen string `json:"fullscreen,omitempty"`
+	Region     string `json:"region,omitempty"`
+	Window     string `json:"window,omitempty"`
+}
+
+// EditorSteps is the default workflow: open the capture in the editor
+func EditorSteps() []WorkflowStep {
+	return []WorkflowStep{{Type: StepEditor}}
+}
+
+// DefaultHotkeyBindings returns the built-in PrintScreen bindings
+func DefaultHotkeyBindings() []HotkeyBinding {
+	return []HotkeyBinding{
+		{Keys: "PrintScreen", Capture: CaptureFullscreen, Steps: EditorSteps()},
+		{Keys: "Ctrl+PrintScreen", Capture: CaptureRegion, Steps: EditorSteps()},
+		{Keys: "Ctrl+Shift+PrintScreen", Capture: CaptureWindow, Steps: EditorSteps()},
+	}
+}
+
+// Headless reports whether the binding's workflow starts without the editor, so
+// the capture is processed in the background and the main window stays hidden
+func (b HotkeyBinding) Headless() bool {
+	return len(b.Steps) > 0 && b.Steps[0].Type != StepEditor
+}
+
+// OpensEditor reports whether the binding's workflow shows the capture in the editor.
+// A binding without steps opens the editor, as every hotkey did before workflows.
+func (b HotkeyBinding) OpensEditor() bool {
+	if len(b.Steps) == 0 {
+		return true
+	}
+	for _, step := range b.Steps {
+		if step.Type == StepEditor {
+			return true
+		}
+	}
+	return false
+}
+
+// migrate converts the legacy per-mode fields into bindings. Configs saved before
+// bindings have no "bindings" key, leaving Bindings nil; an empty list is kept
+// since the user removed every hotkey. Reports whether anything changed.
+func (h *HotkeyConfig) migrate() bool {
+	legacy := []struct {
+		keys    string
+		capture string
+	}{
+		{h.Fullscreen, CaptureFullscreen},
+		{h.Region, CaptureRegion},
+		{h.Window, CaptureWindow},
+	}
+	hasLegacy := h.Fullscreen != "" || h.Region != "" || h.Window != ""
+
+	if h.Bindings == nil {
+		if !hasLegacy {
+			h.Bindings = DefaultHotkeyBindings()
+			return true
+		}
+		h.Bindings = []HotkeyBinding{}
+		for _, l := range legacy {
+			if l.keys != "" {
+				h.Bindings = append(h.Bindings, HotkeyBinding{Keys: l.keys, Capture: l.capture, Steps: EditorSteps()})
+			}
+		}
+	}
+
+	h.Fullscreen, h.Region, h.Window = "", "", ""
+	return hasLegacy
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestHotkeyConfig_Migrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		json        string
+		want        []HotkeyBinding
+		wantChanged bool
+	}{
+		{
+			name: "legacy fields",
+			json: `{"fullscreen":"F9","region":"Ctrl+PrintScreen","window":""}`,
+			want: []HotkeyBinding{
+				{Keys: "F9", Capture: CaptureFullscreen, Steps: EditorSteps()},
+				{Keys: "Ctrl+PrintScreen", Capture: CaptureRegion, Steps: EditorSteps()},
+			},
+			wantChanged: true,
+		},
+		{
+			name:        "no hotkeys saved",
+			json:        `{}`,
+			want:        DefaultHotkeyBindings(),
+			wantChanged: true,
+		},
+		{
+			name: "bindings already present",
+			json: `{"bindings":[{"keys":"F8","capture":"region","steps":[{"type":"upload","provider":"r2"}]}]}`,
+			want: []HotkeyBinding{
+				{Keys: "F8", Capture: CaptureRegion, Steps: []WorkflowStep{{Type: StepUpload, Provider: "r2"}}},
+			},
+		},
+		{
+			name: "all bindings removed",
+			json: `{"bindings":[]}`,
+			want: []HotkeyBinding{},
+		},
+		{
+			name: "legacy fields left next to bindings are dropped",
+			json: `{"bindings":[{"keys":"F8","capture":"window"}],"fullscreen":"PrintScreen"}`,
+			want: []HotkeyBinding{
+				{Keys: "F8", Capture: CaptureWindow},
+			},
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h HotkeyConfig
+			if err := json.Unmarshal([]byte(tt.json), &h); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if changed := h.migrate(); changed != tt.wantChanged {
+				t.Errorf("migrate() = %v, want %v", changed, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(h.Bindings, tt.want) {
+				t.Errorf("Bindings = %+v, want %+v", h.Bindings, tt.want)
+			}
+			if h.Fullscreen != "" || h.Region != "" || h.Window != "" {
+				t.Errorf("legacy fields not cleared: %+v", h)
+			}
+
+			// Migrated configs no longer write the legacy keys
+			data, _ := json.Marshal(h)
+			var raw map[string]any
+			json.Unmarshal(data, &raw)
+			if _, ok := raw["fullscreen"]; ok {
+				t.Errorf("marshalled config still has legacy key: %s", data)
+			}
+		})
+	}
+}
+
+func TestHotkeyBinding_OpensEditor(t *testing.T) {
+	tests := []struct {
+		steps []WorkflowStep
+		want  bool
+	}{
+		{nil, true},
+		{EditorSteps(), true},
+		{[]WorkflowStep{{Type: StepSave}, {Type: StepEditor}}, true},
+		{[]WorkflowStep{{Type: StepUpload, Provider: "gdrive"}, {Type: StepCopy}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := (HotkeyBinding{Steps: tt.steps}).OpensEditor(); got != tt.want {
+			t.Errorf("OpensEditor(%+v) = %v, want %v", tt.steps, got, tt.want)
+		}
+	}
+}
+
+func TestHotkeyBinding_Headless(t *testing.T) {
+	tests := []struct {
+		steps []WorkflowStep
+		want  bool
+	}{
+		{nil, false},
+		{EditorSteps(), false},
+		{[]WorkflowStep{{Type: StepEditor}, {Type: StepSave}}, false},
+		{[]WorkflowStep{{Type: StepSave}, {Type: StepEditor}}, true},
+		{[]WorkflowStep{{Type: StepUpload, Provider: "r2"}, {Type: StepCopyURL}}, true},
+	}
+
+	for _, tt := range tests {
+		if got := (HotkeyBinding{Steps: tt.steps}).Headless(); got != tt.want {
+			t.Errorf("Headless(%+v) = %v, want %v", tt.steps, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,10 @@
+//go:build windows
+
 package hotkeys
 
 import (
+	"errors"
 	"runtime"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -15,31 +17,7 @@ var (
 	procUnregisterHotKey = user32.NewProc("UnregisterHotKey")
 	procGetMessageW     = user32.NewProc("GetMessageW")
 	procPeekMessageW    = user32.NewProc("PeekMessageW")
-)
-
-// Key modifier constants
-const (
-	ModAlt   uint = 0x0001
-	ModCtrl  uint = 0x0002
-	ModShift uint = 0x0004
-	ModWin   uint = 0x0008
-)
-
-// Virtual key codes
-const (
-	VK_SNAPSHOT = 0x2C // Print Screen
-	VK_F1       = 0x70
-	VK_F2       = 0x71
-	VK_F3       = 0x72
-	VK_F4       = 0x73
-	VK_F5       = 0x74
-	VK_F6       = 0x75
-	VK_F7       = 0x76
-	VK_F8       = 0x77
-	VK_F9       = 0x78
-	VK_F10      = 0x79
-	VK_F11      = 0x7A
-	VK_F12      = 0x7B
+	procGetAsyncKeyState = user32.NewProc("GetAsyncKeyState")
 )
 
 // Windows message constants
@@ -48,13 +26,18 @@ const (
 	PM_REMOVE = 0x0001
 )
 
-// Hotkey ID constants
+// Hotkey IDs: Add hands out 1..maxHotkeyID, sequences register their stages
+// from firstStageID, and probeHotkeyID, the largest ID an application may pass
+// to RegisterHotKey, is used to test whether a combination is free
 const (
-	HotkeyFullscreen = 1
-	HotkeyRegion     = 2
-	HotkeyWindow     = 3
+	maxHotkeyID   = 0x7FFF
+	firstStageID  = 0x8000
+	probeHotkeyID = 0xBFFF
 )
 
+// errorHotkeyAlreadyRegistered is ERROR_HOTKEY_ALREADY_REGISTERED
+const errorHotkeyAlreadyRegistered = syscall.Errno(1409)
+
 // MSG structure for Windows messages
 type MSG struct {
 	HWND    uintptr
@@ -70,6 +53,13 @@ type Hotkey struct {
 	ID        int
 	Modifiers uint
 	KeyCode   uint
+	Sides     uint    // Side-specific modifiers that must be held, see SideLCtrl
+	Then      []Combo // For sequences, the combinations pressed after the first
+}
+
+// Sequence returns every combination of the hotkey, in order
+func (h *Hotkey) Sequence() Sequence {
+	return append(Sequence{{Modifiers: h.Modifiers, KeyCode: h.KeyCode, Sides: h.Sides}}, h.Then...)
 }
 
 // HotkeyCallback is called when a hotkey is pressed
@@ -77,10 +67,11 @@ type HotkeyCallback func(id int)
 
 // hotkeyCmd represents a registration/unregistration command
 type hotkeyCmd struct {
-	action    string // "register", "unregister", "unregisterAll"
+	action    string // "register", "unregister", "unregisterAll", "probe"
 	id        int
 	modifiers uint
 	keyCode   uint
+	hotkey    *Hotkey // For "register"
 	resultCh  chan error
 }
 
@@ -92,16 +83,22 @@ type HotkeyManager struct {
 	stopCh   chan struct{}
 	cmdCh    chan hotkeyCmd // Channel for registration commands
 	readyCh  chan struct{}  // Signals message loop is ready
+	nextID   int            // Last ID handed out by Add
 	mu       sync.Mutex
+
+	// Owned by the message loop thread
+	prefixes map[int]Combo // Stage ID -> first combination of one or more sequences
+	chord    *chord        // Sequence in progress, if any
 }
 
 // NewHotkeyManager creates a new hotkey manager
 func NewHotkeyManager() *HotkeyManager {
 	return &HotkeyManager{
-		hotkeys: make(map[int]*Hotkey),
-		stopCh:  make(chan struct{}),
-		cmdCh:   make(chan hotkeyCmd, 10),
-		readyCh: make(chan struct{}),
+		hotkeys:  make(map[int]*Hotkey),
+		stopCh:   make(chan struct{}),
+		cmdCh:    make(chan hotkeyCmd, 10),
+		readyCh:  make(chan struct{}),
+		prefixes: make(map[int]Combo),
 	}
 }
 
@@ -112,9 +109,129 @@ func (m *HotkeyManager) SetCallback(cb HotkeyCallback) {
 	m.callback = cb
 }
 
+// Add registers a global hotkey under a newly allocated ID and returns the ID,
+// which is passed to the callback when the hotkey is pressed. The ID is
+// returned even if registration fails, so callers can report the failure.
+func (m *HotkeyManager) Add(modifiers, keyCode uint) (int, error) {
+	return m.AddSequence(Sequence{{Modifiers: modifiers, KeyCode: keyCode}})
+}
+
+// AddSequence is Add for a combination with sides, or for a chorded sequence.
+// Only a sequence's first combination is registered; the next one registers
+// for ChordTimeout after it is pressed. Sequences may share first combinations.
+func (m *HotkeyManager) AddSequence(seq Sequence) (int, error) {
+	if len(seq) == 0 {
+		return 0, errNoKey
+	}
+	m.mu.Lock()
+	id := 0
+	for n, candidate := 0, m.nextID; n < maxHotkeyID; n++ {
+		candidate = candidate%maxHotkeyID + 1
+		if _, used := m.hotkeys[candidate]; !used {
+			id = candidate
+			break
+		}
+	}
+	if id == 0 {
+		m.mu.Unlock()
+		return 0, errors.New("no free hotkey IDs")
+	}
+	m.nextID = id
+	m.mu.Unlock()
+
+	first := seq[0]
+	return id, m.register(&Hotkey{ID: id, Modifiers: first.Modifiers, KeyCode: first.KeyCode, Sides: first.Sides, Then: seq[1:]})
+}
+
+// AddKeys validates and registers a hotkey string like "Ctrl+Shift+S" under a
+// new ID. If the combination is unusable or taken, the result says why and
+// suggests free alternatives.
+func (m *HotkeyManager) AddKeys(keys string) Registration {
+	reg := Registration{Keys: keys, Status: StatusOK}
+
+	seq, err := ValidateHotkeyString(keys)
+	if err == nil {
+		var id int
+		if id, err = m.AddSequence(seq); err == nil {
+			reg.ID = id
+			return reg
+		}
+	}
+
+	reg.Status = StatusOf(err)
+	reg.Error = err.Error()
+	reg.Suggestions = m.Suggest(keys, 3)
+	return reg
+}
+
+// Probe reports whether a combination could be registered right now, returning
+// ErrHotkeyTaken if it is registered already, by this or another application.
+// Without a running message loop nothing can be tested and Probe returns nil.
+func (m *HotkeyManager) Probe(modifiers, keyCode uint) error {
+	m.mu.Lock()
+	running := m.running
+	m.mu.Unlock()
+
+	if !running {
+		return nil
+	}
+
+	resultCh := make(chan error, 1)
+	m.cmdCh <- hotkeyCmd{
+		action:    "probe",
+		modifiers: modifiers,
+		keyCode:   keyCode,
+		resultCh:  resultCh,
+	}
+
+	return <-resultCh
+}
+
+// probeOnThread registers and immediately unregisters a combination on the message loop thread
+func (m *HotkeyManager) probeOnThread(modifiers, keyCode uint) error {
+	ret, _, err := procRegisterHotKey.Call(0, probeHotkeyID, uintptr(modifiers), uintptr(keyCode))
+	if ret == 0 {
+		return registerError(err)
+	}
+	procUnregisterHotKey.Call(0, probeHotkeyID)
+	return nil
+}
+
+// Suggest returns up to max free hotkeys like keys, with more modifiers on its first combination
+func (m *HotkeyManager) Suggest(keys string, max int) []string {
+	seq, err := ParseSequence(keys)
+	if err != nil {
+		return nil
+	}
+
+	var suggestions []string
+	for _, alt := range alternatives(seq[0]) {
+		if len(suggestions) == max {
+			break
+		}
+		if m.Probe(alt.Modifiers, alt.KeyCode) == nil {
+			suggestions = append(suggestions, append(Sequence{alt}, seq[1:]...).String())
+		}
+	}
+	return suggestions
+}
+
+// registerError maps a RegisterHotKey failure to ErrHotkeyTaken where possible
+func registerError(err error) error {
+	if errors.Is(err, errorHotkeyAlreadyRegistered) {
+		return ErrHotkeyTaken
+	}
+	return err
+}
+
 // Register registers a new global hotkey
 // Must be called after Start() to ensure registration happens on message loop thread
 func (m *HotkeyManager) Register(id int, modifiers, keyCode uint) error {
+	return m.register(&Hotkey{ID: id, Modifiers: modifiers, KeyCode: keyCode})
+}
+
+// register registers hk now if the message loop runs, or when it starts
+func (m *HotkeyManager) register(hk *Hotkey) error {
 	m.mu.Lock()
 	running := m.running
 	m.mu.Unlock()
@@ -122,11 +239,7 @@ func (m *HotkeyManager) Register(id int, modifiers, keyCode uint) error {
 	if !running {
 		// If not running yet, store for later registration
 		m.mu.Lock()
-		m.hotkeys[id] = &Hotkey{
-			ID:        id,
-			Modifiers: modifiers,
-			KeyCode:   keyCode,
-		}
+		m.hotkeys[hk.ID] = hk
 		m.mu.Unlock()
 		return nil
 	}
@@ -134,35 +247,35 @@ func (m *HotkeyManager) Register(id int, modifiers, keyCode uint) error {
 	// Send registration command to message loop thread
 	resultCh := make(chan error, 1)
 	m.cmdCh <- hotkeyCmd{
-		action:    "register",
-		id:        id,
-		modifiers: modifiers,
-		keyCode:   keyCode,
-		resultCh:  resultCh,
+		action:   "register",
+		hotkey:   hk,
+		resultCh: resultCh,
 	}
 
 	return <-resultCh
 }
 
 // registerOnThread performs actual registration on the message loop thread
-func (m *HotkeyManager) registerOnThread(id int, modifiers, keyCode uint) error {
-	ret, _, err := procRegisterHotKey.Call(
-		0,
-		uintptr(id),
-		uintptr(modifiers),
-		uintptr(keyCode),
-	)
-
-	if ret == 0 {
-		return err
+func (m *HotkeyManager) registerOnThread(hk *Hotkey) error {
+	if len(hk.Then) > 0 {
+		if err := m.registerPrefix(hk.Modifiers, hk.KeyCode); err != nil {
+			return err
+		}
+	} else {
+		ret, _, err := procRegisterHotKey.Call(
+			0,
+			uintptr(hk.ID),
+			uintptr(hk.Modifiers),
+			uintptr(hk.KeyCode),
+		)
+
+		if ret == 0 {
+			return registerError(err)
+		}
 	}
 
 	m.mu.Lock()
-	m.hotkeys[id] = &Hotkey{
-		ID:        id,
-		Modifiers: modifiers,
-		KeyCode:   keyCode,
-	}
+	m.hotkeys[hk.ID] = hk
 	m.mu.Unlock()
 
 	return nil
@@ -199,6 +312,19 @@ func (m *HotkeyManager) Unregister(id int) error {
 
 // unregisterOnThread performs actual unregistration on the message loop thread
 func (m *HotkeyManager) unregisterOnThread(id int) error {
+	m.mu.Lock()
+	hk := m.hotkeys[id]
+	m.mu.Unlock()
+
+	if hk != nil && len(hk.Then) > 0 {
+		m.mu.Lock()
+		delete(m.hotkeys, id)
+		m.mu.Unlock()
+		m.endChord()
+		m.releasePrefix(hk.Modifiers, hk.KeyCode)
+		return nil
+	}
+
 	ret, _, err := procUnregisterHotKey.Call(
 		0,
 		uintptr(id),
@@ -219,6 +345,7 @@ func (m *HotkeyManager) unregisterOnThread(id int) error {
 func (m *HotkeyManager) UnregisterAll() {
 	m.mu.Lock()
 	running := m.running
+	m.nextID = 0 // IDs are handed out from 1 again
 	ids := make([]int, 0, len(m.hotkeys))
 	for id := range m.hotkeys {
 		ids = append(ids, id)
@@ -256,6 +383,12 @@ func (m *HotkeyManager) unregisterAllOnThread() {
 		procUnregisterHotKey.Call(0, uintptr(id))
 	}
 
+	m.endChord()
+	for stageID := range m.prefixes {
+		procUnregisterHotKey.Call(0, uintptr(stageID))
+	}
+	m.prefixes = make(map[int]Combo)
+
 	m.mu.Lock()
 	m.hotkeys = make(map[int]*Hotkey)
 	m.mu.Unlock()
@@ -310,7 +443,7 @@ func (m *HotkeyManager) messageLoop(pendingHotkeys []*Hotkey) {
 
 	// Register any pending hotkeys on THIS thread
 	for _, hk := range pendingHotkeys {
-		m.registerOnThread(hk.ID, hk.Modifiers, hk.KeyCode)
+		m.registerOnThread(hk)
 	}
 
 	// Signal that we're ready
@@ -328,16 +461,23 @@ func (m *HotkeyManager) messageLoop(pendingHotkeys []*Hotkey) {
 			var err error
 			switch cmd.action {
 			case "register":
-				err = m.registerOnThread(cmd.id, cmd.modifiers, cmd.keyCode)
+				err = m.registerOnThread(cmd.hotkey)
 			case "unregister":
 				err = m.unregisterOnThread(cmd.id)
 			case "unregisterAll":
 				m.unregisterAllOnThread()
+			case "probe":
+				err = m.probeOnThread(cmd.modifiers, cmd.keyCode)
 			}
 			if cmd.resultCh != nil {
 				cmd.resultCh <- err
 			}
 		default:
+			// A sequence waits only briefly for its next combination
+			if m.chord != nil && time.Now().After(m.chord.deadline) {
+				m.endChord()
+			}
+
 			// Non-blocking message peek
 			ret, _, _ := procPeekMessageW.Call(
 				uintptr(unsafe.Pointer(&msg)),
@@ -353,8 +493,8 @@ func (m *HotkeyManager) messageLoop(pendingHotkeys []*Hotkey) {
 					cb := m.callback
 					m.mu.Unlock()
 
-					if cb != nil {
-						hotkeyID := int(msg.WParam)
+					// Stages of sequences report nothing until the last one
+					if hotkeyID, ok := m.handleHotkey(int(msg.WParam)); ok && cb != nil {
 						cb(hotkeyID)
 					}
 				}
@@ -366,27 +506,6 @@ func (m *HotkeyManager) messageLoop(pendingHotkeys []*Hotkey) {
 	}
 }
 
-// RegisterDefaults registers the default hotkeys for WinShot
-func (m *HotkeyManager) RegisterDefaults() error {
-	// Print Screen - Fullscreen capture
-	if err := m.Register(HotkeyFullscreen, 0, VK_SNAPSHOT); err != nil {
-		// Try Alt+Print Screen if regular Print Screen fails
-		m.Register(HotkeyFullscreen, ModAlt, VK_SNAPSHOT)
-	}
-
-	// Ctrl+Print Screen - Region capture
-	if err := m.Register(HotkeyRegion, ModCtrl, VK_SNAPSHOT); err != nil {
-		return err
-	}
-
-	// Ctrl+Shift+Print Screen - Window capture
-	if err := m.Register(HotkeyWindow, ModCtrl|ModShift, VK_SNAPSHOT); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 // UpdateHotkey updates a single hotkey registration
 func (m *HotkeyManager) UpdateHotkey(id int, modifiers, keyCode uint) error {
 	// Unregister existing hotkey if present
@@ -394,122 +513,3 @@ func (m *HotkeyManager) UpdateHotkey(id int, modifiers, keyCode uint) error {
 	// Register new hotkey
 	return m.Register(id, modifiers, keyCode)
 }
-
-// keyNameToCode maps key names to virtual key codes
-var keyNameToCode = map[string]uint{
-	"PRINTSCREEN": VK_SNAPSHOT,
-	"PRTSC":       VK_SNAPSHOT,
-	"SNAPSHOT":    VK_SNAPSHOT,
-	"F1":          VK_F1,
-	"F2":          VK_F2,
-	"F3":          VK_F3,
-	"F4":          VK_F4,
-	"F5":          VK_F5,
-	"F6":          VK_F6,
-	"F7":          VK_F7,
-	"F8":          VK_F8,
-	"F9":          VK_F9,
-	"F10":         VK_F10,
-	"F11":         VK_F11,
-	"F12":         VK_F12,
-	// Letters A-Z (0x41-0x5A)
-	"A": 0x41, "B": 0x42, "C": 0x43, "D": 0x44, "E": 0x45,
-	"F": 0x46, "G": 0x47, "H": 0x48, "I": 0x49, "J": 0x4A,
-	"K": 0x4B, "L": 0x4C, "M": 0x4D, "N": 0x4E, "O": 0x4F,
-	"P": 0x50, "Q": 0x51, "R": 0x52, "S": 0x53, "T": 0x54,
-	"U": 0x55, "V": 0x56, "W": 0x57, "X": 0x58, "Y": 0x59, "Z": 0x5A,
-	// Numbers 0-9 (0x30-0x39)
-	"0": 0x30, "1": 0x31, "2": 0x32, "3": 0x33, "4": 0x34,
-	"5": 0x35, "6": 0x36, "7": 0x37, "8": 0x38, "9": 0x39,
-	// Common keys
-	"SPACE":     0x20,
-	"ENTER":     0x0D,
-	"TAB":       0x09,
-	"ESCAPE":    0x1B,
-	"ESC":       0x1B,
-	"BACKSPACE": 0x08,
-	"DELETE":    0x2E,
-	"INSERT":    0x2D,
-	"HOME":      0x24,
-	"END":       0x23,
-	"PAGEUP":    0x21,
-	"PAGEDOWN":  0x22,
-	"UP":        0x26,
-	"DOWN":      0x28,
-	"LEFT":      0x25,
-	"RIGHT":     0x27,
-}
-
-// ParseHotkeyString parses a hotkey string like "Ctrl+Shift+PrintScreen" into modifiers and key code
-func ParseHotkeyString(hotkeyStr string) (modifiers uint, keyCode uint, ok bool) {
-	if hotkeyStr == "" {
-		return 0, 0, false
-	}
-
-	// Normalize separators and case
-	normalized := strings.ToUpper(strings.ReplaceAll(hotkeyStr, " ", ""))
-	parts := strings.Split(normalized, "+")
-
-	modifiers = 0
-	keyCode = 0
-
-	for _, part := range parts {
-		switch part {
-		case "CTRL", "CONTROL":
-			modifiers |= ModCtrl
-		case "ALT":
-			modifiers |= ModAlt
-		case "SHIFT":
-			modifiers |= ModShift
-		case "WIN", "WINDOWS", "META", "SUPER":
-			modifiers |= ModWin
-		default:
-			// This should be the main key
-			if code, found := keyNameToCode[part]; found {
-				keyCode = code
-			} else {
-				// Unknown key
-				return 0, 0, false
-			}
-		}
-	}
-
-	if keyCode == 0 {
-		return 0, 0, false
-	}
-
-	return modifiers, keyCode, true
-}
-
-// FormatHotkey formats modifiers and key code back to a readable string
-func FormatHotkey(modifiers, keyCode uint) string {
-	var parts []string
-
-	if modifiers&ModCtrl != 0 {
-		parts = append(parts, "Ctrl")
-	}
-	if modifiers&ModAlt != 0 {
-		parts = append(parts, "Alt")
-	}
-	if modifiers&ModShift != 0 {
-		parts = append(parts, "Shift")
-	}
-	if modifiers&ModWin != 0 {
-		parts = append(parts, "Win")
-	}
-
-	// Find key name
-	keyName := ""
-	for name, code := range keyNameToCode {
-		if code == keyCode {
-			keyName = name
-			break
-		}
-	}
-
-	if keyName != "" {
-		parts = append(parts, keyName)
-	}
-
-	return strings.Join(parts, "+")
-}
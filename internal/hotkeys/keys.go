@@ -0,0 +1,372 @@
+package hotkeys
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Key modifier constants
+const (
+	ModAlt   uint = 0x0001
+	ModCtrl  uint = 0x0002
+	ModShift uint = 0x0004
+	ModWin   uint = 0x0008
+)
+
+// Virtual key codes
+const (
+	VK_SNAPSHOT = 0x2C // Print Screen
+	VK_F1       = 0x70
+	VK_F2       = 0x71
+	VK_F3       = 0x72
+	VK_F4       = 0x73
+	VK_F5       = 0x74
+	VK_F6       = 0x75
+	VK_F7       = 0x76
+	VK_F8       = 0x77
+	VK_F9       = 0x78
+	VK_F10      = 0x79
+	VK_F11      = 0x7A
+	VK_F12      = 0x7B
+)
+
+// Side-specific modifiers. RegisterHotKey can't tell left and right apart, so a
+// combination naming a side is registered for both and ignored when it fires
+// without that side held.
+const (
+	SideLCtrl uint = 1 << iota
+	SideRCtrl
+	SideLAlt
+	SideRAlt
+	SideLShift
+	SideRShift
+	SideLWin
+	SideRWin
+)
+
+// MaxSequenceLength is the most combinations a chorded sequence may have
+const MaxSequenceLength = 3
+
+// modifierNames lists the modifiers in canonical order. A side-specific name
+// sets both the modifier and its side.
+var modifierNames = []struct {
+	name     string
+	modifier uint
+	side     uint
+	aliases  []string
+}{
+	{"Ctrl", ModCtrl, 0, []string{"CONTROL"}},
+	{"LCtrl", ModCtrl, SideLCtrl, []string{"LCONTROL", "LEFTCTRL", "LEFTCONTROL"}},
+	{"RCtrl", ModCtrl, SideRCtrl, []string{"RCONTROL", "RIGHTCTRL", "RIGHTCONTROL"}},
+	{"Alt", ModAlt, 0, nil},
+	{"LAlt", ModAlt, SideLAlt, []string{"LEFTALT"}},
+	{"RAlt", ModAlt, SideRAlt, []string{"RIGHTALT"}},
+	{"Shift", ModShift, 0, nil},
+	{"LShift", ModShift, SideLShift, []string{"LEFTSHIFT"}},
+	{"RShift", ModShift, SideRShift, []string{"RIGHTSHIFT"}},
+	{"Win", ModWin, 0, []string{"WINDOWS", "META", "SUPER"}},
+	{"LWin", ModWin, SideLWin, []string{"LEFTWIN"}},
+	{"RWin", ModWin, SideRWin, []string{"RIGHTWIN"}},
+}
+
+// keyNames lists every key by virtual key code. The first name is the one
+// FormatHotkey writes; aliases are accepted when parsing, ignoring case.
+var keyNames = []struct {
+	code    uint
+	name    string
+	aliases []string
+}{
+	{VK_SNAPSHOT, "PrintScreen", []string{"PRTSC", "PRTSCN", "SNAPSHOT"}},
+	{0x13, "Pause", []string{"BREAK"}},
+	{0x5D, "Apps", []string{"MENU", "CONTEXTMENU"}},
+
+	// Editing and navigation
+	{0x20, "Space", nil},
+	{0x0D, "Enter", []string{"RETURN"}},
+	{0x09, "Tab", nil},
+	{0x1B, "Escape", []string{"ESC"}},
+	{0x08, "Backspace", nil},
+	{0x2E, "Delete", []string{"DEL"}},
+	{0x2D, "Insert", []string{"INS"}},
+	{0x24, "Home", nil},
+	{0x23, "End", nil},
+	{0x21, "PageUp", []string{"PGUP"}},
+	{0x22, "PageDown", []string{"PGDN"}},
+	{0x26, "Up", []string{"ARROWUP"}},
+	{0x28, "Down", []string{"ARROWDOWN"}},
+	{0x25, "Left", []string{"ARROWLEFT"}},
+	{0x27, "Right", []string{"ARROWRIGHT"}},
+
+	// OEM punctuation, named after the US layout
+	{0xBA, "Semicolon", []string{";"}},
+	{0xBB, "Equals", []string{"=", "+", "PLUS", "EQUAL"}},
+	{0xBC, "Comma", []string{","}},
+	{0xBD, "Minus", []string{"-"}},
+	{0xBE, "Period", []string{"."}},
+	{0xBF, "Slash", []string{"/"}},
+	{0xC0, "Backquote", []string{"`", "GRAVE", "TILDE"}},
+	{0xDB, "BracketLeft", []string{"["}},
+	{0xDC, "Backslash", []string{"\\"}},
+	{0xDD, "BracketRight", []string{"]"}},
+	{0xDE, "Quote", []string{"'"}},
+	{0xE2, "IntlBackslash", []string{"OEM102"}},
+
+	// Numpad
+	{0x60, "Num0", []string{"NUMPAD0"}},
+	{0x61, "Num1", []string{"NUMPAD1"}},
+	{0x62, "Num2", []string{"NUMPAD2"}},
+	{0x63, "Num3", []string{"NUMPAD3"}},
+	{0x64, "Num4", []string{"NUMPAD4"}},
+	{0x65, "Num5", []string{"NUMPAD5"}},
+	{0x66, "Num6", []string{"NUMPAD6"}},
+	{0x67, "Num7", []string{"NUMPAD7"}},
+	{0x68, "Num8", []string{"NUMPAD8"}},
+	{0x69, "Num9", []string{"NUMPAD9"}},
+	{0x6A, "NumMultiply", []string{"NUMPADMULTIPLY"}},
+	{0x6B, "NumAdd", []string{"NUMPADADD"}},
+	{0x6D, "NumSubtract", []string{"NUMPADSUBTRACT"}},
+	{0x6E, "NumDecimal", []string{"NUMPADDECIMAL"}},
+	{0x6F, "NumDivide", []string{"NUMPADDIVIDE"}},
+
+	// Browser keys
+	{0xA6, "BrowserBack", nil},
+	{0xA7, "BrowserForward", nil},
+	{0xA8, "BrowserRefresh", nil},
+	{0xA9, "BrowserStop", nil},
+	{0xAA, "BrowserSearch", nil},
+	{0xAB, "BrowserFavorites", nil},
+	{0xAC, "BrowserHome", nil},
+
+	// Media and launch keys
+	{0xAD, "VolumeMute", []string{"AUDIOVOLUMEMUTE"}},
+	{0xAE, "VolumeDown", []string{"AUDIOVOLUMEDOWN"}},
+	{0xAF, "VolumeUp", []string{"AUDIOVOLUMEUP"}},
+	{0xB0, "MediaNext", []string{"MEDIATRACKNEXT"}},
+	{0xB1, "MediaPrev", []string{"MEDIAPREVIOUS", "MEDIATRACKPREVIOUS"}},
+	{0xB2, "MediaStop", nil},
+	{0xB3, "MediaPlayPause", []string{"PLAYPAUSE"}},
+	{0xB4, "LaunchMail", []string{"MAIL"}},
+	{0xB5, "LaunchMedia", []string{"LAUNCHMEDIASELECT"}},
+	{0xB6, "LaunchApp1", nil},
+	{0xB7, "LaunchApp2", nil},
+}
+
+var (
+	// keyNameToCode maps upper-case key names and aliases to virtual key codes
+	keyNameToCode = make(map[string]uint)
+	// keyCodeToName maps virtual key codes to canonical key names
+	keyCodeToName = make(map[uint]string)
+	// modifierByName maps upper-case modifier names to their modifier and side
+	modifierByName = make(map[string][2]uint)
+)
+
+func init() {
+	addKey := func(code uint, name string, aliases ...string) {
+		keyCodeToName[code] = name
+		keyNameToCode[strings.ToUpper(name)] = code
+		for _, alias := range aliases {
+			keyNameToCode[alias] = code
+		}
+	}
+	// Letters A-Z (0x41-0x5A) and digits 0-9 (0x30-0x39)
+	for c := 'A'; c <= 'Z'; c++ {
+		addKey(uint(c), string(c))
+	}
+	for c := '0'; c <= '9'; c++ {
+		addKey(uint(c), string(c))
+	}
+	// F1-F24 (0x70-0x87)
+	for n := uint(1); n <= 24; n++ {
+		addKey(VK_F1+n-1, fmt.Sprintf("F%d", n))
+	}
+	for _, k := range keyNames {
+		addKey(k.code, k.name, k.aliases...)
+	}
+
+	for _, m := range modifierNames {
+		modifierByName[strings.ToUpper(m.name)] = [2]uint{m.modifier, m.side}
+		for _, alias := range m.aliases {
+			modifierByName[alias] = [2]uint{m.modifier, m.side}
+		}
+	}
+}
+
+// Combo is one key combination: modifiers held while a key is pressed
+type Combo struct {
+	Modifiers uint // ModCtrl, ModAlt, ModShift, ModWin
+	KeyCode   uint // Virtual key code
+	Sides     uint // SideLCtrl etc. for modifiers that must be held on one side
+}
+
+// String formats the combination canonically, e.g. "Ctrl+Shift+PrintScreen"
+func (c Combo) String() string {
+	var parts []string
+	for _, m := range modifierNames {
+		if c.Modifiers&m.modifier == 0 {
+			continue
+		}
+		// A side-specific name replaces the generic one
+		sided := c.Sides & sidesOf(m.modifier)
+		if (m.side == 0 && sided == 0) || c.Sides&m.side != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	if name, ok := keyCodeToName[c.KeyCode]; ok {
+		parts = append(parts, name)
+	} else if c.KeyCode != 0 {
+		parts = append(parts, fmt.Sprintf("0x%02X", c.KeyCode))
+	}
+	return strings.Join(parts, "+")
+}
+
+// sidesOf returns both side bits of a modifier
+func sidesOf(modifier uint) uint {
+	var sides uint
+	for _, m := range modifierNames {
+		if m.modifier == modifier {
+			sides |= m.side
+		}
+	}
+	return sides
+}
+
+// Sequence is a hotkey: a single combination, or a chord of combinations
+// pressed one after the other, like "Ctrl+Shift+S, R"
+type Sequence []Combo
+
+// String formats the sequence canonically, separating combinations with ", "
+func (s Sequence) String() string {
+	parts := make([]string, len(s))
+	for i, c := range s {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Conflicts reports whether two sequences can't be registered together: they
+// are the same, or one starts with the other. Sides are ignored since the
+// combinations register the same way. Sequences that only share a first
+// combination are fine.
+func (s Sequence) Conflicts(other Sequence) bool {
+	n := min(len(s), len(other))
+	for i := 0; i < n; i++ {
+		if s[i].Modifiers != other[i].Modifiers || s[i].KeyCode != other[i].KeyCode {
+			return false
+		}
+	}
+	return n > 0
+}
+
+// matches reports whether a pressed combination, with the sides that were
+// held, satisfies a combination of a hotkey
+func (c Combo) matches(pressed Combo) bool {
+	return c.Modifiers == pressed.Modifiers && c.KeyCode == pressed.KeyCode && c.Sides&^pressed.Sides == 0
+}
+
+// sameKeys reports whether two combinations register the same way
+func (c Combo) sameKeys(other Combo) bool {
+	return c.Modifiers == other.Modifiers && c.KeyCode == other.KeyCode
+}
+
+// startsWith reports whether the sequence begins with the pressed combinations
+func (s Sequence) startsWith(pressed Sequence) bool {
+	if len(pressed) > len(s) {
+		return false
+	}
+	for i, p := range pressed {
+		if !s[i].matches(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// errNoKey is returned for a combination of modifiers only
+var errNoKey = errors.New("no key")
+
+// ParseSequence parses a hotkey string like "Ctrl+Shift+PrintScreen" or a
+// chorded sequence like "Ctrl+Shift+S, R". Names are case-insensitive and
+// spaces are ignored; a "+" or "," right after a separator is the key itself,
+// as in "Ctrl++" or "Ctrl+,".
+func ParseSequence(hotkeyStr string) (Sequence, error) {
+	var seq Sequence
+	for _, tokens := range splitHotkey(hotkeyStr) {
+		var combo Combo
+		for _, token := range tokens {
+			upper := strings.ToUpper(token)
+			if m, ok := modifierByName[upper]; ok {
+				combo.Modifiers |= m[0]
+				combo.Sides |= m[1]
+				continue
+			}
+			code, ok := keyNameToCode[upper]
+			if !ok {
+				return nil, fmt.Errorf("unknown key %q", token)
+			}
+			if combo.KeyCode != 0 {
+				return nil, fmt.Errorf("more than one key in %q", strings.Join(tokens, "+"))
+			}
+			combo.KeyCode = code
+		}
+		if combo.KeyCode == 0 {
+			return nil, errNoKey
+		}
+		seq = append(seq, combo)
+	}
+	if len(seq) > MaxSequenceLength {
+		return nil, fmt.Errorf("more than %d combinations", MaxSequenceLength)
+	}
+	return seq, nil
+}
+
+// splitHotkey splits a hotkey string into combinations of tokens
+func splitHotkey(hotkeyStr string) [][]string {
+	var combos [][]string
+	var tokens []string
+	var token strings.Builder
+	expectKey := true // At the start of a token, where "+" and "," are keys
+
+	flush := func() {
+		if token.Len() > 0 {
+			tokens = append(tokens, token.String())
+			token.Reset()
+		}
+	}
+	for _, r := range hotkeyStr {
+		switch {
+		case unicode.IsSpace(r):
+		case expectKey:
+			token.WriteRune(r)
+			expectKey = false
+		case r == '+':
+			flush()
+			expectKey = true
+		case r == ',':
+			flush()
+			combos = append(combos, tokens)
+			tokens = nil
+			expectKey = true
+		default:
+			token.WriteRune(r)
+		}
+	}
+	flush()
+	return append(combos, tokens)
+}
+
+// ParseHotkeyString parses a hotkey string like "Ctrl+Shift+PrintScreen" into modifiers and key code.
+// Sequences aren't single combinations and don't parse; use ParseSequence for those.
+func ParseHotkeyString(hotkeyStr string) (modifiers uint, keyCode uint, ok bool) {
+	seq, err := ParseSequence(hotkeyStr)
+	if err != nil || len(seq) != 1 {
+		return 0, 0, false
+	}
+	return seq[0].Modifiers, seq[0].KeyCode, true
+}
+
+// FormatHotkey formats modifiers and key code back to a canonical string
+func FormatHotkey(modifiers, keyCode uint) string {
+	return Combo{Modifiers: modifiers, KeyCode: keyCode}.String()
+}
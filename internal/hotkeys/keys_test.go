@@ -0,0 +1,148 @@
+package hotkeys
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSequence_RoundTrip(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Sequence
+		out  string // Canonical form
+	}{
+		{"PrintScreen", Sequence{{KeyCode: VK_SNAPSHOT}}, "PrintScreen"},
+		{"ctrl+shift+prtsc", Sequence{{Modifiers: ModCtrl | ModShift, KeyCode: VK_SNAPSHOT}}, "Ctrl+Shift+PrintScreen"},
+		{"Shift + Ctrl + Snapshot", Sequence{{Modifiers: ModCtrl | ModShift, KeyCode: VK_SNAPSHOT}}, "Ctrl+Shift+PrintScreen"},
+		{"Win+Alt+Ctrl+Shift+A", Sequence{{Modifiers: ModCtrl | ModAlt | ModShift | ModWin, KeyCode: 0x41}}, "Ctrl+Alt+Shift+Win+A"},
+		{"Ctrl+Esc", Sequence{{Modifiers: ModCtrl, KeyCode: 0x1B}}, "Ctrl+Escape"},
+		{"F13", Sequence{{KeyCode: 0x7C}}, "F13"},
+		{"F24", Sequence{{KeyCode: 0x87}}, "F24"},
+		{"Ctrl+;", Sequence{{Modifiers: ModCtrl, KeyCode: 0xBA}}, "Ctrl+Semicolon"},
+		{"Ctrl++", Sequence{{Modifiers: ModCtrl, KeyCode: 0xBB}}, "Ctrl+Equals"},
+		{"Ctrl+,", Sequence{{Modifiers: ModCtrl, KeyCode: 0xBC}}, "Ctrl+Comma"},
+		{"Alt+`", Sequence{{Modifiers: ModAlt, KeyCode: 0xC0}}, "Alt+Backquote"},
+		{`Ctrl+\`, Sequence{{Modifiers: ModCtrl, KeyCode: 0xDC}}, "Ctrl+Backslash"},
+		{"Ctrl+Numpad7", Sequence{{Modifiers: ModCtrl, KeyCode: 0x67}}, "Ctrl+Num7"},
+		{"Alt+NumAdd", Sequence{{Modifiers: ModAlt, KeyCode: 0x6B}}, "Alt+NumAdd"},
+		{"PlayPause", Sequence{{KeyCode: 0xB3}}, "MediaPlayPause"},
+		{"Shift+BrowserSearch", Sequence{{Modifiers: ModShift, KeyCode: 0xAA}}, "Shift+BrowserSearch"},
+		{"RCtrl+S", Sequence{{Modifiers: ModCtrl, KeyCode: 0x53, Sides: SideRCtrl}}, "RCtrl+S"},
+		{"LeftAlt+Shift+1", Sequence{{Modifiers: ModAlt | ModShift, KeyCode: 0x31, Sides: SideLAlt}}, "LAlt+Shift+1"},
+		{
+			"Ctrl+Shift+S, R",
+			Sequence{{Modifiers: ModCtrl | ModShift, KeyCode: 0x53}, {KeyCode: 0x52}},
+			"Ctrl+Shift+S, R",
+		},
+		{
+			"Ctrl+,,Ctrl+.",
+			Sequence{{Modifiers: ModCtrl, KeyCode: 0xBC}, {Modifiers: ModCtrl, KeyCode: 0xBE}},
+			"Ctrl+Comma, Ctrl+Period",
+		},
+		{
+			"ctrl+k, ctrl+k, w",
+			Sequence{{Modifiers: ModCtrl, KeyCode: 0x4B}, {Modifiers: ModCtrl, KeyCode: 0x4B}, {KeyCode: 0x57}},
+			"Ctrl+K, Ctrl+K, W",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			seq, err := ParseSequence(tt.in)
+			if err != nil {
+				t.Fatalf("ParseSequence(%q) error = %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(seq, tt.want) {
+				t.Fatalf("ParseSequence(%q) = %+v, want %+v", tt.in, seq, tt.want)
+			}
+			if got := seq.String(); got != tt.out {
+				t.Errorf("String() = %q, want %q", got, tt.out)
+			}
+			again, err := ParseSequence(tt.out)
+			if err != nil || !reflect.DeepEqual(again, seq) {
+				t.Errorf("ParseSequence(%q) = %+v, %v; want %+v", tt.out, again, err, seq)
+			}
+		})
+	}
+}
+
+func TestParseSequence_Errors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"Ctrl+Shift",
+		"Ctrl+",
+		"Ctrl+Banana",
+		"Ctrl+A+B",
+		"Ctrl+S,",
+		", R",
+		"A, B, C, D",
+	} {
+		if seq, err := ParseSequence(in); err == nil {
+			t.Errorf("ParseSequence(%q) = %+v, want an error", in, seq)
+		}
+	}
+}
+
+// Every key name and alias formats back to its canonical name, which parses
+// to the same code
+func TestKeyNames_RoundTrip(t *testing.T) {
+	for name, code := range keyNameToCode {
+		canonical := FormatHotkey(0, code)
+		if _, got, ok := ParseHotkeyString(canonical); !ok || got != code {
+			t.Errorf("%s: FormatHotkey = %q, which parses to 0x%02X, want 0x%02X", name, canonical, got, code)
+		}
+	}
+}
+
+func TestFormatHotkey_Deterministic(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		if got := FormatHotkey(ModCtrl, VK_SNAPSHOT); got != "Ctrl+PrintScreen" {
+			t.Fatalf("FormatHotkey = %q, want %q", got, "Ctrl+PrintScreen")
+		}
+	}
+}
+
+func TestParseHotkeyString_RejectsSequences(t *testing.T) {
+	if _, _, ok := ParseHotkeyString("Ctrl+S, R"); ok {
+		t.Error("ParseHotkeyString accepted a sequence")
+	}
+}
+
+func TestSequence_Conflicts(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Ctrl+S", "ctrl+s", true},
+		{"Ctrl+S", "Ctrl+S, R", true},
+		{"Ctrl+S, R", "Ctrl+S, W", false},
+		{"Ctrl+S, R", "Ctrl+S, R", true},
+		{"LCtrl+S", "RCtrl+S", true},
+		{"Ctrl+S", "Ctrl+Shift+S", false},
+	}
+	for _, tt := range tests {
+		a, _ := ParseSequence(tt.a)
+		b, _ := ParseSequence(tt.b)
+		if got := a.Conflicts(b); got != tt.want {
+			t.Errorf("%q.Conflicts(%q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSequence_StartsWith(t *testing.T) {
+	seq, _ := ParseSequence("RCtrl+S, R")
+	tests := []struct {
+		pressed Sequence
+		want    bool
+	}{
+		{Sequence{{Modifiers: ModCtrl, KeyCode: 0x53, Sides: SideRCtrl}}, true},
+		{Sequence{{Modifiers: ModCtrl, KeyCode: 0x53, Sides: SideLCtrl | SideRCtrl}}, true},
+		{Sequence{{Modifiers: ModCtrl, KeyCode: 0x53, Sides: SideLCtrl}}, false},
+		{Sequence{{Modifiers: ModCtrl, KeyCode: 0x53, Sides: SideRCtrl}, {KeyCode: 0x52}}, true},
+		{Sequence{{Modifiers: ModCtrl, KeyCode: 0x53, Sides: SideRCtrl}, {KeyCode: 0x57}}, false},
+	}
+	for _, tt := range tests {
+		if got := seq.startsWith(tt.pressed); got != tt.want {
+			t.Errorf("startsWith(%v) = %v, want %v", tt.pressed, got, tt.want)
+		}
+	}
+}
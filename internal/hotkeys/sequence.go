@@ -0,0 +1,234 @@
+//go:build windows
+
+package hotkeys
+
+import (
+	"errors"
+	"time"
+)
+
+// ChordTimeout is how long a sequence waits for its next combination
+const ChordTimeout = 1500 * time.Millisecond
+
+// sideKeys maps side bits to the virtual keys checked with GetAsyncKeyState
+var sideKeys = []struct {
+	side   uint
+	vkCode uintptr
+}{
+	{SideLCtrl, 0xA2},
+	{SideRCtrl, 0xA3},
+	{SideLAlt, 0xA4},
+	{SideRAlt, 0xA5},
+	{SideLShift, 0xA0},
+	{SideRShift, 0xA1},
+	{SideLWin, 0x5B},
+	{SideRWin, 0x5C},
+}
+
+// chord is a sequence in progress: the combinations pressed so far and the
+// second-stage registrations that wait for the next one
+type chord struct {
+	pressed  Sequence      // Sides are the ones held when each was pressed
+	next     map[int]Combo // Stage ID -> combination registered for this stage only
+	expect   []Combo       // Every combination that continues a sequence
+	deadline time.Time
+}
+
+// heldSides returns the side-specific modifiers held right now
+func heldSides() uint {
+	var held uint
+	for _, k := range sideKeys {
+		if state, _, _ := procGetAsyncKeyState.Call(k.vkCode); state&0x8000 != 0 {
+			held |= k.side
+		}
+	}
+	return held
+}
+
+// allocStageID returns a stage ID no prefix or chord stage uses, or 0 if all are taken
+func (m *HotkeyManager) allocStageID() int {
+	for id := firstStageID; id < probeHotkeyID; id++ {
+		if _, used := m.prefixes[id]; used {
+			continue
+		}
+		if m.chord != nil {
+			if _, used := m.chord.next[id]; used {
+				continue
+			}
+		}
+		return id
+	}
+	return 0
+}
+
+// registerPrefix registers the first combination of a sequence, unless another
+// sequence starting the same way already did
+func (m *HotkeyManager) registerPrefix(modifiers, keyCode uint) error {
+	combo := Combo{Modifiers: modifiers, KeyCode: keyCode}
+	for _, prefix := range m.prefixes {
+		if prefix.sameKeys(combo) {
+			return nil
+		}
+	}
+
+	id := m.allocStageID()
+	if id == 0 {
+		return errors.New("no free hotkey IDs")
+	}
+	ret, _, err := procRegisterHotKey.Call(0, uintptr(id), uintptr(modifiers), uintptr(keyCode))
+	if ret == 0 {
+		return registerError(err)
+	}
+	m.prefixes[id] = combo
+	return nil
+}
+
+// releasePrefix unregisters a sequence's first combination once no sequence starts with it
+func (m *HotkeyManager) releasePrefix(modifiers, keyCode uint) {
+	combo := Combo{Modifiers: modifiers, KeyCode: keyCode}
+	m.mu.Lock()
+	for _, hk := range m.hotkeys {
+		if len(hk.Then) > 0 && combo.sameKeys(Combo{Modifiers: hk.Modifiers, KeyCode: hk.KeyCode}) {
+			m.mu.Unlock()
+			return
+		}
+	}
+	m.mu.Unlock()
+
+	for id, prefix := range m.prefixes {
+		if prefix.sameKeys(combo) {
+			procUnregisterHotKey.Call(0, uintptr(id))
+			delete(m.prefixes, id)
+		}
+	}
+}
+
+// endChord abandons the sequence in progress and drops its stage registrations
+func (m *HotkeyManager) endChord() {
+	if m.chord == nil {
+		return
+	}
+	for id := range m.chord.next {
+		procUnregisterHotKey.Call(0, uintptr(id))
+	}
+	m.chord = nil
+}
+
+// handleHotkey handles a WM_HOTKEY on the message loop thread. It returns the
+// ID of the hotkey the press completes, if any.
+func (m *HotkeyManager) handleHotkey(id int) (int, bool) {
+	m.mu.Lock()
+	hk := m.hotkeys[id]
+	m.mu.Unlock()
+
+	var pressed Combo
+	if combo, ok := m.chordStage(id); ok {
+		pressed = combo
+	} else if combo, ok := m.prefixes[id]; ok {
+		pressed = combo
+	} else if hk != nil {
+		pressed = Combo{Modifiers: hk.Modifiers, KeyCode: hk.KeyCode}
+	} else {
+		return 0, false
+	}
+	pressed.Sides = heldSides()
+
+	// A combination the sequence in progress waits for continues it, even if
+	// it is registered for something else as well
+	if m.chord != nil {
+		for _, next := range m.chord.expect {
+			if next.matches(pressed) {
+				return m.advanceChord(append(m.chord.pressed, pressed))
+			}
+		}
+	}
+	m.endChord()
+
+	if _, ok := m.prefixes[id]; ok {
+		return m.advanceChord(Sequence{pressed})
+	}
+	if hk != nil && len(hk.Then) == 0 && hk.Sides&^pressed.Sides == 0 {
+		return id, true
+	}
+	return 0, false
+}
+
+// chordStage returns the combination a stage ID of the sequence in progress stands for
+func (m *HotkeyManager) chordStage(id int) (Combo, bool) {
+	if m.chord == nil {
+		return Combo{}, false
+	}
+	combo, ok := m.chord.next[id]
+	return combo, ok
+}
+
+// advanceChord moves the sequence in progress to the pressed combinations. A
+// complete sequence ends it and is returned; otherwise the combinations that
+// may follow are registered until ChordTimeout.
+func (m *HotkeyManager) advanceChord(pressed Sequence) (int, bool) {
+	m.endChord()
+
+	var expect []Combo
+	m.mu.Lock()
+	for _, hk := range m.hotkeys {
+		seq := hk.Sequence()
+		if len(hk.Then) == 0 || !seq.startsWith(pressed) {
+			continue
+		}
+		if len(seq) == len(pressed) {
+			m.mu.Unlock()
+			return hk.ID, true
+		}
+		expect = append(expect, seq[len(pressed)])
+	}
+	m.mu.Unlock()
+
+	if len(expect) == 0 {
+		return 0, false
+	}
+
+	m.chord = &chord{
+		pressed:  pressed,
+		next:     make(map[int]Combo),
+		expect:   expect,
+		deadline: time.Now().Add(ChordTimeout),
+	}
+	for _, combo := range expect {
+		m.registerStage(combo)
+	}
+	return 0, false
+}
+
+// registerStage registers a combination for the sequence in progress. One that
+// is registered already, as a hotkey, a prefix or for another sequence, reaches
+// handleHotkey anyway.
+func (m *HotkeyManager) registerStage(combo Combo) {
+	combo.Sides = 0
+	for _, c := range m.chord.next {
+		if c.sameKeys(combo) {
+			return
+		}
+	}
+	for _, prefix := range m.prefixes {
+		if prefix.sameKeys(combo) {
+			return
+		}
+	}
+	m.mu.Lock()
+	for _, hk := range m.hotkeys {
+		if len(hk.Then) == 0 && combo.sameKeys(Combo{Modifiers: hk.Modifiers, KeyCode: hk.KeyCode}) {
+			m.mu.Unlock()
+			return
+		}
+	}
+	m.mu.Unlock()
+
+	id := m.allocStageID()
+	if id == 0 {
+		return
+	}
+	// Another application may own the combination; the sequence then can't complete
+	if ret, _, _ := procRegisterHotKey.Call(0, uintptr(id), uintptr(combo.Modifiers), uintptr(combo.KeyCode)); ret != 0 {
+		m.chord.next[id] = combo
+	}
+}
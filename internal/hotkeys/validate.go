@@ -0,0 +1,148 @@
+package hotkeys
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Registration statuses
+const (
+	StatusOK      = "ok"      // Registered and working
+	StatusTaken   = "taken"   // Another application already registered the combination
+	StatusInvalid = "invalid" // Unknown key, or a reserved or unusable combination
+)
+
+var (
+	// ErrHotkeyTaken is returned when another application owns the combination
+	ErrHotkeyTaken = errors.New("already in use by another application")
+	// ErrInvalidHotkey is wrapped by every validation error
+	ErrInvalidHotkey = errors.New("invalid hotkey")
+)
+
+// Registration is the outcome of registering one hotkey
+type Registration struct {
+	ID          int      `json:"id"` // Passed to the callback; 0 unless Status is StatusOK
+	Keys        string   `json:"keys"`
+	Status      string   `json:"status"`
+	Error       string   `json:"error,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"` // Free combinations to try instead
+}
+
+// StatusOf maps a registration or validation error to a status
+func StatusOf(err error) string {
+	switch {
+	case err == nil:
+		return StatusOK
+	case errors.Is(err, ErrHotkeyTaken):
+		return StatusTaken
+	default:
+		return StatusInvalid
+	}
+}
+
+// reservedHotkeys are combinations Windows handles itself. RegisterHotKey
+// either fails for them or steals them from the shell.
+var reservedHotkeys = []struct {
+	modifiers uint
+	keyCode   uint
+	reason    string
+}{
+	{ModCtrl | ModAlt, 0x2E, "reserved by Windows for the security screen"}, // Ctrl+Alt+Delete
+	{ModCtrl | ModShift, 0x1B, "reserved by Windows for Task Manager"},      // Ctrl+Shift+Esc
+	{ModCtrl, 0x1B, "reserved by Windows for the Start menu"},               // Ctrl+Esc
+	{ModAlt, 0x09, "reserved by Windows for switching windows"},             // Alt+Tab
+	{ModAlt | ModShift, 0x09, "reserved by Windows for switching windows"},  // Alt+Shift+Tab
+	{ModAlt, 0x1B, "reserved by Windows for switching windows"},             // Alt+Esc
+	{ModAlt, VK_F4, "reserved by Windows for closing windows"},              // Alt+F4
+	{ModWin, 0x4C, "reserved by Windows for locking the PC"},                // Win+L
+	{ModWin, 0x44, "reserved by Windows for showing the desktop"},           // Win+D
+	{ModWin, 0x45, "reserved by Windows for File Explorer"},                 // Win+E
+	{ModWin, 0x52, "reserved by Windows for the Run dialog"},                // Win+R
+	{ModWin, 0x09, "reserved by Windows for Task View"},                     // Win+Tab
+	{ModWin, VK_SNAPSHOT, "reserved by Windows for screenshots"},            // Win+PrintScreen
+	{ModWin | ModShift, 0x53, "reserved by Windows for Snipping Tool"},      // Win+Shift+S
+	{0, VK_F12, "reserved for debuggers"},                                   // F12
+}
+
+// standalone reports whether a key may be a hotkey without modifiers.
+// Other keys would stop working everywhere else, e.g. typing a letter.
+func standalone(keyCode uint) bool {
+	switch {
+	case keyCode == VK_SNAPSHOT, keyCode == 0x13: // PrintScreen, Pause
+		return true
+	case keyCode >= VK_F1 && keyCode <= VK_F1+23: // F1-F24
+		return true
+	case keyCode >= 0xA6 && keyCode <= 0xB7: // Browser, media and launch keys
+		return true
+	}
+	return false
+}
+
+// reservedReason returns why Windows keeps a combination to itself, or "" if it doesn't
+func reservedReason(modifiers, keyCode uint) string {
+	for _, r := range reservedHotkeys {
+		if r.modifiers == modifiers && r.keyCode == keyCode {
+			return r.reason
+		}
+	}
+	return ""
+}
+
+// ValidateHotkey rejects combinations that can't or shouldn't be global hotkeys
+func ValidateHotkey(modifiers, keyCode uint) error {
+	if keyCode == 0 {
+		return fmt.Errorf("%w: no key", ErrInvalidHotkey)
+	}
+	if reason := reservedReason(modifiers, keyCode); reason != "" {
+		return fmt.Errorf("%w: %s", ErrInvalidHotkey, reason)
+	}
+	if !standalone(keyCode) && modifiers&(ModCtrl|ModAlt|ModWin) == 0 {
+		return fmt.Errorf("%w: needs Ctrl, Alt or Win", ErrInvalidHotkey)
+	}
+	return nil
+}
+
+// ValidateHotkeyString parses and validates a hotkey string like "Ctrl+Shift+S"
+// or a sequence like "Ctrl+Shift+S, R". Only the first combination stays
+// registered, so the ones after it may be plain keys, but none may be reserved.
+func ValidateHotkeyString(hotkeyStr string) (Sequence, error) {
+	seq, err := ParseSequence(hotkeyStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHotkey, err)
+	}
+	if err := ValidateHotkey(seq[0].Modifiers, seq[0].KeyCode); err != nil {
+		return nil, err
+	}
+	for _, combo := range seq[1:] {
+		if reason := reservedReason(combo.Modifiers, combo.KeyCode); reason != "" {
+			return nil, fmt.Errorf("%w: %s is %s", ErrInvalidHotkey, combo, reason)
+		}
+	}
+	return seq, nil
+}
+
+// alternativeModifiers are tried in order when suggesting replacements
+var alternativeModifiers = []uint{
+	ModCtrl,
+	ModAlt,
+	ModCtrl | ModShift,
+	ModCtrl | ModAlt,
+	ModAlt | ModShift,
+	ModCtrl | ModAlt | ModShift,
+}
+
+// alternatives returns valid combinations of the same key with more modifiers,
+// closest first
+func alternatives(combo Combo) []Combo {
+	var result []Combo
+	seen := map[uint]bool{combo.Modifiers: true}
+	for _, extra := range alternativeModifiers {
+		mods := combo.Modifiers | extra
+		if seen[mods] || ValidateHotkey(mods, combo.KeyCode) != nil {
+			continue
+		}
+		seen[mods] = true
+		result = append(result, Combo{Modifiers: mods, KeyCode: combo.KeyCode, Sides: combo.Sides})
+	}
+	return result
+}
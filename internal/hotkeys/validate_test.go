@@ -0,0 +1,97 @@
+package hotkeys
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestValidateHotkeyString(t *testing.T) {
+	tests := []struct {
+		keys    string
+		wantErr bool
+	}{
+		{"PrintScreen", false},
+		{"Ctrl+Shift+PrintScreen", false},
+		{"F9", false},
+		{"Ctrl+Shift+S", false},
+		{"Alt+1", false},
+		{"Win+Shift+X", false},
+		{"", true},
+		{"Ctrl+Shift", true},
+		{"Ctrl+Banana", true},
+		{"S", true},
+		{"Shift+S", true},
+		{"Space", true},
+		{"Ctrl+Alt+Delete", true},
+		{"Ctrl+Shift+Esc", true},
+		{"Alt+Tab", true},
+		{"Alt+F4", true},
+		{"Win+L", true},
+		{"Win+Shift+S", true},
+		{"F12", true},
+		{"Ctrl+F12", false},
+		{"F24", false},
+		{"MediaPlayPause", false},
+		{"Num5", true},
+		{"Ctrl+Num5", false},
+		{"Ctrl+Shift+S, R", false},
+		{"R, Ctrl+S", true},
+		{"Ctrl+Shift+S, Alt+Tab", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.keys, func(t *testing.T) {
+			_, err := ValidateHotkeyString(tt.keys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateHotkeyString(%q) error = %v, wantErr %v", tt.keys, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidHotkey) {
+				t.Errorf("error %v does not wrap ErrInvalidHotkey", err)
+			}
+		})
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, StatusOK},
+		{ErrHotkeyTaken, StatusTaken},
+		{fmt.Errorf("register: %w", ErrHotkeyTaken), StatusTaken},
+		{fmt.Errorf("%w: no key", ErrInvalidHotkey), StatusInvalid},
+		{errors.New("access denied"), StatusInvalid},
+	}
+	for _, tt := range tests {
+		if got := StatusOf(tt.err); got != tt.want {
+			t.Errorf("StatusOf(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestAlternatives(t *testing.T) {
+	var got []string
+	for _, alt := range alternatives(Combo{KeyCode: VK_SNAPSHOT}) {
+		got = append(got, alt.String())
+	}
+	want := []string{
+		"Ctrl+PrintScreen",
+		"Alt+PrintScreen",
+		"Ctrl+Shift+PrintScreen",
+		"Ctrl+Alt+PrintScreen",
+		"Alt+Shift+PrintScreen",
+		"Ctrl+Alt+Shift+PrintScreen",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("alternatives = %q, want %q", got, want)
+	}
+
+	// Reserved combinations are never suggested
+	for _, alt := range alternatives(Combo{Modifiers: ModShift, KeyCode: 0x1B}) {
+		if alt.Modifiers == ModCtrl|ModShift {
+			t.Errorf("suggested Ctrl+Shift+Esc")
+		}
+	}
+}
@@ -0,0 +1,348 @@
+// Package imagediff compares two screenshots pixel by pixel and renders the
+// differences as an overlay, side-by-side view or onion skin.
+package imagediff
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"sort"
+)
+
+// MaxAlignOffset is the largest MaxOffset searched. The search tries every
+// offset in a square of that radius, so larger values take minutes.
+const MaxAlignOffset = 64
+
+// MaxRegions is the most changed regions a comparison reports. Past it, as
+// with noise all over the image, only the largest are kept.
+const MaxRegions = 500
+
+// Options controls how two images are compared
+type Options struct {
+	Tolerance     int     `json:"tolerance"`     // Max per-channel difference (0-255) still treated as equal
+	MaxOffset     int     `json:"maxOffset"`     // Search up to this many pixels in each direction for the best alignment (0 = top-left aligned, at most MaxAlignOffset)
+	MergeDistance int     `json:"mergeDistance"` // Changed pixels at most this far apart are reported as one region (0 = default)
+	OnionOpacity  float64 `json:"onionOpacity"`  // Opacity of the second image in the onion skin (0-1, 0 = default)
+}
+
+// DefaultOptions returns the default compare options
+func DefaultOptions() Options {
+	return Options{
+		Tolerance:     16,
+		MaxOffset:     0,
+		MergeDistance: 8,
+		OnionOpacity:  0.5,
+	}
+}
+
+// withDefaults fills in zero or out-of-range fields
+func (o Options) withDefaults() Options {
+	def := DefaultOptions()
+	if o.Tolerance < 0 {
+		o.Tolerance = 0
+	}
+	o.MaxOffset = min(max(o.MaxOffset, 0), MaxAlignOffset)
+	if o.MergeDistance <= 0 {
+		o.MergeDistance = def.MergeDistance
+	}
+	if o.OnionOpacity <= 0 || o.OnionOpacity > 1 {
+		o.OnionOpacity = def.OnionOpacity
+	}
+	return o
+}
+
+// Rect is a changed area in compare canvas coordinates
+type Rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Result summarizes a comparison
+type Result struct {
+	Width         int     `json:"width"` // Compare canvas size (union of both images after alignment)
+	Height        int     `json:"height"`
+	OffsetX       int     `json:"offsetX"` // Position of the second image relative to the first
+	OffsetY       int     `json:"offsetY"`
+	ChangedPixels int     `json:"changedPixels"`
+	TotalPixels   int     `json:"totalPixels"`
+	Similarity    float64 `json:"similarity"` // 1 = identical, 0 = every pixel differs
+	Regions       []Rect  `json:"regions"`    // Bounding boxes of changed areas, top to bottom, at most MaxRegions
+}
+
+// Diff is the outcome of Compare: the metrics plus both images placed on a
+// shared canvas, used by the renderers
+type Diff struct {
+	Result
+	a, b    *image.RGBA // Canvas-sized; transparent where the image doesn't cover
+	originA image.Point // Top-left of each image on the canvas
+	originB image.Point
+	sizeA   image.Point
+	sizeB   image.Point
+	mask    []bool
+	opts    Options
+}
+
+// Compare aligns b to a and computes the difference mask, changed regions and
+// similarity. Pixels covered by only one of the images count as changed.
+func Compare(a, b image.Image, opts Options) *Diff {
+	opts = opts.withDefaults()
+	ra, rb := toRGBA(a), toRGBA(b)
+
+	var offset image.Point
+	if opts.MaxOffset > 0 {
+		offset = findOffset(ra, rb, opts.MaxOffset)
+	}
+
+	// Canvas is the union of a at the origin and b at the offset
+	boundsA := ra.Bounds()
+	boundsB := rb.Bounds().Add(offset)
+	union := boundsA.Union(boundsB)
+	shift := union.Min
+
+	d := &Diff{
+		originA: boundsA.Min.Sub(shift),
+		originB: boundsB.Min.Sub(shift),
+		sizeA:   boundsA.Size(),
+		sizeB:   boundsB.Size(),
+		opts:    opts,
+	}
+	d.Width, d.Height = union.Dx(), union.Dy()
+	d.OffsetX, d.OffsetY = offset.X, offset.Y
+	d.TotalPixels = d.Width * d.Height
+
+	canvas := image.Rect(0, 0, d.Width, d.Height)
+	d.a = image.NewRGBA(canvas)
+	d.b = image.NewRGBA(canvas)
+	draw.Draw(d.a, image.Rectangle{d.originA, d.originA.Add(d.sizeA)}, ra, ra.Bounds().Min, draw.Src)
+	draw.Draw(d.b, image.Rectangle{d.originB, d.originB.Add(d.sizeB)}, rb, rb.Bounds().Min, draw.Src)
+
+	d.mask = make([]bool, d.TotalPixels)
+	for i := range d.mask {
+		if pixelsDiffer(d.a.Pix[i*4:i*4+4], d.b.Pix[i*4:i*4+4], opts.Tolerance) {
+			d.mask[i] = true
+			d.ChangedPixels++
+		}
+	}
+
+	if d.TotalPixels > 0 {
+		d.Similarity = 1 - float64(d.ChangedPixels)/float64(d.TotalPixels)
+	}
+	d.Regions = sortRegions(findRegions(d.mask, d.Width, d.Height, opts.MergeDistance))
+	return d
+}
+
+// Changed reports whether the canvas pixel (x, y) differs
+func (d *Diff) Changed(x, y int) bool {
+	if x < 0 || y < 0 || x >= d.Width || y >= d.Height {
+		return false
+	}
+	return d.mask[y*d.Width+x]
+}
+
+// toRGBA returns img as an *image.RGBA with bounds starting at (0, 0)
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	if rgba, ok := img.(*image.RGBA); ok && bounds.Min == (image.Point{}) {
+		return rgba
+	}
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// pixelsDiffer compares two RGBA pixels channel by channel
+func pixelsDiffer(p, q []uint8, tolerance int) bool {
+	for c := 0; c < 4; c++ {
+		delta := int(p[c]) - int(q[c])
+		if delta < 0 {
+			delta = -delta
+		}
+		if delta > tolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// alignSamples is roughly how many pixels are compared per candidate offset
+const alignSamples = 20000
+
+// findOffset searches offsets within ±maxOffset for the position of b over a
+// with the lowest mean luminance difference. Offsets must keep at least half of
+// the smaller image overlapping; ties prefer the offset closest to (0, 0).
+func findOffset(a, b *image.RGBA, maxOffset int) image.Point {
+	ga, gb := luminance(a), luminance(b)
+	wa, ha := a.Bounds().Dx(), a.Bounds().Dy()
+	wb, hb := b.Bounds().Dx(), b.Bounds().Dy()
+	minOverlap := min(wa*ha, wb*hb) / 2
+
+	best := image.Point{}
+	bestCost := math.Inf(1)
+	for dy := -maxOffset; dy <= maxOffset; dy++ {
+		for dx := -maxOffset; dx <= maxOffset; dx++ {
+			// Overlap in a's coordinates
+			x0, y0 := max(0, dx), max(0, dy)
+			x1, y1 := min(wa, dx+wb), min(ha, dy+hb)
+			if x1 <= x0 || y1 <= y0 || (x1-x0)*(y1-y0) < minOverlap {
+				continue
+			}
+
+			step := int(math.Sqrt(float64((x1-x0)*(y1-y0)) / alignSamples))
+			if step < 1 {
+				step = 1
+			}
+			var sum float64
+			var count int
+			for y := y0; y < y1; y += step {
+				for x := x0; x < x1; x += step {
+					delta := ga[y*wa+x] - gb[(y-dy)*wb+(x-dx)]
+					if delta < 0 {
+						delta = -delta
+					}
+					sum += delta
+					count++
+				}
+			}
+
+			cost := sum / float64(count)
+			candidate := image.Pt(dx, dy)
+			if cost < bestCost || (cost == bestCost && manhattan(candidate) < manhattan(best)) {
+				best, bestCost = candidate, cost
+			}
+		}
+	}
+	return best
+}
+
+// luminance converts an image to a row-major slice of luma values
+func luminance(img *image.RGBA) []float64 {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	out := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		row := img.Pix[y*img.Stride:]
+		for x := 0; x < w; x++ {
+			p := row[x*4 : x*4+4]
+			out[y*w+x] = 0.299*float64(p[0]) + 0.587*float64(p[1]) + 0.114*float64(p[2])
+		}
+	}
+	return out
+}
+
+func manhattan(p image.Point) int {
+	return abs(p.X) + abs(p.Y)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// findRegions returns the bounding boxes of groups of changed pixels, joining
+// pixels at most distance apart horizontally and vertically. Each changed
+// pixel grows into a distance x distance square down and to the right; two
+// squares overlap or touch diagonally exactly when their pixels are that close,
+// so the 8-connected groups of the grown mask are the groups of pixels.
+func findRegions(mask []bool, width, height, distance int) []image.Rectangle {
+	grown := growMask(mask, width, height, distance)
+	labels := make([]int32, len(grown)) // Group number + 1, 0 = not yet visited
+	var boxes []image.Rectangle
+	var stack []int
+
+	for start, on := range grown {
+		if !on || labels[start] != 0 {
+			continue
+		}
+
+		boxes = append(boxes, image.Rectangle{})
+		label := int32(len(boxes))
+		labels[start] = label
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			x, y := i%width, i/width
+
+			for ny := y - 1; ny <= y+1; ny++ {
+				for nx := x - 1; nx <= x+1; nx++ {
+					if nx < 0 || ny < 0 || nx >= width || ny >= height {
+						continue
+					}
+					n := ny*width + nx
+					if grown[n] && labels[n] == 0 {
+						labels[n] = label
+						stack = append(stack, n)
+					}
+				}
+			}
+		}
+	}
+
+	// Bound the changed pixels of each group rather than their squares
+	for i, changed := range mask {
+		if changed {
+			x, y := i%width, i/width
+			label := labels[i] - 1
+			boxes[label] = boxes[label].Union(image.Rect(x, y, x+1, y+1))
+		}
+	}
+	return boxes
+}
+
+// growMask returns mask with every set pixel grown into a size x size square
+// extending right and down, in two linear passes
+func growMask(mask []bool, width, height, size int) []bool {
+	rows := make([]bool, len(mask))
+	for y := 0; y < height; y++ {
+		last := -size // Last set x in the row
+		for x := 0; x < width; x++ {
+			if mask[y*width+x] {
+				last = x
+			}
+			rows[y*width+x] = x-last < size
+		}
+	}
+
+	grown := make([]bool, len(mask))
+	for x := 0; x < width; x++ {
+		last := -size // Last set y in the column
+		for y := 0; y < height; y++ {
+			if rows[y*width+x] {
+				last = y
+			}
+			grown[y*width+x] = y-last < size
+		}
+	}
+	return grown
+}
+
+// sortRegions keeps the MaxRegions largest boxes and returns them sorted top
+// to bottom
+func sortRegions(boxes []image.Rectangle) []Rect {
+	if len(boxes) > MaxRegions {
+		sort.SliceStable(boxes, func(i, j int) bool {
+			return area(boxes[i]) > area(boxes[j])
+		})
+		boxes = boxes[:MaxRegions]
+	}
+
+	sort.Slice(boxes, func(i, j int) bool {
+		if boxes[i].Min.Y != boxes[j].Min.Y {
+			return boxes[i].Min.Y < boxes[j].Min.Y
+		}
+		return boxes[i].Min.X < boxes[j].Min.X
+	})
+
+	regions := make([]Rect, 0, len(boxes))
+	for _, box := range boxes {
+		regions = append(regions, Rect{X: box.Min.X, Y: box.Min.Y, Width: box.Dx(), Height: box.Dy()})
+	}
+	return regions
+}
+
+func area(r image.Rectangle) int {
+	return r.Dx() * r.Dy()
+}
@@ -0,0 +1,272 @@
+package imagediff
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden images in testdata")
+
+// testScreen draws a small synthetic screenshot: title bar, sidebar and text rows
+func testScreen(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{245, 245, 245, 255}
+			switch {
+			case y < 6:
+				c = color.RGBA{40, 60, 120, 255}
+			case x < 12:
+				c = color.RGBA{200, 210, 220, 255}
+			case y%6 < 2 && x > 16 && x < width-8:
+				c = color.RGBA{uint8(30 + x%5), 30, 30, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// editedScreen is testScreen with a new button, a tiny change and sub-tolerance noise
+func editedScreen(width, height int) *image.RGBA {
+	img := testScreen(width, height)
+	fill(img, image.Rect(40, 12, 52, 20), color.RGBA{0, 120, 215, 255}) // Button
+	fill(img, image.Rect(20, 40, 22, 42), color.RGBA{255, 0, 0, 255})   // Cursor-sized change
+	for x := 12; x < width; x += 3 {
+		p := img.RGBAAt(x, 30)
+		p.R -= 5 // Compression noise, within tolerance
+		img.SetRGBA(x, 30, p)
+	}
+	return img
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := testScreen(64, 48)
+
+	tests := []struct {
+		name        string
+		after       image.Image
+		opts        Options
+		wantChanged int
+		wantRegions []Rect
+	}{
+		{
+			name:        "identical",
+			after:       testScreen(64, 48),
+			opts:        DefaultOptions(),
+			wantChanged: 0,
+			wantRegions: []Rect{},
+		},
+		{
+			name:        "edited",
+			after:       editedScreen(64, 48),
+			opts:        DefaultOptions(),
+			wantChanged: -1, // Checked via regions
+			wantRegions: []Rect{{X: 40, Y: 12, Width: 12, Height: 8}, {X: 20, Y: 40, Width: 2, Height: 2}},
+		},
+		{
+			name:        "zero tolerance catches noise",
+			after:       editedScreen(64, 48),
+			opts:        Options{Tolerance: 0, MergeDistance: 1},
+			wantChanged: -1,
+			wantRegions: nil, // Just more than the edited case
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Compare(before, tt.after, tt.opts)
+			if d.Width != 64 || d.Height != 48 || d.OffsetX != 0 || d.OffsetY != 0 {
+				t.Fatalf("canvas = %dx%d at (%d,%d), want 64x48 at (0,0)", d.Width, d.Height, d.OffsetX, d.OffsetY)
+			}
+			if tt.wantChanged >= 0 && d.ChangedPixels != tt.wantChanged {
+				t.Errorf("ChangedPixels = %d, want %d", d.ChangedPixels, tt.wantChanged)
+			}
+			if tt.wantRegions == nil {
+				if len(d.Regions) <= 2 {
+					t.Errorf("Regions = %v, want more than 2", d.Regions)
+				}
+				return
+			}
+			if len(d.Regions) != len(tt.wantRegions) {
+				t.Fatalf("Regions = %v, want %v", d.Regions, tt.wantRegions)
+			}
+			for i := range d.Regions {
+				if d.Regions[i] != tt.wantRegions[i] {
+					t.Errorf("Regions[%d] = %v, want %v", i, d.Regions[i], tt.wantRegions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompareSimilarity(t *testing.T) {
+	d := Compare(testScreen(64, 48), editedScreen(64, 48), DefaultOptions())
+
+	want := 12*8 + 2*2
+	if d.ChangedPixels != want {
+		t.Errorf("ChangedPixels = %d, want %d", d.ChangedPixels, want)
+	}
+	if d.TotalPixels != 64*48 {
+		t.Errorf("TotalPixels = %d, want %d", d.TotalPixels, 64*48)
+	}
+	if got := 1 - float64(want)/float64(64*48); d.Similarity != got {
+		t.Errorf("Similarity = %v, want %v", d.Similarity, got)
+	}
+	if !d.Changed(45, 15) || d.Changed(30, 30) || d.Changed(-1, 0) {
+		t.Error("Changed() does not match the diff mask")
+	}
+}
+
+func TestCompareAlignment(t *testing.T) {
+	full := testScreen(80, 60)
+
+	// The same screen scrolled: its content starts 3px right and 2px down of base
+	shifted := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			shifted.SetRGBA(x, y, full.RGBAAt(x+3, y+2))
+		}
+	}
+	base := full.SubImage(image.Rect(0, 0, 64, 48))
+
+	d := Compare(base, shifted, Options{MaxOffset: 6})
+	if d.OffsetX != 3 || d.OffsetY != 2 {
+		t.Fatalf("offset = (%d,%d), want (3,2)", d.OffsetX, d.OffsetY)
+	}
+	if d.Width != 67 || d.Height != 50 {
+		t.Errorf("canvas = %dx%d, want 67x50", d.Width, d.Height)
+	}
+
+	// Only the areas covered by just one image differ
+	overlap := (64 - 3) * (48 - 2)
+	if want := 2 * (64*48 - overlap); d.ChangedPixels != want {
+		t.Errorf("ChangedPixels = %d, want %d", d.ChangedPixels, want)
+	}
+
+	// Without alignment the shifted text rows differ all over
+	unaligned := Compare(base, shifted, DefaultOptions())
+	if unaligned.Similarity >= d.Similarity {
+		t.Errorf("unaligned similarity %v should be below aligned %v", unaligned.Similarity, d.Similarity)
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	d := Compare(testScreen(64, 48), editedScreen(64, 48), DefaultOptions())
+	shifted := Compare(testScreen(64, 48), testScreen(56, 40), Options{MaxOffset: 0})
+
+	tests := []struct {
+		name string
+		img  *image.RGBA
+	}{
+		{"overlay", d.Overlay()},
+		{"side_by_side", d.SideBySide(4)},
+		{"onion_skin", d.OnionSkin()},
+		{"onion_skin_sizes", shifted.OnionSkin()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkGolden(t, filepath.Join("testdata", tt.name+".png"), tt.img)
+		})
+	}
+}
+
+// checkGolden compares img with a golden PNG, rewriting it when -update is set
+func checkGolden(t *testing.T, path string, img *image.RGBA) {
+	t.Helper()
+
+	if *update {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatalf("png.Encode() error = %v", err)
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("missing golden image (run with -update): %v", err)
+	}
+	defer file.Close()
+	golden, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+
+	want := toRGBA(golden)
+	if want.Bounds() != img.Bounds() {
+		t.Fatalf("bounds = %v, golden %v", img.Bounds(), want.Bounds())
+	}
+	if !bytes.Equal(want.Pix, img.Pix) {
+		t.Errorf("rendering differs from %s (run with -update to accept)", path)
+	}
+}
+
+func TestOptionsClampMaxOffset(t *testing.T) {
+	tests := []struct{ in, want int }{{-5, 0}, {6, 6}, {2000, MaxAlignOffset}}
+	for _, tt := range tests {
+		if got := (Options{MaxOffset: tt.in}).withDefaults().MaxOffset; got != tt.want {
+			t.Errorf("MaxOffset %d = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompareNoisyFullHD(t *testing.T) {
+	before := image.NewRGBA(image.Rect(0, 0, 1920, 1080))
+	fill(before, before.Rect, color.RGBA{245, 245, 245, 255})
+	after := image.NewRGBA(before.Rect)
+	copy(after.Pix, before.Pix)
+
+	// 2% of pixels differ, scattered like JPEG noise
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 1920*1080/50; i++ {
+		after.SetRGBA(rng.IntN(1920), rng.IntN(1080), color.RGBA{0, 0, 0, 255})
+	}
+
+	for _, distance := range []int{1, 8} {
+		start := time.Now()
+		d := Compare(before, after, Options{Tolerance: 16, MergeDistance: distance})
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("MergeDistance %d: Compare took %v", distance, elapsed)
+		}
+		if len(d.Regions) == 0 || len(d.Regions) > MaxRegions {
+			t.Errorf("MergeDistance %d: %d regions, want 1 to %d", distance, len(d.Regions), MaxRegions)
+		}
+	}
+}
+
+func TestFindRegionsDistance(t *testing.T) {
+	// Pixels 3 apart join at distance 3, not at 2
+	mask := make([]bool, 10*4)
+	mask[1*10+2] = true
+	mask[2*10+5] = true
+	for _, tt := range []struct{ distance, want int }{{2, 2}, {3, 1}} {
+		boxes := findRegions(mask, 10, 4, tt.distance)
+		if len(boxes) != tt.want {
+			t.Errorf("distance %d: regions = %v, want %d", tt.distance, boxes, tt.want)
+		}
+	}
+	if boxes := findRegions(mask, 10, 4, 3); boxes[0] != image.Rect(2, 1, 6, 3) {
+		t.Errorf("joined region = %v, want the changed pixels' bounds", boxes[0])
+	}
+}
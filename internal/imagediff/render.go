@@ -0,0 +1,121 @@
+package imagediff
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Highlight is the color used to mark changed pixels and regions
+var Highlight = color.RGBA{230, 30, 60, 255}
+
+// gapColor fills the space between images in the side-by-side view
+var gapColor = color.RGBA{200, 200, 200, 255}
+
+// Overlay renders the second image faded to grey with changed pixels tinted
+// and changed regions outlined
+func (d *Diff) Overlay() *image.RGBA {
+	out := image.NewRGBA(image.Rect(0, 0, d.Width, d.Height))
+	for i := 0; i < d.TotalPixels; i++ {
+		src := d.b.Pix[i*4 : i*4+4]
+		if src[3] == 0 {
+			src = d.a.Pix[i*4 : i*4+4] // Only the first image covers this pixel
+		}
+		dst := out.Pix[i*4 : i*4+4]
+
+		if d.mask[i] {
+			// Tint changed pixels: 70% highlight over the original
+			dst[0] = blend(src[0], Highlight.R, 0.7)
+			dst[1] = blend(src[1], Highlight.G, 0.7)
+			dst[2] = blend(src[2], Highlight.B, 0.7)
+		} else {
+			// Fade unchanged pixels so the changes stand out
+			luma := (299*int(src[0]) + 587*int(src[1]) + 114*int(src[2])) / 1000
+			faded := uint8(255 - (255-luma)/3)
+			dst[0], dst[1], dst[2] = faded, faded, faded
+		}
+		dst[3] = 255
+	}
+
+	for _, region := range d.Regions {
+		outlineRegion(out, region, image.Point{}, out.Bounds())
+	}
+	return out
+}
+
+// SideBySide renders both images next to each other, separated by gap pixels,
+// with changed regions outlined on each
+func (d *Diff) SideBySide(gap int) *image.RGBA {
+	if gap < 0 {
+		gap = 0
+	}
+	width := d.sizeA.X + gap + d.sizeB.X
+	height := max(d.sizeA.Y, d.sizeB.Y)
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(out, out.Bounds(), image.NewUniform(gapColor), image.Point{}, draw.Src)
+
+	left := image.Rect(0, 0, d.sizeA.X, d.sizeA.Y)
+	right := image.Rect(d.sizeA.X+gap, 0, width, d.sizeB.Y)
+	draw.Draw(out, left, d.a, d.originA, draw.Src)
+	draw.Draw(out, right, d.b, d.originB, draw.Src)
+
+	// Regions are in canvas coordinates; translate into each panel and clip
+	for _, region := range d.Regions {
+		outlineRegion(out, region, left.Min.Sub(d.originA), left)
+		outlineRegion(out, region, right.Min.Sub(d.originB), right)
+	}
+	return out
+}
+
+// OnionSkin renders the second image over the first at the configured opacity
+func (d *Diff) OnionSkin() *image.RGBA {
+	out := image.NewRGBA(image.Rect(0, 0, d.Width, d.Height))
+	opacity := d.opts.OnionOpacity
+	for i := 0; i < d.TotalPixels; i++ {
+		pa := d.a.Pix[i*4 : i*4+4]
+		pb := d.b.Pix[i*4 : i*4+4]
+		dst := out.Pix[i*4 : i*4+4]
+
+		switch {
+		case pa[3] == 0 && pb[3] == 0:
+			dst[0], dst[1], dst[2] = gapColor.R, gapColor.G, gapColor.B
+		case pb[3] == 0:
+			copy(dst[:3], pa[:3])
+		case pa[3] == 0:
+			copy(dst[:3], pb[:3])
+		default:
+			for c := 0; c < 3; c++ {
+				dst[c] = blend(pa[c], pb[c], opacity)
+			}
+		}
+		dst[3] = 255
+	}
+	return out
+}
+
+// blend mixes from toward to by amount (0-1)
+func blend(from, to uint8, amount float64) uint8 {
+	return uint8(float64(from)*(1-amount) + float64(to)*amount + 0.5)
+}
+
+// outlineRegion draws a 1px border around region (translated by offset), only
+// inside clip
+func outlineRegion(img *image.RGBA, region Rect, offset image.Point, clip image.Rectangle) {
+	r := image.Rect(region.X, region.Y, region.X+region.Width, region.Y+region.Height).Add(offset)
+	if !r.Overlaps(clip) {
+		return
+	}
+	set := func(x, y int) {
+		if (image.Point{x, y}).In(clip) {
+			img.SetRGBA(x, y, Highlight)
+		}
+	}
+	for x := r.Min.X; x < r.Max.X; x++ {
+		set(x, r.Min.Y)
+		set(x, r.Max.Y-1)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		set(r.Min.X, y)
+		set(r.Max.X-1, y)
+	}
+}
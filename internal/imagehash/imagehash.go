@@ -0,0 +1,146 @@
+// Package imagehash computes perceptual hashes for finding visually similar images
+package imagehash
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+// Hash is a 64-bit perceptual hash. Similar images have hashes with a small
+// Hamming distance.
+type Hash uint64
+
+// String formats the hash as 16 hex digits
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// Parse reads a hash formatted by Hash.String
+func Parse(s string) (Hash, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hash %q: %w", s, err)
+	}
+	return Hash(v), nil
+}
+
+// Distance returns the Hamming distance between two hashes (0 = identical, 64 = opposite)
+func Distance(a, b Hash) int {
+	return bits.OnesCount64(uint64(a ^ b))
+}
+
+// DHash computes a difference hash: the image is shrunk to 9x8 grayscale and each
+// bit records whether a pixel is brighter than its right neighbour. Fast, and robust
+// to scaling and brightness changes.
+func DHash(img image.Image) Hash {
+	pixels := grayscale(img, 9, 8)
+
+	var hash Hash
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if pixels[y*9+x] > pixels[y*9+x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// PHash computes a DCT-based perceptual hash: the image is shrunk to 32x32
+// grayscale, and each bit records whether one of the 8x8 lowest-frequency DCT
+// coefficients is above their median. More robust than DHash to small edits
+// such as a moved cursor or a changed clock.
+func PHash(img image.Image) Hash {
+	const size = 32
+	pixels := grayscale(img, size, size)
+	coeffs := dct2D(pixels, size)
+
+	// Lowest 8x8 frequencies, skipping the DC term (overall brightness)
+	low := make([]float64, 0, 64)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			low = append(low, coeffs[y*size+x])
+		}
+	}
+	median := medianOf(low[1:])
+
+	var hash Hash
+	for _, c := range low {
+		hash <<= 1
+		if c > median {
+			hash |= 1
+		}
+	}
+	return hash
+}
+
+// grayscale scales img to width x height and returns luminance values row by row
+func grayscale(img image.Image, width, height int) []float64 {
+	small := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	pixels := make([]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := small.PixOffset(x, y)
+			r, g, b := small.Pix[i], small.Pix[i+1], small.Pix[i+2]
+			pixels[y*width+x] = 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+		}
+	}
+	return pixels
+}
+
+// dct2D computes the 2D DCT-II of an n x n matrix (row-major)
+func dct2D(pixels []float64, n int) []float64 {
+	// Precompute cosine table
+	table := make([]float64, n*n)
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			table[k*n+i] = math.Cos(math.Pi / float64(n) * (float64(i) + 0.5) * float64(k))
+		}
+	}
+
+	// Rows, then columns
+	rows := make([]float64, n*n)
+	for y := 0; y < n; y++ {
+		for k := 0; k < n; k++ {
+			var sum float64
+			for i := 0; i < n; i++ {
+				sum += pixels[y*n+i] * table[k*n+i]
+			}
+			rows[y*n+k] = sum
+		}
+	}
+
+	out := make([]float64, n*n)
+	for x := 0; x < n; x++ {
+		for k := 0; k < n; k++ {
+			var sum float64
+			for i := 0; i < n; i++ {
+				sum += rows[i*n+x] * table[k*n+i]
+			}
+			out[k*n+x] = sum
+		}
+	}
+	return out
+}
+
+// medianOf returns the median of values without modifying them
+func medianOf(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
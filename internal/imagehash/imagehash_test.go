@@ -0,0 +1,123 @@
+package imagehash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// testScreen draws a synthetic "screenshot": a title bar, a sidebar and text-like rows
+func testScreen(width, height int, variant int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{240, 240, 240, 255}
+			switch {
+			case y < height/10:
+				c = color.RGBA{40, 60, 120, 255} // Title bar
+			case x < width/5:
+				c = color.RGBA{200, 210, 220, 255} // Sidebar
+			case variant == 0 && (y/(height/20))%2 == 0 && x < width*3/4:
+				c = color.RGBA{30, 30, 30, 255} // Text rows
+			case variant == 1 && x > width/2 && y > height/3:
+				c = color.RGBA{20, 20, 30, 255} // Dark panel - a different screen
+			case variant == 1 && x > width/3:
+				c = color.RGBA{uint8(x * 255 / width), 120, 60, 255} // Gradient
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestHashes(t *testing.T) {
+	base := testScreen(320, 200, 0)
+
+	// Same screen with a small change (e.g. a cursor)
+	edited := testScreen(320, 200, 0)
+	for y := 100; y < 108; y++ {
+		for x := 200; x < 206; x++ {
+			edited.SetRGBA(x, y, color.RGBA{0, 0, 0, 255})
+		}
+	}
+
+	// Same screen captured at a different scale
+	scaled := testScreen(640, 400, 0)
+
+	// Same screen, slightly brighter
+	brighter := testScreen(320, 200, 0)
+	for i := 0; i < len(brighter.Pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			if brighter.Pix[i+c] < 245 {
+				brighter.Pix[i+c] += 10
+			}
+		}
+	}
+
+	different := testScreen(320, 200, 1)
+
+	hashers := []struct {
+		name string
+		fn   func(image.Image) Hash
+	}{
+		{"DHash", DHash},
+		{"PHash", PHash},
+	}
+
+	for _, h := range hashers {
+		t.Run(h.name, func(t *testing.T) {
+			baseHash := h.fn(base)
+
+			if d := Distance(baseHash, h.fn(base)); d != 0 {
+				t.Errorf("identical distance = %d, want 0", d)
+			}
+			for _, similar := range []struct {
+				name string
+				img  image.Image
+			}{
+				{"edited", edited},
+				{"scaled", scaled},
+				{"brighter", brighter},
+			} {
+				if d := Distance(baseHash, h.fn(similar.img)); d > 6 {
+					t.Errorf("%s distance = %d, want <= 6", similar.name, d)
+				}
+			}
+			if d := Distance(baseHash, h.fn(different)); d < 12 {
+				t.Errorf("different screen distance = %d, want >= 12", d)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b Hash
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xFF, 0x0F, 4},
+		{0, ^Hash(0), 64},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%x, %x) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	h := Hash(0x0123456789abcdef)
+	s := h.String()
+	if s != "0123456789abcdef" {
+		t.Errorf("String() = %q", s)
+	}
+	parsed, err := Parse(s)
+	if err != nil || parsed != h {
+		t.Errorf("Parse(%q) = %x, %v, want %x", s, parsed, err, h)
+	}
+	if _, err := Parse("not-hex"); err == nil {
+		t.Error("Parse() of invalid input should fail")
+	}
+}
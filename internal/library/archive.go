@@ -0,0 +1,119 @@
+package library
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Archive is a zip file being written. Entries go to a temp file that is only
+// moved into place by Close, so an aborted or failed archive leaves nothing behind.
+type Archive struct {
+	path    string
+	tmpPath string
+	file    *os.File
+	zw      *zip.Writer
+}
+
+// CreateArchive starts a new zip at zipPath
+func CreateArchive(zipPath string) (*Archive, error) {
+	if err := os.MkdirAll(filepath.Dir(zipPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive folder: %w", err)
+	}
+
+	tmpPath := zipPath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	return &Archive{
+		path:    zipPath,
+		tmpPath: tmpPath,
+		file:    file,
+		zw:      zip.NewWriter(file),
+	}, nil
+}
+
+// Add stores file in the archive under its path relative to root
+// (or its base name if it is outside root)
+func (a *Archive) Add(root, file string) error {
+	return addToZip(a.zw, root, file)
+}
+
+// Close finishes the archive and moves it into place
+func (a *Archive) Close() error {
+	if err := a.zw.Close(); err != nil {
+		a.file.Close()
+		os.Remove(a.tmpPath)
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := a.file.Close(); err != nil {
+		os.Remove(a.tmpPath)
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return os.Rename(a.tmpPath, a.path)
+}
+
+// Abort discards the archive
+func (a *Archive) Abort() {
+	a.zw.Close()
+	a.file.Close()
+	os.Remove(a.tmpPath)
+}
+
+// ArchiveFiles writes files into a new zip at zipPath, stored under their paths
+// relative to root. On error no partial archive is left behind.
+// The source files are not removed.
+func ArchiveFiles(zipPath, root string, files []string) error {
+	archive, err := CreateArchive(zipPath)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := archive.Add(root, file); err != nil {
+			archive.Abort()
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// addToZip copies one file into the archive
+func addToZip(zw *zip.Writer, root, file string) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filepath.Base(file), err)
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", filepath.Base(file), err)
+	}
+
+	name, err := filepath.Rel(root, file)
+	if root == "" || err != nil || strings.HasPrefix(name, "..") {
+		name = filepath.Base(file)
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(name)
+	header.Method = zip.Store // PNG/JPEG are already compressed
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", filepath.Base(file), err)
+	}
+	return nil
+}
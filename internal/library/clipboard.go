@@ -0,0 +1,86 @@
+package library
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"image"
+	"image/draw"
+	"path/filepath"
+	"time"
+)
+
+// ClipboardFolderName is the QuickSave subfolder that holds images imported by the clipboard watcher
+const ClipboardFolderName = "Clipboard"
+
+// ClipboardCollectionName is the collection listing images captured from the clipboard
+const ClipboardCollectionName = "Clipboard"
+
+// DefaultClipboardMaxItems is how many clipboard imports are kept when no cap is configured
+const DefaultClipboardMaxItems = 200
+
+// ClipboardCollection returns the collection of images captured from the clipboard, newest first
+func ClipboardCollection() Collection {
+	return Collection{
+		Name:    ClipboardCollectionName,
+		Filters: SearchFilters{Mode: CaptureModeClipboard},
+		Sort:    SortNewest,
+	}
+}
+
+// ClipboardFilename returns the file name for a clipboard import at t.
+// Milliseconds keep names unique when several images are copied in quick succession.
+func ClipboardFilename(t time.Time) string {
+	return "clipboard_" + t.Format("2006-01-02_15-04-05.000") + ".png"
+}
+
+// EnsureCollection saves collection unless one with the same name already exists,
+// so user edits to a built-in collection are kept
+func (idx *Index) EnsureCollection(collection Collection) error {
+	if _, ok := idx.GetCollection(collection.Name); ok {
+		return nil
+	}
+	return idx.SaveCollection(collection)
+}
+
+// ContentHash returns the SHA-256 of img's size and pixels as hex. Unlike the
+// perceptual hash it only matches identical images, so a copy with a small
+// text change isn't taken for one already imported.
+func ContentHash(img image.Image) string {
+	b := img.Bounds()
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok || nrgba.Stride != 4*b.Dx() {
+		nrgba = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(nrgba, nrgba.Rect, img, b.Min, draw.Src)
+	}
+
+	h := sha256.New()
+	binary.Write(h, binary.LittleEndian, [2]int64{int64(b.Dx()), int64(b.Dy())})
+	h.Write(nrgba.Pix[:4*b.Dx()*b.Dy()])
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SetContentHash stores the content hash of a library file and persists the index
+func (idx *Index) SetContentHash(imagePath, hash string) error {
+	return idx.update(imagePath, func(entry *IndexEntry) {
+		entry.ContentHash = hash
+	})
+}
+
+// FindContentHashIn returns an entry directly inside folder whose content hash equals hash
+func (idx *Index) FindContentHashIn(folder, hash string) (IndexEntry, bool) {
+	if hash == "" {
+		return IndexEntry{}, false
+	}
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return IndexEntry{}, false
+	}
+
+	for _, entry := range idx.EntriesIn(absFolder, 0) {
+		if entry.ContentHash == hash && fileExists(entry.Path) {
+			return entry, true
+		}
+	}
+	return IndexEntry{}, false
+}
@@ -0,0 +1,125 @@
+package library
+
+import (
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIndex_EnsureCollection(t *testing.T) {
+	idx, folder := newSearchFixture(t)
+
+	if err := idx.EnsureCollection(ClipboardCollection()); err != nil {
+		t.Fatalf("EnsureCollection() error = %v", err)
+	}
+
+	// User edits survive later calls
+	edited := ClipboardCollection()
+	edited.Sort = SortOldest
+	if err := idx.SaveCollection(edited); err != nil {
+		t.Fatalf("SaveCollection() error = %v", err)
+	}
+	if err := idx.EnsureCollection(ClipboardCollection()); err != nil {
+		t.Fatalf("EnsureCollection() second call error = %v", err)
+	}
+	collection, ok := idx.GetCollection(ClipboardCollectionName)
+	if !ok || collection.Sort != SortOldest || len(idx.Collections()) != 1 {
+		t.Errorf("collection = %+v (%d total), want edited sort kept", collection, len(idx.Collections()))
+	}
+
+	// The collection lists clipboard captures only
+	if err := idx.Record(filepath.Join(folder, "bravo.png"), CaptureSource{Mode: CaptureModeClipboard}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	got := filenames(idx.Search(collection.SearchQuery(1)).Images)
+	if len(got) != 1 || got[0] != "bravo.png" {
+		t.Errorf("clipboard collection = %v, want [bravo.png]", got)
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	for i := range img.Pix {
+		img.Pix[i] = byte(i)
+	}
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0xFF
+	}
+	hash := ContentHash(img)
+
+	// The same pixels hash the same whatever the image type or origin
+	nrgba := image.NewNRGBA(image.Rect(10, 10, 50, 40))
+	draw.Draw(nrgba, nrgba.Rect, img, image.Point{}, draw.Src)
+	if got := ContentHash(nrgba); got != hash {
+		t.Errorf("ContentHash(NRGBA copy) = %s, want %s", got, hash)
+	}
+	if got := ContentHash(nrgba.SubImage(nrgba.Rect)); got != hash {
+		t.Errorf("ContentHash(sub image) = %s, want %s", got, hash)
+	}
+
+	// Any change counts, even one too small for the perceptual hash
+	img.Pix[0]++
+	if ContentHash(img) == hash {
+		t.Error("ContentHash() ignored a changed pixel")
+	}
+	if HashImage(img) != HashImage(nrgba) {
+		t.Fatal("test image change should keep the perceptual hash")
+	}
+	if ContentHash(img.SubImage(image.Rect(0, 0, 30, 40))) == hash {
+		t.Error("ContentHash() ignored the size")
+	}
+}
+
+func TestIndex_FindContentHashIn(t *testing.T) {
+	idx, folder := newSearchFixture(t)
+	clipFolder := filepath.Join(folder, ClipboardFolderName)
+	if err := os.MkdirAll(clipFolder, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	now := time.Now()
+	first := filepath.Join(clipFolder, ClipboardFilename(now))
+	second := filepath.Join(clipFolder, ClipboardFilename(now.Add(time.Second)))
+	writeScreenPNG(t, first, 160, 120, false, now)
+	writeScreenPNG(t, second, 160, 120, true, now)
+	for _, path := range []string{first, second} {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		img, err := png.Decode(file)
+		file.Close()
+		if err != nil {
+			t.Fatalf("png.Decode() error = %v", err)
+		}
+		if err := idx.SetContentHash(path, ContentHash(img)); err != nil {
+			t.Fatalf("SetContentHash() error = %v", err)
+		}
+	}
+
+	entry, _ := idx.Get(second)
+	match, ok := idx.FindContentHashIn(clipFolder, entry.ContentHash)
+	if !ok || match.Path != second {
+		t.Errorf("FindContentHashIn() = %q, %v, want %q", match.Path, ok, second)
+	}
+
+	// Files outside the folder are not considered
+	if _, ok := idx.FindContentHashIn(folder, entry.ContentHash); ok {
+		t.Error("FindContentHashIn(parent) found a file in a subfolder")
+	}
+
+	// Deleted files no longer count as duplicates
+	if err := os.Remove(second); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, ok := idx.FindContentHashIn(clipFolder, entry.ContentHash); ok {
+		t.Error("FindContentHashIn() matched a deleted file")
+	}
+	if _, ok := idx.FindContentHashIn(clipFolder, ""); ok {
+		t.Error("FindContentHashIn(\"\") should not match")
+	}
+}
@@ -0,0 +1,90 @@
+package library
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Collection is a virtual album: a saved search evaluated against the index
+type Collection struct {
+	Name    string        `json:"name"`
+	Query   string        `json:"query,omitempty"`
+	Filters SearchFilters `json:"filters"`
+	Sort    string        `json:"sort,omitempty"`
+}
+
+// SearchQuery returns the query for one page of the collection
+func (c Collection) SearchQuery(page int) SearchQuery {
+	return SearchQuery{
+		Text:    c.Query,
+		Filters: c.Filters,
+		Sort:    c.Sort,
+		Page:    page,
+	}
+}
+
+// Collections returns the saved collections in creation order
+func (idx *Index) Collections() []Collection {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	result := make([]Collection, len(idx.collections))
+	copy(result, idx.collections)
+	return result
+}
+
+// GetCollection looks up a collection by name (case-insensitive)
+func (idx *Index) GetCollection(name string) (Collection, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if i := idx.collectionIndexLocked(name); i >= 0 {
+		return idx.collections[i], true
+	}
+	return Collection{}, false
+}
+
+// SaveCollection adds a collection or replaces the one with the same name, and persists the index
+func (idx *Index) SaveCollection(collection Collection) error {
+	collection.Name = strings.TrimSpace(collection.Name)
+	if collection.Name == "" {
+		return fmt.Errorf("collection name is empty")
+	}
+	collection.Filters.Tags = normalizeTags(collection.Filters.Tags)
+
+	idx.mu.Lock()
+	if i := idx.collectionIndexLocked(collection.Name); i >= 0 {
+		idx.collections[i] = collection
+	} else {
+		idx.collections = append(idx.collections, collection)
+	}
+	idx.mu.Unlock()
+
+	return idx.saveLater()
+}
+
+// DeleteCollection removes a collection by name and persists the index
+func (idx *Index) DeleteCollection(name string) error {
+	idx.mu.Lock()
+	i := idx.collectionIndexLocked(name)
+	if i >= 0 {
+		idx.collections = append(idx.collections[:i], idx.collections[i+1:]...)
+	}
+	idx.mu.Unlock()
+
+	if i < 0 {
+		return fmt.Errorf("collection not found: %s", name)
+	}
+	return idx.saveLater()
+}
+
+// collectionIndexLocked returns the position of the named collection, or -1. Caller must hold mu.
+func (idx *Index) collectionIndexLocked(name string) int {
+	name = strings.TrimSpace(name)
+	for i, collection := range idx.collections {
+		if strings.EqualFold(collection.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
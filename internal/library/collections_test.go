@@ -0,0 +1,64 @@
+package library
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIndex_Collections(t *testing.T) {
+	idx, folder := newSearchFixture(t)
+	if err := idx.SetFavorite(filepath.Join(folder, "charlie.png"), true); err != nil {
+		t.Fatalf("SetFavorite() error = %v", err)
+	}
+
+	if err := idx.SaveCollection(Collection{Name: "  "}); err == nil {
+		t.Error("SaveCollection() with empty name should fail")
+	}
+	if err := idx.SaveCollection(Collection{Name: "Favorites", Filters: SearchFilters{FavoritesOnly: true}}); err != nil {
+		t.Fatalf("SaveCollection() error = %v", err)
+	}
+	if err := idx.SaveCollection(Collection{Name: "Invoices", Filters: SearchFilters{Tags: []string{"invoice"}}}); err != nil {
+		t.Fatalf("SaveCollection() error = %v", err)
+	}
+	// Same name (case-insensitive) replaces
+	if err := idx.SaveCollection(Collection{Name: "invoices", Query: "bravo"}); err != nil {
+		t.Fatalf("SaveCollection() replace error = %v", err)
+	}
+
+	// Reload from disk
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	reloaded, err := OpenIndex(idx.path)
+	if err != nil {
+		t.Fatalf("OpenIndex() reload error = %v", err)
+	}
+	collections := reloaded.Collections()
+	if len(collections) != 2 {
+		t.Fatalf("Collections() = %d, want 2", len(collections))
+	}
+
+	favorites, ok := reloaded.GetCollection("FAVORITES")
+	if !ok {
+		t.Fatal("GetCollection(FAVORITES) not found")
+	}
+	got := filenames(reloaded.Search(favorites.SearchQuery(1)).Images)
+	if len(got) != 1 || got[0] != "charlie.png" {
+		t.Errorf("favorites collection = %v, want [charlie.png]", got)
+	}
+
+	invoices, _ := reloaded.GetCollection("Invoices")
+	if invoices.Query != "bravo" || len(invoices.Filters.Tags) != 0 {
+		t.Errorf("replaced collection = %+v", invoices)
+	}
+
+	if err := reloaded.DeleteCollection("favorites"); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+	if err := reloaded.DeleteCollection("favorites"); err == nil {
+		t.Error("DeleteCollection() of missing collection should fail")
+	}
+	if len(reloaded.Collections()) != 1 {
+		t.Errorf("Collections() after delete = %d, want 1", len(reloaded.Collections()))
+	}
+}
@@ -0,0 +1,142 @@
+package library
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// ConvertOptions configures ConvertImage
+type ConvertOptions struct {
+	Format    string `json:"format"`    // "png" or "jpeg"; empty keeps the source format
+	Quality   int    `json:"quality"`   // JPEG quality 1-100 (default: 90)
+	MaxWidth  int    `json:"maxWidth"`  // Downscale to fit, keeping aspect ratio (0 = no limit)
+	MaxHeight int    `json:"maxHeight"` // Downscale to fit, keeping aspect ratio (0 = no limit)
+	Folder    string `json:"folder"`    // Output folder (empty = next to the source)
+}
+
+// ConvertImage re-encodes (and optionally downscales) an image file and writes it
+// as a new file, never overwriting: a numbered suffix is added if the name is taken.
+// Returns the path of the new file.
+func ConvertImage(src string, opts ConvertOptions) (string, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", filepath.Base(src), err)
+	}
+
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(src)), ".")
+	}
+	var ext string
+	switch format {
+	case "png":
+		ext = ".png"
+	case "jpeg", "jpg":
+		format, ext = "jpeg", ".jpg"
+	default:
+		return "", fmt.Errorf("unsupported format: %s", opts.Format)
+	}
+
+	img = fitImage(img, opts.MaxWidth, opts.MaxHeight)
+
+	folder := opts.Folder
+	if folder == "" {
+		folder = filepath.Dir(src)
+	}
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return "", fmt.Errorf("failed to create folder: %w", err)
+	}
+	base := strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+	dst := availablePath(filepath.Join(folder, base+ext))
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	if format == "jpeg" {
+		quality := opts.Quality
+		if quality <= 0 || quality > 100 {
+			quality = 90
+		}
+		err = jpeg.Encode(out, img, &jpeg.Options{Quality: quality})
+	} else {
+		err = png.Encode(out, img)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return "", fmt.Errorf("failed to encode %s: %w", filepath.Base(dst), err)
+	}
+
+	return dst, nil
+}
+
+// fitImage downscales img to fit within maxWidth x maxHeight (0 = unbounded)
+func fitImage(img image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := img.Bounds()
+	if maxWidth <= 0 {
+		maxWidth = bounds.Dx()
+	}
+	if maxHeight <= 0 {
+		maxHeight = bounds.Dy()
+	}
+	if bounds.Dx() <= maxWidth && bounds.Dy() <= maxHeight {
+		return img
+	}
+
+	width, height := calculateThumbnailSize(bounds.Dx(), bounds.Dy(), maxWidth, maxHeight)
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Over, nil)
+	return scaled
+}
+
+// CopyFile copies src into folder without overwriting; a numbered suffix is added
+// if the name is taken. Returns the path of the copy.
+func CopyFile(src, folder string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return "", fmt.Errorf("failed to create folder: %w", err)
+	}
+	dst := availablePath(filepath.Join(folder, filepath.Base(src)))
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return "", fmt.Errorf("failed to copy %s: %w", filepath.Base(src), err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return "", err
+	}
+
+	// Keep the original timestamp so the library sorts the copy by capture time
+	if info, err := in.Stat(); err == nil {
+		os.Chtimes(dst, info.ModTime(), info.ModTime())
+	}
+	return dst, nil
+}
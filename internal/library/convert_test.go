@@ -0,0 +1,78 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConvertImage(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "shot.png")
+	writeTestPNG(t, src, 400, 200, time.Now())
+
+	tests := []struct {
+		name       string
+		opts       ConvertOptions
+		wantName   string
+		wantWidth  int
+		wantHeight int
+	}{
+		{"to jpeg", ConvertOptions{Format: "jpeg", Quality: 80}, "shot.jpg", 400, 200},
+		{"resize keeps aspect", ConvertOptions{Format: "jpg", MaxWidth: 100}, "shot (1).jpg", 100, 50},
+		{"keeps format into folder", ConvertOptions{MaxHeight: 50, Folder: filepath.Join(dir, "out")}, "shot.png", 100, 50},
+		{"no upscale", ConvertOptions{Format: "png", MaxWidth: 1000, MaxHeight: 1000}, "shot (1).png", 400, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst, err := ConvertImage(src, tt.opts)
+			if err != nil {
+				t.Fatalf("ConvertImage() error = %v", err)
+			}
+			if filepath.Base(dst) != tt.wantName {
+				t.Errorf("output = %s, want %s", filepath.Base(dst), tt.wantName)
+			}
+			width, height, err := ImageDimensions(dst)
+			if err != nil {
+				t.Fatalf("ImageDimensions() error = %v", err)
+			}
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("size = %dx%d, want %dx%d", width, height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+
+	if _, err := ConvertImage(src, ConvertOptions{Format: "gif"}); err == nil {
+		t.Error("ConvertImage() to an unsupported format should fail")
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "shot.png")
+	modTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	writeTestPNG(t, src, 2, 2, modTime)
+
+	dest := filepath.Join(dir, "dest")
+	first, err := CopyFile(src, dest)
+	if err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	second, err := CopyFile(src, dest)
+	if err != nil {
+		t.Fatalf("CopyFile() second error = %v", err)
+	}
+
+	if filepath.Base(first) != "shot.png" || filepath.Base(second) != "shot (1).png" {
+		t.Errorf("copies = %s, %s, want shot.png, shot (1).png", filepath.Base(first), filepath.Base(second))
+	}
+	info, err := os.Stat(first)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if !info.ModTime().Equal(modTime) {
+		t.Errorf("copy ModTime = %v, want %v", info.ModTime(), modTime)
+	}
+}
@@ -0,0 +1,203 @@
+package library
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+
+	"winshot/internal/imagehash"
+)
+
+// DefaultDuplicateThreshold is the largest hash distance treated as a near-duplicate
+const DefaultDuplicateThreshold = 5
+
+// DuplicateGroup is a set of visually similar screenshots, newest first
+type DuplicateGroup struct {
+	Images      []LibraryImage `json:"images"`
+	MaxDistance int            `json:"maxDistance"` // Largest hash distance to the group's newest image
+}
+
+// HashImage computes the perceptual hash used for duplicate detection
+func HashImage(img image.Image) string {
+	return imagehash.PHash(img).String()
+}
+
+// HashImageFile decodes an image file and computes its perceptual hash
+func HashImageFile(imagePath string) (string, error) {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", imagePath, err)
+	}
+	return HashImage(img), nil
+}
+
+// SetHash stores the perceptual hash of imagePath and persists the index
+func (idx *Index) SetHash(imagePath, hash string) error {
+	return idx.update(imagePath, func(entry *IndexEntry) {
+		entry.Hash = hash
+	})
+}
+
+// EnsureHashes computes missing perceptual hashes for entries in parallel, stores
+// them in the index (saving once) and returns the entries with hashes filled in.
+// Files that can't be decoded keep an empty hash.
+func (idx *Index) EnsureHashes(entries []IndexEntry) []IndexEntry {
+	var missing []int
+	for i := range entries {
+		if entries[i].Hash == "" {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return entries
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if hash, err := HashImageFile(entries[i].Path); err == nil {
+					entries[i].Hash = hash
+				}
+			}
+		}()
+	}
+	for _, i := range missing {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	idx.mu.Lock()
+	for _, i := range missing {
+		if entry, ok := idx.entries[indexKey(entries[i].Path)]; ok && entries[i].Hash != "" {
+			entry.Hash = entries[i].Hash
+		}
+	}
+	idx.mu.Unlock()
+	idx.saveLater()
+
+	return entries
+}
+
+// FindSimilarRecent compares hash against the `recent` most recently modified
+// entries (other than exclude) and returns the closest one within threshold
+func (idx *Index) FindSimilarRecent(hash, exclude string, recent, threshold int) (IndexEntry, int, bool) {
+	target, err := imagehash.Parse(hash)
+	if err != nil {
+		return IndexEntry{}, 0, false
+	}
+
+	idx.mu.Lock()
+	var candidates []IndexEntry
+	excludeKey := indexKey(exclude)
+	for key, entry := range idx.entries {
+		if key != excludeKey && entry.Hash != "" {
+			candidates = append(candidates, *entry)
+		}
+	}
+	idx.mu.Unlock()
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ModTime.After(candidates[j].ModTime)
+	})
+	if len(candidates) > recent {
+		candidates = candidates[:recent]
+	}
+
+	best, bestDistance, found := IndexEntry{}, threshold+1, false
+	for _, candidate := range candidates {
+		h, err := imagehash.Parse(candidate.Hash)
+		if err != nil {
+			continue
+		}
+		if d := imagehash.Distance(target, h); d < bestDistance {
+			best, bestDistance, found = candidate, d, true
+		}
+	}
+	return best, bestDistance, found
+}
+
+// GroupDuplicates groups entries whose perceptual hashes are within threshold of
+// each other (transitively). Only groups with two or more images are returned,
+// largest first. Entries without a hash are ignored.
+func GroupDuplicates(entries []IndexEntry, threshold int) []DuplicateGroup {
+	type hashed struct {
+		entry IndexEntry
+		hash  imagehash.Hash
+	}
+	var items []hashed
+	for _, entry := range entries {
+		if h, err := imagehash.Parse(entry.Hash); err == nil {
+			items = append(items, hashed{entry: entry, hash: h})
+		}
+	}
+
+	// Union-find over all pairs within threshold
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			if imagehash.Distance(items[i].hash, items[j].hash) <= threshold {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	members := make(map[int][]hashed)
+	for i := range items {
+		root := find(i)
+		members[root] = append(members[root], items[i])
+	}
+
+	var groups []DuplicateGroup
+	for _, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			if !group[i].entry.ModTime.Equal(group[j].entry.ModTime) {
+				return group[i].entry.ModTime.After(group[j].entry.ModTime)
+			}
+			return group[i].entry.Path < group[j].entry.Path
+		})
+
+		dg := DuplicateGroup{Images: make([]LibraryImage, 0, len(group))}
+		for _, item := range group {
+			if d := imagehash.Distance(group[0].hash, item.hash); d > dg.MaxDistance {
+				dg.MaxDistance = d
+			}
+			dg.Images = append(dg.Images, entryImage(&item.entry))
+		}
+		groups = append(groups, dg)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i].Images) != len(groups[j].Images) {
+			return len(groups[i].Images) > len(groups[j].Images)
+		}
+		return groups[i].Images[0].Filepath < groups[j].Images[0].Filepath
+	})
+	return groups
+}
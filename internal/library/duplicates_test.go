@@ -0,0 +1,164 @@
+package library
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeScreenPNG writes a synthetic screenshot: a title bar, a sidebar and either
+// text-like rows or a dark panel
+func writeScreenPNG(t *testing.T, path string, width, height int, panel bool, modTime time.Time) {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{240, 240, 240, 255}
+			switch {
+			case y < height/10:
+				c = color.RGBA{40, 60, 120, 255}
+			case x < width/5:
+				c = color.RGBA{200, 210, 220, 255}
+			case !panel && (y/(height/20))%2 == 0 && x < width*3/4:
+				c = color.RGBA{30, 30, 30, 255}
+			case panel && x > width/2 && y > height/3:
+				c = color.RGBA{20, 20, 30, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	file.Close()
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+}
+
+func TestGroupDuplicates(t *testing.T) {
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	entries := []IndexEntry{
+		{Path: "a.png", Filename: "a.png", ModTime: base, Hash: "ff00ff00ff00ff00"},
+		{Path: "b.png", Filename: "b.png", ModTime: base.Add(time.Hour), Hash: "ff00ff00ff00ff01"},     // 1 from a
+		{Path: "c.png", Filename: "c.png", ModTime: base.Add(2 * time.Hour), Hash: "ff00ff00ff00ff07"}, // 2 from b, 3 from a
+		{Path: "d.png", Filename: "d.png", ModTime: base, Hash: "00ff00ff00ff00ff"},
+		{Path: "e.png", Filename: "e.png", ModTime: base, Hash: "00ff00ff00ff00fe"}, // 1 from d
+		{Path: "f.png", Filename: "f.png", ModTime: base},                           // No hash - ignored
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		want      [][]string
+	}{
+		{"exact only", 0, nil},
+		{"tight", 1, [][]string{{"b.png", "a.png"}, {"d.png", "e.png"}}},
+		{"transitive", 2, [][]string{{"c.png", "b.png", "a.png"}, {"d.png", "e.png"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := GroupDuplicates(entries, tt.threshold)
+			if len(groups) != len(tt.want) {
+				t.Fatalf("GroupDuplicates() = %d groups, want %d", len(groups), len(tt.want))
+			}
+			for i, group := range groups {
+				got := filenames(group.Images)
+				if len(got) != len(tt.want[i]) {
+					t.Fatalf("group %d = %v, want %v", i, got, tt.want[i])
+				}
+				for j := range got {
+					if got[j] != tt.want[i][j] {
+						t.Errorf("group %d = %v, want %v", i, got, tt.want[i])
+						break
+					}
+				}
+			}
+		})
+	}
+
+	groups := GroupDuplicates(entries, 2)
+	if groups[0].MaxDistance != 3 {
+		t.Errorf("MaxDistance = %d, want 3", groups[0].MaxDistance)
+	}
+}
+
+func TestEnsureHashes(t *testing.T) {
+	dir := t.TempDir()
+	folder := filepath.Join(dir, "shots")
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	writeScreenPNG(t, filepath.Join(folder, "one.png"), 320, 200, false, base)
+	writeScreenPNG(t, filepath.Join(folder, "one-large.png"), 640, 400, false, base.Add(time.Hour))
+	writeScreenPNG(t, filepath.Join(folder, "other.png"), 320, 200, true, base.Add(2*time.Hour))
+	if err := os.WriteFile(filepath.Join(folder, "broken.png"), []byte("not an image"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	indexPath := filepath.Join(dir, "library-index.json")
+	idx, err := OpenIndex(indexPath)
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+	if _, err := idx.Rescan(folder, 0); err != nil {
+		t.Fatalf("Rescan() error = %v", err)
+	}
+
+	entries := idx.EnsureHashes(idx.EntriesIn(folder, 0))
+	hashed := 0
+	for _, entry := range entries {
+		if entry.Hash != "" {
+			hashed++
+		}
+	}
+	if hashed != 3 {
+		t.Errorf("hashed %d entries, want 3", hashed)
+	}
+
+	groups := GroupDuplicates(entries, DefaultDuplicateThreshold)
+	if len(groups) != 1 {
+		t.Fatalf("GroupDuplicates() = %d groups, want 1", len(groups))
+	}
+	if got := filenames(groups[0].Images); len(got) != 2 || got[0] != "one-large.png" || got[1] != "one.png" {
+		t.Errorf("group = %v, want [one-large.png one.png]", got)
+	}
+
+	// Hashes persist across reopen
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	reopened, err := OpenIndex(indexPath)
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+	hash := ""
+	for _, entry := range reopened.EntriesIn(folder, 0) {
+		if entry.Filename == "one.png" {
+			hash = entry.Hash
+		}
+	}
+	if hash == "" {
+		t.Fatal("hash not persisted")
+	}
+
+	match, _, ok := reopened.FindSimilarRecent(hash, filepath.Join(folder, "one.png"), 10, DefaultDuplicateThreshold)
+	if !ok || match.Filename != "one-large.png" {
+		t.Errorf("FindSimilarRecent() = %q, %v, want one-large.png", match.Filename, ok)
+	}
+}
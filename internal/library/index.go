@@ -0,0 +1,512 @@
+package library
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register JPEG decoder for DecodeConfig
+	_ "image/png"  // Register PNG decoder for DecodeConfig
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"winshot/internal/metadata"
+)
+
+// Capture modes recorded in CaptureSource.Mode
+const (
+	CaptureModeFullscreen = "fullscreen"
+	CaptureModeRegion     = "region"
+	CaptureModeWindow     = "window"
+	CaptureModeClipboard  = "clipboard"
+	CaptureModeFile       = "file" // Opened from disk and re-saved
+)
+
+// CaptureSource describes where a screenshot came from
+type CaptureSource struct {
+	Mode        string  `json:"mode,omitempty"`
+	WindowTitle string  `json:"windowTitle,omitempty"`
+	WindowClass string  `json:"windowClass,omitempty"`
+	ProcessName string  `json:"processName,omitempty"`
+	Monitor     int     `json:"monitor,omitempty"`  // 1-based display number, 0 = unknown
+	DPIScale    float64 `json:"dpiScale,omitempty"` // Display scale factor at capture time
+}
+
+// IndexEntry holds everything the library knows about a screenshot
+type IndexEntry struct {
+	Path        string        `json:"path"`
+	Filename    string        `json:"filename"`
+	Size        int64         `json:"size"`
+	ModTime     time.Time     `json:"modTime"`
+	Width       int           `json:"width"`
+	Height      int           `json:"height"`
+	Source      CaptureSource `json:"source"`
+	Tags        []string      `json:"tags,omitempty"`
+	UploadURLs  []string      `json:"uploadUrls,omitempty"`
+	Favorite    bool          `json:"favorite,omitempty"`
+	OCRText     string        `json:"ocrText,omitempty"`
+	Hash        string        `json:"hash,omitempty"`        // Perceptual hash (hex), computed on demand
+	ContentHash string        `json:"contentHash,omitempty"` // SHA-256 of the pixels (hex), for exact duplicates
+
+	Metadata *metadata.Metadata `json:"metadata,omitempty"` // Capture metadata embedded in the file, if any
+}
+
+// RescanStats summarizes the changes applied by Rescan
+type RescanStats struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Removed int `json:"removed"`
+}
+
+// SaveDelay is how long the index batches changes before writing them to disk
+const SaveDelay = 2 * time.Second
+
+// Index is a JSON-backed store of per-screenshot metadata keyed by file path.
+// Changes are written in batches, SaveDelay after the first unsaved one; call
+// Save to write them right away, e.g. before exiting.
+type Index struct {
+	path        string
+	entries     map[string]*IndexEntry
+	collections []Collection
+	mu          sync.Mutex
+	saveMu      sync.Mutex  // Held by Save so snapshots reach disk in order
+	dirty       bool        // Changes not yet on disk, protected by mu
+	saveTimer   *time.Timer // Pending batched save, protected by mu
+}
+
+// indexFile is the on-disk representation of the index
+type indexFile struct {
+	Entries     map[string]*IndexEntry `json:"entries"`
+	Collections []Collection           `json:"collections,omitempty"`
+}
+
+// OpenIndex loads the index stored at path, or returns an empty index if it doesn't exist yet
+func OpenIndex(path string) (*Index, error) {
+	if path == "" {
+		return nil, fmt.Errorf("index path is empty")
+	}
+
+	idx := &Index{
+		path:    path,
+		entries: make(map[string]*IndexEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		// Corrupt index - start fresh rather than blocking the library
+		return idx, nil
+	}
+	for key, entry := range file.Entries {
+		if entry != nil {
+			idx.entries[key] = entry
+		}
+	}
+	idx.collections = file.Collections
+
+	return idx, nil
+}
+
+// indexKey normalizes a file path for use as an index key.
+// Windows paths are case-insensitive, so keys are lower-cased.
+func indexKey(imagePath string) string {
+	return strings.ToLower(filepath.Clean(imagePath))
+}
+
+// Get returns a copy of the entry for imagePath
+func (idx *Index) Get(imagePath string) (IndexEntry, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	entry, ok := idx.entries[indexKey(imagePath)]
+	if !ok {
+		return IndexEntry{}, false
+	}
+	return *entry, true
+}
+
+// entryLocked returns the entry for imagePath, creating it if needed. Caller must hold mu.
+func (idx *Index) entryLocked(imagePath string) *IndexEntry {
+	key := indexKey(imagePath)
+	entry, ok := idx.entries[key]
+	if !ok {
+		entry = &IndexEntry{
+			Path:     imagePath,
+			Filename: filepath.Base(imagePath),
+		}
+		idx.entries[key] = entry
+	}
+	return entry
+}
+
+// update applies fn to the entry for imagePath and persists the index
+func (idx *Index) update(imagePath string, fn func(entry *IndexEntry)) error {
+	idx.mu.Lock()
+	fn(idx.entryLocked(imagePath))
+	idx.mu.Unlock()
+
+	return idx.saveLater()
+}
+
+// SetOCRText stores recognized text for imagePath and persists the index
+func (idx *Index) SetOCRText(imagePath, text string) error {
+	return idx.update(imagePath, func(entry *IndexEntry) {
+		entry.OCRText = text
+	})
+}
+
+// SetTags replaces the tags of imagePath (trimmed, de-duplicated) and persists the index
+func (idx *Index) SetTags(imagePath string, tags []string) error {
+	return idx.update(imagePath, func(entry *IndexEntry) {
+		entry.Tags = normalizeTags(tags)
+	})
+}
+
+// SetFavorite marks or unmarks imagePath as a favorite and persists the index
+func (idx *Index) SetFavorite(imagePath string, favorite bool) error {
+	return idx.update(imagePath, func(entry *IndexEntry) {
+		entry.Favorite = favorite
+	})
+}
+
+// AddUploadURL records a public URL that imagePath was uploaded to and persists the index
+func (idx *Index) AddUploadURL(imagePath, url string) error {
+	if url == "" {
+		return fmt.Errorf("upload URL is empty")
+	}
+	return idx.update(imagePath, func(entry *IndexEntry) {
+		for _, existing := range entry.UploadURLs {
+			if existing == url {
+				return
+			}
+		}
+		entry.UploadURLs = append(entry.UploadURLs, url)
+	})
+}
+
+// Record adds or refreshes the entry for a newly saved screenshot along with its capture source
+func (idx *Index) Record(imagePath string, source CaptureSource) error {
+	info, err := os.Stat(imagePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	details := readDetails(imagePath)
+	idx.mu.Lock()
+	entry := idx.entryLocked(imagePath)
+	entry.Source = source
+	details.apply(entry, imagePath, info)
+	idx.mu.Unlock()
+
+	return idx.saveLater()
+}
+
+// Remove drops the entry for imagePath and persists the index
+func (idx *Index) Remove(imagePath string) error {
+	idx.mu.Lock()
+	delete(idx.entries, indexKey(imagePath))
+	idx.mu.Unlock()
+
+	return idx.saveLater()
+}
+
+// Put stores entry under imagePath, replacing any existing entry, and persists the index.
+// File-derived fields are refreshed from disk when the file exists.
+func (idx *Index) Put(imagePath string, entry IndexEntry) error {
+	entry.Path = imagePath
+	entry.Filename = filepath.Base(imagePath)
+	if info, err := os.Stat(imagePath); err == nil {
+		readDetails(imagePath).apply(&entry, imagePath, info)
+	}
+	idx.mu.Lock()
+	idx.entries[indexKey(imagePath)] = &entry
+	idx.mu.Unlock()
+
+	return idx.saveLater()
+}
+
+// Move re-keys the entry for oldPath to newPath, keeping its metadata, and persists the index
+func (idx *Index) Move(oldPath, newPath string) error {
+	idx.mu.Lock()
+	oldKey := indexKey(oldPath)
+	entry, ok := idx.entries[oldKey]
+	if ok {
+		delete(idx.entries, oldKey)
+		entry.Path = newPath
+		entry.Filename = filepath.Base(newPath)
+		idx.entries[indexKey(newPath)] = entry
+	}
+	idx.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return idx.saveLater()
+}
+
+// fileDetails holds what an entry records from the content of its file
+type fileDetails struct {
+	width, height int
+	metadata      *metadata.Metadata
+}
+
+// readDetails reads an image file's dimensions and embedded capture metadata.
+// It does file IO, so callers run it before taking idx.mu.
+func readDetails(imagePath string) fileDetails {
+	var d fileDetails
+
+	// Read dimensions from the header only - no full decode needed
+	if file, err := os.Open(imagePath); err == nil {
+		if cfg, _, err := image.DecodeConfig(file); err == nil {
+			d.width, d.height = cfg.Width, cfg.Height
+		}
+		file.Close()
+	}
+
+	if md, ok, err := metadata.ReadFile(imagePath); err == nil && ok {
+		d.metadata = &md
+	}
+	return d
+}
+
+// apply updates file-derived fields (size, mtime, dimensions, metadata) of an entry
+func (d fileDetails) apply(entry *IndexEntry, imagePath string, info os.FileInfo) {
+	entry.Path = imagePath
+	entry.Filename = filepath.Base(imagePath)
+	entry.Size = info.Size()
+	entry.ModTime = info.ModTime()
+	entry.Width, entry.Height = d.width, d.height
+	entry.Hash = "" // Content may have changed
+	entry.ContentHash = ""
+
+	// Fill in the source from embedded capture metadata for files the index
+	// didn't record itself (e.g. copied from another machine)
+	entry.Metadata = d.metadata
+	if md := d.metadata; md != nil {
+		if entry.Source.Mode == "" {
+			entry.Source = CaptureSource{
+				Mode:        md.CaptureMode,
+				WindowTitle: md.WindowTitle,
+				WindowClass: md.WindowClass,
+				ProcessName: md.ProcessName,
+				Monitor:     md.Monitor,
+				DPIScale:    md.DPIScale,
+			}
+		}
+	}
+}
+
+// Rescan synchronizes the index with the image files in folder and up to
+// maxDepth levels of subfolders. Only new or modified files (by size and mtime)
+// are re-read, and entries for files that no longer exist are removed.
+func (idx *Index) Rescan(folder string, maxDepth int) (RescanStats, error) {
+	var stats RescanStats
+
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return stats, fmt.Errorf("invalid folder path: %w", err)
+	}
+
+	type scannedFile struct {
+		path string
+		info os.FileInfo
+	}
+	var files []scannedFile
+	if _, err := os.Stat(absFolder); err == nil {
+		err := walkImages(absFolder, maxDepth, func(path string, info os.FileInfo) {
+			files = append(files, scannedFile{path: path, info: info})
+		})
+		if err != nil {
+			return stats, err
+		}
+	} else if !os.IsNotExist(err) {
+		return stats, fmt.Errorf("failed to stat folder: %w", err)
+	}
+
+	// Find new and modified files, then read them without holding the lock
+	idx.mu.Lock()
+	seen := make(map[string]bool)
+	var changed []scannedFile
+	for _, file := range files {
+		key := indexKey(file.path)
+		seen[key] = true
+
+		entry, exists := idx.entries[key]
+		if exists && entry.Size == file.info.Size() && entry.ModTime.Equal(file.info.ModTime()) {
+			continue
+		}
+
+		if exists {
+			stats.Updated++
+		} else {
+			stats.Added++
+		}
+		changed = append(changed, file)
+	}
+	idx.mu.Unlock()
+
+	details := make([]fileDetails, len(changed))
+	for i, file := range changed {
+		details[i] = readDetails(file.path)
+	}
+
+	idx.mu.Lock()
+	for i, file := range changed {
+		details[i].apply(idx.entryLocked(file.path), file.path, file.info)
+	}
+
+	// Remove entries for files that disappeared. Entries outside the scanned
+	// depth are kept while their file exists, so their tags survive a depth change.
+	folderKey := indexKey(absFolder) + string(filepath.Separator)
+	for key, entry := range idx.entries {
+		if !strings.HasPrefix(key, folderKey) || seen[key] {
+			continue
+		}
+		if withinScan(key[len(folderKey):], maxDepth) || !fileExists(entry.Path) {
+			delete(idx.entries, key)
+			stats.Removed++
+		}
+	}
+	idx.mu.Unlock()
+
+	if stats.Added+stats.Updated+stats.Removed == 0 {
+		return stats, nil
+	}
+	return stats, idx.saveLater()
+}
+
+// EntriesIn returns copies of the entries for files in folder and up to maxDepth
+// levels of subfolders, as last seen by Rescan
+func (idx *Index) EntriesIn(folder string, maxDepth int) []IndexEntry {
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return nil
+	}
+	folderKey := indexKey(absFolder) + string(filepath.Separator)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	var entries []IndexEntry
+	for key, entry := range idx.entries {
+		if strings.HasPrefix(key, folderKey) && withinScan(key[len(folderKey):], maxDepth) {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Annotate fills index metadata (tags, source, uploads, OCR text) into scanned library images
+func (idx *Index) Annotate(images []LibraryImage) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for i := range images {
+		if entry, ok := idx.entries[indexKey(images[i].Filepath)]; ok {
+			applyEntry(&images[i], entry)
+		}
+	}
+}
+
+// applyEntry copies index metadata onto a LibraryImage
+func applyEntry(img *LibraryImage, entry *IndexEntry) {
+	img.Tags = entry.Tags
+	img.Source = entry.Source
+	img.UploadURLs = entry.UploadURLs
+	img.Favorite = entry.Favorite
+	img.OCRText = entry.OCRText
+	img.Metadata = entry.Metadata
+}
+
+// saveLater marks the index changed and schedules a save SaveDelay from now,
+// unless one is pending already, so a burst of changes is written once
+func (idx *Index) saveLater() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.dirty = true
+	if idx.saveTimer == nil {
+		idx.saveTimer = time.AfterFunc(SaveDelay, func() {
+			if err := idx.Save(); err != nil {
+				println("Warning: failed to save library index:", err.Error())
+			}
+		})
+	}
+	return nil
+}
+
+// Save writes the index to disk now if it has unsaved changes. Concurrent saves
+// are serialized, so an older snapshot can't replace a newer one.
+func (idx *Index) Save() error {
+	idx.saveMu.Lock()
+	defer idx.saveMu.Unlock()
+
+	idx.mu.Lock()
+	if idx.saveTimer != nil {
+		idx.saveTimer.Stop()
+		idx.saveTimer = nil
+	}
+	if !idx.dirty {
+		idx.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(indexFile{Entries: idx.entries, Collections: idx.collections})
+	if err == nil {
+		idx.dirty = false
+	}
+	idx.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := idx.write(data); err != nil {
+		// Keep the changes for the next save
+		idx.saveLater()
+		return err
+	}
+	return nil
+}
+
+// write replaces the index file with data
+func (idx *Index) write(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a crash can't leave a truncated index
+	tmpPath := idx.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, idx.path)
+}
+
+// normalizeTags trims, drops empty and de-duplicates (case-insensitively) a tag list
+func normalizeTags(tags []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+	return result
+}
@@ -0,0 +1,264 @@
+package library
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"winshot/internal/metadata"
+)
+
+func TestIndex_OCRTextRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	indexPath := filepath.Join(dir, "library-index.json")
+
+	idx, err := OpenIndex(indexPath)
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+
+	imagePath := filepath.Join(dir, "winshot_001.png")
+	if err := idx.SetOCRText(imagePath, "File not found"); err != nil {
+		t.Fatalf("SetOCRText() error = %v", err)
+	}
+
+	// Changes are batched until the delayed save or an explicit one
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Errorf("index written before the batched save, Stat() error = %v", err)
+	}
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Reopen from disk
+	reloaded, err := OpenIndex(indexPath)
+	if err != nil {
+		t.Fatalf("OpenIndex() reload error = %v", err)
+	}
+
+	entry, ok := reloaded.Get(imagePath)
+	if !ok {
+		t.Fatal("Get() entry not found after reload")
+	}
+	if entry.OCRText != "File not found" {
+		t.Errorf("OCRText = %q, want %q", entry.OCRText, "File not found")
+	}
+}
+
+// Concurrent writers each save a snapshot; the last one on disk has every entry
+func TestIndex_ConcurrentSaves(t *testing.T) {
+	dir := t.TempDir()
+	indexPath := filepath.Join(dir, "library-index.json")
+	idx, err := OpenIndex(indexPath)
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := idx.SetTags(filepath.Join(dir, fmt.Sprintf("shot_%d.png", i)), []string{"bug"}); err != nil {
+				t.Errorf("SetTags() error = %v", err)
+			}
+			if err := idx.Save(); err != nil {
+				t.Errorf("Save() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	reloaded, err := OpenIndex(indexPath)
+	if err != nil {
+		t.Fatalf("OpenIndex() reload error = %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		if _, ok := reloaded.Get(filepath.Join(dir, fmt.Sprintf("shot_%d.png", i))); !ok {
+			t.Errorf("shot_%d.png lost", i)
+		}
+	}
+}
+
+func TestIndex_Annotate(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := OpenIndex(filepath.Join(dir, "library-index.json"))
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+
+	withText := filepath.Join(dir, "a.png")
+	withoutText := filepath.Join(dir, "b.png")
+	if err := idx.SetOCRText(withText, "hello"); err != nil {
+		t.Fatalf("SetOCRText() error = %v", err)
+	}
+
+	images := []LibraryImage{{Filepath: withText}, {Filepath: withoutText}}
+	idx.Annotate(images)
+
+	if images[0].OCRText != "hello" {
+		t.Errorf("images[0].OCRText = %q, want %q", images[0].OCRText, "hello")
+	}
+	if images[1].OCRText != "" {
+		t.Errorf("images[1].OCRText = %q, want empty", images[1].OCRText)
+	}
+}
+
+func TestOpenIndex_CorruptFile(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "library-index.json")
+	if err := os.WriteFile(indexPath, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := OpenIndex(indexPath)
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v, want empty index", err)
+	}
+	if _, ok := idx.Get("anything.png"); ok {
+		t.Error("Get() found entry in corrupt index")
+	}
+}
+
+func TestIndex_RescanRecursive(t *testing.T) {
+	dir := t.TempDir()
+	modTime := time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local)
+	for _, rel := range []string{
+		"top.png",
+		filepath.Join("work", "one.png"),
+		filepath.Join("work", "bugs", "two.png"),
+		filepath.Join(".trash", "hidden.png"),
+	} {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		writeTestPNG(t, path, 2, 2, modTime)
+	}
+
+	idx, err := OpenIndex(filepath.Join(t.TempDir(), "library-index.json"))
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+
+	tests := []struct {
+		depth int
+		want  int
+	}{
+		{0, 1},
+		{1, 2},
+		{5, 3}, // Hidden folders are never scanned
+		{0, 3}, // Deeper entries are kept while their files exist
+	}
+	for _, tt := range tests {
+		if _, err := idx.Rescan(dir, tt.depth); err != nil {
+			t.Fatalf("Rescan(depth=%d) error = %v", tt.depth, err)
+		}
+		if got := idx.Search(SearchQuery{}).Total; got != tt.want {
+			t.Errorf("Rescan(depth=%d) indexed %d files, want %d", tt.depth, got, tt.want)
+		}
+	}
+
+	// ...and dropped once the file is gone
+	if err := os.Remove(filepath.Join(dir, "work", "bugs", "two.png")); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	stats, err := idx.Rescan(dir, 0)
+	if err != nil {
+		t.Fatalf("Rescan() error = %v", err)
+	}
+	if stats.Removed != 1 {
+		t.Errorf("Rescan() removed %d, want 1", stats.Removed)
+	}
+}
+
+func TestIndex_MoveKeepsMetadata(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := OpenIndex(filepath.Join(dir, "library-index.json"))
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+
+	oldPath := filepath.Join(dir, "a.png")
+	newPath := filepath.Join(dir, "sub", "renamed.png")
+	if err := idx.SetTags(oldPath, []string{"bug"}); err != nil {
+		t.Fatalf("SetTags() error = %v", err)
+	}
+	if err := idx.SetFavorite(oldPath, true); err != nil {
+		t.Fatalf("SetFavorite() error = %v", err)
+	}
+	if err := idx.Move(oldPath, newPath); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+
+	if _, ok := idx.Get(oldPath); ok {
+		t.Error("entry still present at old path")
+	}
+	entry, ok := idx.Get(newPath)
+	if !ok {
+		t.Fatal("entry not found at new path")
+	}
+	if entry.Filename != "renamed.png" || !entry.Favorite || len(entry.Tags) != 1 {
+		t.Errorf("moved entry = %+v, want filename renamed.png, favorite, 1 tag", entry)
+	}
+}
+
+func TestIndex_RescanReadsMetadata(t *testing.T) {
+	dir := t.TempDir()
+	folder := filepath.Join(dir, "shots")
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 6))); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	data, err := metadata.Embed(buf.Bytes(), metadata.Metadata{
+		CaptureMode: CaptureModeWindow,
+		CapturedAt:  time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC),
+		WindowTitle: "Quarterly report - Excel",
+		ProcessName: "EXCEL.EXE",
+		Monitor:     2,
+		Author:      "Dana",
+	})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	imagePath := filepath.Join(folder, "copied.png")
+	if err := os.WriteFile(imagePath, data, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	idx, err := OpenIndex(filepath.Join(dir, "library-index.json"))
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+	if _, err := idx.Rescan(folder, 0); err != nil {
+		t.Fatalf("Rescan() error = %v", err)
+	}
+
+	entry, ok := idx.Get(imagePath)
+	if !ok || entry.Metadata == nil {
+		t.Fatalf("Get() = %+v, %v, want entry with metadata", entry, ok)
+	}
+	if entry.Width != 8 || entry.Height != 6 {
+		t.Errorf("dimensions = %dx%d, want 8x6", entry.Width, entry.Height)
+	}
+	if entry.Source.Mode != CaptureModeWindow || entry.Source.ProcessName != "EXCEL.EXE" || entry.Source.Monitor != 2 {
+		t.Errorf("Source = %+v, want window capture from EXCEL.EXE on monitor 2", entry.Source)
+	}
+
+	// Embedded fields are searchable
+	for _, query := range []string{"quarterly", "dana"} {
+		result := idx.Search(SearchQuery{Text: query})
+		if result.Total != 1 || result.Images[0].Metadata == nil {
+			t.Errorf("Search(%q) = %d results, want 1 with metadata", query, result.Total)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package library
+
+import (
+	"sync"
+	"time"
+)
+
+// Janitor runs a housekeeping task once on Start and then on a fixed interval
+type Janitor struct {
+	interval time.Duration
+	task     func()
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	mu       sync.Mutex
+}
+
+// NewJanitor creates a janitor that runs task every interval
+func NewJanitor(interval time.Duration, task func()) *Janitor {
+	return &Janitor{interval: interval, task: task}
+}
+
+// Start runs the task in the background immediately and then on every tick
+func (j *Janitor) Start() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.stopCh != nil {
+		return // Already running
+	}
+	j.stopCh = make(chan struct{})
+	j.doneCh = make(chan struct{})
+
+	go func(stopCh, doneCh chan struct{}) {
+		defer close(doneCh)
+
+		j.task()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				j.task()
+			}
+		}
+	}(j.stopCh, j.doneCh)
+}
+
+// Stop stops the schedule and waits for a running task to finish
+func (j *Janitor) Stop() {
+	j.mu.Lock()
+	stopCh, doneCh := j.stopCh, j.doneCh
+	j.stopCh, j.doneCh = nil, nil
+	j.mu.Unlock()
+
+	if stopCh == nil {
+		return
+	}
+	close(stopCh)
+	<-doneCh
+}
@@ -5,19 +5,30 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"winshot/internal/metadata"
 )
 
 // LibraryImage represents a screenshot in the library
 type LibraryImage struct {
-	Filepath     string `json:"filepath"`
-	Filename     string `json:"filename"`
-	ModifiedDate string `json:"modifiedDate"`
-	Thumbnail    string `json:"thumbnail"` // Base64 encoded PNG
-	Width        int    `json:"width"`
-	Height       int    `json:"height"`
+	Filepath     string        `json:"filepath"`
+	Filename     string        `json:"filename"`
+	ModifiedDate string        `json:"modifiedDate"`
+	Thumbnail    string        `json:"thumbnail"` // Base64 encoded PNG
+	Width        int           `json:"width"`
+	Height       int           `json:"height"`
+	Tags         []string      `json:"tags,omitempty"`
+	Source       CaptureSource `json:"source"`
+	UploadURLs   []string      `json:"uploadUrls,omitempty"`
+	Favorite     bool          `json:"favorite,omitempty"`
+	OCRText      string        `json:"ocrText,omitempty"` // Text recognized by OCR, if any
+
+	Metadata *metadata.Metadata `json:"metadata,omitempty"` // Capture metadata embedded in the file
 }
 
 // ScanOptions configures the folder scan behavior
@@ -25,6 +36,10 @@ type ScanOptions struct {
 	ThumbnailWidth  int // Max thumbnail width (default: 160)
 	ThumbnailHeight int // Max thumbnail height (default: 120)
 	MaxFiles        int // Max files to scan (0 = unlimited, default: 500)
+	MaxDepth        int // Subfolder levels to include (0 = top folder only)
+	Workers         int // Parallel thumbnail workers (0 = number of CPUs)
+
+	Cache *ThumbnailCache // Optional on-disk thumbnail cache
 }
 
 // DefaultScanOptions returns sensible defaults for scanning
@@ -46,6 +61,64 @@ var supportedExtensions = map[string]bool{
 // ScanFolder scans a directory for image files and returns a list of LibraryImage
 // sorted by modified date (newest first). Creates folder if it doesn't exist.
 func ScanFolder(folderPath string, opts ScanOptions) ([]LibraryImage, error) {
+	images, err := ListFolder(folderPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	LoadThumbnails(images, opts)
+
+	// Skip files we can't read/decode
+	valid := images[:0]
+	for _, img := range images {
+		if img.Thumbnail != "" {
+			valid = append(valid, img)
+		}
+	}
+	return valid, nil
+}
+
+// ScanFolderPage lists folderPath (newest first) and generates thumbnails for one
+// page only. Pages are 1-based; pageSize <= 0 uses DefaultPageSize.
+func ScanFolderPage(folderPath string, opts ScanOptions, page, pageSize int) (*SearchResult, error) {
+	images, err := ListFolder(folderPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	result := &SearchResult{
+		Images:     []LibraryImage{},
+		Total:      len(images),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (len(images) + pageSize - 1) / pageSize,
+	}
+
+	start := (page - 1) * pageSize
+	if start >= len(images) {
+		return result, nil
+	}
+	end := start + pageSize
+	if end > len(images) {
+		end = len(images)
+	}
+
+	result.Images = images[start:end]
+	LoadThumbnails(result.Images, opts)
+	return result, nil
+}
+
+// ListFolder returns the image files in folderPath sorted by modified date (newest first)
+// without reading them. Thumbnails and dimensions are left empty for LoadThumbnails.
+// Creates folder if it doesn't exist.
+func ListFolder(folderPath string, opts ScanOptions) ([]LibraryImage, error) {
 	if folderPath == "" {
 		return nil, fmt.Errorf("folder path is empty")
 	}
@@ -65,63 +138,171 @@ func ScanFolder(folderPath string, opts ScanOptions) ([]LibraryImage, error) {
 		return nil, fmt.Errorf("path is not a directory: %s", folderPath)
 	}
 
-	// Read directory entries
-	entries, err := os.ReadDir(folderPath)
+	type listedFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []listedFile
+
+	err = walkImages(folderPath, opts.MaxDepth, func(path string, info os.FileInfo) {
+		files = append(files, listedFile{path: path, modTime: info.ModTime()})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read directory: %w", err)
+		return nil, err
 	}
 
-	var images []LibraryImage
+	// Sort by modified date descending (newest first) before applying the limit,
+	// so MaxFiles keeps the most recent screenshots
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+	if opts.MaxFiles > 0 && len(files) > opts.MaxFiles {
+		files = files[:opts.MaxFiles]
+	}
+
+	images := make([]LibraryImage, 0, len(files))
+	for _, file := range files {
+		images = append(images, LibraryImage{
+			Filepath:     file.path,
+			Filename:     filepath.Base(file.path),
+			ModifiedDate: file.modTime.Format(time.RFC3339),
+		})
+	}
+
+	return images, nil
+}
+
+// walkImages calls fn for every supported image file in folder, descending at most
+// maxDepth levels of subfolders. Hidden folders (e.g. ".trash") are skipped, and
+// unreadable subfolders are ignored.
+func walkImages(folder string, maxDepth int, fn func(path string, info os.FileInfo)) error {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
 
 	for _, entry := range entries {
-		// Skip directories
+		path := filepath.Join(folder, entry.Name())
+
 		if entry.IsDir() {
+			if maxDepth > 0 && !strings.HasPrefix(entry.Name(), ".") {
+				walkImages(path, maxDepth-1, fn)
+			}
 			continue
 		}
 
 		// Check file extension
-		ext := strings.ToLower(filepath.Ext(entry.Name()))
-		if !supportedExtensions[ext] {
+		if !supportedExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
 			continue
 		}
 
-		fullPath := filepath.Join(folderPath, entry.Name())
-
-		// Get file info for modified date
-		fileInfo, err := entry.Info()
+		info, err := entry.Info()
 		if err != nil {
 			continue // Skip files we can't stat
 		}
+		fn(path, info)
+	}
+	return nil
+}
 
-		// Generate thumbnail
-		thumb, width, height, err := GenerateThumbnail(fullPath, opts.ThumbnailWidth, opts.ThumbnailHeight)
-		if err != nil {
-			continue // Skip files we can't read/decode
+// InScan reports whether path, inside folder, would be found by a scan of
+// folder with maxDepth: it isn't in a hidden folder (like .trash) or nested deeper
+func InScan(folder, path string, maxDepth int) bool {
+	rel, err := filepath.Rel(folder, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return withinScan(rel, maxDepth)
+}
+
+// withinScan reports whether a path relative to a scanned folder would be visited
+// by walkImages with maxDepth
+func withinScan(rel string, maxDepth int) bool {
+	parts := strings.Split(rel, string(filepath.Separator))
+	if len(parts)-1 > maxDepth {
+		return false
+	}
+	for _, dir := range parts[:len(parts)-1] {
+		if strings.HasPrefix(dir, ".") {
+			return false
 		}
+	}
+	return true
+}
 
-		images = append(images, LibraryImage{
-			Filepath:     fullPath,
-			Filename:     entry.Name(),
-			ModifiedDate: fileInfo.ModTime().Format(time.RFC3339),
-			Thumbnail:    thumb,
-			Width:        width,
-			Height:       height,
-		})
+// ListSubfolders returns the non-hidden subfolders of folder down to maxDepth levels,
+// as sorted paths relative to folder
+func ListSubfolders(folder string, maxDepth int) ([]string, error) {
+	if _, err := os.Stat(folder); err != nil {
+		return nil, fmt.Errorf("failed to stat folder: %w", err)
+	}
 
-		// Respect MaxFiles limit
-		if opts.MaxFiles > 0 && len(images) >= opts.MaxFiles {
-			break
+	dirs := []string{}
+	for _, dir := range listSubfolders(folder, maxDepth) {
+		if rel, err := filepath.Rel(folder, dir); err == nil {
+			dirs = append(dirs, rel)
 		}
 	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
 
-	// Sort by modified date descending (newest first)
-	sort.Slice(images, func(i, j int) bool {
-		ti, _ := time.Parse(time.RFC3339, images[i].ModifiedDate)
-		tj, _ := time.Parse(time.RFC3339, images[j].ModifiedDate)
-		return ti.After(tj)
-	})
+// LoadThumbnails generates thumbnails for images that don't have one yet, using a
+// pool of opts.Workers goroutines and opts.Cache when set.
+// Images that can't be decoded are left without a thumbnail.
+func LoadThumbnails(images []LibraryImage, opts ScanOptions) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
 
-	return images, nil
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				thumb, width, height, err := GenerateThumbnailCached(images[i].Filepath, opts.ThumbnailWidth, opts.ThumbnailHeight, opts.Cache)
+				if err != nil {
+					continue
+				}
+				images[i].Thumbnail = thumb
+				images[i].Width = width
+				images[i].Height = height
+			}
+		}()
+	}
+
+	for i := range images {
+		if images[i].Thumbnail == "" {
+			jobs <- i
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// LoadLibraryImage builds a LibraryImage with thumbnail for a single file
+func LoadLibraryImage(imagePath string, opts ScanOptions) (LibraryImage, error) {
+	info, err := os.Stat(imagePath)
+	if err != nil {
+		return LibraryImage{}, err
+	}
+
+	thumb, width, height, err := GenerateThumbnailCached(imagePath, opts.ThumbnailWidth, opts.ThumbnailHeight, opts.Cache)
+	if err != nil {
+		return LibraryImage{}, err
+	}
+
+	return LibraryImage{
+		Filepath:     imagePath,
+		Filename:     filepath.Base(imagePath),
+		ModifiedDate: info.ModTime().Format(time.RFC3339),
+		Thumbnail:    thumb,
+		Width:        width,
+		Height:       height,
+	}, nil
 }
 
 // DeleteImage removes an image file from disk
@@ -0,0 +1,122 @@
+package library
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reasons recorded on RetentionCandidate
+const (
+	RetentionReasonAge   = "age"
+	RetentionReasonCount = "count"
+	RetentionReasonSize  = "size"
+)
+
+// RetentionPolicy limits how much the library may hold. Zero values disable a limit.
+// Favorites and tagged screenshots are exempt and never selected for removal,
+// but still count towards the count and size limits.
+type RetentionPolicy struct {
+	MaxAge       time.Duration
+	MaxCount     int
+	MaxTotalSize int64 // Bytes
+}
+
+// RetentionCandidate is a screenshot selected for removal
+type RetentionCandidate struct {
+	Path     string    `json:"path"`
+	Filename string    `json:"filename"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"modTime"`
+	Reason   string    `json:"reason"` // "age", "count" or "size"
+}
+
+// RetentionPlan is the outcome of applying a policy to the library
+type RetentionPlan struct {
+	Remove     []RetentionCandidate `json:"remove"`
+	Kept       int                  `json:"kept"`
+	Exempt     int                  `json:"exempt"`     // Favorites and tagged screenshots
+	TotalSize  int64                `json:"totalSize"`  // Library size before removal
+	FreedSize  int64                `json:"freedSize"`  // Bytes removed by the plan
+	OverLimits bool                 `json:"overLimits"` // Limits still exceeded because of exempt files
+}
+
+// IsEmpty reports whether the plan removes nothing
+func (p RetentionPlan) IsEmpty() bool {
+	return len(p.Remove) == 0
+}
+
+// PlanRetention selects the screenshots to remove so the library satisfies policy.
+// Oldest files go first: everything past MaxAge, then the oldest remaining until
+// both the count and total size are within their limits.
+func PlanRetention(entries []IndexEntry, policy RetentionPolicy, now time.Time) RetentionPlan {
+	plan := RetentionPlan{Remove: []RetentionCandidate{}}
+
+	var candidates []IndexEntry
+	for _, entry := range entries {
+		plan.TotalSize += entry.Size
+		if isRetentionExempt(entry) {
+			plan.Exempt++
+			continue
+		}
+		candidates = append(candidates, entry)
+	}
+
+	// Oldest first; path tie-break keeps plans deterministic
+	sort.Slice(candidates, func(i, j int) bool {
+		if !candidates[i].ModTime.Equal(candidates[j].ModTime) {
+			return candidates[i].ModTime.Before(candidates[j].ModTime)
+		}
+		return candidates[i].Path < candidates[j].Path
+	})
+
+	count := len(entries)
+	size := plan.TotalSize
+	remove := func(entry IndexEntry, reason string) {
+		plan.Remove = append(plan.Remove, RetentionCandidate{
+			Path:     entry.Path,
+			Filename: entry.Filename,
+			Size:     entry.Size,
+			ModTime:  entry.ModTime,
+			Reason:   reason,
+		})
+		plan.FreedSize += entry.Size
+		count--
+		size -= entry.Size
+	}
+
+	i := 0
+	if policy.MaxAge > 0 {
+		cutoff := now.Add(-policy.MaxAge)
+		for ; i < len(candidates) && candidates[i].ModTime.Before(cutoff); i++ {
+			remove(candidates[i], RetentionReasonAge)
+		}
+	}
+
+	overCount := func() bool { return policy.MaxCount > 0 && count > policy.MaxCount }
+	overSize := func() bool { return policy.MaxTotalSize > 0 && size > policy.MaxTotalSize }
+	for ; i < len(candidates) && (overCount() || overSize()); i++ {
+		reason := RetentionReasonSize
+		if overCount() {
+			reason = RetentionReasonCount
+		}
+		remove(candidates[i], reason)
+	}
+
+	plan.Kept = count
+	plan.OverLimits = overCount() || overSize()
+	return plan
+}
+
+// isRetentionExempt reports whether the user has marked the screenshot as worth keeping
+func isRetentionExempt(entry IndexEntry) bool {
+	if entry.Favorite {
+		return true
+	}
+	for _, tag := range entry.Tags {
+		if strings.TrimSpace(tag) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package library
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPlanRetention(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	entry := func(name string, age time.Duration, size int64) IndexEntry {
+		return IndexEntry{Path: "/shots/" + name, Filename: name, Size: size, ModTime: now.Add(-age)}
+	}
+
+	favorite := entry("fav.png", 100*day, 500)
+	favorite.Favorite = true
+	tagged := entry("tagged.png", 90*day, 500)
+	tagged.Tags = []string{"invoice"}
+
+	entries := []IndexEntry{
+		entry("new.png", 1*day, 100),
+		entry("week.png", 7*day, 100),
+		entry("month.png", 31*day, 100),
+		entry("old.png", 60*day, 100),
+		favorite,
+		tagged,
+	}
+
+	tests := []struct {
+		name       string
+		policy     RetentionPolicy
+		want       []string
+		wantReason []string
+		overLimits bool
+	}{
+		{"no limits", RetentionPolicy{}, nil, nil, false},
+		{"max age", RetentionPolicy{MaxAge: 30 * day}, []string{"old.png", "month.png"}, []string{"age", "age"}, false},
+		{"max count", RetentionPolicy{MaxCount: 4}, []string{"old.png", "month.png"}, []string{"count", "count"}, false},
+		{"max size", RetentionPolicy{MaxTotalSize: 1200}, []string{"old.png", "month.png"}, []string{"size", "size"}, false},
+		{"age then count", RetentionPolicy{MaxAge: 45 * day, MaxCount: 4}, []string{"old.png", "month.png"}, []string{"age", "count"}, false},
+		{"exempt files over limit", RetentionPolicy{MaxCount: 1}, []string{"old.png", "month.png", "week.png", "new.png"}, []string{"count", "count", "count", "count"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PlanRetention(entries, tt.policy, now)
+
+			if len(plan.Remove) != len(tt.want) {
+				t.Fatalf("Remove = %+v, want %v", plan.Remove, tt.want)
+			}
+			var freed int64
+			for i, candidate := range plan.Remove {
+				if candidate.Filename != tt.want[i] || candidate.Reason != tt.wantReason[i] {
+					t.Errorf("Remove[%d] = %s (%s), want %s (%s)", i, candidate.Filename, candidate.Reason, tt.want[i], tt.wantReason[i])
+				}
+				freed += candidate.Size
+			}
+			if plan.Exempt != 2 {
+				t.Errorf("Exempt = %d, want 2", plan.Exempt)
+			}
+			if plan.Kept != len(entries)-len(tt.want) {
+				t.Errorf("Kept = %d, want %d", plan.Kept, len(entries)-len(tt.want))
+			}
+			if plan.FreedSize != freed || plan.TotalSize != 1400 {
+				t.Errorf("FreedSize = %d, TotalSize = %d, want %d, 1400", plan.FreedSize, plan.TotalSize, freed)
+			}
+			if plan.OverLimits != tt.overLimits {
+				t.Errorf("OverLimits = %v, want %v", plan.OverLimits, tt.overLimits)
+			}
+		})
+	}
+}
+
+func TestArchiveFiles(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		filepath.Join(root, "a.png"),
+		filepath.Join(root, "sub", "b.png"),
+	}
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		writeTestPNG(t, file, 2, 2, time.Now())
+	}
+
+	archives := t.TempDir()
+	zipPath := filepath.Join(archives, "archive.zip")
+	if err := ArchiveFiles(zipPath, root, files); err != nil {
+		t.Fatalf("ArchiveFiles() error = %v", err)
+	}
+
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatalf("zip.OpenReader() error = %v", err)
+	}
+	defer zr.Close()
+
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	if len(names) != 2 || names[0] != "a.png" || names[1] != "sub/b.png" {
+		t.Errorf("archive entries = %v, want [a.png sub/b.png]", names)
+	}
+
+	// A missing file fails the whole archive without leaving a partial zip
+	badZip := filepath.Join(archives, "bad.zip")
+	if err := ArchiveFiles(badZip, root, []string{filepath.Join(root, "missing.png")}); err == nil {
+		t.Error("ArchiveFiles() with a missing file should fail")
+	}
+	if fileExists(badZip) || fileExists(badZip+".tmp") {
+		t.Error("partial archive left behind")
+	}
+}
@@ -0,0 +1,220 @@
+package library
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sort orders accepted by Search
+const (
+	SortNewest = "newest" // Most recently modified first (default)
+	SortOldest = "oldest"
+	SortName   = "name"
+	SortSize   = "size" // Largest first
+)
+
+// DefaultPageSize is the number of results per page when none is given
+const DefaultPageSize = 50
+
+// SearchFilters narrows down library search results
+type SearchFilters struct {
+	From    string   `json:"from,omitempty"`    // Inclusive start date (YYYY-MM-DD or RFC3339)
+	To      string   `json:"to,omitempty"`      // Inclusive end date (YYYY-MM-DD or RFC3339)
+	Tags    []string `json:"tags,omitempty"`    // Images must have all of these tags
+	Mode    string   `json:"mode,omitempty"`    // Capture mode, e.g. "window"
+	Process string   `json:"process,omitempty"` // Substring of the captured process name
+
+	FavoritesOnly bool `json:"favoritesOnly,omitempty"`
+}
+
+// SearchQuery is a full library query
+type SearchQuery struct {
+	Text     string
+	Filters  SearchFilters
+	Sort     string
+	Page     int // 1-based
+	PageSize int
+}
+
+// SearchResult holds one page of library search results
+type SearchResult struct {
+	Images     []LibraryImage `json:"images"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"pageSize"`
+	TotalPages int            `json:"totalPages"`
+}
+
+// Search returns the page of indexed images matching query.
+// Text is split into words; every word must appear (case-insensitively) in the
+// filename, window title, process name, tags or OCR text.
+// Thumbnails are not generated - callers fill them for the returned page only.
+func (idx *Index) Search(query SearchQuery) SearchResult {
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	page := query.Page
+	if page < 1 {
+		page = 1
+	}
+
+	from, hasFrom := parseFilterDate(query.Filters.From, false)
+	to, hasTo := parseFilterDate(query.Filters.To, true)
+	terms := strings.Fields(strings.ToLower(query.Text))
+
+	idx.mu.Lock()
+	var matches []IndexEntry
+	for _, entry := range idx.entries {
+		if entry.Size == 0 && entry.ModTime.IsZero() {
+			continue // Metadata only, file not scanned yet
+		}
+		if hasFrom && entry.ModTime.Before(from) {
+			continue
+		}
+		if hasTo && entry.ModTime.After(to) {
+			continue
+		}
+		if !matchesFilters(entry, query.Filters) || !matchesText(entry, terms) {
+			continue
+		}
+		matches = append(matches, *entry)
+	}
+	idx.mu.Unlock()
+
+	sortEntries(matches, query.Sort)
+
+	result := SearchResult{
+		Images:     []LibraryImage{},
+		Total:      len(matches),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (len(matches) + pageSize - 1) / pageSize,
+	}
+
+	start := (page - 1) * pageSize
+	if start >= len(matches) {
+		return result
+	}
+	end := start + pageSize
+	if end > len(matches) {
+		end = len(matches)
+	}
+
+	for i := start; i < end; i++ {
+		result.Images = append(result.Images, entryImage(&matches[i]))
+	}
+
+	return result
+}
+
+// entryImage converts an index entry to a LibraryImage without thumbnail
+func entryImage(entry *IndexEntry) LibraryImage {
+	img := LibraryImage{
+		Filepath:     entry.Path,
+		Filename:     entry.Filename,
+		ModifiedDate: entry.ModTime.Format(time.RFC3339),
+		Width:        entry.Width,
+		Height:       entry.Height,
+	}
+	applyEntry(&img, entry)
+	return img
+}
+
+// parseFilterDate parses a YYYY-MM-DD or RFC3339 date.
+// Date-only values used as an end bound include the whole day.
+func parseFilterDate(value string, endOfDay bool) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, true
+}
+
+// matchesFilters checks favorite, tag, mode and process filters
+func matchesFilters(entry *IndexEntry, filters SearchFilters) bool {
+	if filters.FavoritesOnly && !entry.Favorite {
+		return false
+	}
+	if filters.Mode != "" && !strings.EqualFold(entry.Source.Mode, filters.Mode) {
+		return false
+	}
+	if filters.Process != "" && !strings.Contains(strings.ToLower(entry.Source.ProcessName), strings.ToLower(filters.Process)) {
+		return false
+	}
+	for _, want := range filters.Tags {
+		found := false
+		for _, tag := range entry.Tags {
+			if strings.EqualFold(tag, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesText checks that every search term appears in the entry's searchable fields
+func matchesText(entry *IndexEntry, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+
+	fields := []string{
+		entry.Filename,
+		entry.Source.WindowTitle,
+		entry.Source.ProcessName,
+		strings.Join(entry.Tags, " "),
+		entry.OCRText,
+	}
+	if md := entry.Metadata; md != nil {
+		fields = append(fields, md.WindowTitle, md.ProcessName, md.Author)
+	}
+	haystack := strings.ToLower(strings.Join(fields, "\n"))
+
+	for _, term := range terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortEntries orders entries by the given sort key (newest first by default)
+func sortEntries(entries []IndexEntry, order string) {
+	var less func(a, b *IndexEntry) bool
+	switch order {
+	case SortOldest:
+		less = func(a, b *IndexEntry) bool { return a.ModTime.Before(b.ModTime) }
+	case SortName:
+		less = func(a, b *IndexEntry) bool { return strings.ToLower(a.Filename) < strings.ToLower(b.Filename) }
+	case SortSize:
+		less = func(a, b *IndexEntry) bool { return a.Size > b.Size }
+	default:
+		less = func(a, b *IndexEntry) bool { return a.ModTime.After(b.ModTime) }
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if less(&entries[i], &entries[j]) {
+			return true
+		}
+		if less(&entries[j], &entries[i]) {
+			return false
+		}
+		// Tie-break on path so paging is stable
+		return entries[i].Path < entries[j].Path
+	})
+}
@@ -0,0 +1,217 @@
+package library
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestPNG writes a small PNG and sets its modification time
+func writeTestPNG(t *testing.T, path string, width, height int, modTime time.Time) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := png.Encode(file, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		file.Close()
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	file.Close()
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+}
+
+// newSearchFixture builds an index over three screenshots with varied metadata
+func newSearchFixture(t *testing.T) (*Index, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	folder := filepath.Join(dir, "shots")
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	writeTestPNG(t, filepath.Join(folder, "alpha.png"), 10, 10, base)
+	writeTestPNG(t, filepath.Join(folder, "bravo.png"), 40, 30, base.Add(24*time.Hour))
+	writeTestPNG(t, filepath.Join(folder, "charlie.png"), 20, 20, base.Add(48*time.Hour))
+
+	idx, err := OpenIndex(filepath.Join(dir, "library-index.json"))
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+	if _, err := idx.Rescan(folder, 0); err != nil {
+		t.Fatalf("Rescan() error = %v", err)
+	}
+
+	if err := idx.Record(filepath.Join(folder, "alpha.png"), CaptureSource{
+		Mode:        CaptureModeWindow,
+		WindowTitle: "Build failed - Visual Studio",
+		ProcessName: "devenv.exe",
+	}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := idx.SetTags(filepath.Join(folder, "bravo.png"), []string{"bug", " Invoice ", "bug"}); err != nil {
+		t.Fatalf("SetTags() error = %v", err)
+	}
+	if err := idx.SetOCRText(filepath.Join(folder, "charlie.png"), "Error 404: page not found"); err != nil {
+		t.Fatalf("SetOCRText() error = %v", err)
+	}
+
+	return idx, folder
+}
+
+func filenames(images []LibraryImage) []string {
+	names := make([]string, len(images))
+	for i, img := range images {
+		names[i] = img.Filename
+	}
+	return names
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx, _ := newSearchFixture(t)
+
+	tests := []struct {
+		name  string
+		query SearchQuery
+		want  []string
+	}{
+		{"all newest first", SearchQuery{}, []string{"charlie.png", "bravo.png", "alpha.png"}},
+		{"oldest first", SearchQuery{Sort: SortOldest}, []string{"alpha.png", "bravo.png", "charlie.png"}},
+		{"by name", SearchQuery{Sort: SortName}, []string{"alpha.png", "bravo.png", "charlie.png"}},
+		{"by size", SearchQuery{Sort: SortSize}, []string{"bravo.png", "charlie.png", "alpha.png"}},
+		{"filename", SearchQuery{Text: "BRAVO"}, []string{"bravo.png"}},
+		{"window title", SearchQuery{Text: "visual studio"}, []string{"alpha.png"}},
+		{"ocr text", SearchQuery{Text: "404 found"}, []string{"charlie.png"}},
+		{"all terms required", SearchQuery{Text: "404 invoice"}, []string{}},
+		{"tag filter", SearchQuery{Filters: SearchFilters{Tags: []string{"invoice"}}}, []string{"bravo.png"}},
+		{"mode filter", SearchQuery{Filters: SearchFilters{Mode: CaptureModeWindow}}, []string{"alpha.png"}},
+		{"process filter", SearchQuery{Filters: SearchFilters{Process: "DEVENV"}}, []string{"alpha.png"}},
+		{"date range inclusive", SearchQuery{Filters: SearchFilters{From: "2026-03-11", To: "2026-03-11"}}, []string{"bravo.png"}},
+		{"from only", SearchQuery{Filters: SearchFilters{From: "2026-03-11"}}, []string{"charlie.png", "bravo.png"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filenames(idx.Search(tt.query).Images)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Search() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Search() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_SearchPaging(t *testing.T) {
+	idx, _ := newSearchFixture(t)
+
+	result := idx.Search(SearchQuery{Sort: SortName, Page: 2, PageSize: 2})
+	if result.Total != 3 || result.TotalPages != 2 {
+		t.Errorf("Total = %d, TotalPages = %d, want 3, 2", result.Total, result.TotalPages)
+	}
+	if got := filenames(result.Images); len(got) != 1 || got[0] != "charlie.png" {
+		t.Errorf("page 2 = %v, want [charlie.png]", got)
+	}
+
+	result = idx.Search(SearchQuery{Page: 5, PageSize: 2})
+	if len(result.Images) != 0 {
+		t.Errorf("out of range page returned %d images, want 0", len(result.Images))
+	}
+}
+
+func TestIndex_SearchResultMetadata(t *testing.T) {
+	idx, _ := newSearchFixture(t)
+
+	result := idx.Search(SearchQuery{Text: "bravo"})
+	if len(result.Images) != 1 {
+		t.Fatalf("Search() returned %d images, want 1", len(result.Images))
+	}
+	img := result.Images[0]
+	if img.Width != 40 || img.Height != 30 {
+		t.Errorf("dimensions = %dx%d, want 40x30", img.Width, img.Height)
+	}
+	if len(img.Tags) != 2 || img.Tags[0] != "bug" || img.Tags[1] != "Invoice" {
+		t.Errorf("Tags = %v, want [bug Invoice]", img.Tags)
+	}
+}
+
+func TestIndex_Rescan(t *testing.T) {
+	idx, folder := newSearchFixture(t)
+
+	// Unchanged folder is a no-op
+	stats, err := idx.Rescan(folder, 0)
+	if err != nil {
+		t.Fatalf("Rescan() error = %v", err)
+	}
+	if stats != (RescanStats{}) {
+		t.Errorf("Rescan() unchanged = %+v, want zero", stats)
+	}
+
+	// Modify one, delete one, add one
+	later := time.Date(2026, 4, 1, 0, 0, 0, 0, time.Local)
+	writeTestPNG(t, filepath.Join(folder, "alpha.png"), 50, 50, later)
+	if err := os.Remove(filepath.Join(folder, "charlie.png")); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	writeTestPNG(t, filepath.Join(folder, "delta.png"), 5, 5, later)
+
+	stats, err = idx.Rescan(folder, 0)
+	if err != nil {
+		t.Fatalf("Rescan() error = %v", err)
+	}
+	want := RescanStats{Added: 1, Updated: 1, Removed: 1}
+	if stats != want {
+		t.Errorf("Rescan() = %+v, want %+v", stats, want)
+	}
+
+	entry, ok := idx.Get(filepath.Join(folder, "alpha.png"))
+	if !ok {
+		t.Fatal("Get(alpha.png) not found")
+	}
+	if entry.Width != 50 {
+		t.Errorf("alpha.png Width = %d, want 50", entry.Width)
+	}
+	// Capture source survives a content refresh
+	if entry.Source.ProcessName != "devenv.exe" {
+		t.Errorf("alpha.png Source.ProcessName = %q, want devenv.exe", entry.Source.ProcessName)
+	}
+	if _, ok := idx.Get(filepath.Join(folder, "charlie.png")); ok {
+		t.Error("charlie.png still indexed after deletion")
+	}
+}
+
+func TestInScan(t *testing.T) {
+	root := "shots"
+	tests := []struct {
+		name     string
+		path     string
+		maxDepth int
+		want     bool
+	}{
+		{"top level", filepath.Join(root, "a.png"), 0, true},
+		{"subfolder", filepath.Join(root, "bugs", "a.png"), 1, true},
+		{"below the scan depth", filepath.Join(root, "bugs", "a.png"), 0, false},
+		{"trash", filepath.Join(root, ".trash", "a.png"), 2, false},
+		{"archive subfolder", filepath.Join(root, "bugs", ".archive", "a.png"), 2, false},
+		{"outside", filepath.Join("other", "a.png"), 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InScan(root, tt.path, tt.maxDepth); got != tt.want {
+				t.Errorf("InScan(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package library
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ThumbnailCache stores generated thumbnails on disk, keyed by source path,
+// size, modification time and thumbnail bounds. A changed file gets a new key,
+// so stale thumbnails are never served; they are removed by Prune.
+type ThumbnailCache struct {
+	dir string
+}
+
+// NewThumbnailCache creates a cache in dir, creating the directory if needed
+func NewThumbnailCache(dir string) (*ThumbnailCache, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("cache directory is empty")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &ThumbnailCache{dir: dir}, nil
+}
+
+// cachePath returns the file that holds the thumbnail for the given source file and bounds
+func (c *ThumbnailCache) cachePath(imagePath string, info os.FileInfo, maxWidth, maxHeight int) string {
+	key := fmt.Sprintf("%s|%d|%d|%dx%d",
+		strings.ToLower(filepath.Clean(imagePath)), info.Size(), info.ModTime().UnixNano(), maxWidth, maxHeight)
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".png")
+}
+
+// Get returns the cached PNG thumbnail for imagePath, if present
+func (c *ThumbnailCache) Get(imagePath string, info os.FileInfo, maxWidth, maxHeight int) ([]byte, bool) {
+	path := c.cachePath(imagePath, info, maxWidth, maxHeight)
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		return nil, false
+	}
+
+	// Mark as recently used so Prune keeps it
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return data, true
+}
+
+// Put stores a PNG thumbnail for imagePath
+func (c *ThumbnailCache) Put(imagePath string, info os.FileInfo, maxWidth, maxHeight int, thumbnail []byte) error {
+	path := c.cachePath(imagePath, info, maxWidth, maxHeight)
+
+	// Write to a temp file first so concurrent readers never see a partial thumbnail
+	tmpPath := fmt.Sprintf("%s.%d.tmp", path, time.Now().UnixNano())
+	if err := os.WriteFile(tmpPath, thumbnail, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// Prune removes cached thumbnails that haven't been used for maxAge.
+// Returns the number of files removed.
+func (c *ThumbnailCache) Prune(maxAge time.Duration) (int, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if os.Remove(filepath.Join(c.dir, entry.Name())) == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
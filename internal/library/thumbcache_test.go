@@ -0,0 +1,155 @@
+package library
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerateThumbnailCached_HitAndInvalidate(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewThumbnailCache(filepath.Join(dir, "thumbs"))
+	if err != nil {
+		t.Fatalf("NewThumbnailCache() error = %v", err)
+	}
+
+	imagePath := filepath.Join(dir, "shot.png")
+	modTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+	writeTestPNG(t, imagePath, 400, 300, modTime)
+
+	thumb, width, height, err := GenerateThumbnailCached(imagePath, 160, 120, cache)
+	if err != nil {
+		t.Fatalf("GenerateThumbnailCached() error = %v", err)
+	}
+	if width != 400 || height != 300 {
+		t.Errorf("dimensions = %dx%d, want 400x300", width, height)
+	}
+
+	// Replace the cached bytes with a marker; a hit must return them unchanged
+	info, _ := os.Stat(imagePath)
+	marker := []byte("cached")
+	if err := cache.Put(imagePath, info, 160, 120, marker); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	thumb, width, height, err = GenerateThumbnailCached(imagePath, 160, 120, cache)
+	if err != nil {
+		t.Fatalf("GenerateThumbnailCached() hit error = %v", err)
+	}
+	if thumb != base64.StdEncoding.EncodeToString(marker) {
+		t.Error("expected cached thumbnail on second call")
+	}
+	if width != 400 || height != 300 {
+		t.Errorf("cached dimensions = %dx%d, want 400x300", width, height)
+	}
+
+	// A different thumbnail size is a different key
+	if _, ok := cache.Get(imagePath, info, 80, 60); ok {
+		t.Error("Get() hit for different thumbnail bounds")
+	}
+
+	// Modifying the file invalidates the entry
+	writeTestPNG(t, imagePath, 200, 100, modTime.Add(time.Minute))
+	thumb, width, height, err = GenerateThumbnailCached(imagePath, 160, 120, cache)
+	if err != nil {
+		t.Fatalf("GenerateThumbnailCached() after modify error = %v", err)
+	}
+	if thumb == base64.StdEncoding.EncodeToString(marker) {
+		t.Error("stale thumbnail returned after file changed")
+	}
+	if width != 200 || height != 100 {
+		t.Errorf("dimensions after modify = %dx%d, want 200x100", width, height)
+	}
+}
+
+func TestThumbnailCache_Prune(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewThumbnailCache(dir)
+	if err != nil {
+		t.Fatalf("NewThumbnailCache() error = %v", err)
+	}
+
+	oldFile := filepath.Join(dir, "old.png")
+	newFile := filepath.Join(dir, "new.png")
+	for _, path := range []string{oldFile, newFile} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(oldFile, old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+
+	removed, err := cache.Prune(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Prune() removed %d, want 1", removed)
+	}
+	if _, err := os.Stat(oldFile); !os.IsNotExist(err) {
+		t.Error("old thumbnail not pruned")
+	}
+	if _, err := os.Stat(newFile); err != nil {
+		t.Error("recent thumbnail was pruned")
+	}
+}
+
+func TestScanFolderPage(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local)
+	names := []string{"a.png", "b.png", "c.png", "d.png", "e.png"}
+	for i, name := range names {
+		writeTestPNG(t, filepath.Join(dir, name), 8, 8, base.Add(time.Duration(i)*time.Hour))
+	}
+	// Non-image files are ignored
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("skip"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	opts := DefaultScanOptions()
+	opts.Workers = 2
+
+	result, err := ScanFolderPage(dir, opts, 2, 2)
+	if err != nil {
+		t.Fatalf("ScanFolderPage() error = %v", err)
+	}
+	if result.Total != 5 || result.TotalPages != 3 {
+		t.Errorf("Total = %d, TotalPages = %d, want 5, 3", result.Total, result.TotalPages)
+	}
+	got := filenames(result.Images)
+	if len(got) != 2 || got[0] != "c.png" || got[1] != "b.png" {
+		t.Fatalf("page 2 = %v, want [c.png b.png]", got)
+	}
+	for _, img := range result.Images {
+		if img.Thumbnail == "" || img.Width != 8 {
+			t.Errorf("%s: thumbnail/dimensions not loaded", img.Filename)
+		}
+	}
+}
+
+func TestScanFolder_MaxFilesKeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local)
+	for i, name := range []string{"old.png", "mid.png", "new.png"} {
+		writeTestPNG(t, filepath.Join(dir, name), 4, 4, base.Add(time.Duration(i)*time.Hour))
+	}
+	// Corrupt image is skipped
+	if err := os.WriteFile(filepath.Join(dir, "broken.png"), []byte("not a png"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	os.Chtimes(filepath.Join(dir, "broken.png"), base, base)
+
+	opts := DefaultScanOptions()
+	opts.MaxFiles = 2
+	images, err := ScanFolder(dir, opts)
+	if err != nil {
+		t.Fatalf("ScanFolder() error = %v", err)
+	}
+	got := filenames(images)
+	if len(got) != 2 || got[0] != "new.png" || got[1] != "mid.png" {
+		t.Errorf("ScanFolder() = %v, want [new.png mid.png]", got)
+	}
+}
@@ -16,10 +16,63 @@ import (
 // GenerateThumbnail creates a base64 PNG thumbnail from an image file
 // Returns: thumbnail base64 string, original width, original height, error
 func GenerateThumbnail(imagePath string, maxWidth, maxHeight int) (string, int, int, error) {
-	file, err := os.Open(imagePath)
+	data, origWidth, origHeight, err := renderThumbnail(imagePath, maxWidth, maxHeight)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return base64.StdEncoding.EncodeToString(data), origWidth, origHeight, nil
+}
+
+// GenerateThumbnailCached is GenerateThumbnail backed by an on-disk cache.
+// On a cache hit only the image header is read (for the original dimensions).
+// A nil cache always renders.
+func GenerateThumbnailCached(imagePath string, maxWidth, maxHeight int, cache *ThumbnailCache) (string, int, int, error) {
+	if cache == nil {
+		return GenerateThumbnail(imagePath, maxWidth, maxHeight)
+	}
+
+	info, err := os.Stat(imagePath)
 	if err != nil {
 		return "", 0, 0, err
 	}
+
+	if data, ok := cache.Get(imagePath, info, maxWidth, maxHeight); ok {
+		if width, height, err := ImageDimensions(imagePath); err == nil {
+			return base64.StdEncoding.EncodeToString(data), width, height, nil
+		}
+	}
+
+	data, origWidth, origHeight, err := renderThumbnail(imagePath, maxWidth, maxHeight)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	cache.Put(imagePath, info, maxWidth, maxHeight, data) // Non-fatal: next call re-renders
+
+	return base64.StdEncoding.EncodeToString(data), origWidth, origHeight, nil
+}
+
+// ImageDimensions reads an image's width and height from its header without decoding pixels
+func ImageDimensions(imagePath string) (int, int, error) {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	cfg, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// renderThumbnail decodes an image file and scales it into PNG thumbnail bytes
+// Returns: thumbnail PNG, original width, original height, error
+func renderThumbnail(imagePath string, maxWidth, maxHeight int) ([]byte, int, int, error) {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return nil, 0, 0, err
+	}
 	defer file.Close()
 
 	// Decode based on extension
@@ -37,7 +90,7 @@ func GenerateThumbnail(imagePath string, maxWidth, maxHeight int) (string, int,
 	}
 
 	if err != nil {
-		return "", 0, 0, err
+		return nil, 0, 0, err
 	}
 
 	bounds := img.Bounds()
@@ -54,10 +107,10 @@ func GenerateThumbnail(imagePath string, maxWidth, maxHeight int) (string, int,
 	// Encode as PNG (smaller than JPEG for small images with solid colors)
 	var buf bytes.Buffer
 	if err := png.Encode(&buf, thumb); err != nil {
-		return "", 0, 0, err
+		return nil, 0, 0, err
 	}
 
-	return base64.StdEncoding.EncodeToString(buf.Bytes()), origWidth, origHeight, nil
+	return buf.Bytes(), origWidth, origHeight, nil
 }
 
 // calculateThumbnailSize maintains aspect ratio within max bounds
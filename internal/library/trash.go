@@ -0,0 +1,333 @@
+package library
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TrashFolderName is the hidden folder inside the QuickSave root that holds deleted screenshots
+const TrashFolderName = ".trash"
+
+// trashManifestName is the file inside the trash folder that records trashed items
+const trashManifestName = "manifest.json"
+
+// TrashItem is a deleted screenshot that can still be restored
+type TrashItem struct {
+	ID           string      `json:"id"`
+	Filename     string      `json:"filename"`
+	OriginalPath string      `json:"originalPath"`
+	TrashPath    string      `json:"trashPath"`
+	DeletedAt    time.Time   `json:"deletedAt"`
+	Size         int64       `json:"size"`
+	Entry        *IndexEntry `json:"entry,omitempty"`     // Library metadata to restore with the file
+	Thumbnail    string      `json:"thumbnail,omitempty"` // Base64 PNG, filled in for display only
+}
+
+// Trash moves deleted screenshots into a hidden folder with a manifest
+// recording where they came from, so they can be restored or purged later
+type Trash struct {
+	root string
+	dir  string
+	mu   sync.Mutex
+}
+
+// trashManifest is the on-disk representation of the trash contents
+type trashManifest struct {
+	Items []TrashItem `json:"items"`
+}
+
+// NewTrash returns the trash for a QuickSave root folder
+func NewTrash(root string) *Trash {
+	return &Trash{
+		root: root,
+		dir:  filepath.Join(root, TrashFolderName),
+	}
+}
+
+// Root returns the QuickSave folder this trash belongs to
+func (t *Trash) Root() string {
+	return t.root
+}
+
+// Move moves imagePath into the trash. entry, if non-nil, is kept so the file's
+// library metadata can be restored with it.
+func (t *Trash) Move(imagePath string, entry *IndexEntry) (TrashItem, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info, err := os.Stat(imagePath)
+	if err != nil {
+		return TrashItem{}, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	manifest, err := t.load()
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return TrashItem{}, fmt.Errorf("failed to create trash folder: %w", err)
+	}
+	hideFolder(t.dir)
+
+	id, err := newTrashID()
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	item := TrashItem{
+		ID:           id,
+		Filename:     filepath.Base(imagePath),
+		OriginalPath: imagePath,
+		TrashPath:    filepath.Join(t.dir, id+filepath.Ext(imagePath)),
+		DeletedAt:    time.Now(),
+		Size:         info.Size(),
+		Entry:        entry,
+	}
+
+	if err := os.Rename(imagePath, item.TrashPath); err != nil {
+		return TrashItem{}, fmt.Errorf("failed to move file to trash: %w", err)
+	}
+
+	manifest.Items = append(manifest.Items, item)
+	if err := t.save(manifest); err != nil {
+		// Put the file back rather than leave it untracked
+		os.Rename(item.TrashPath, imagePath)
+		return TrashItem{}, err
+	}
+
+	return item, nil
+}
+
+// List returns the items in the trash, most recently deleted first.
+// Items whose file has gone missing are dropped from the manifest.
+func (t *Trash) List() ([]TrashItem, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	manifest, err := t.load()
+	if err != nil {
+		return nil, err
+	}
+
+	items := []TrashItem{}
+	for _, item := range manifest.Items {
+		if fileExists(item.TrashPath) {
+			items = append(items, item)
+		}
+	}
+	if len(items) != len(manifest.Items) {
+		t.save(&trashManifest{Items: items})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+// Restore moves a trashed file back to its original location. If that path is
+// taken, a numbered suffix is added. Returns the restored item and its new path.
+func (t *Trash) Restore(id string) (TrashItem, string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	manifest, err := t.load()
+	if err != nil {
+		return TrashItem{}, "", err
+	}
+
+	i := manifest.find(id)
+	if i < 0 {
+		return TrashItem{}, "", fmt.Errorf("trash item not found: %s", id)
+	}
+	item := manifest.Items[i]
+
+	if err := os.MkdirAll(filepath.Dir(item.OriginalPath), 0755); err != nil {
+		return TrashItem{}, "", fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	target := availablePath(item.OriginalPath)
+	if err := os.Rename(item.TrashPath, target); err != nil {
+		return TrashItem{}, "", fmt.Errorf("failed to restore file: %w", err)
+	}
+
+	manifest.Items = append(manifest.Items[:i], manifest.Items[i+1:]...)
+	if err := t.save(manifest); err != nil {
+		return TrashItem{}, "", err
+	}
+
+	return item, target, nil
+}
+
+// Delete permanently removes one item from the trash
+func (t *Trash) Delete(id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	manifest, err := t.load()
+	if err != nil {
+		return err
+	}
+
+	i := manifest.find(id)
+	if i < 0 {
+		return fmt.Errorf("trash item not found: %s", id)
+	}
+	if err := os.Remove(manifest.Items[i].TrashPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	manifest.Items = append(manifest.Items[:i], manifest.Items[i+1:]...)
+	return t.save(manifest)
+}
+
+// Empty permanently removes everything in the trash. Returns the number of items removed.
+func (t *Trash) Empty() (int, error) {
+	return t.purge(func(TrashItem) bool { return true })
+}
+
+// Purge permanently removes items deleted more than maxAge ago.
+// Returns the number of items removed.
+func (t *Trash) Purge(maxAge time.Duration) (int, error) {
+	cutoff := time.Now().Add(-maxAge)
+	return t.purge(func(item TrashItem) bool {
+		return item.DeletedAt.Before(cutoff)
+	})
+}
+
+// PurgeToSize permanently removes the earliest deleted items until the trash
+// holds at most maxSize bytes. Returns the number of items removed.
+func (t *Trash) PurgeToSize(maxSize int64) (int, error) {
+	items, err := t.List() // Most recently deleted first
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, item := range items {
+		size += item.Size
+	}
+	purge := make(map[string]bool)
+	for i := len(items) - 1; i >= 0 && size > maxSize; i-- {
+		purge[items[i].ID] = true
+		size -= items[i].Size
+	}
+	if len(purge) == 0 {
+		return 0, nil
+	}
+	return t.purge(func(item TrashItem) bool { return purge[item.ID] })
+}
+
+// purge permanently removes the items matching fn
+func (t *Trash) purge(fn func(TrashItem) bool) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	manifest, err := t.load()
+	if err != nil {
+		return 0, err
+	}
+
+	var kept []TrashItem
+	removed := 0
+	for _, item := range manifest.Items {
+		if !fn(item) {
+			kept = append(kept, item)
+			continue
+		}
+		if err := os.Remove(item.TrashPath); err != nil && !os.IsNotExist(err) {
+			kept = append(kept, item) // Locked or in use - try again next time
+			continue
+		}
+		removed++
+	}
+
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, t.save(&trashManifest{Items: kept})
+}
+
+// load reads the manifest. Caller must hold mu.
+func (t *Trash) load() (*trashManifest, error) {
+	manifest := &trashManifest{}
+
+	data, err := os.ReadFile(filepath.Join(t.dir, trashManifestName))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse trash manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// save writes the manifest. Caller must hold mu.
+func (t *Trash) save(manifest *trashManifest) error {
+	if manifest.Items == nil {
+		manifest.Items = []TrashItem{}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a crash can't leave a truncated manifest
+	path := filepath.Join(t.dir, trashManifestName)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// find returns the position of the item with id, or -1
+func (m *trashManifest) find(id string) int {
+	for i, item := range m.Items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// newTrashID returns a random identifier for a trashed file
+func newTrashID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate trash id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// availablePath returns path, or path with " (n)" before the extension if path exists
+func availablePath(path string) string {
+	if !fileExists(path) {
+		return path
+	}
+
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 1; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, n, ext)
+		if !fileExists(candidate) {
+			return candidate
+		}
+	}
+}
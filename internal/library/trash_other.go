@@ -0,0 +1,6 @@
+//go:build !windows
+
+package library
+
+// hideFolder is a no-op; the leading dot already hides the folder
+func hideFolder(path string) {}
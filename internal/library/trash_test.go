@@ -0,0 +1,155 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrash_MoveListRestore(t *testing.T) {
+	root := t.TempDir()
+	imagePath := filepath.Join(root, "sub", "shot.png")
+	if err := os.MkdirAll(filepath.Dir(imagePath), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	writeTestPNG(t, imagePath, 2, 2, time.Now())
+
+	trash := NewTrash(root)
+	entry := &IndexEntry{Tags: []string{"keep"}}
+	item, err := trash.Move(imagePath, entry)
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if fileExists(imagePath) {
+		t.Error("original file still exists after Move()")
+	}
+	if filepath.Dir(item.TrashPath) != filepath.Join(root, TrashFolderName) {
+		t.Errorf("TrashPath = %q, want inside %s", item.TrashPath, TrashFolderName)
+	}
+
+	// A fresh Trash reads the same manifest
+	items, err := NewTrash(root).List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(items) != 1 || items[0].OriginalPath != imagePath || items[0].Entry == nil || items[0].Entry.Tags[0] != "keep" {
+		t.Fatalf("List() = %+v, want the trashed item with its metadata", items)
+	}
+
+	// Restoring onto a taken path adds a suffix
+	writeTestPNG(t, imagePath, 2, 2, time.Now())
+	_, restoredPath, err := trash.Restore(item.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	want := filepath.Join(root, "sub", "shot (1).png")
+	if restoredPath != want {
+		t.Errorf("Restore() path = %q, want %q", restoredPath, want)
+	}
+	if !fileExists(restoredPath) {
+		t.Error("restored file missing")
+	}
+
+	if _, _, err := trash.Restore(item.ID); err == nil {
+		t.Error("Restore() of an already restored item should fail")
+	}
+	if items, _ := trash.List(); len(items) != 0 {
+		t.Errorf("List() after restore = %d items, want 0", len(items))
+	}
+}
+
+func TestTrash_PurgeAndEmpty(t *testing.T) {
+	root := t.TempDir()
+	trash := NewTrash(root)
+
+	var ids []string
+	for _, name := range []string{"a.png", "b.png", "c.png"} {
+		path := filepath.Join(root, name)
+		writeTestPNG(t, path, 2, 2, time.Now())
+		item, err := trash.Move(path, nil)
+		if err != nil {
+			t.Fatalf("Move(%s) error = %v", name, err)
+		}
+		ids = append(ids, item.ID)
+	}
+
+	// Backdate the first item
+	trash.mu.Lock()
+	manifest, _ := trash.load()
+	manifest.Items[0].DeletedAt = time.Now().Add(-40 * 24 * time.Hour)
+	trash.save(manifest)
+	trash.mu.Unlock()
+
+	removed, err := trash.Purge(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Purge() removed %d, want 1", removed)
+	}
+
+	if err := trash.Delete(ids[1]); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	removed, err = trash.Empty()
+	if err != nil {
+		t.Fatalf("Empty() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Empty() removed %d, want 1", removed)
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(root, TrashFolderName))
+	for _, entry := range entries {
+		if entry.Name() != trashManifestName {
+			t.Errorf("unexpected file left in trash: %s", entry.Name())
+		}
+	}
+}
+
+func TestTrash_PurgeToSize(t *testing.T) {
+	root := t.TempDir()
+	trash := NewTrash(root)
+	var size int64
+	for _, name := range []string{"a.png", "b.png", "c.png"} {
+		path := filepath.Join(root, name)
+		writeTestPNG(t, path, 2, 2, time.Now())
+		item, err := trash.Move(path, nil)
+		if err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+		size = item.Size
+	}
+
+	if n, err := trash.PurgeToSize(3 * size); n != 0 || err != nil {
+		t.Errorf("PurgeToSize() under the limit = %d, %v; want nothing removed", n, err)
+	}
+	if n, err := trash.PurgeToSize(size); n != 2 || err != nil {
+		t.Errorf("PurgeToSize() = %d, %v; want 2 removed", n, err)
+	}
+	if items, _ := trash.List(); len(items) != 1 {
+		t.Errorf("List() = %d items, want 1", len(items))
+	}
+}
+
+func TestTrash_HiddenFromScans(t *testing.T) {
+	root := t.TempDir()
+	imagePath := filepath.Join(root, "shot.png")
+	writeTestPNG(t, imagePath, 2, 2, time.Now())
+
+	if _, err := NewTrash(root).Move(imagePath, nil); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+
+	opts := DefaultScanOptions()
+	opts.MaxDepth = 5
+	images, err := ListFolder(root, opts)
+	if err != nil {
+		t.Fatalf("ListFolder() error = %v", err)
+	}
+	if len(images) != 0 {
+		t.Errorf("ListFolder() = %v, want trash to be skipped", filenames(images))
+	}
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package library
+
+import "golang.org/x/sys/windows"
+
+// hideFolder sets the hidden attribute so the trash folder stays out of Explorer
+func hideFolder(path string) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return
+	}
+	attrs, err := windows.GetFileAttributes(p)
+	if err != nil || attrs&windows.FILE_ATTRIBUTE_HIDDEN != 0 {
+		return
+	}
+	windows.SetFileAttributes(p, attrs|windows.FILE_ATTRIBUTE_HIDDEN)
+}
@@ -0,0 +1,294 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// File change kinds reported by Watcher
+const (
+	FileAdded   = "added"
+	FileRemoved = "removed"
+	FileChanged = "changed"
+)
+
+// FileEvent describes a change to an image file in a watched folder
+type FileEvent struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+}
+
+// WatchOptions configures a Watcher
+type WatchOptions struct {
+	Debounce     time.Duration // Quiet period before changes are reported (default: 500ms)
+	PollInterval time.Duration // Folder poll interval when polling (default: 3s)
+	ForcePolling bool          // Skip native notifications, e.g. for network drives
+	MaxDepth     int           // Subfolder levels to watch (0 = top folder only)
+}
+
+// DefaultWatchOptions returns sensible defaults for watching the QuickSave folder
+func DefaultWatchOptions() WatchOptions {
+	return WatchOptions{
+		Debounce:     500 * time.Millisecond,
+		PollInterval: 3 * time.Second,
+	}
+}
+
+// fileState is the part of a file's metadata used to detect changes
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// Watcher reports image files added to, removed from or modified in a folder.
+// It uses native filesystem notifications when available and falls back to
+// polling. Bursts of changes (e.g. a file being written or synced in chunks)
+// are debounced and reported as one batch, diffed against the last snapshot.
+type Watcher struct {
+	folder  string
+	opts    WatchOptions
+	handler func([]FileEvent)
+
+	snapshot map[string]fileState
+	watched  map[string]bool // Folders registered with the native notifier
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	mu       sync.Mutex
+}
+
+// NewWatcher creates a watcher for folder. handler is called from the watcher
+// goroutine with each debounced batch of changes.
+func NewWatcher(folder string, opts WatchOptions, handler func([]FileEvent)) *Watcher {
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultWatchOptions().Debounce
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultWatchOptions().PollInterval
+	}
+	return &Watcher{
+		folder:  folder,
+		opts:    opts,
+		handler: handler,
+	}
+}
+
+// Start takes an initial snapshot and begins watching in the background
+func (w *Watcher) Start() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stopCh != nil {
+		return nil // Already running
+	}
+
+	if err := os.MkdirAll(w.folder, 0755); err != nil {
+		return err
+	}
+	snapshot, err := snapshotFolder(w.folder, w.opts.MaxDepth)
+	if err != nil {
+		return err
+	}
+	w.snapshot = snapshot
+
+	var notifier *fsnotify.Watcher
+	if !w.opts.ForcePolling {
+		if nw, err := fsnotify.NewWatcher(); err == nil {
+			if err := nw.Add(w.folder); err == nil {
+				notifier = nw
+				w.watched = map[string]bool{w.folder: true}
+				w.watchSubfolders(notifier)
+			} else {
+				nw.Close()
+			}
+		}
+	}
+
+	w.stopCh = make(chan struct{})
+	w.doneCh = make(chan struct{})
+	go w.run(notifier, w.stopCh, w.doneCh)
+	return nil
+}
+
+// Stop stops watching and waits for the watcher goroutine to exit
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	stopCh, doneCh := w.stopCh, w.doneCh
+	w.stopCh, w.doneCh = nil, nil
+	w.mu.Unlock()
+
+	if stopCh == nil {
+		return
+	}
+	close(stopCh)
+	<-doneCh
+}
+
+// Folder returns the watched folder
+func (w *Watcher) Folder() string {
+	return w.folder
+}
+
+// MaxDepth returns the number of subfolder levels being watched
+func (w *Watcher) MaxDepth() int {
+	return w.opts.MaxDepth
+}
+
+// watchSubfolders registers folders within MaxDepth that aren't watched yet.
+// fsnotify is not recursive, and removed folders are dropped by it automatically.
+func (w *Watcher) watchSubfolders(notifier *fsnotify.Watcher) {
+	for _, dir := range listSubfolders(w.folder, w.opts.MaxDepth) {
+		if !w.watched[dir] && notifier.Add(dir) == nil {
+			w.watched[dir] = true
+		}
+	}
+}
+
+// run is the watcher loop. With a nil notifier it polls on opts.PollInterval.
+func (w *Watcher) run(notifier *fsnotify.Watcher, stopCh, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	var events chan fsnotify.Event
+	var errors chan error
+	var pollC <-chan time.Time
+	var ticker *time.Ticker
+	defer func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
+	// startPolling stops listening for notifications and checks the folder on a timer instead
+	startPolling := func() {
+		events, errors = nil, nil
+		if ticker == nil {
+			ticker = time.NewTicker(w.opts.PollInterval)
+			pollC = ticker.C
+		}
+	}
+	if notifier != nil {
+		defer notifier.Close()
+		events, errors = notifier.Events, notifier.Errors
+	} else {
+		startPolling()
+	}
+
+	debounce := time.NewTimer(w.opts.Debounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			debounce.Stop()
+			return
+
+		case event, ok := <-events:
+			if !ok {
+				// Notifications stopped - keep going by polling
+				startPolling()
+				continue
+			}
+			// Extension-less names are usually folders, which may bring new files with them
+			ext := strings.ToLower(filepath.Ext(event.Name))
+			if supportedExtensions[ext] || ext == "" {
+				debounce.Reset(w.opts.Debounce)
+			}
+
+		case _, ok := <-errors:
+			if !ok {
+				startPolling()
+				continue
+			}
+			// Overflow or similar - resync on the next quiet period
+			debounce.Reset(w.opts.Debounce)
+
+		case <-pollC:
+			w.sync()
+
+		case <-debounce.C:
+			if notifier != nil && events != nil {
+				w.watchSubfolders(notifier)
+			}
+			w.sync()
+		}
+	}
+}
+
+// sync diffs the folder against the last snapshot and reports any changes
+func (w *Watcher) sync() {
+	current, err := snapshotFolder(w.folder, w.opts.MaxDepth)
+	if err != nil {
+		return // Folder temporarily unavailable (e.g. drive disconnected)
+	}
+
+	changes := diffSnapshots(w.snapshot, current)
+	w.snapshot = current
+
+	if len(changes) > 0 && w.handler != nil {
+		w.handler(changes)
+	}
+}
+
+// snapshotFolder records size and mtime of the supported image files in folder
+// and up to maxDepth levels of subfolders
+func snapshotFolder(folder string, maxDepth int) (map[string]fileState, error) {
+	snapshot := make(map[string]fileState)
+	err := walkImages(folder, maxDepth, func(path string, info os.FileInfo) {
+		snapshot[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// listSubfolders returns the non-hidden folders below folder, down to maxDepth levels
+func listSubfolders(folder string, maxDepth int) []string {
+	if maxDepth <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(folder, entry.Name())
+		dirs = append(dirs, dir)
+		dirs = append(dirs, listSubfolders(dir, maxDepth-1)...)
+	}
+	return dirs
+}
+
+// diffSnapshots returns the changes between two folder snapshots, sorted by path
+func diffSnapshots(before, after map[string]fileState) []FileEvent {
+	var changes []FileEvent
+	for path, state := range after {
+		prev, existed := before[path]
+		switch {
+		case !existed:
+			changes = append(changes, FileEvent{Kind: FileAdded, Path: path})
+		case prev.size != state.size || !prev.modTime.Equal(state.modTime):
+			changes = append(changes, FileEvent{Kind: FileChanged, Path: path})
+		}
+	}
+	for path := range before {
+		if _, exists := after[path]; !exists {
+			changes = append(changes, FileEvent{Kind: FileRemoved, Path: path})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
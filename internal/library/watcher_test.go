@@ -0,0 +1,92 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := map[string]fileState{
+		"keep.png":   {size: 10, modTime: t0},
+		"resize.png": {size: 10, modTime: t0},
+		"touch.png":  {size: 10, modTime: t0},
+		"gone.png":   {size: 10, modTime: t0},
+	}
+	after := map[string]fileState{
+		"keep.png":   {size: 10, modTime: t0},
+		"resize.png": {size: 20, modTime: t0},
+		"touch.png":  {size: 10, modTime: t0.Add(time.Second)},
+		"new.png":    {size: 5, modTime: t0},
+	}
+
+	want := []FileEvent{
+		{Kind: FileRemoved, Path: "gone.png"},
+		{Kind: FileAdded, Path: "new.png"},
+		{Kind: FileChanged, Path: "resize.png"},
+		{Kind: FileChanged, Path: "touch.png"},
+	}
+
+	got := diffSnapshots(before, after)
+	if len(got) != len(want) {
+		t.Fatalf("diffSnapshots() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("diffSnapshots()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if changes := diffSnapshots(before, before); len(changes) != 0 {
+		t.Errorf("diffSnapshots() unchanged = %v, want none", changes)
+	}
+}
+
+func TestWatcher_Polling(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.png")
+	writeTestPNG(t, existing, 4, 4, time.Now().Add(-time.Hour))
+
+	batches := make(chan []FileEvent, 10)
+	w := NewWatcher(dir, WatchOptions{
+		Debounce:     20 * time.Millisecond,
+		PollInterval: 20 * time.Millisecond,
+		ForcePolling: true,
+	}, func(events []FileEvent) {
+		batches <- events
+	})
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer w.Stop()
+
+	added := filepath.Join(dir, "added.png")
+	writeTestPNG(t, added, 4, 4, time.Now())
+	if err := os.Remove(existing); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	// Non-image files are ignored
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644)
+
+	seen := make(map[FileEvent]bool)
+	timeout := time.After(5 * time.Second)
+	for !seen[FileEvent{Kind: FileAdded, Path: added}] || !seen[FileEvent{Kind: FileRemoved, Path: existing}] {
+		select {
+		case batch := <-batches:
+			for _, event := range batch {
+				if filepath.Ext(event.Path) != ".png" {
+					t.Errorf("unexpected event for non-image file: %v", event)
+				}
+				seen[event] = true
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for events, got %v", seen)
+		}
+	}
+
+	// Stop is idempotent
+	w.Stop()
+	w.Stop()
+}
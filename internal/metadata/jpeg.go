@@ -0,0 +1,314 @@
+package metadata
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JPEG markers
+const (
+	markerSOI   = 0xD8
+	markerSOS   = 0xDA
+	markerEOI   = 0xD9
+	markerAPP0  = 0xE0
+	markerAPP1  = 0xE1
+	markerAPP13 = 0xED
+	markerCOM   = 0xFE
+)
+
+var (
+	exifHeader = []byte("Exif\x00\x00")
+	xmpHeader  = []byte(nsXMP + "\x00")
+)
+
+// EXIF IFD0 tags written and read by this package (all ASCII)
+const (
+	tagImageDescription = 0x010E
+	tagSoftware         = 0x0131
+	tagDateTime         = 0x0132
+	tagArtist           = 0x013B
+)
+
+// exifTimeLayout is the EXIF DateTime format (local time, no zone)
+const exifTimeLayout = "2006:01:02 15:04:05"
+
+// maxSegment is the largest JPEG segment payload (length field includes itself)
+const maxSegment = 0xFFFF - 2
+
+func isJPEG(data []byte) bool {
+	return len(data) >= 3 && data[0] == 0xFF && data[1] == markerSOI && data[2] == 0xFF
+}
+
+// jpegSegment is a marker segment before the scan data
+type jpegSegment struct {
+	marker byte
+	data   []byte // Payload without marker and length
+}
+
+// splitJPEG parses the segments before the first SOS; the remainder (SOS
+// onwards, including the entropy-coded data) is returned verbatim
+func splitJPEG(data []byte) ([]jpegSegment, []byte, error) {
+	var segments []jpegSegment
+	pos := 2
+	for {
+		// Skip fill bytes
+		for pos < len(data) && data[pos] == 0xFF && pos+1 < len(data) && data[pos+1] == 0xFF {
+			pos++
+		}
+		if pos+4 > len(data) || data[pos] != 0xFF {
+			return nil, nil, errors.New("invalid JPEG marker")
+		}
+		marker := data[pos+1]
+		if marker == markerSOS || marker == markerEOI {
+			return segments, data[pos:], nil
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return nil, nil, fmt.Errorf("truncated JPEG segment 0x%02X", marker)
+		}
+		segments = append(segments, jpegSegment{marker: marker, data: data[pos+4 : pos+2+length]})
+		pos += 2 + length
+	}
+}
+
+// joinJPEG serializes segments followed by the scan data
+func joinJPEG(segments []jpegSegment, rest []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, markerSOI})
+	for _, s := range segments {
+		buf.Write([]byte{0xFF, s.marker})
+		binary.Write(&buf, binary.BigEndian, uint16(len(s.data)+2))
+		buf.Write(s.data)
+	}
+	buf.Write(rest)
+	return buf.Bytes()
+}
+
+// isJPEGMetadata reports whether a segment carries textual metadata:
+// APP1 (EXIF/XMP), APP13 (IPTC/Photoshop) or a comment
+func isJPEGMetadata(s jpegSegment) bool {
+	return s.marker == markerAPP1 || s.marker == markerAPP13 || s.marker == markerCOM
+}
+
+func stripJPEG(data []byte) ([]byte, error) {
+	segments, rest, err := splitJPEG(data)
+	if err != nil {
+		return nil, err
+	}
+	kept := segments[:0]
+	for _, s := range segments {
+		if !isJPEGMetadata(s) {
+			kept = append(kept, s)
+		}
+	}
+	return joinJPEG(kept, rest), nil
+}
+
+func embedJPEG(data []byte, md Metadata) ([]byte, error) {
+	segments, rest, err := splitJPEG(data)
+	if err != nil {
+		return nil, err
+	}
+
+	xmp := append(append([]byte{}, xmpHeader...), buildXMP(md)...)
+	if len(xmp) > maxSegment {
+		return nil, errors.New("metadata too large for a JPEG segment")
+	}
+	added := []jpegSegment{
+		{marker: markerAPP1, data: buildEXIF(md)},
+		{marker: markerAPP1, data: xmp},
+	}
+
+	// Keep a leading JFIF APP0 first, as the JFIF spec requires
+	var out []jpegSegment
+	i := 0
+	if len(segments) > 0 && segments[0].marker == markerAPP0 {
+		out = append(out, segments[0])
+		i = 1
+	}
+	out = append(out, added...)
+	for _, s := range segments[i:] {
+		if !isJPEGMetadata(s) {
+			out = append(out, s)
+		}
+	}
+	return joinJPEG(out, rest), nil
+}
+
+// buildEXIF builds an APP1 EXIF payload with the IFD0 ASCII tags
+func buildEXIF(md Metadata) []byte {
+	values := map[uint16]string{tagSoftware: softwareString(md.AppVersion)}
+	if md.WindowTitle != "" {
+		values[tagImageDescription] = md.WindowTitle
+	}
+	if !md.CapturedAt.IsZero() {
+		values[tagDateTime] = md.CapturedAt.Local().Format(exifTimeLayout)
+	}
+	if md.Author != "" {
+		values[tagArtist] = md.Author
+	}
+
+	tags := make([]uint16, 0, len(values))
+	for tag := range values {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+	// Little-endian TIFF: header, IFD0 (count, entries, next IFD), value area
+	order := binary.LittleEndian
+	ifdSize := 2 + 12*len(tags) + 4
+	valueOffset := 8 + ifdSize
+
+	var ifd, area bytes.Buffer
+	binary.Write(&ifd, order, uint16(len(tags)))
+	for _, tag := range tags {
+		value := append([]byte(values[tag]), 0)
+		binary.Write(&ifd, order, tag)
+		binary.Write(&ifd, order, uint16(2)) // ASCII
+		binary.Write(&ifd, order, uint32(len(value)))
+		if len(value) <= 4 {
+			var inline [4]byte
+			copy(inline[:], value)
+			ifd.Write(inline[:])
+			continue
+		}
+		binary.Write(&ifd, order, uint32(valueOffset+area.Len()))
+		area.Write(value)
+		if area.Len()%2 == 1 {
+			area.WriteByte(0) // Keep offsets word-aligned
+		}
+	}
+	binary.Write(&ifd, order, uint32(0)) // No next IFD
+
+	var buf bytes.Buffer
+	buf.Write(exifHeader)
+	buf.WriteString("II*\x00")
+	binary.Write(&buf, order, uint32(8))
+	buf.Write(ifd.Bytes())
+	buf.Write(area.Bytes())
+	return buf.Bytes()
+}
+
+// parseEXIF reads the IFD0 ASCII tags this package writes
+func parseEXIF(payload []byte) map[uint16]string {
+	tiff := payload[len(exifHeader):]
+	if len(tiff) < 8 {
+		return nil
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return nil
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	values := make(map[uint16]string)
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + 12*i
+		if entry+12 > len(tiff) {
+			break
+		}
+		tag := order.Uint16(tiff[entry:])
+		typ := order.Uint16(tiff[entry+2:])
+		n := int(order.Uint32(tiff[entry+4:]))
+		if typ != 2 || n <= 0 {
+			continue
+		}
+
+		var raw []byte
+		if n <= 4 {
+			raw = tiff[entry+8 : entry+8+n]
+		} else {
+			start := int(order.Uint32(tiff[entry+8:]))
+			if start < 0 || start+n > len(tiff) {
+				continue
+			}
+			raw = tiff[start : start+n]
+		}
+		values[tag] = strings.TrimRight(string(raw), "\x00")
+	}
+	return values
+}
+
+// readJPEG reads the segments before the scan data and maps EXIF/XMP to Metadata
+func readJPEG(r *bufio.Reader) (Metadata, error) {
+	if _, err := r.Discard(2); err != nil {
+		return Metadata{}, err
+	}
+
+	var xmp []byte
+	var exif map[uint16]string
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return Metadata{}, fmt.Errorf("failed to read JPEG marker: %w", err)
+		}
+		if b != 0xFF {
+			return Metadata{}, errors.New("invalid JPEG marker")
+		}
+		marker, err := r.ReadByte()
+		if err != nil {
+			return Metadata{}, fmt.Errorf("failed to read JPEG marker: %w", err)
+		}
+		if marker == 0xFF {
+			r.UnreadByte() // Fill byte
+			continue
+		}
+		if marker == markerSOS || marker == markerEOI {
+			break
+		}
+
+		var length uint16
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil || length < 2 {
+			return Metadata{}, fmt.Errorf("truncated JPEG segment 0x%02X", marker)
+		}
+		if marker != markerAPP1 {
+			if _, err := r.Discard(int(length) - 2); err != nil {
+				return Metadata{}, fmt.Errorf("truncated JPEG segment 0x%02X", marker)
+			}
+			continue
+		}
+
+		payload := make([]byte, length-2)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return Metadata{}, fmt.Errorf("truncated JPEG segment 0x%02X", marker)
+		}
+		switch {
+		case bytes.HasPrefix(payload, xmpHeader):
+			xmp = payload[len(xmpHeader):]
+		case bytes.HasPrefix(payload, exifHeader):
+			exif = parseEXIF(payload)
+		}
+	}
+
+	var md Metadata
+	if xmp != nil {
+		if err := parseXMP(xmp, &md); err != nil {
+			return Metadata{}, fmt.Errorf("invalid XMP: %w", err)
+		}
+	}
+	if version, ok := parseSoftware(exif[tagSoftware]); ok && md.AppVersion == "" {
+		md.AppVersion = version
+	}
+	if t, err := time.ParseInLocation(exifTimeLayout, exif[tagDateTime], time.Local); err == nil && md.CapturedAt.IsZero() {
+		md.CapturedAt = t
+	}
+	setIfEmpty(&md.WindowTitle, exif[tagImageDescription])
+	setIfEmpty(&md.Author, exif[tagArtist])
+	return md, nil
+}
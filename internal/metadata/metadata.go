@@ -0,0 +1,118 @@
+// Package metadata embeds capture context into saved PNG and JPEG files and
+// reads it back. PNGs get tEXt/iTXt chunks, JPEGs get EXIF and XMP segments;
+// both carry the full record as XMP.
+package metadata
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// Metadata describes where a screenshot came from
+type Metadata struct {
+	CaptureMode string    `json:"captureMode,omitempty"`
+	CapturedAt  time.Time `json:"capturedAt"`
+	WindowTitle string    `json:"windowTitle,omitempty"`
+	WindowClass string    `json:"windowClass,omitempty"`
+	ProcessName string    `json:"processName,omitempty"`
+	Monitor     int       `json:"monitor,omitempty"`  // 1-based display number, 0 = unknown
+	DPIScale    float64   `json:"dpiScale,omitempty"` // e.g. 1.5 for 150%, 0 = unknown
+	AppVersion  string    `json:"appVersion,omitempty"`
+	Author      string    `json:"author,omitempty"`
+}
+
+// IsZero reports whether no field is set
+func (m *Metadata) IsZero() bool {
+	return *m == Metadata{}
+}
+
+// softwareName prefixes the app version in Software/CreatorTool fields
+const softwareName = "WinShot"
+
+// ErrUnsupportedFormat is returned for data that is neither PNG nor JPEG
+var ErrUnsupportedFormat = errors.New("unsupported image format (PNG and JPEG only)")
+
+// Embed returns data with any existing metadata replaced by md
+func Embed(data []byte, md Metadata) ([]byte, error) {
+	switch {
+	case isPNG(data):
+		return embedPNG(data, md)
+	case isJPEG(data):
+		return embedJPEG(data, md)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// Strip returns data without textual metadata: PNG text, time and EXIF chunks,
+// and JPEG EXIF, XMP, IPTC and comment segments. Pixel data, color profiles and
+// everything needed to decode the image are kept.
+func Strip(data []byte) ([]byte, error) {
+	switch {
+	case isPNG(data):
+		return stripPNG(data)
+	case isJPEG(data):
+		return stripJPEG(data)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// Read extracts metadata from PNG or JPEG data. ok is false when the file
+// carries none of the fields this package writes.
+func Read(data []byte) (md Metadata, ok bool, err error) {
+	return read(bufio.NewReader(bytes.NewReader(data)))
+}
+
+// ReadFile extracts metadata from an image file, reading only the headers
+func ReadFile(path string) (md Metadata, ok bool, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Metadata{}, false, err
+	}
+	defer file.Close()
+	return read(bufio.NewReader(file))
+}
+
+func read(r *bufio.Reader) (Metadata, bool, error) {
+	head, err := r.Peek(8)
+	if err != nil && err != io.EOF {
+		return Metadata{}, false, err
+	}
+
+	var md Metadata
+	switch {
+	case isPNG(head):
+		md, err = readPNG(r)
+	case isJPEG(head):
+		md, err = readJPEG(r)
+	default:
+		return Metadata{}, false, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return Metadata{}, false, err
+	}
+	return md, !md.IsZero(), nil
+}
+
+// softwareString formats the Software/CreatorTool value
+func softwareString(version string) string {
+	if version == "" {
+		return softwareName
+	}
+	return softwareName + " " + version
+}
+
+// parseSoftware extracts the app version from a Software/CreatorTool value.
+// Values written by other tools are ignored.
+func parseSoftware(s string) (string, bool) {
+	if s == softwareName {
+		return "", true
+	}
+	if len(s) > len(softwareName)+1 && s[:len(softwareName)+1] == softwareName+" " {
+		return s[len(softwareName)+1:], true
+	}
+	return "", false
+}
@@ -0,0 +1,218 @@
+package metadata
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 16; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(x * 16), uint8(y * 32), 128, 255})
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func encodeJPEG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(), &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func sampleMetadata() Metadata {
+	return Metadata{
+		CaptureMode: "window",
+		CapturedAt:  time.Date(2026, 3, 10, 14, 30, 45, 0, time.Local),
+		WindowTitle: `Build "failed" <Visual Studio> – Ünïcode`,
+		WindowClass: "HwndWrapper[DefaultDomain;;]",
+		ProcessName: "devenv.exe",
+		Monitor:     2,
+		DPIScale:    1.5,
+		AppVersion:  "1.4.0",
+		Author:      "Jane Tester",
+	}
+}
+
+func TestEmbedRead(t *testing.T) {
+	tests := []struct {
+		name   string
+		encode func(*testing.T) []byte
+		decode func([]byte) (image.Image, error)
+	}{
+		{"png", encodePNG, func(b []byte) (image.Image, error) { return png.Decode(bytes.NewReader(b)) }},
+		{"jpeg", encodeJPEG, func(b []byte) (image.Image, error) { return jpeg.Decode(bytes.NewReader(b)) }},
+	}
+
+	want := sampleMetadata()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.encode(t)
+
+			embedded, err := Embed(original, want)
+			if err != nil {
+				t.Fatalf("Embed() error = %v", err)
+			}
+			if _, err := tt.decode(embedded); err != nil {
+				t.Fatalf("embedded image no longer decodes: %v", err)
+			}
+
+			got, ok, err := Read(embedded)
+			if err != nil || !ok {
+				t.Fatalf("Read() = %v, %v", ok, err)
+			}
+			if !got.CapturedAt.Equal(want.CapturedAt) {
+				t.Errorf("CapturedAt = %v, want %v", got.CapturedAt, want.CapturedAt)
+			}
+			got.CapturedAt = want.CapturedAt
+			if got != want {
+				t.Errorf("Read() = %+v\nwant %+v", got, want)
+			}
+
+			// Embedding again replaces rather than duplicates
+			changed := want
+			changed.Author = "Someone Else"
+			again, err := Embed(embedded, changed)
+			if err != nil {
+				t.Fatalf("Embed() error = %v", err)
+			}
+			if got, _, _ := Read(again); got.Author != "Someone Else" {
+				t.Errorf("re-embedded Author = %q", got.Author)
+			}
+			if len(again)-len(embedded) > 16 {
+				t.Errorf("re-embed grew file from %d to %d bytes", len(embedded), len(again))
+			}
+
+			// Stripping removes everything and keeps the image intact
+			stripped, err := Strip(embedded)
+			if err != nil {
+				t.Fatalf("Strip() error = %v", err)
+			}
+			if _, err := tt.decode(stripped); err != nil {
+				t.Fatalf("stripped image no longer decodes: %v", err)
+			}
+			if _, ok, err := Read(stripped); ok || err != nil {
+				t.Errorf("Read(stripped) = %v, %v, want nothing", ok, err)
+			}
+			if !bytes.Equal(stripped, mustStrip(t, original)) {
+				t.Error("Strip(Embed(x)) differs from Strip(x)")
+			}
+		})
+	}
+}
+
+func mustStrip(t *testing.T, data []byte) []byte {
+	t.Helper()
+	out, err := Strip(data)
+	if err != nil {
+		t.Fatalf("Strip() error = %v", err)
+	}
+	return out
+}
+
+func TestReadFallbacks(t *testing.T) {
+	md := Metadata{
+		CapturedAt:  time.Date(2026, 3, 10, 14, 30, 45, 0, time.Local),
+		WindowTitle: "Notepad",
+		AppVersion:  "1.4.0",
+		Author:      "Jane",
+	}
+
+	t.Run("png text chunks", func(t *testing.T) {
+		embedded, err := Embed(encodePNG(t), md)
+		if err != nil {
+			t.Fatalf("Embed() error = %v", err)
+		}
+		chunks, _ := splitPNG(embedded)
+		var kept []pngChunk
+		for _, c := range chunks {
+			if keyword, _, _ := parsePNGText(c.typ, c.data); keyword != pngKeyXMP {
+				kept = append(kept, c)
+			}
+		}
+		assertRead(t, joinPNG(kept), md)
+	})
+
+	t.Run("jpeg exif", func(t *testing.T) {
+		embedded, err := Embed(encodeJPEG(t), md)
+		if err != nil {
+			t.Fatalf("Embed() error = %v", err)
+		}
+		segments, rest, _ := splitJPEG(embedded)
+		var kept []jpegSegment
+		for _, s := range segments {
+			if !bytes.HasPrefix(s.data, xmpHeader) {
+				kept = append(kept, s)
+			}
+		}
+		assertRead(t, joinJPEG(kept, rest), md)
+	})
+}
+
+func assertRead(t *testing.T, data []byte, want Metadata) {
+	t.Helper()
+	got, ok, err := Read(data)
+	if err != nil || !ok {
+		t.Fatalf("Read() = %v, %v", ok, err)
+	}
+	if !got.CapturedAt.Equal(want.CapturedAt) {
+		t.Errorf("CapturedAt = %v, want %v", got.CapturedAt, want.CapturedAt)
+	}
+	if got.WindowTitle != want.WindowTitle || got.AppVersion != want.AppVersion || got.Author != want.Author {
+		t.Errorf("Read() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	embedded, err := Embed(encodePNG(t), sampleMetadata())
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "shot.png")
+	if err := os.WriteFile(path, embedded, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	got, ok, err := ReadFile(path)
+	if err != nil || !ok || got.ProcessName != "devenv.exe" || got.Monitor != 2 {
+		t.Errorf("ReadFile() = %+v, %v, %v", got, ok, err)
+	}
+
+	// Plain images have no metadata
+	if _, ok, err := Read(encodeJPEG(t)); ok || err != nil {
+		t.Errorf("Read(plain jpeg) = %v, %v", ok, err)
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	data := []byte("GIF89a....")
+	if _, err := Embed(data, sampleMetadata()); err != ErrUnsupportedFormat {
+		t.Errorf("Embed() error = %v, want ErrUnsupportedFormat", err)
+	}
+	if _, err := Strip(data); err != ErrUnsupportedFormat {
+		t.Errorf("Strip() error = %v, want ErrUnsupportedFormat", err)
+	}
+	if _, _, err := Read(data); err != ErrUnsupportedFormat {
+		t.Errorf("Read() error = %v, want ErrUnsupportedFormat", err)
+	}
+}
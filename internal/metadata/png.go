@@ -0,0 +1,242 @@
+package metadata
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// PNG text keywords
+const (
+	pngKeySoftware     = "Software"
+	pngKeyCreationTime = "Creation Time"
+	pngKeyTitle        = "Title"
+	pngKeyAuthor       = "Author"
+	pngKeyXMP          = "XML:com.adobe.xmp"
+)
+
+// pngMetadataChunks are removed by Strip and replaced by Embed
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true,
+	"iTXt": true,
+	"zTXt": true,
+	"eXIf": true,
+	"tIME": true,
+}
+
+// maxPNGTextChunk bounds text chunks read back, to avoid huge allocations
+const maxPNGTextChunk = 1 << 20
+
+func isPNG(data []byte) bool {
+	return bytes.HasPrefix(data, pngSignature)
+}
+
+// pngChunk is a raw chunk: type and data (length and CRC are derived)
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+// splitPNG parses the chunk list of a PNG file
+func splitPNG(data []byte) ([]pngChunk, error) {
+	var chunks []pngChunk
+	pos := len(pngSignature)
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, errors.New("truncated PNG chunk header")
+		}
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		typ := string(data[pos+4 : pos+8])
+		end := pos + 8 + length + 4
+		if length < 0 || end > len(data) {
+			return nil, fmt.Errorf("truncated PNG chunk %q", typ)
+		}
+		chunks = append(chunks, pngChunk{typ: typ, data: data[pos+8 : pos+8+length]})
+		pos = end
+		if typ == "IEND" {
+			break
+		}
+	}
+	if len(chunks) == 0 || chunks[0].typ != "IHDR" {
+		return nil, errors.New("PNG does not start with IHDR")
+	}
+	return chunks, nil
+}
+
+// joinPNG serializes chunks back into a PNG file
+func joinPNG(chunks []pngChunk) []byte {
+	var buf bytes.Buffer
+	buf.Write(pngSignature)
+	for _, c := range chunks {
+		var header [8]byte
+		binary.BigEndian.PutUint32(header[:4], uint32(len(c.data)))
+		copy(header[4:], c.typ)
+		buf.Write(header[:])
+		buf.Write(c.data)
+
+		crc := crc32.NewIEEE()
+		crc.Write(header[4:])
+		crc.Write(c.data)
+		binary.Write(&buf, binary.BigEndian, crc.Sum32())
+	}
+	return buf.Bytes()
+}
+
+func stripPNG(data []byte) ([]byte, error) {
+	chunks, err := splitPNG(data)
+	if err != nil {
+		return nil, err
+	}
+	kept := chunks[:0]
+	for _, c := range chunks {
+		if !pngMetadataChunks[c.typ] {
+			kept = append(kept, c)
+		}
+	}
+	return joinPNG(kept), nil
+}
+
+func embedPNG(data []byte, md Metadata) ([]byte, error) {
+	chunks, err := splitPNG(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Text chunks go right after IHDR so readers find them before the image data
+	out := []pngChunk{chunks[0]}
+	out = append(out, pngTextChunk(pngKeySoftware, softwareString(md.AppVersion)))
+	if !md.CapturedAt.IsZero() {
+		out = append(out, pngTextChunk(pngKeyCreationTime, md.CapturedAt.Format(time.RFC1123Z)))
+	}
+	if md.WindowTitle != "" {
+		out = append(out, pngITextChunk(pngKeyTitle, md.WindowTitle))
+	}
+	if md.Author != "" {
+		out = append(out, pngITextChunk(pngKeyAuthor, md.Author))
+	}
+	out = append(out, pngITextChunk(pngKeyXMP, string(buildXMP(md))))
+
+	for _, c := range chunks[1:] {
+		if !pngMetadataChunks[c.typ] {
+			out = append(out, c)
+		}
+	}
+	return joinPNG(out), nil
+}
+
+// pngTextChunk builds a tEXt chunk (Latin-1; only used for ASCII values)
+func pngTextChunk(keyword, text string) pngChunk {
+	return pngChunk{typ: "tEXt", data: []byte(keyword + "\x00" + text)}
+}
+
+// pngITextChunk builds an uncompressed iTXt chunk (UTF-8)
+func pngITextChunk(keyword, text string) pngChunk {
+	// keyword, NUL, compression flag, compression method, language tag NUL, translated keyword NUL
+	return pngChunk{typ: "iTXt", data: []byte(keyword + "\x00\x00\x00\x00\x00" + text)}
+}
+
+// readPNG collects text chunks up to the image data and maps them to Metadata
+func readPNG(r *bufio.Reader) (Metadata, error) {
+	if _, err := r.Discard(len(pngSignature)); err != nil {
+		return Metadata{}, err
+	}
+
+	text := make(map[string]string)
+	var header [8]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return Metadata{}, fmt.Errorf("failed to read PNG chunk: %w", err)
+		}
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		typ := string(header[4:])
+		if typ == "IDAT" || typ == "IEND" {
+			break // Metadata we write always precedes the image data
+		}
+
+		if (typ == "tEXt" || typ == "iTXt") && length <= maxPNGTextChunk {
+			chunk := make([]byte, length)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return Metadata{}, fmt.Errorf("failed to read PNG chunk: %w", err)
+			}
+			if keyword, value, ok := parsePNGText(typ, chunk); ok {
+				text[keyword] = value
+			}
+			length = 0
+		}
+		if _, err := r.Discard(int(length) + 4); err != nil { // Data (if not read) + CRC
+			return Metadata{}, fmt.Errorf("truncated PNG chunk %q", typ)
+		}
+	}
+
+	var md Metadata
+	if xmp, ok := text[pngKeyXMP]; ok {
+		if err := parseXMP([]byte(xmp), &md); err != nil {
+			return Metadata{}, fmt.Errorf("invalid XMP: %w", err)
+		}
+	}
+	if version, ok := parseSoftware(text[pngKeySoftware]); ok && md.AppVersion == "" {
+		md.AppVersion = version
+	}
+	if t, err := time.Parse(time.RFC1123Z, text[pngKeyCreationTime]); err == nil && md.CapturedAt.IsZero() {
+		md.CapturedAt = t
+	}
+	setIfEmpty(&md.WindowTitle, text[pngKeyTitle])
+	setIfEmpty(&md.Author, text[pngKeyAuthor])
+	return md, nil
+}
+
+// parsePNGText decodes a tEXt or iTXt chunk into keyword and UTF-8 value
+func parsePNGText(typ string, chunk []byte) (string, string, bool) {
+	keyword, rest, ok := bytes.Cut(chunk, []byte{0})
+	if !ok {
+		return "", "", false
+	}
+	if typ == "tEXt" {
+		return string(keyword), latin1ToUTF8(rest), true
+	}
+
+	// iTXt: compression flag, method, language tag, translated keyword, text
+	if len(rest) < 2 {
+		return "", "", false
+	}
+	compressed := rest[0] == 1
+	_, rest, ok = bytes.Cut(rest[2:], []byte{0})
+	if !ok {
+		return "", "", false
+	}
+	_, text, ok := bytes.Cut(rest, []byte{0})
+	if !ok {
+		return "", "", false
+	}
+	if compressed {
+		zr, err := zlib.NewReader(bytes.NewReader(text))
+		if err != nil {
+			return "", "", false
+		}
+		defer zr.Close()
+		text, err = io.ReadAll(io.LimitReader(zr, maxPNGTextChunk))
+		if err != nil {
+			return "", "", false
+		}
+	}
+	return string(keyword), string(text), true
+}
+
+func latin1ToUTF8(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
@@ -0,0 +1,124 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+// XMP namespaces
+const (
+	nsXMP     = "http://ns.adobe.com/xap/1.0/"
+	nsDC      = "http://purl.org/dc/elements/1.1/"
+	nsWinShot = "https://github.com/mrgoonie/winshot/ns/1.0/"
+)
+
+// buildXMP serializes md as an XMP packet. Standard properties (creator tool,
+// create date, title, creator) are used where they exist; the rest go in the
+// WinShot namespace.
+func buildXMP(md Metadata) []byte {
+	var buf bytes.Buffer
+	attr := func(name, value string) {
+		if value == "" {
+			return
+		}
+		buf.WriteString("\n    " + name + `="`)
+		xml.EscapeText(&buf, []byte(value))
+		buf.WriteString(`"`)
+	}
+
+	buf.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	buf.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/">` + "\n")
+	buf.WriteString(` <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` + "\n")
+	buf.WriteString(`  <rdf:Description rdf:about=""`)
+	buf.WriteString("\n    xmlns:xmp=\"" + nsXMP + `"`)
+	buf.WriteString("\n    xmlns:dc=\"" + nsDC + `"`)
+	buf.WriteString("\n    xmlns:winshot=\"" + nsWinShot + `"`)
+	attr("xmp:CreatorTool", softwareString(md.AppVersion))
+	if !md.CapturedAt.IsZero() {
+		attr("xmp:CreateDate", md.CapturedAt.Format(time.RFC3339))
+	}
+	attr("winshot:CaptureMode", md.CaptureMode)
+	attr("winshot:WindowTitle", md.WindowTitle)
+	attr("winshot:WindowClass", md.WindowClass)
+	attr("winshot:ProcessName", md.ProcessName)
+	if md.Monitor > 0 {
+		attr("winshot:Monitor", strconv.Itoa(md.Monitor))
+	}
+	if md.DPIScale > 0 {
+		attr("winshot:DPIScale", strconv.FormatFloat(md.DPIScale, 'f', -1, 64))
+	}
+	buf.WriteString(">\n")
+
+	if md.WindowTitle != "" {
+		buf.WriteString(`   <dc:title><rdf:Alt><rdf:li xml:lang="x-default">`)
+		xml.EscapeText(&buf, []byte(md.WindowTitle))
+		buf.WriteString("</rdf:li></rdf:Alt></dc:title>\n")
+	}
+	if md.Author != "" {
+		buf.WriteString("   <dc:creator><rdf:Seq><rdf:li>")
+		xml.EscapeText(&buf, []byte(md.Author))
+		buf.WriteString("</rdf:li></rdf:Seq></dc:creator>\n")
+	}
+
+	buf.WriteString("  </rdf:Description>\n </rdf:RDF>\n</x:xmpmeta>\n")
+	buf.WriteString(`<?xpacket end="w"?>`)
+	return buf.Bytes()
+}
+
+// xmpPacket is the subset of an XMP packet that parseXMP understands
+type xmpPacket struct {
+	Descriptions []struct {
+		CreatorTool string   `xml:"http://ns.adobe.com/xap/1.0/ CreatorTool,attr"`
+		CreateDate  string   `xml:"http://ns.adobe.com/xap/1.0/ CreateDate,attr"`
+		CaptureMode string   `xml:"https://github.com/mrgoonie/winshot/ns/1.0/ CaptureMode,attr"`
+		WindowTitle string   `xml:"https://github.com/mrgoonie/winshot/ns/1.0/ WindowTitle,attr"`
+		WindowClass string   `xml:"https://github.com/mrgoonie/winshot/ns/1.0/ WindowClass,attr"`
+		ProcessName string   `xml:"https://github.com/mrgoonie/winshot/ns/1.0/ ProcessName,attr"`
+		Monitor     string   `xml:"https://github.com/mrgoonie/winshot/ns/1.0/ Monitor,attr"`
+		DPIScale    string   `xml:"https://github.com/mrgoonie/winshot/ns/1.0/ DPIScale,attr"`
+		Title       []string `xml:"title>Alt>li"`
+		Creator     []string `xml:"creator>Seq>li"`
+	} `xml:"RDF>Description"`
+}
+
+// parseXMP merges the fields found in an XMP packet into md
+func parseXMP(data []byte, md *Metadata) error {
+	var packet xmpPacket
+	if err := xml.Unmarshal(data, &packet); err != nil {
+		return err
+	}
+
+	for _, d := range packet.Descriptions {
+		if version, ok := parseSoftware(d.CreatorTool); ok {
+			md.AppVersion = version
+		}
+		if t, err := time.Parse(time.RFC3339, d.CreateDate); err == nil {
+			md.CapturedAt = t
+		}
+		setIfEmpty(&md.CaptureMode, d.CaptureMode)
+		setIfEmpty(&md.WindowTitle, d.WindowTitle)
+		setIfEmpty(&md.WindowClass, d.WindowClass)
+		setIfEmpty(&md.ProcessName, d.ProcessName)
+		if n, err := strconv.Atoi(d.Monitor); err == nil && n > 0 {
+			md.Monitor = n
+		}
+		if f, err := strconv.ParseFloat(d.DPIScale, 64); err == nil && f > 0 {
+			md.DPIScale = f
+		}
+		if len(d.Title) > 0 {
+			setIfEmpty(&md.WindowTitle, d.Title[0])
+		}
+		if len(d.Creator) > 0 {
+			setIfEmpty(&md.Author, d.Creator[0])
+		}
+	}
+	return nil
+}
+
+func setIfEmpty(dst *string, value string) {
+	if *dst == "" {
+		*dst = value
+	}
+}
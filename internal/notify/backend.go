@@ -0,0 +1,34 @@
+package notify
+
+import "sync"
+
+// Discard is a backend that shows nothing, for when notifications are off or
+// there is nowhere to show them
+type Discard struct{}
+
+// Show does nothing
+func (Discard) Show(Notification) error {
+	return nil
+}
+
+// Recorder is a backend that keeps the notifications it is asked to show,
+// for tests
+type Recorder struct {
+	mu    sync.Mutex
+	shown []Notification
+}
+
+// Show records n
+func (r *Recorder) Show(n Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shown = append(r.shown, n)
+	return nil
+}
+
+// Shown returns the recorded notifications, oldest first
+func (r *Recorder) Shown() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Notification(nil), r.shown...)
+}
@@ -0,0 +1,177 @@
+// Package notify shows notifications after a capture is saved or uploaded and
+// routes clicks on their actions back to the app. The only Windows backend is
+// the tray icon's balloons, which have no buttons, so it offers the actions in
+// a menu when a balloon is clicked.
+package notify
+
+import (
+	"image"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/image/draw"
+)
+
+// Actions a notification can offer
+const (
+	ActionOpen         = "open"         // Open the saved capture in the editor
+	ActionCopyURL      = "copyUrl"      // Copy the upload link
+	ActionShowInFolder = "showInFolder" // Select the saved capture in File Explorer
+)
+
+// maxShown is how many notifications stay clickable; older ones have left
+// the screen and Action Center by the time more have been shown
+const maxShown = 8
+
+// Action is a button on a notification
+type Action struct {
+	ID    string // One of the Action constants
+	Label string
+}
+
+// Notification is a message about a saved or uploaded capture
+type Notification struct {
+	ID        int // Assigned by Notifier.Show
+	Title     string
+	Message   string
+	Path      string      // Saved file, if any
+	URL       string      // Upload link, if any
+	Thumbnail image.Image // Shown next to the message if the backend can
+	Actions   []Action
+	Failed    bool
+}
+
+// Backend displays notifications. Clicks on an action are reported back with
+// Notifier.Activate.
+type Backend interface {
+	Show(n Notification) error
+}
+
+// Notifier shows notifications through a backend and hands clicks on their
+// actions to a handler
+type Notifier struct {
+	mu       sync.Mutex
+	backend  Backend
+	onAction func(n Notification, action string)
+	shown    []Notification // Newest last
+	nextID   int
+}
+
+// New returns a Notifier showing notifications with backend and calling
+// onAction when one of their actions is clicked
+func New(backend Backend, onAction func(n Notification, action string)) *Notifier {
+	return &Notifier{backend: backend, onAction: onAction}
+}
+
+// Show assigns n an ID and shows it
+func (nt *Notifier) Show(n Notification) (int, error) {
+	nt.mu.Lock()
+	nt.nextID++
+	n.ID = nt.nextID
+	nt.shown = append(nt.shown, n)
+	if len(nt.shown) > maxShown {
+		nt.shown = nt.shown[len(nt.shown)-maxShown:]
+	}
+	nt.mu.Unlock()
+
+	return n.ID, nt.backend.Show(n)
+}
+
+// Activate runs action of the notification with the given ID. An empty action,
+// a click on the notification itself, runs its first action. It reports false
+// if the notification is gone or doesn't offer the action.
+func (nt *Notifier) Activate(id int, action string) bool {
+	nt.mu.Lock()
+	var n Notification
+	found := false
+	for _, shown := range nt.shown {
+		if shown.ID == id {
+			n, found = shown, true
+			break
+		}
+	}
+	nt.mu.Unlock()
+
+	if !found || len(n.Actions) == 0 {
+		return false
+	}
+	if action == "" {
+		action = n.Actions[0].ID
+	}
+	if !n.Offers(action) {
+		return false
+	}
+	if nt.onAction != nil {
+		nt.onAction(n, action)
+	}
+	return true
+}
+
+// Offers reports whether n has the action
+func (n Notification) Offers(action string) bool {
+	for _, a := range n.Actions {
+		if a.ID == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Saved returns the notification for a capture saved to path
+func Saved(path string, thumbnail image.Image) Notification {
+	return Notification{
+		Title:     "Screenshot saved",
+		Message:   filepath.Base(path),
+		Path:      path,
+		Thumbnail: thumbnail,
+		Actions: []Action{
+			{ID: ActionOpen, Label: "Open"},
+			{ID: ActionShowInFolder, Label: "Show in folder"},
+		},
+	}
+}
+
+// Uploaded returns the notification for a capture uploaded to url. Path is
+// the saved file, or empty if the capture was uploaded without saving it.
+func Uploaded(path, url string, thumbnail image.Image) Notification {
+	n := Notification{
+		Title:     "Screenshot uploaded",
+		Message:   url,
+		Path:      path,
+		URL:       url,
+		Thumbnail: thumbnail,
+		Actions:   []Action{{ID: ActionCopyURL, Label: "Copy URL"}},
+	}
+	if path != "" {
+		n.Actions = append(n.Actions,
+			Action{ID: ActionOpen, Label: "Open"},
+			Action{ID: ActionShowInFolder, Label: "Show in folder"},
+		)
+	}
+	return n
+}
+
+// Failed returns the notification for a save or upload that failed
+func Failed(title string, err error) Notification {
+	return Notification{Title: title, Message: err.Error(), Failed: true}
+}
+
+// Thumbnail scales img to fit a size x size square, centered on transparency,
+// for backends that show a fixed-size icon
+func Thumbnail(img image.Image, size int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	b := img.Bounds()
+	if b.Empty() || size <= 0 {
+		return dst
+	}
+
+	w, h := size, size
+	if b.Dx() > b.Dy() {
+		h = max(1, size*b.Dy()/b.Dx())
+	} else {
+		w = max(1, size*b.Dx()/b.Dy())
+	}
+	x, y := (size-w)/2, (size-h)/2
+	draw.ApproxBiLinear.Scale(dst, image.Rect(x, y, x+w, y+h), img, b, draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+)
+
+func TestNotifier_Activate(t *testing.T) {
+	var got []string
+	backend := &Recorder{}
+	nt := New(backend, func(n Notification, action string) {
+		got = append(got, n.Path+" "+action)
+	})
+
+	saved, _ := nt.Show(Saved(`C:\shots\a.png`, nil))
+	uploaded, _ := nt.Show(Uploaded("", "https://example.com/b.png", nil))
+
+	tests := []struct {
+		name   string
+		id     int
+		action string
+		wantOK bool
+	}{
+		{"button", saved, ActionShowInFolder, true},
+		{"click runs the first action", saved, "", true},
+		{"action not offered", uploaded, ActionOpen, false},
+		{"unknown notification", uploaded + 1, ActionCopyURL, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ok := nt.Activate(tt.id, tt.action); ok != tt.wantOK {
+				t.Errorf("Activate(%d, %q) = %v, want %v", tt.id, tt.action, ok, tt.wantOK)
+			}
+		})
+	}
+
+	want := []string{`C:\shots\a.png showInFolder`, `C:\shots\a.png open`}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("handled %q, want %q", got, want)
+	}
+	if shown := backend.Shown(); len(shown) != 2 || shown[0].ID != saved || shown[1].ID != uploaded {
+		t.Errorf("backend showed %+v", shown)
+	}
+}
+
+// Only the most recent notifications can still be clicked
+func TestNotifier_ForgetsOld(t *testing.T) {
+	nt := New(Discard{}, nil)
+	first, _ := nt.Show(Saved("first.png", nil))
+	for i := 0; i < maxShown; i++ {
+		nt.Show(Saved("later.png", nil))
+	}
+	if nt.Activate(first, ActionOpen) {
+		t.Error("oldest notification still clickable")
+	}
+	if !nt.Activate(first+maxShown, ActionOpen) {
+		t.Error("newest notification not clickable")
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	tests := []struct {
+		name        string
+		n           Notification
+		wantActions []string
+	}{
+		{"saved", Saved(`C:\shots\a.png`, nil), []string{ActionOpen, ActionShowInFolder}},
+		{"uploaded from disk", Uploaded(`C:\shots\a.png`, "https://example.com/a.png", nil), []string{ActionCopyURL, ActionOpen, ActionShowInFolder}},
+		{"uploaded from the editor", Uploaded("", "https://example.com/a.png", nil), []string{ActionCopyURL}},
+		{"failed", Failed("Upload failed", errors.New("no network")), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.n.Actions) != len(tt.wantActions) {
+				t.Fatalf("actions = %+v, want %q", tt.n.Actions, tt.wantActions)
+			}
+			for i, a := range tt.n.Actions {
+				if a.ID != tt.wantActions[i] || a.Label == "" {
+					t.Errorf("action %d = %+v, want %q", i, a, tt.wantActions[i])
+				}
+			}
+		})
+	}
+
+	if n := Saved(filepath.Join("shots", "a.png"), nil); n.Message != "a.png" {
+		t.Errorf("saved message = %q, want the file name", n.Message)
+	}
+	if n := Failed("Upload failed", errors.New("no network")); !n.Failed || n.Message != "no network" {
+		t.Errorf("failed = %+v", n)
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+
+	thumb := Thumbnail(img, 32)
+	if thumb.Bounds() != image.Rect(0, 0, 32, 32) {
+		t.Fatalf("bounds = %v", thumb.Bounds())
+	}
+	// 32 x 16, centered vertically
+	if got := thumb.RGBAAt(16, 16); got != (color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Errorf("center = %v, want the image", got)
+	}
+	if got := thumb.RGBAAt(16, 4); got.A != 0 {
+		t.Errorf("above the image = %v, want transparent", got)
+	}
+
+	if empty := Thumbnail(image.NewRGBA(image.Rectangle{}), 32); empty.RGBAAt(16, 16).A != 0 {
+		t.Error("empty image drew something")
+	}
+}
@@ -0,0 +1,63 @@
+// Package ocr provides offline text recognition for screenshots.
+package ocr
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/draw"
+)
+
+// ErrEngineUnavailable is returned when the OCR engine is not installed or cannot be found
+var ErrEngineUnavailable = errors.New("OCR engine not available")
+
+// Word is a single recognized word with its bounding box in image pixels
+type Word struct {
+	Text       string  `json:"text"`
+	X          int     `json:"x"`
+	Y          int     `json:"y"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	Confidence float64 `json:"confidence"` // 0-100
+	Line       int     `json:"line"`       // Sequential line index across the whole image
+}
+
+// Result holds the recognized text and the individual words it was built from
+type Result struct {
+	Text  string `json:"text"`
+	Words []Word `json:"words"`
+}
+
+// OCREngine recognizes text in images
+type OCREngine interface {
+	// Recognize extracts text and word boxes from the given image.
+	Recognize(ctx context.Context, img image.Image) (*Result, error)
+	// IsAvailable returns true if the engine can be used on this machine.
+	IsAvailable() bool
+}
+
+// RecognizeRegion runs OCR on a sub-rectangle of img.
+// Word coordinates in the result are relative to img, not to the region.
+func RecognizeRegion(ctx context.Context, engine OCREngine, img image.Image, region image.Rectangle) (*Result, error) {
+	region = region.Intersect(img.Bounds())
+	if region.Empty() {
+		return nil, errors.New("OCR region is empty")
+	}
+
+	// Copy the region into its own image so the engine only sees the selection
+	cropped := image.NewRGBA(image.Rect(0, 0, region.Dx(), region.Dy()))
+	draw.Draw(cropped, cropped.Bounds(), img, region.Min, draw.Src)
+
+	result, err := engine.Recognize(ctx, cropped)
+	if err != nil {
+		return nil, err
+	}
+
+	offsetX := region.Min.X - img.Bounds().Min.X
+	offsetY := region.Min.Y - img.Bounds().Min.Y
+	for i := range result.Words {
+		result.Words[i].X += offsetX
+		result.Words[i].Y += offsetY
+	}
+	return result, nil
+}
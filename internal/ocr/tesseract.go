@@ -0,0 +1,218 @@
+package ocr
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tesseractTimeout  = 60 * time.Second
+	tesseractLanguage = "eng"
+)
+
+// defaultTesseractPaths lists common install locations checked when tesseract is not on PATH
+var defaultTesseractPaths = []string{
+	`C:\Program Files\Tesseract-OCR\tesseract.exe`,
+	`C:\Program Files (x86)\Tesseract-OCR\tesseract.exe`,
+}
+
+// TesseractEngine implements OCREngine by shelling out to a locally installed Tesseract
+type TesseractEngine struct {
+	path     string // Explicit path to tesseract executable (optional)
+	language string // Tesseract language code(s), e.g. "eng" or "eng+deu"
+}
+
+// NewTesseractEngine creates a new TesseractEngine.
+// An empty path searches PATH and the default install locations; an empty language uses English.
+func NewTesseractEngine(path, language string) *TesseractEngine {
+	if language == "" {
+		language = tesseractLanguage
+	}
+	return &TesseractEngine{path: path, language: language}
+}
+
+// findExecutable resolves the tesseract executable path
+func (t *TesseractEngine) findExecutable() (string, error) {
+	if t.path != "" {
+		if _, err := os.Stat(t.path); err != nil {
+			return "", ErrEngineUnavailable
+		}
+		return t.path, nil
+	}
+
+	if path, err := exec.LookPath("tesseract"); err == nil {
+		return path, nil
+	}
+
+	for _, path := range defaultTesseractPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", ErrEngineUnavailable
+}
+
+// IsAvailable returns true if a tesseract executable can be found
+func (t *TesseractEngine) IsAvailable() bool {
+	_, err := t.findExecutable()
+	return err == nil
+}
+
+// Recognize writes the image to a temp file, runs tesseract with TSV output and parses the words
+func (t *TesseractEngine) Recognize(ctx context.Context, img image.Image) (*Result, error) {
+	exePath, err := t.findExecutable()
+	if err != nil {
+		return nil, err
+	}
+
+	tmpFile, err := os.CreateTemp("", "winshot-ocr-*.png")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if err := png.Encode(tmpFile, img); err != nil {
+		tmpFile.Close()
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tesseractTimeout)
+	defer cancel()
+
+	// "stdout" as output base makes tesseract print the TSV instead of writing a file
+	cmd := exec.CommandContext(ctx, exePath, tmpPath, "stdout", "-l", t.language, "tsv")
+	cmd.Dir = filepath.Dir(exePath) // tessdata is resolved relative to the install dir on Windows
+	hideConsoleWindow(cmd)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("tesseract timed out: %w", ctx.Err())
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("tesseract failed: %s", msg)
+	}
+
+	return parseTSV(stdout.Bytes())
+}
+
+// tsvColumns is the number of columns in tesseract TSV output:
+// level page_num block_num par_num line_num word_num left top width height conf text
+const tsvColumns = 12
+
+// tsvLevelWord is the TSV "level" value for word rows
+const tsvLevelWord = 5
+
+// parseTSV converts tesseract TSV output into a Result.
+// Words on the same line are joined with spaces, lines with newlines,
+// and paragraphs/blocks are separated by a blank line.
+func parseTSV(data []byte) (*Result, error) {
+	result := &Result{Words: []Word{}}
+
+	type lineKey struct{ block, par, line int }
+	var (
+		text      strings.Builder
+		lastKey   lineKey
+		haveLast  bool
+		lineIndex = -1
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	header := true
+	for scanner.Scan() {
+		row := strings.TrimRight(scanner.Text(), "\r")
+		if header {
+			header = false
+			if strings.HasPrefix(row, "level") {
+				continue
+			}
+		}
+		if row == "" {
+			continue
+		}
+
+		cols := strings.Split(row, "\t")
+		if len(cols) < tsvColumns-1 {
+			return nil, errors.New("invalid tesseract TSV output")
+		}
+
+		nums := make([]int, 10)
+		for i := range nums {
+			n, err := strconv.Atoi(cols[i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid tesseract TSV value %q", cols[i])
+			}
+			nums[i] = n
+		}
+		if nums[0] != tsvLevelWord {
+			continue
+		}
+
+		wordText := ""
+		if len(cols) >= tsvColumns {
+			wordText = strings.TrimSpace(cols[11])
+		}
+		if wordText == "" {
+			continue
+		}
+
+		conf, _ := strconv.ParseFloat(cols[10], 64)
+		key := lineKey{block: nums[2], par: nums[3], line: nums[4]}
+
+		if !haveLast || key != lastKey {
+			lineIndex++
+			if haveLast {
+				if key.block != lastKey.block || key.par != lastKey.par {
+					text.WriteString("\n\n")
+				} else {
+					text.WriteString("\n")
+				}
+			}
+		} else {
+			text.WriteString(" ")
+		}
+		text.WriteString(wordText)
+		lastKey = key
+		haveLast = true
+
+		result.Words = append(result.Words, Word{
+			Text:       wordText,
+			X:          nums[6],
+			Y:          nums[7],
+			Width:      nums[8],
+			Height:     nums[9],
+			Confidence: conf,
+			Line:       lineIndex,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tesseract output: %w", err)
+	}
+
+	result.Text = text.String()
+	return result, nil
+}
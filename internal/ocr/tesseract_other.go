@@ -0,0 +1,8 @@
+//go:build !windows
+
+package ocr
+
+import "os/exec"
+
+// hideConsoleWindow is a no-op outside Windows
+func hideConsoleWindow(cmd *exec.Cmd) {}
@@ -0,0 +1,149 @@
+package ocr
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+)
+
+const sampleTSV = "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+	"1\t1\t0\t0\t0\t0\t0\t0\t640\t480\t-1\t\n" +
+	"2\t1\t1\t0\t0\t0\t10\t10\t300\t40\t-1\t\n" +
+	"4\t1\t1\t1\t1\t0\t10\t10\t300\t20\t-1\t\n" +
+	"5\t1\t1\t1\t1\t1\t10\t10\t80\t20\t96.5\tFile\n" +
+	"5\t1\t1\t1\t1\t2\t95\t10\t60\t20\t91.2\tnot\n" +
+	"5\t1\t1\t1\t1\t3\t160\t10\t70\t20\t93.0\tfound\n" +
+	"5\t1\t1\t1\t2\t1\t10\t35\t120\t20\t88.0\tError:\n" +
+	"5\t1\t1\t1\t2\t2\t135\t35\t40\t20\t90.0\t0x2\n" +
+	"5\t1\t2\t1\t1\t1\t10\t100\t50\t20\t95.0\tOK\n" +
+	"5\t1\t2\t1\t1\t2\t70\t100\t50\t20\t-1\t \n"
+
+func TestParseTSV(t *testing.T) {
+	result, err := parseTSV([]byte(sampleTSV))
+	if err != nil {
+		t.Fatalf("parseTSV() error = %v", err)
+	}
+
+	wantText := "File not found\nError: 0x2\n\nOK"
+	if result.Text != wantText {
+		t.Errorf("Text = %q, want %q", result.Text, wantText)
+	}
+
+	if len(result.Words) != 6 {
+		t.Fatalf("len(Words) = %d, want 6", len(result.Words))
+	}
+
+	first := result.Words[0]
+	if first.Text != "File" || first.X != 10 || first.Y != 10 || first.Width != 80 || first.Height != 20 {
+		t.Errorf("Words[0] = %+v, want File at (10,10) 80x20", first)
+	}
+	if first.Confidence != 96.5 {
+		t.Errorf("Words[0].Confidence = %v, want 96.5", first.Confidence)
+	}
+
+	wantLines := []int{0, 0, 0, 1, 1, 2}
+	for i, w := range result.Words {
+		if w.Line != wantLines[i] {
+			t.Errorf("Words[%d].Line = %d, want %d", i, w.Line, wantLines[i])
+		}
+	}
+}
+
+func TestParseTSV_Empty(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "no output", data: ""},
+		{name: "header only", data: "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n"},
+		{name: "CRLF line endings", data: "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\r\n1\t1\t0\t0\t0\t0\t0\t0\t10\t10\t-1\t\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseTSV([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("parseTSV() error = %v", err)
+			}
+			if result.Text != "" || len(result.Words) != 0 {
+				t.Errorf("parseTSV() = %+v, want empty result", result)
+			}
+		})
+	}
+}
+
+func TestParseTSV_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "too few columns", data: "5\t1\t1\n"},
+		{name: "non-numeric column", data: "5\t1\tx\t1\t1\t1\t10\t10\t80\t20\t96\tword\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseTSV([]byte(tt.data)); err == nil {
+				t.Error("parseTSV() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestTesseractEngine_MissingExecutable(t *testing.T) {
+	engine := NewTesseractEngine("/nonexistent/tesseract.exe", "")
+
+	if engine.IsAvailable() {
+		t.Error("IsAvailable() = true for missing executable")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	_, err := engine.Recognize(context.Background(), img)
+	if !errors.Is(err, ErrEngineUnavailable) {
+		t.Errorf("Recognize() error = %v, want ErrEngineUnavailable", err)
+	}
+}
+
+// fakeEngine returns a fixed word at the origin of whatever image it receives
+type fakeEngine struct {
+	gotBounds image.Rectangle
+}
+
+func (f *fakeEngine) Recognize(ctx context.Context, img image.Image) (*Result, error) {
+	f.gotBounds = img.Bounds()
+	return &Result{
+		Text:  "hello",
+		Words: []Word{{Text: "hello", X: 2, Y: 3, Width: 20, Height: 8}},
+	}, nil
+}
+
+func (f *fakeEngine) IsAvailable() bool { return true }
+
+func TestRecognizeRegion(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	engine := &fakeEngine{}
+
+	result, err := RecognizeRegion(context.Background(), engine, img, image.Rect(50, 40, 150, 90))
+	if err != nil {
+		t.Fatalf("RecognizeRegion() error = %v", err)
+	}
+
+	if engine.gotBounds.Dx() != 100 || engine.gotBounds.Dy() != 50 {
+		t.Errorf("engine got %v, want 100x50 crop", engine.gotBounds)
+	}
+
+	word := result.Words[0]
+	if word.X != 52 || word.Y != 43 {
+		t.Errorf("word at (%d,%d), want (52,43) in image coordinates", word.X, word.Y)
+	}
+}
+
+func TestRecognizeRegion_OutsideImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
+
+	_, err := RecognizeRegion(context.Background(), &fakeEngine{}, img, image.Rect(200, 200, 300, 300))
+	if err == nil {
+		t.Error("RecognizeRegion() expected error for region outside image")
+	}
+}
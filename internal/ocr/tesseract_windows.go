@@ -0,0 +1,17 @@
+package ocr
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// createNoWindow prevents a console window from flashing when running tesseract
+const createNoWindow = 0x08000000
+
+// hideConsoleWindow configures cmd so it runs without a visible console window
+func hideConsoleWindow(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow,
+	}
+}
@@ -0,0 +1,312 @@
+package overlay
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"unicode/utf8"
+)
+
+// canvas is a 32-bit BGRA pixel buffer with premultiplied alpha, the layout
+// UpdateLayeredWindow takes. Drawing is clipped to the buffer.
+type canvas struct {
+	pix           []uint32
+	width, height int
+}
+
+// argb packs a color into the buffer's layout. Colors drawn here are opaque
+// unless stated, so premultiplying is a no-op.
+func argb(a, r, g, b uint8) uint32 {
+	return uint32(a)<<24 | uint32(r)<<16 | uint32(g)<<8 | uint32(b)
+}
+
+// Colors shared by the overlay's drawing
+var (
+	colorWhite = argb(255, 255, 255, 255)
+	colorBlack = argb(255, 0, 0, 0)
+	colorPill  = argb(220, 0, 0, 0)
+)
+
+func (c *canvas) set(x, y int, col uint32) {
+	if x >= 0 && x < c.width && y >= 0 && y < c.height {
+		c.pix[y*c.width+x] = col
+	}
+}
+
+func (c *canvas) at(x, y int) uint32 {
+	if x >= 0 && x < c.width && y >= 0 && y < c.height {
+		return c.pix[y*c.width+x]
+	}
+	return 0
+}
+
+// blend mixes col into the pixel at x, y by alpha/255, keeping the pixel's alpha
+func (c *canvas) blend(x, y int, col uint32, alpha uint8) {
+	if x < 0 || x >= c.width || y < 0 || y >= c.height {
+		return
+	}
+	dst := c.pix[y*c.width+x]
+	mix := func(shift uint) uint32 {
+		d := dst >> shift & 0xFF
+		s := col >> shift & 0xFF
+		return (s*uint32(alpha) + d*uint32(255-alpha)) / 255
+	}
+	c.pix[y*c.width+x] = dst&0xFF000000 | mix(16)<<16 | mix(8)<<8 | mix(0)
+}
+
+// fillRect fills r with col
+func (c *canvas) fillRect(r image.Rectangle, col uint32) {
+	r = r.Intersect(image.Rect(0, 0, c.width, c.height))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row := c.pix[y*c.width:]
+		for x := r.Min.X; x < r.Max.X; x++ {
+			row[x] = col
+		}
+	}
+}
+
+// strokeRect draws the inside border of r, thickness pixels wide
+func (c *canvas) strokeRect(r image.Rectangle, col uint32, thickness int) {
+	for t := 0; t < thickness; t++ {
+		c.hline(r.Min.X, r.Max.X, r.Min.Y+t, col)
+		c.hline(r.Min.X, r.Max.X, r.Max.Y-1-t, col)
+		c.vline(r.Min.X+t, r.Min.Y, r.Max.Y, col)
+		c.vline(r.Max.X-1-t, r.Min.Y, r.Max.Y, col)
+	}
+}
+
+// hline draws a horizontal line from x1 up to but excluding x2
+func (c *canvas) hline(x1, x2, y int, col uint32) {
+	c.fillRect(image.Rect(x1, y, x2, y+1), col)
+}
+
+// vline draws a vertical line from y1 up to but excluding y2
+func (c *canvas) vline(x, y1, y2 int, col uint32) {
+	c.fillRect(image.Rect(x, y1, x+1, y2), col)
+}
+
+// textWidth returns the width of s drawn with text
+func textWidth(s string) int {
+	return utf8.RuneCountInString(s) * glyphAdvance
+}
+
+// text draws s in the 5x7 bitmap font with its top-left corner at x, y.
+// Characters without a glyph leave a gap.
+func (c *canvas) text(x, y int, s string, col uint32) {
+	for _, ch := range s {
+		glyph := glyphs[ch]
+		for col5, bits := range glyph {
+			for row := 0; row < 7; row++ {
+				if bits&(1<<row) != 0 {
+					c.set(x+col5, y+row, col)
+				}
+			}
+		}
+		x += glyphAdvance
+	}
+}
+
+// pill draws text on a dark label whose top-left is at x, y and returns the
+// label's bounds
+func (c *canvas) pill(x, y int, s string) image.Rectangle {
+	r := image.Rect(x, y, x+textWidth(s)+12, y+17)
+	c.fillRect(r, colorPill)
+	c.text(x+6, y+5, s, colorWhite)
+	return r
+}
+
+// Magnifier loupe layout
+const (
+	loupeCells  = 15 // Screenshot pixels across; odd so one sits in the middle
+	loupeZoom   = 8  // Size of each magnified pixel
+	loupeSize   = loupeCells * loupeZoom
+	loupeOffset = 24 // Gap between the cursor and the loupe
+	loupeLabelH = 17
+)
+
+// loupeRect places the loupe's magnified area below and right of the cursor,
+// flipping to the other side near the overlay's edges. Room is left for the
+// label below it.
+func loupeRect(cx, cy, width, height int) image.Rectangle {
+	x := cx + loupeOffset
+	if x+loupeSize > width {
+		x = cx - loupeOffset - loupeSize
+	}
+	y := cy + loupeOffset
+	if y+loupeSize+loupeLabelH > height {
+		y = cy - loupeOffset - loupeSize - loupeLabelH
+	}
+	x = clampInt(x, 0, maxInt(width-loupeSize, 0))
+	y = clampInt(y, 0, maxInt(height-loupeSize-loupeLabelH, 0))
+	return image.Rect(x, y, x+loupeSize, y+loupeSize)
+}
+
+// pixelAt returns the opaque color of the screenshot pixel at x, y
+func pixelAt(img *image.RGBA, x, y int) (color.RGBA, bool) {
+	if img == nil || !image.Pt(x, y).In(img.Bounds()) {
+		return color.RGBA{}, false
+	}
+	i := img.PixOffset(x, y)
+	return color.RGBA{R: img.Pix[i], G: img.Pix[i+1], B: img.Pix[i+2], A: 255}, true
+}
+
+// pixelHex returns the color of the screenshot pixel at x, y as "#RRGGBB",
+// or "" outside the screenshot
+func pixelHex(img *image.RGBA, x, y int) string {
+	c, ok := pixelAt(img, x, y)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// drawCrosshair draws guidelines across the whole overlay through the cursor
+func (c *canvas) drawCrosshair(cx, cy int) {
+	for x := 0; x < c.width; x++ {
+		c.blend(x, cy, colorWhite, 110)
+	}
+	for y := 0; y < c.height; y++ {
+		if y != cy {
+			c.blend(cx, y, colorWhite, 110)
+		}
+	}
+}
+
+// drawLoupe draws the magnifier for the screenshot pixel at cx, cy: the pixels
+// around it enlarged on a grid, the middle one outlined, and a label with its
+// color and position. label is the position text, e.g. in physical pixels.
+func (c *canvas) drawLoupe(img *image.RGBA, cx, cy int, label string) {
+	r := loupeRect(cx, cy, c.width, c.height)
+	half := loupeCells / 2
+
+	for j := 0; j < loupeCells; j++ {
+		for i := 0; i < loupeCells; i++ {
+			col := argb(255, 32, 32, 32) // Outside the screenshot
+			sx, sy := cx-half+i, cy-half+j
+			if img != nil && image.Pt(sx, sy).In(img.Bounds()) {
+				p := img.PixOffset(sx, sy)
+				col = argb(255, img.Pix[p], img.Pix[p+1], img.Pix[p+2])
+			}
+			cell := image.Rect(0, 0, loupeZoom, loupeZoom).Add(r.Min.Add(image.Pt(i*loupeZoom, j*loupeZoom)))
+			c.fillRect(cell, col)
+		}
+	}
+
+	// Pixel grid
+	for k := 1; k < loupeCells; k++ {
+		for t := 0; t < loupeSize; t++ {
+			c.blend(r.Min.X+k*loupeZoom, r.Min.Y+t, colorBlack, 50)
+			c.blend(r.Min.X+t, r.Min.Y+k*loupeZoom, colorBlack, 50)
+		}
+	}
+
+	// Outline the middle pixel in a color that stands out from it
+	middle := image.Rect(0, 0, loupeZoom+2, loupeZoom+2).Add(r.Min.Add(image.Pt(half*loupeZoom-1, half*loupeZoom-1)))
+	outline := colorWhite
+	if luminance(c.at(middle.Min.X+1, middle.Min.Y+1)) > 160 {
+		outline = colorBlack
+	}
+	c.strokeRect(middle, outline, 1)
+
+	c.strokeRect(r.Inset(-1), colorWhite, 1)
+
+	text := label
+	if hex := pixelHex(img, cx, cy); hex != "" {
+		text = hex + "  " + label
+	}
+	labelRect := image.Rect(r.Min.X-1, r.Max.Y+1, r.Max.X+1, r.Max.Y+loupeLabelH)
+	c.fillRect(labelRect, colorPill)
+	c.text(r.Min.X+(loupeSize-textWidth(text))/2, r.Max.Y+5, text, colorWhite)
+}
+
+// luminance approximates the perceived brightness of a buffer pixel, 0-255
+func luminance(col uint32) int {
+	r, g, b := int(col>>16&0xFF), int(col>>8&0xFF), int(col&0xFF)
+	return (r*299 + g*587 + b*114) / 1000
+}
+
+// glyphAdvance is the width of a character including spacing
+const glyphAdvance = 6
+
+// glyphs is a 5x7 bitmap font. Each byte is a column, top row in bit 0.
+var glyphs = map[rune][5]uint8{
+	' ':  {0x00, 0x00, 0x00, 0x00, 0x00},
+	'!':  {0x00, 0x00, 0x5F, 0x00, 0x00},
+	'#':  {0x14, 0x7F, 0x14, 0x7F, 0x14},
+	'%':  {0x23, 0x13, 0x08, 0x64, 0x62},
+	'\'': {0x00, 0x05, 0x03, 0x00, 0x00},
+	'(':  {0x00, 0x1C, 0x22, 0x41, 0x00},
+	')':  {0x00, 0x41, 0x22, 0x1C, 0x00},
+	'+':  {0x08, 0x08, 0x3E, 0x08, 0x08},
+	',':  {0x00, 0x50, 0x30, 0x00, 0x00},
+	'-':  {0x08, 0x08, 0x08, 0x08, 0x08},
+	'.':  {0x00, 0x60, 0x60, 0x00, 0x00},
+	'/':  {0x20, 0x10, 0x08, 0x04, 0x02},
+	'0':  {0x3E, 0x51, 0x49, 0x45, 0x3E},
+	'1':  {0x00, 0x42, 0x7F, 0x40, 0x00},
+	'2':  {0x42, 0x61, 0x51, 0x49, 0x46},
+	'3':  {0x21, 0x41, 0x45, 0x4B, 0x31},
+	'4':  {0x18, 0x14, 0x12, 0x7F, 0x10},
+	'5':  {0x27, 0x45, 0x45, 0x45, 0x39},
+	'6':  {0x3C, 0x4A, 0x49, 0x49, 0x30},
+	'7':  {0x01, 0x71, 0x09, 0x05, 0x03},
+	'8':  {0x36, 0x49, 0x49, 0x49, 0x36},
+	'9':  {0x06, 0x49, 0x49, 0x29, 0x1E},
+	':':  {0x00, 0x36, 0x36, 0x00, 0x00},
+	'<':  {0x08, 0x14, 0x22, 0x41, 0x00},
+	'=':  {0x14, 0x14, 0x14, 0x14, 0x14},
+	'>':  {0x00, 0x41, 0x22, 0x14, 0x08},
+	'A':  {0x7E, 0x11, 0x11, 0x11, 0x7E},
+	'B':  {0x7F, 0x49, 0x49, 0x49, 0x36},
+	'C':  {0x3E, 0x41, 0x41, 0x41, 0x22},
+	'D':  {0x7F, 0x41, 0x41, 0x22, 0x1C},
+	'E':  {0x7F, 0x49, 0x49, 0x49, 0x41},
+	'F':  {0x7F, 0x09, 0x09, 0x09, 0x01},
+	'G':  {0x3E, 0x41, 0x49, 0x49, 0x7A},
+	'H':  {0x7F, 0x08, 0x08, 0x08, 0x7F},
+	'I':  {0x00, 0x41, 0x7F, 0x41, 0x00},
+	'J':  {0x20, 0x40, 0x41, 0x3F, 0x01},
+	'K':  {0x7F, 0x08, 0x14, 0x22, 0x41},
+	'L':  {0x7F, 0x40, 0x40, 0x40, 0x40},
+	'M':  {0x7F, 0x02, 0x0C, 0x02, 0x7F},
+	'N':  {0x7F, 0x04, 0x08, 0x10, 0x7F},
+	'O':  {0x3E, 0x41, 0x41, 0x41, 0x3E},
+	'P':  {0x7F, 0x09, 0x09, 0x09, 0x06},
+	'Q':  {0x3E, 0x41, 0x51, 0x21, 0x5E},
+	'R':  {0x7F, 0x09, 0x19, 0x29, 0x46},
+	'S':  {0x46, 0x49, 0x49, 0x49, 0x31},
+	'T':  {0x01, 0x01, 0x7F, 0x01, 0x01},
+	'U':  {0x3F, 0x40, 0x40, 0x40, 0x3F},
+	'V':  {0x1F, 0x20, 0x40, 0x20, 0x1F},
+	'W':  {0x3F, 0x40, 0x38, 0x40, 0x3F},
+	'X':  {0x63, 0x14, 0x08, 0x14, 0x63},
+	'Y':  {0x07, 0x08, 0x70, 0x08, 0x07},
+	'Z':  {0x61, 0x51, 0x49, 0x45, 0x43},
+	'a':  {0x20, 0x54, 0x54, 0x54, 0x78},
+	'b':  {0x7F, 0x48, 0x44, 0x44, 0x38},
+	'c':  {0x38, 0x44, 0x44, 0x44, 0x20},
+	'd':  {0x38, 0x44, 0x44, 0x48, 0x7F},
+	'e':  {0x38, 0x54, 0x54, 0x54, 0x18},
+	'f':  {0x08, 0x7E, 0x09, 0x01, 0x02},
+	'g':  {0x0C, 0x52, 0x52, 0x52, 0x3E},
+	'h':  {0x7F, 0x08, 0x04, 0x04, 0x78},
+	'i':  {0x00, 0x44, 0x7D, 0x40, 0x00},
+	'j':  {0x20, 0x40, 0x44, 0x3D, 0x00},
+	'k':  {0x7F, 0x10, 0x28, 0x44, 0x00},
+	'l':  {0x00, 0x41, 0x7F, 0x40, 0x00},
+	'm':  {0x7C, 0x04, 0x18, 0x04, 0x78},
+	'n':  {0x7C, 0x08, 0x04, 0x04, 0x78},
+	'o':  {0x38, 0x44, 0x44, 0x44, 0x38},
+	'p':  {0x7C, 0x14, 0x14, 0x14, 0x08},
+	'q':  {0x08, 0x14, 0x14, 0x18, 0x7C},
+	'r':  {0x7C, 0x08, 0x04, 0x04, 0x08},
+	's':  {0x48, 0x54, 0x54, 0x54, 0x20},
+	't':  {0x04, 0x3F, 0x44, 0x40, 0x20},
+	'u':  {0x3C, 0x40, 0x40, 0x20, 0x7C},
+	'v':  {0x1C, 0x20, 0x40, 0x20, 0x1C},
+	'w':  {0x3C, 0x40, 0x30, 0x40, 0x3C},
+	'x':  {0x44, 0x28, 0x10, 0x28, 0x44},
+	'y':  {0x0C, 0x50, 0x50, 0x50, 0x3C},
+	'z':  {0x44, 0x64, 0x54, 0x4C, 0x44},
+	'°':  {0x00, 0x06, 0x09, 0x09, 0x06},
+}
@@ -0,0 +1,144 @@
+package overlay
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func newTestCanvas(width, height int) *canvas {
+	return &canvas{pix: make([]uint32, width*height), width: width, height: height}
+}
+
+func TestLoupeRect(t *testing.T) {
+	const w, h = 800, 600
+	tests := []struct {
+		name   string
+		cx, cy int
+		want   image.Point
+	}{
+		{"below right", 100, 100, image.Pt(100+loupeOffset, 100+loupeOffset)},
+		{"flips left near right edge", 750, 100, image.Pt(750-loupeOffset-loupeSize, 100+loupeOffset)},
+		{"flips up near bottom edge", 100, 550, image.Pt(100+loupeOffset, 550-loupeOffset-loupeSize-loupeLabelH)},
+		{"middle", 400, 300, image.Pt(400+loupeOffset, 300+loupeOffset)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := loupeRect(tt.cx, tt.cy, w, h)
+			if r.Min != tt.want {
+				t.Errorf("loupeRect(%d, %d).Min = %v, want %v", tt.cx, tt.cy, r.Min, tt.want)
+			}
+			if r.Dx() != loupeSize || r.Dy() != loupeSize {
+				t.Errorf("size = %dx%d, want %dx%d", r.Dx(), r.Dy(), loupeSize, loupeSize)
+			}
+			if !r.Add(image.Pt(0, loupeLabelH)).In(image.Rect(0, 0, w, h)) {
+				t.Errorf("loupe %v and label leave the overlay", r)
+			}
+		})
+	}
+}
+
+func TestLoupeRect_SmallOverlay(t *testing.T) {
+	// Neither side of the cursor has room, so the loupe is kept on screen
+	r := loupeRect(100, 100, 200, 200)
+	if want := image.Pt(0, 0); r.Min != want {
+		t.Errorf("loupeRect.Min = %v, want %v", r.Min, want)
+	}
+}
+
+func TestPixelHex(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(2, 1, color.RGBA{0x12, 0xAB, 0xEF, 0xFF})
+
+	tests := []struct {
+		x, y int
+		want string
+	}{
+		{2, 1, "#12ABEF"},
+		{0, 0, "#000000"},
+		{4, 0, ""},
+		{-1, 2, ""},
+	}
+	for _, tt := range tests {
+		if got := pixelHex(img, tt.x, tt.y); got != tt.want {
+			t.Errorf("pixelHex(%d, %d) = %q, want %q", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestPixelAt(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(3, 3, color.RGBA{0x10, 0x20, 0x30, 0xFF})
+
+	if c, ok := pixelAt(img, 3, 3); !ok || c != (color.RGBA{0x10, 0x20, 0x30, 0xFF}) {
+		t.Errorf("pixelAt(3, 3) = %v, %v", c, ok)
+	}
+	if _, ok := pixelAt(img, 4, 3); ok {
+		t.Error("pixelAt outside the screenshot succeeded")
+	}
+	if _, ok := pixelAt(nil, 0, 0); ok {
+		t.Error("pixelAt without a screenshot succeeded")
+	}
+}
+
+func TestCanvas_FillRectClips(t *testing.T) {
+	c := newTestCanvas(4, 4)
+	c.fillRect(image.Rect(-2, 2, 10, 10), colorWhite)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			want := uint32(0)
+			if y >= 2 {
+				want = colorWhite
+			}
+			if got := c.at(x, y); got != want {
+				t.Errorf("pixel %d,%d = %08X, want %08X", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestDrawLoupe_MagnifiesCursorPixel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 300, 300))
+	img.Set(50, 60, color.RGBA{0xFF, 0x00, 0x00, 0xFF})
+
+	c := newTestCanvas(300, 300)
+	c.drawLoupe(img, 50, 60, "50, 60")
+
+	// The middle cell, inside its outline, is the cursor pixel
+	r := loupeRect(50, 60, 300, 300)
+	half := loupeCells / 2
+	x, y := r.Min.X+half*loupeZoom+loupeZoom/2, r.Min.Y+half*loupeZoom+loupeZoom/2
+	if got, want := c.at(x, y), argb(255, 0xFF, 0, 0); got != want {
+		t.Errorf("middle cell = %08X, want %08X", got, want)
+	}
+	// Its neighbour shows the black pixel beside it
+	if got, want := c.at(x+loupeZoom, y), argb(255, 0, 0, 0); got != want {
+		t.Errorf("next cell = %08X, want %08X", got, want)
+	}
+}
+
+// Every character the overlay draws has a glyph
+func TestGlyphs_CoverInstructions(t *testing.T) {
+	var sels []Selection
+	for edge := EdgeNone; edge < edgeCount; edge++ {
+		sels = append(sels, Selection{Pending: true, ActiveEdge: edge})
+	}
+	sels = append(sels,
+		Selection{},
+		Selection{IsDragging: true},
+		Selection{IsDragging: true, SpaceHeld: true},
+		Selection{Mode: ModePickColor},
+		Selection{Mode: ModeMeasure},
+		Selection{Mode: ModeMeasure, IsDragging: true},
+	)
+
+	// The loupe and measurement labels too
+	labels := "#0123456789ABCDEF, x" + Measurement{Width: 1, Height: 2, Distance: 3, Angle: -4}.String()
+	for _, sel := range sels {
+		for _, ch := range instructionText(&sel) + labels {
+			if _, ok := glyphs[ch]; !ok {
+				t.Errorf("no glyph for %q", ch)
+			}
+		}
+	}
+}
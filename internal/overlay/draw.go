@@ -1,3 +1,5 @@
+//go:build windows
+
 package overlay
 
 import (
@@ -76,15 +78,27 @@ func NewDrawContext(screenDC uintptr, width, height int) (*DrawContext, error) {
 	}, nil
 }
 
+// canvas returns the drawing primitives for the pixel buffer
+func (dc *DrawContext) canvas() *canvas {
+	return &canvas{
+		pix:    unsafe.Slice((*uint32)(dc.pixels), dc.width*dc.height),
+		width:  dc.width,
+		height: dc.height,
+	}
+}
+
 // DrawOverlay renders the selection overlay
 func (dc *DrawContext) DrawOverlay(screenshot *image.RGBA, sel *Selection, scaleRatio float64) {
 	// 1. Draw screenshot as background
 	dc.drawScreenshot(screenshot)
 
-	// 2. Draw semi-transparent dark overlay
-	dc.fillOverlay(128) // 50% opacity
+	// 2. Draw semi-transparent dark overlay, only when selecting a region so
+	// picking colors and measuring show the screen as it is
+	if sel.Mode == ModeRegion {
+		dc.fillOverlay(128) // 50% opacity
+	}
 
-	if sel.IsDragging {
+	if sel.Visible() && sel.Mode == ModeRegion {
 		// 3. Calculate normalized selection bounds
 		x1, y1 := minInt(sel.StartX, sel.EndX), minInt(sel.StartY, sel.EndY)
 		x2, y2 := maxInt(sel.StartX, sel.EndX), maxInt(sel.StartY, sel.EndY)
@@ -96,19 +110,54 @@ func (dc *DrawContext) DrawOverlay(screenshot *image.RGBA, sel *Selection, scale
 		// 5. Draw blue border (2px)
 		dc.drawSelectionBorder(x1, y1, w, h)
 
-		// 6. Draw corner handles
-		dc.drawCornerHandles(x1, y1, w, h)
+		// 6. Draw resize handles
+		dc.drawHandles(x1, y1, w, h)
 
-		// 7. Draw size indicator
+		// 7. Highlight the edge arrow keys move
+		if sel.Pending {
+			dc.drawActiveEdge(image.Rect(x1, y1, x2, y2), sel.ActiveEdge)
+		}
+
+		// 8. Draw size indicator
 		scaledW := int(float64(w) * scaleRatio)
 		scaledH := int(float64(h) * scaleRatio)
 		dc.drawSizeIndicator(x1, y2+8, scaledW, scaledH)
 	}
 
-	// 8. Draw instructions
+	// 9. Draw crosshair guidelines, or the measured line and gap, and the
+	// magnifier at the cursor
+	c := dc.canvas()
+	if sel.Mode == ModeMeasure {
+		c.drawMeasure(screenshot, sel, scaleRatio)
+	} else {
+		c.drawCrosshair(sel.CursorX, sel.CursorY)
+	}
+	c.drawLoupe(screenshot, sel.CursorX, sel.CursorY, fmt.Sprintf("%d, %d",
+		int(float64(sel.CursorX)*scaleRatio), int(float64(sel.CursorY)*scaleRatio)))
+
+	// 10. Draw instructions
 	dc.drawInstructions(sel)
 }
 
+// drawActiveEdge draws the edge of r that arrow keys move in a thick white line
+func (dc *DrawContext) drawActiveEdge(r image.Rectangle, edge Edge) {
+	const thickness = 4
+	var line image.Rectangle
+	switch edge {
+	case EdgeLeft:
+		line = image.Rect(r.Min.X-thickness/2, r.Min.Y, r.Min.X+thickness/2, r.Max.Y)
+	case EdgeTop:
+		line = image.Rect(r.Min.X, r.Min.Y-thickness/2, r.Max.X, r.Min.Y+thickness/2)
+	case EdgeRight:
+		line = image.Rect(r.Max.X-thickness/2, r.Min.Y, r.Max.X+thickness/2, r.Max.Y)
+	case EdgeBottom:
+		line = image.Rect(r.Min.X, r.Max.Y-thickness/2, r.Max.X, r.Max.Y+thickness/2)
+	default:
+		return
+	}
+	dc.canvas().fillRect(line, colorWhite)
+}
+
 // drawScreenshot copies screenshot to pixel buffer
 func (dc *DrawContext) drawScreenshot(screenshot *image.RGBA) {
 	if screenshot == nil {
@@ -140,6 +189,22 @@ func (dc *DrawContext) drawScreenshot(screenshot *image.RGBA) {
 	}
 }
 
+// drawImage copies img to the pixel buffer keeping its alpha channel.
+// image.RGBA is already premultiplied, as UpdateLayeredWindow expects.
+func (dc *DrawContext) drawImage(img *image.RGBA) {
+	pixelCount := dc.width * dc.height
+	pixels := unsafe.Slice((*uint32)(dc.pixels), pixelCount)
+
+	bounds := img.Bounds()
+	for y := 0; y < minInt(bounds.Dy(), dc.height); y++ {
+		row := img.Pix[y*img.Stride:]
+		for x := 0; x < minInt(bounds.Dx(), dc.width); x++ {
+			r, g, b, a := row[x*4], row[x*4+1], row[x*4+2], row[x*4+3]
+			pixels[y*dc.width+x] = (uint32(a) << 24) | (uint32(r) << 16) | (uint32(g) << 8) | uint32(b)
+		}
+	}
+}
+
 // fillOverlay adds semi-transparent overlay
 func (dc *DrawContext) fillOverlay(alpha uint8) {
 	pixelCount := dc.width * dc.height
@@ -239,8 +304,8 @@ func (dc *DrawContext) drawSelectionBorder(x, y, w, h int) {
 	}
 }
 
-// drawCornerHandles draws 6x6 blue squares at corners
-func (dc *DrawContext) drawCornerHandles(x, y, w, h int) {
+// drawHandles draws 6x6 blue squares at the corners and edge midpoints
+func (dc *DrawContext) drawHandles(x, y, w, h int) {
 	pixelCount := dc.width * dc.height
 	pixels := unsafe.Slice((*uint32)(dc.pixels), pixelCount)
 
@@ -252,6 +317,10 @@ func (dc *DrawContext) drawCornerHandles(x, y, w, h int) {
 		{x + w - handleSize/2, y - handleSize/2},             // Top-right
 		{x - handleSize/2, y + h - handleSize/2},             // Bottom-left
 		{x + w - handleSize/2, y + h - handleSize/2},         // Bottom-right
+		{x + w/2 - handleSize/2, y - handleSize/2},           // Top
+		{x + w/2 - handleSize/2, y + h - handleSize/2},       // Bottom
+		{x - handleSize/2, y + h/2 - handleSize/2},           // Left
+		{x + w - handleSize/2, y + h/2 - handleSize/2},       // Right
 	}
 
 	for _, corner := range corners {
@@ -353,108 +422,9 @@ func (dc *DrawContext) drawText(x, y int, text string, pixels []uint32) {
 
 // drawInstructions draws instruction text at top center
 func (dc *DrawContext) drawInstructions(sel *Selection) {
-	pixelCount := dc.width * dc.height
-	pixels := unsafe.Slice((*uint32)(dc.pixels), pixelCount)
-
-	var text string
-	if sel.IsDragging && sel.SpaceHeld {
-		text = "Hold Space + Drag to reposition"
-	} else {
-		text = "Drag to select. Space to move. ESC cancel"
-	}
-
-	textWidth := len(text) * 7
-	pillWidth := textWidth + 20
-	pillHeight := 24
-	pillX := (dc.width - pillWidth) / 2
-	pillY := 16
-
-	// Black background with slight transparency
-	bgColor := uint32((220 << 24) | (0 << 16) | (0 << 8) | 0)
-
-	// Draw pill background
-	for dy := 0; dy < pillHeight; dy++ {
-		for dx := 0; dx < pillWidth; dx++ {
-			px := pillX + dx
-			py := pillY + dy
-			if px >= 0 && px < dc.width && py >= 0 && py < dc.height {
-				pixels[py*dc.width+px] = bgColor
-			}
-		}
-	}
-
-	// Draw text
-	dc.drawInstructionText(pillX+10, pillY+6, text, pixels)
-}
-
-// drawInstructionText draws instruction text with extended character support
-func (dc *DrawContext) drawInstructionText(x, y int, text string, pixels []uint32) {
-	white := uint32((255 << 24) | (255 << 16) | (255 << 8) | 255)
-
-	// Extended 5x7 bitmap font
-	font := map[rune][]uint8{
-		'0': {0x3E, 0x45, 0x49, 0x51, 0x3E},
-		'1': {0x00, 0x21, 0x7F, 0x01, 0x00},
-		'2': {0x27, 0x49, 0x49, 0x49, 0x31},
-		'3': {0x22, 0x49, 0x49, 0x49, 0x36},
-		'4': {0x0C, 0x14, 0x24, 0x7F, 0x04},
-		'5': {0x72, 0x51, 0x51, 0x51, 0x4E},
-		'6': {0x3E, 0x49, 0x49, 0x49, 0x26},
-		'7': {0x40, 0x40, 0x47, 0x48, 0x70},
-		'8': {0x36, 0x49, 0x49, 0x49, 0x36},
-		'9': {0x32, 0x49, 0x49, 0x49, 0x3E},
-		'A': {0x3F, 0x48, 0x48, 0x48, 0x3F},
-		'B': {0x7F, 0x49, 0x49, 0x49, 0x36},
-		'C': {0x3E, 0x41, 0x41, 0x41, 0x22},
-		'D': {0x7F, 0x41, 0x41, 0x41, 0x3E},
-		'E': {0x7F, 0x49, 0x49, 0x49, 0x41},
-		'S': {0x32, 0x49, 0x49, 0x49, 0x26},
-		'a': {0x02, 0x15, 0x15, 0x15, 0x0F},
-		'c': {0x0E, 0x11, 0x11, 0x11, 0x0A},
-		'd': {0x0E, 0x11, 0x11, 0x11, 0x7F},
-		'e': {0x0E, 0x15, 0x15, 0x15, 0x0C},
-		'g': {0x08, 0x15, 0x15, 0x15, 0x1E},
-		'h': {0x7F, 0x08, 0x08, 0x08, 0x07},
-		'i': {0x00, 0x00, 0x2F, 0x00, 0x00},
-		'l': {0x00, 0x00, 0x7F, 0x00, 0x00},
-		'm': {0x1F, 0x10, 0x0E, 0x10, 0x0F},
-		'n': {0x1F, 0x08, 0x10, 0x10, 0x0F},
-		'o': {0x0E, 0x11, 0x11, 0x11, 0x0E},
-		'p': {0x1F, 0x14, 0x14, 0x14, 0x08},
-		'r': {0x1F, 0x08, 0x10, 0x10, 0x08},
-		's': {0x09, 0x15, 0x15, 0x15, 0x12},
-		't': {0x10, 0x7E, 0x11, 0x01, 0x02},
-		'u': {0x1E, 0x01, 0x01, 0x01, 0x1E},
-		'v': {0x18, 0x06, 0x01, 0x06, 0x18},
-		'x': {0x11, 0x0A, 0x04, 0x0A, 0x11},
-		' ': {0x00, 0x00, 0x00, 0x00, 0x00},
-		'.': {0x00, 0x01, 0x00, 0x00, 0x00},
-		'+': {0x04, 0x04, 0x1F, 0x04, 0x04},
-		'H': {0x7F, 0x08, 0x08, 0x08, 0x7F},
-		'P': {0x7F, 0x48, 0x48, 0x48, 0x30},
-	}
-
-	curX := x
-	for _, ch := range text {
-		glyph, ok := font[ch]
-		if !ok {
-			curX += 6
-			continue
-		}
-
-		for col, bits := range glyph {
-			for row := 0; row < 7; row++ {
-				if bits&(1<<(6-row)) != 0 {
-					px := curX + col
-					py := y + row
-					if px >= 0 && px < dc.width && py >= 0 && py < dc.height {
-						pixels[py*dc.width+px] = white
-					}
-				}
-			}
-		}
-		curX += 6
-	}
+	text := instructionText(sel)
+	c := dc.canvas()
+	c.pill((dc.width-textWidth(text)-12)/2, 16, text)
 }
 
 // Cleanup releases GDI resources
@@ -469,18 +439,3 @@ func (dc *DrawContext) Cleanup() {
 		procDeleteDC.Call(dc.HMemDC)
 	}
 }
-
-// Helper functions
-func minInt(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
-func maxInt(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
@@ -0,0 +1,194 @@
+package overlay
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// edgeTolerance is how far a channel may differ from the pixel under the
+// cursor before findEdges takes it as an edge, so antialiasing and
+// compression noise inside a gap don't end it early
+const edgeTolerance = 12
+
+// colorMeasure draws measured lines and gaps, standing out on light and dark UIs
+var colorMeasure = argb(255, 255, 0, 128)
+
+// Measurement is a line or gap measured in the overlay, in physical pixels
+type Measurement struct {
+	Width, Height int     // Horizontal and vertical extent
+	Distance      float64 // Length of a line; 0 for a gap
+	Angle         float64 // Degrees counterclockwise from pointing right, -180 to 180
+}
+
+// String returns the measurement as "120 x 48 px", followed by the length and
+// angle of a line
+func (m Measurement) String() string {
+	s := fmt.Sprintf("%d x %d px", m.Width, m.Height)
+	if m.Distance > 0 {
+		s += fmt.Sprintf(", %.1f px at %.1f°", m.Distance, m.Angle)
+	}
+	return s
+}
+
+// measureLine measures the line from x1, y1 to x2, y2 in overlay pixels,
+// which scaleRatio converts to physical ones
+func measureLine(x1, y1, x2, y2 int, scaleRatio float64) Measurement {
+	dx := float64(x2-x1) * scaleRatio
+	dy := float64(y2-y1) * scaleRatio
+	angle := math.Atan2(-dy, dx) * 180 / math.Pi // Screen y grows downward
+	if angle == -180 {
+		angle = 180
+	}
+	return Measurement{
+		Width:    round(math.Abs(dx)),
+		Height:   round(math.Abs(dy)),
+		Distance: math.Hypot(dx, dy),
+		Angle:    angle,
+	}
+}
+
+// measureGap measures a span found by findEdges
+func measureGap(r image.Rectangle, scaleRatio float64) Measurement {
+	return Measurement{
+		Width:  round(float64(r.Dx()) * scaleRatio),
+		Height: round(float64(r.Dy()) * scaleRatio),
+	}
+}
+
+// pressLine starts measuring a new line from x, y
+func (s *Selection) pressLine(x, y int) {
+	*s = Selection{
+		StartX:     x,
+		StartY:     y,
+		EndX:       x,
+		EndY:       y,
+		IsDragging: true,
+		CursorX:    x,
+		CursorY:    y,
+		Mode:       s.Mode,
+	}
+}
+
+// Measure returns the line being or last measured or, without one, the gap
+// around the cursor in img. It reports false when the cursor is off img.
+func (s *Selection) Measure(img *image.RGBA, scaleRatio float64) (Measurement, bool) {
+	if s.Visible() {
+		return measureLine(s.StartX, s.StartY, s.EndX, s.EndY, scaleRatio), true
+	}
+	r, ok := findEdges(img, s.CursorX, s.CursorY, edgeTolerance)
+	if !ok {
+		return Measurement{}, false
+	}
+	return measureGap(r, scaleRatio), true
+}
+
+// findEdges scans outward from x, y along its row and column in img for the
+// nearest pixels differing from the one at x, y by more than tolerance in any
+// channel. It returns the span of similar pixels between them, which stops at
+// img's bounds where no edge is found, and false if x, y is off img.
+func findEdges(img *image.RGBA, x, y, tolerance int) (image.Rectangle, bool) {
+	if img == nil || !image.Pt(x, y).In(img.Bounds()) {
+		return image.Rectangle{}, false
+	}
+	b := img.Bounds()
+	i := img.PixOffset(x, y)
+	ref := img.Pix[i : i+3 : i+3]
+	similar := func(px, py int) bool {
+		j := img.PixOffset(px, py)
+		for ch, v := range ref {
+			if absInt(int(img.Pix[j+ch])-int(v)) > tolerance {
+				return false
+			}
+		}
+		return true
+	}
+
+	r := image.Rect(x, y, x+1, y+1)
+	for r.Min.X > b.Min.X && similar(r.Min.X-1, y) {
+		r.Min.X--
+	}
+	for r.Max.X < b.Max.X && similar(r.Max.X, y) {
+		r.Max.X++
+	}
+	for r.Min.Y > b.Min.Y && similar(x, r.Min.Y-1) {
+		r.Min.Y--
+	}
+	for r.Max.Y < b.Max.Y && similar(x, r.Max.Y) {
+		r.Max.Y++
+	}
+	return r, true
+}
+
+// line draws a line from x1, y1 to x2, y2, both ends included
+func (c *canvas) line(x1, y1, x2, y2 int, col uint32) {
+	dx, dy := absInt(x2-x1), -absInt(y2-y1)
+	sx, sy := 1, 1
+	if x2 < x1 {
+		sx = -1
+	}
+	if y2 < y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		c.set(x1, y1, col)
+		if x1 == x2 && y1 == y2 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x1 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y1 += sy
+		}
+	}
+}
+
+// drawMeasureLine draws a measured line with a dot at each end and its label
+// above the middle
+func (c *canvas) drawMeasureLine(x1, y1, x2, y2 int, label string) {
+	c.line(x1, y1, x2, y2, colorMeasure)
+	for _, p := range []image.Point{{x1, y1}, {x2, y2}} {
+		c.fillRect(image.Rect(p.X-2, p.Y-2, p.X+3, p.Y+3), colorMeasure)
+	}
+	c.label((x1+x2)/2-(textWidth(label)+12)/2, minInt(y1, y2)-24, label)
+}
+
+// drawGap draws the span r found around the cursor at cx, cy: a line across
+// it each way with a tick at every end, and its label above right of the cursor
+func (c *canvas) drawGap(r image.Rectangle, cx, cy int, label string) {
+	c.hline(r.Min.X, r.Max.X, cy, colorMeasure)
+	c.vline(cx, r.Min.Y, r.Max.Y, colorMeasure)
+	c.vline(r.Min.X, cy-4, cy+5, colorMeasure)
+	c.vline(r.Max.X-1, cy-4, cy+5, colorMeasure)
+	c.hline(cx-4, cx+5, r.Min.Y, colorMeasure)
+	c.hline(cx-4, cx+5, r.Max.Y-1, colorMeasure)
+	c.label(cx+8, cy-25, label)
+}
+
+// label draws a pill at x, y, moved as needed to lie inside the canvas
+func (c *canvas) label(x, y int, s string) image.Rectangle {
+	w, h := textWidth(s)+12, 17
+	x = clampInt(x, 0, maxInt(c.width-w, 0))
+	y = clampInt(y, 0, maxInt(c.height-h, 0))
+	return c.pill(x, y, s)
+}
+
+// drawMeasure draws the line being or last measured and, unless one is being
+// dragged, the gap around the cursor in img
+func (c *canvas) drawMeasure(img *image.RGBA, sel *Selection, scaleRatio float64) {
+	if sel.Visible() {
+		m := measureLine(sel.StartX, sel.StartY, sel.EndX, sel.EndY, scaleRatio)
+		c.drawMeasureLine(sel.StartX, sel.StartY, sel.EndX, sel.EndY, m.String())
+	}
+	if sel.IsDragging {
+		return
+	}
+	if r, ok := findEdges(img, sel.CursorX, sel.CursorY, edgeTolerance); ok {
+		c.drawGap(r, sel.CursorX, sel.CursorY, measureGap(r, scaleRatio).String())
+	}
+}
@@ -0,0 +1,192 @@
+package overlay
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+)
+
+// gapImage returns a white 100x60 image with a black frame from x 20 to 80
+// and y 10 to 50, one pixel wide, around a light gray area
+func gapImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 60))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(20, 10, 80, 50), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(21, 11, 79, 49), image.NewUniform(color.RGBA{0xF0, 0xF0, 0xF0, 0xFF}), image.Point{}, draw.Src)
+	return img
+}
+
+func TestFindEdges(t *testing.T) {
+	img := gapImage()
+	// Within tolerance, like antialiasing, so the gap continues past it
+	img.Set(30, 30, color.RGBA{0xF4, 0xEC, 0xF0, 0xFF})
+
+	tests := []struct {
+		name   string
+		x, y   int
+		want   image.Rectangle
+		wantOK bool
+	}{
+		{"inside the frame", 50, 30, image.Rect(21, 11, 79, 49), true},
+		{"past a similar pixel", 25, 30, image.Rect(21, 11, 79, 49), true},
+		{"between frame and image edge", 10, 30, image.Rect(0, 0, 20, 60), true},
+		{"on the frame", 20, 30, image.Rect(20, 10, 21, 50), true},
+		{"off the image", 100, 30, image.Rectangle{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := findEdges(img, tt.x, tt.y, edgeTolerance)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("findEdges(%d, %d) = %v, %v; want %v, %v", tt.x, tt.y, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if _, ok := findEdges(nil, 0, 0, edgeTolerance); ok {
+		t.Error("findEdges(nil) reported a gap")
+	}
+}
+
+// A screenshot whose bounds don't start at 0, 0 is scanned within its bounds
+func TestFindEdges_Offset(t *testing.T) {
+	img := gapImage().SubImage(image.Rect(10, 5, 90, 55)).(*image.RGBA)
+	if got, _ := findEdges(img, 15, 30, edgeTolerance); got != image.Rect(10, 5, 20, 55) {
+		t.Errorf("findEdges() = %v, want %v", got, image.Rect(10, 5, 20, 55))
+	}
+}
+
+func TestFindEdges_Tolerance(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 1))
+	for x := 0; x < 10; x++ {
+		img.Set(x, 0, color.RGBA{uint8(100 + 5*x), 100, 100, 0xFF})
+	}
+	if got, _ := findEdges(img, 5, 0, 0); got != image.Rect(5, 0, 6, 1) {
+		t.Errorf("tolerance 0 = %v, want only the cursor pixel", got)
+	}
+	if got, _ := findEdges(img, 5, 0, 10); got != image.Rect(3, 0, 8, 1) {
+		t.Errorf("tolerance 10 = %v, want 2 pixels each way", got)
+	}
+}
+
+func TestMeasureLine(t *testing.T) {
+	tests := []struct {
+		name             string
+		x1, y1, x2, y2   int
+		scaleRatio       float64
+		wantW, wantH     int
+		wantDist, wantAn float64
+	}{
+		{"right", 10, 10, 110, 10, 1, 100, 0, 100, 0},
+		{"up", 10, 110, 10, 10, 1, 0, 100, 100, 90},
+		{"left", 110, 10, 10, 10, 1, 100, 0, 100, 180},
+		{"down right", 0, 0, 30, 40, 1, 30, 40, 50, -53.13},
+		{"scaled", 0, 0, 30, 40, 1.5, 45, 60, 75, -53.13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := measureLine(tt.x1, tt.y1, tt.x2, tt.y2, tt.scaleRatio)
+			if m.Width != tt.wantW || m.Height != tt.wantH ||
+				math.Abs(m.Distance-tt.wantDist) > 0.01 || math.Abs(m.Angle-tt.wantAn) > 0.01 {
+				t.Errorf("measureLine() = %+v, want %d x %d, %.2f at %.2f", m, tt.wantW, tt.wantH, tt.wantDist, tt.wantAn)
+			}
+		})
+	}
+}
+
+func TestMeasurement_String(t *testing.T) {
+	if got, want := measureGap(image.Rect(21, 11, 79, 49), 1.25).String(), "73 x 48 px"; got != want {
+		t.Errorf("gap = %q, want %q", got, want)
+	}
+	if got, want := measureLine(0, 0, 30, 40, 1).String(), "30 x 40 px, 50.0 px at -53.1°"; got != want {
+		t.Errorf("line = %q, want %q", got, want)
+	}
+}
+
+func TestSelection_MeasureMode(t *testing.T) {
+	img := gapImage()
+	s := Selection{Mode: ModeMeasure, CursorX: 50, CursorY: 30}
+
+	// Without a line the gap around the cursor is measured
+	if m, ok := s.Measure(img, 2); !ok || m != (Measurement{Width: 116, Height: 76}) {
+		t.Fatalf("Measure() = %+v, %v", m, ok)
+	}
+
+	// A drag draws a line, with no handles or menu to grab, that stays on release
+	s.Press(10, 10)
+	s.Move(40, 50, 100, 60, false)
+	s.Release()
+	if !s.Pending || s.StartX != 10 || s.StartY != 10 || s.EndX != 40 || s.EndY != 50 {
+		t.Fatalf("after drag: %+v", s)
+	}
+	if h := s.HandleAt(40, 50); h != HandleNone {
+		t.Errorf("HandleAt() = %d on a measured line", h)
+	}
+	if m, _ := s.Measure(img, 1); m.Distance != 50 {
+		t.Errorf("Measure().Distance = %v, want 50", m.Distance)
+	}
+
+	// Arrow keys move the cursor, not the line
+	if moved := s.Nudge(1, 0, 100, 60); !moved || s.EndX != 40 {
+		t.Errorf("Nudge() moved %v, line end %d", moved, s.EndX)
+	}
+
+	// A new line keeps its direction, here pointing left
+	s.Press(60, 40)
+	s.Move(20, 40, 100, 60, false)
+	if s.StartX != 60 || s.EndX != 20 {
+		t.Errorf("dragging left: start %d, end %d", s.StartX, s.EndX)
+	}
+
+	// A click without a drag clears the line
+	s.Release()
+	s.Press(5, 5)
+	s.Release()
+	if s.Visible() || s.Mode != ModeMeasure {
+		t.Errorf("after click: %+v", s)
+	}
+}
+
+func TestCanvas_Line(t *testing.T) {
+	tests := []struct {
+		name           string
+		x1, y1, x2, y2 int
+		wantPixels     int
+	}{
+		{"horizontal", 2, 5, 12, 5, 11},
+		{"vertical up", 5, 12, 5, 2, 11},
+		{"diagonal", 0, 0, 9, 9, 10},
+		{"steep", 3, 0, 6, 10, 11},
+		{"point", 4, 4, 4, 4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCanvas(20, 20)
+			c.line(tt.x1, tt.y1, tt.x2, tt.y2, colorWhite)
+			if c.at(tt.x1, tt.y1) != colorWhite || c.at(tt.x2, tt.y2) != colorWhite {
+				t.Error("line doesn't include both ends")
+			}
+			n := 0
+			for _, p := range c.pix {
+				if p == colorWhite {
+					n++
+				}
+			}
+			if n != tt.wantPixels {
+				t.Errorf("drew %d pixels, want %d", n, tt.wantPixels)
+			}
+		})
+	}
+}
+
+func TestCanvas_LabelStaysInside(t *testing.T) {
+	c := newTestCanvas(200, 100)
+	r := c.label(190, -10, "30 x 40 px")
+	if !r.In(image.Rect(0, 0, 200, 100)) {
+		t.Errorf("label() = %v, outside the canvas", r)
+	}
+	if got, want := textWidth("1.0°"), 4*glyphAdvance; got != want {
+		t.Errorf("textWidth() = %d, want %d", got, want)
+	}
+}
@@ -1,3 +1,5 @@
+//go:build windows
+
 package overlay
 
 import (
@@ -35,6 +37,11 @@ var (
 	procReleaseCapture       = user32.NewProc("ReleaseCapture")
 	procSetForegroundWindow  = user32.NewProc("SetForegroundWindow")
 	procSetFocus             = user32.NewProc("SetFocus")
+	procSetCursorPos         = user32.NewProc("SetCursorPos")
+	procCreatePopupMenu      = user32.NewProc("CreatePopupMenu")
+	procAppendMenuW          = user32.NewProc("AppendMenuW")
+	procTrackPopupMenu       = user32.NewProc("TrackPopupMenu")
+	procDestroyMenu          = user32.NewProc("DestroyMenu")
 )
 
 // Command types for channel communication
@@ -52,6 +59,7 @@ type overlayCmd struct {
 	Bounds     image.Rectangle
 	ScaleRatio float64
 	ResultCh   chan Result
+	Mode       Mode
 }
 
 // Manager manages the native overlay window
@@ -102,6 +110,22 @@ func (m *Manager) Start() error {
 
 // Show displays the overlay with screenshot
 func (m *Manager) Show(screenshot *image.RGBA, bounds image.Rectangle, scaleRatio float64) <-chan Result {
+	return m.show(screenshot, bounds, scaleRatio, ModeRegion)
+}
+
+// ShowPicker displays the overlay with screenshot to pick a color instead of a
+// region. The result is the clicked pixel and its color.
+func (m *Manager) ShowPicker(screenshot *image.RGBA, bounds image.Rectangle, scaleRatio float64) <-chan Result {
+	return m.show(screenshot, bounds, scaleRatio, ModePickColor)
+}
+
+// ShowMeasure displays the overlay with screenshot to measure distances and
+// gaps in it. The result is the measurement confirmed with Enter.
+func (m *Manager) ShowMeasure(screenshot *image.RGBA, bounds image.Rectangle, scaleRatio float64) <-chan Result {
+	return m.show(screenshot, bounds, scaleRatio, ModeMeasure)
+}
+
+func (m *Manager) show(screenshot *image.RGBA, bounds image.Rectangle, scaleRatio float64, mode Mode) <-chan Result {
 	m.mu.Lock()
 	if m.isShowing {
 		m.mu.Unlock()
@@ -120,6 +144,7 @@ func (m *Manager) Show(screenshot *image.RGBA, bounds image.Rectangle, scaleRati
 		Bounds:     bounds,
 		ScaleRatio: scaleRatio,
 		ResultCh:   resultCh,
+		Mode:       mode,
 	}
 	return resultCh
 }
@@ -155,7 +180,7 @@ func (m *Manager) messageLoop(readyCh chan<- error) {
 
 	wc := WNDCLASSEXW{
 		CbSize:        uint32(unsafe.Sizeof(WNDCLASSEXW{})),
-		Style:         0,
+		Style:         CS_DBLCLKS,
 		LpfnWndProc:   overlayWndProc,
 		HInstance:     m.hInstance,
 		HCursor:       loadCursor(IDC_CROSS),
@@ -219,9 +244,15 @@ func (m *Manager) handleShow(cmd overlayCmd) {
 	// Hide window first to avoid flash of old content
 	procShowWindow.Call(m.hwnd, SW_HIDE)
 
-	// Reset selection state
+	// Reset selection state, starting the magnifier where the mouse is
+	var pt POINT
+	procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
 	m.mu.Lock()
-	m.selection = Selection{}
+	m.selection = Selection{
+		CursorX: clampInt(int(pt.X)-cmd.Bounds.Min.X, 0, cmd.Bounds.Dx()),
+		CursorY: clampInt(int(pt.Y)-cmd.Bounds.Min.Y, 0, cmd.Bounds.Dy()),
+		Mode:    cmd.Mode,
+	}
 	m.mu.Unlock()
 
 	m.screenshot = cmd.Screenshot
@@ -344,15 +375,16 @@ func wndProc(hwnd, msg, wParam, lParam uintptr) uintptr {
 		return HTCLIENT // Enable mouse events
 
 	case WM_SETCURSOR:
-		// Set crosshair cursor, or move cursor if space held
+		// Set crosshair cursor, a resize cursor over a handle, or move cursor if space held
 		m.mu.Lock()
 		spaceHeld := m.selection.SpaceHeld
+		handle := m.selection.HandleAt(m.selection.CursorX, m.selection.CursorY)
 		m.mu.Unlock()
 
 		if spaceHeld {
 			procSetCursor.Call(loadCursor(IDC_SIZEALL))
 		} else {
-			procSetCursor.Call(loadCursor(IDC_CROSS))
+			procSetCursor.Call(loadCursor(handleCursor(handle)))
 		}
 		return 1
 
@@ -365,104 +397,150 @@ func wndProc(hwnd, msg, wParam, lParam uintptr) uintptr {
 		y = clampInt(y, 0, m.bounds.Dy())
 
 		m.mu.Lock()
-		m.selection.StartX = x
-		m.selection.StartY = y
-		m.selection.EndX = x
-		m.selection.EndY = y
-		m.selection.IsDragging = true
+		picking := m.selection.Mode == ModePickColor
+		if !picking {
+			m.selection.Press(x, y)
+		}
 		m.mu.Unlock()
 
+		if picking {
+			m.pick(x, y)
+			break
+		}
+
 		// Capture mouse
 		procSetCapture.Call(m.hwnd)
 
 		m.redraw()
 
 	case WM_MOUSEMOVE:
-		m.mu.Lock()
-		isDragging := m.selection.IsDragging
-		m.mu.Unlock()
-
-		if isDragging {
-			x := int(int16(lParam & 0xFFFF))
-			y := int(int16((lParam >> 16) & 0xFFFF))
-
-			// Clamp to bounds (use Dx() not Dx()-1 to allow edge pixels)
-			x = clampInt(x, 0, m.bounds.Dx())
-			y = clampInt(y, 0, m.bounds.Dy())
+		x := int(int16(lParam & 0xFFFF))
+		y := int(int16((lParam >> 16) & 0xFFFF))
 
-			// Check if Space is held for repositioning
-			spaceState, _, _ := procGetAsyncKeyState.Call(VK_SPACE)
-			spaceHeld := spaceState&0x8000 != 0
+		// Clamp to bounds (use Dx() not Dx()-1 to allow edge pixels)
+		x = clampInt(x, 0, m.bounds.Dx())
+		y = clampInt(y, 0, m.bounds.Dy())
 
-			m.mu.Lock()
-			if spaceHeld {
-				if !m.selection.SpaceHeld {
-					m.selection.SpaceHeld = true
-				}
-				dx := x - m.selection.EndX
-				dy := y - m.selection.EndY
-				m.selection.StartX += dx
-				m.selection.StartY += dy
-			} else if m.selection.SpaceHeld {
-				m.selection.SpaceHeld = false
-			}
+		// Check if Space is held for repositioning
+		spaceState, _, _ := procGetAsyncKeyState.Call(VK_SPACE)
+		spaceHeld := spaceState&0x8000 != 0
 
-			m.selection.EndX = x
-			m.selection.EndY = y
-			m.mu.Unlock()
-			m.redraw()
-		}
+		m.mu.Lock()
+		m.selection.Move(x, y, m.bounds.Dx(), m.bounds.Dy(), spaceHeld)
+		m.mu.Unlock()
+		m.redraw()
 
 	case WM_LBUTTONUP:
 		// Release mouse capture
 		procReleaseCapture.Call()
 
+		// The selection stays editable until Enter or a double-click
 		m.mu.Lock()
-		wasDragging := m.selection.IsDragging
-		if wasDragging {
-			m.selection.IsDragging = false
-			m.selection.SpaceHeld = false
+		m.selection.Release()
+		m.mu.Unlock()
+		m.redraw()
+
+	case WM_LBUTTONDBLCLK:
+		x := clampInt(int(int16(lParam&0xFFFF)), 0, m.bounds.Dx())
+		y := clampInt(int(int16((lParam>>16)&0xFFFF)), 0, m.bounds.Dy())
+
+		m.mu.Lock()
+		if m.selection.Mode == ModePickColor {
+			m.mu.Unlock()
+			break
+		}
+		rect, confirm := m.selection.DoubleClick(x, y)
+		if !confirm {
+			// Outside the selection it's just another press
+			m.selection.Press(x, y)
 		}
-		startX, startY := m.selection.StartX, m.selection.StartY
-		endX, endY := m.selection.EndX, m.selection.EndY
-		resultCh := m.resultCh
 		m.mu.Unlock()
 
-		if wasDragging {
-			// Calculate final selection
-			x1 := minInt(startX, endX)
-			y1 := minInt(startY, endY)
-			x2 := maxInt(startX, endX)
-			y2 := maxInt(startY, endY)
-
-			w, h := x2-x1, y2-y1
-			if w > 10 && h > 10 && resultCh != nil {
-				// Non-blocking send to avoid UI freeze
-				select {
-				case resultCh <- Result{X: x1, Y: y1, Width: w, Height: h}:
-				default:
-				}
-				m.handleHide()
-			}
+		if confirm {
+			m.confirm(rect)
+		} else {
+			procSetCapture.Call(m.hwnd)
+			m.redraw()
+		}
+
+	case WM_RBUTTONUP:
+		m.mu.Lock()
+		hasMenu := !m.selection.IsDragging && m.selection.Mode == ModeRegion
+		m.mu.Unlock()
+		if hasMenu {
+			m.showMenu()
 		}
 
 	case WM_KEYDOWN:
-		if wParam == VK_ESCAPE {
+		switch wParam {
+		case VK_ESCAPE:
+			m.cancel()
+
+		case VK_SPACE:
 			m.mu.Lock()
-			resultCh := m.resultCh
+			m.selection.SpaceHeld = true
 			m.mu.Unlock()
-			if resultCh != nil {
-				select {
-				case resultCh <- Result{Cancelled: true}:
-				default:
+			procSetCursor.Call(loadCursor(IDC_SIZEALL))
+
+		case VK_LEFT, VK_UP, VK_RIGHT, VK_DOWN:
+			// Nudge by a pixel, or 10 with Shift
+			step := 1
+			if shift, _, _ := procGetKeyState.Call(VK_SHIFT); shift&0x8000 != 0 {
+				step = 10
+			}
+			dx, dy := 0, 0
+			switch wParam {
+			case VK_LEFT:
+				dx = -step
+			case VK_RIGHT:
+				dx = step
+			case VK_UP:
+				dy = -step
+			case VK_DOWN:
+				dy = step
+			}
+
+			m.mu.Lock()
+			moved := m.selection.Nudge(dx, dy, m.bounds.Dx(), m.bounds.Dy())
+			cx, cy := m.selection.CursorX, m.selection.CursorY
+			m.mu.Unlock()
+
+			// Keep the mouse pointer on the cursor so the next mouse move doesn't jump
+			if moved {
+				procSetCursorPos.Call(uintptr(m.bounds.Min.X+cx), uintptr(m.bounds.Min.Y+cy))
+			}
+			m.redraw()
+
+		case VK_RETURN:
+			m.mu.Lock()
+			mode := m.selection.Mode
+			x, y := m.selection.CursorX, m.selection.CursorY
+			m.mu.Unlock()
+
+			switch mode {
+			case ModePickColor:
+				m.pick(x, y)
+			case ModeMeasure:
+				m.measure()
+			default:
+				m.mu.Lock()
+				rect, confirm := m.selection.Enter()
+				m.mu.Unlock()
+
+				if confirm {
+					m.confirm(rect)
+				} else {
+					m.redraw()
 				}
 			}
-			m.handleHide()
-		} else if wParam == VK_SPACE {
+
+		case VK_TAB:
+			shift, _, _ := procGetKeyState.Call(VK_SHIFT)
+
 			m.mu.Lock()
-			m.selection.SpaceHeld = true
+			m.selection.CycleEdge(shift&0x8000 != 0)
 			m.mu.Unlock()
-			procSetCursor.Call(loadCursor(IDC_SIZEALL))
+			m.redraw()
 		}
 
 	case WM_KEYUP:
@@ -480,6 +558,148 @@ func wndProc(hwnd, msg, wParam, lParam uintptr) uintptr {
 	return defWindowProc(hwnd, msg, wParam, lParam)
 }
 
+// handleCursor returns the cursor for dragging a handle
+func handleCursor(h Handle) uintptr {
+	switch h {
+	case HandleLeft | HandleTop, HandleRight | HandleBottom:
+		return IDC_SIZENWSE
+	case HandleRight | HandleTop, HandleLeft | HandleBottom:
+		return IDC_SIZENESW
+	case HandleLeft, HandleRight:
+		return IDC_SIZEWE
+	case HandleTop, HandleBottom:
+		return IDC_SIZENS
+	case HandleMove:
+		return IDC_SIZEALL
+	default:
+		return IDC_CROSS
+	}
+}
+
+// showMenu shows the right-click menu of aspect ratios and size presets at the
+// mouse pointer and carries out the chosen command
+func (m *Manager) showMenu() {
+	hMenu, _, _ := procCreatePopupMenu.Call()
+	if hMenu == 0 {
+		return
+	}
+	defer procDestroyMenu.Call(hMenu)
+
+	m.mu.Lock()
+	items := contextMenu(&m.selection, m.scaleRatio)
+	m.mu.Unlock()
+
+	for _, item := range items {
+		flags := uintptr(MF_STRING)
+		switch {
+		case item.id == 0:
+			flags = MF_SEPARATOR
+		case !item.enabled:
+			flags |= MF_GRAYED
+		case item.checked:
+			flags |= MF_CHECKED
+		}
+		text, _ := syscall.UTF16PtrFromString(item.label)
+		procAppendMenuW.Call(hMenu, flags, uintptr(item.id), uintptr(unsafe.Pointer(text)))
+	}
+
+	var pt POINT
+	procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+	cmd, _, _ := procTrackPopupMenu.Call(hMenu, TPM_RETURNCMD|TPM_RIGHTBUTTON, uintptr(pt.X), uintptr(pt.Y), 0, m.hwnd, 0)
+
+	switch int(cmd) {
+	case 0:
+		// Dismissed
+	case menuCapture:
+		m.mu.Lock()
+		rect, confirm := m.selection.Enter()
+		m.mu.Unlock()
+		if confirm {
+			m.confirm(rect)
+		}
+	case menuCancel:
+		m.cancel()
+	default:
+		m.mu.Lock()
+		m.selection.applyMenu(int(cmd), m.bounds.Dx(), m.bounds.Dy(), m.scaleRatio)
+		m.mu.Unlock()
+		m.redraw()
+	}
+}
+
+// cancel reports a cancelled selection and hides the overlay
+func (m *Manager) cancel() {
+	m.mu.Lock()
+	resultCh := m.resultCh
+	m.mu.Unlock()
+	if resultCh != nil {
+		select {
+		case resultCh <- Result{Cancelled: true}:
+		default:
+		}
+	}
+	m.handleHide()
+}
+
+// pick sends the color of the screenshot pixel at x, y, the one the magnifier
+// shows, and hides the overlay
+func (m *Manager) pick(x, y int) {
+	m.mu.Lock()
+	resultCh := m.resultCh
+	m.mu.Unlock()
+
+	// The cursor may sit on the overlay's right or bottom edge, past the last pixel
+	x = clampInt(x, 0, m.bounds.Dx()-1)
+	y = clampInt(y, 0, m.bounds.Dy()-1)
+	c, ok := pixelAt(m.screenshot, x, y)
+	if !ok {
+		return
+	}
+	if resultCh != nil {
+		select {
+		case resultCh <- Result{X: x, Y: y, Color: c}:
+		default:
+		}
+	}
+	m.handleHide()
+}
+
+// measure sends the measured line, or the gap at the cursor without one, and
+// hides the overlay
+func (m *Manager) measure() {
+	m.mu.Lock()
+	resultCh := m.resultCh
+	measurement, ok := m.selection.Measure(m.screenshot, m.scaleRatio)
+	m.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	if resultCh != nil {
+		select {
+		case resultCh <- Result{Measurement: measurement}:
+		default:
+		}
+	}
+	m.handleHide()
+}
+
+// confirm sends the selected region and hides the overlay
+func (m *Manager) confirm(rect image.Rectangle) {
+	m.mu.Lock()
+	resultCh := m.resultCh
+	m.mu.Unlock()
+
+	if resultCh != nil {
+		// Non-blocking send to avoid UI freeze
+		select {
+		case resultCh <- Result{X: rect.Min.X, Y: rect.Min.Y, Width: rect.Dx(), Height: rect.Dy()}:
+		default:
+		}
+	}
+	m.handleHide()
+}
+
 func defWindowProc(hwnd, msg, wParam, lParam uintptr) uintptr {
 	ret, _, _ := procDefWindowProcW.Call(hwnd, msg, wParam, lParam)
 	return ret
@@ -489,13 +709,3 @@ func loadCursor(id uintptr) uintptr {
 	cursor, _, _ := procLoadCursorW.Call(0, id)
 	return cursor
 }
-
-func clampInt(val, min, max int) int {
-	if val < min {
-		return min
-	}
-	if val > max {
-		return max
-	}
-	return val
-}
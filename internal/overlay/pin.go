@@ -0,0 +1,648 @@
+//go:build windows
+
+package overlay
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/image/draw"
+)
+
+var (
+	procGetCursorPos      = user32.NewProc("GetCursorPos")
+	procMonitorFromPoint  = user32.NewProc("MonitorFromPoint")
+	procGetMonitorInfoW   = user32.NewProc("GetMonitorInfoW")
+	procSetWindowLongPtrW = user32.NewProc("SetWindowLongPtrW")
+	procGetWindowLongPtrW = user32.NewProc("GetWindowLongPtrW")
+	procGetKeyState       = user32.NewProc("GetKeyState")
+)
+
+// Pin window constants
+const (
+	WM_ACTIVATE        = 0x0006
+	WM_MOVE            = 0x0003
+	WM_MOUSEWHEEL      = 0x020A
+	WM_NCLBUTTONDBLCLK = 0x00A3
+	WM_CLOSE           = 0x0010
+	WS_EX_TRANSPARENT  = 0x00000020
+	GWL_EXSTYLE        = ^uintptr(19) // -20
+	HTCAPTION          = 2
+	VK_CONTROL         = 0x11
+	WHEEL_DELTA        = 120
+	SW_SHOWNOACTIVATE  = 4
+	SWP_NOACTIVATE     = 0x0010
+	SWP_NOZORDER       = 0x0004
+	MONITOR_NEAREST    = 2
+)
+
+// Pin limits
+const (
+	MaxPins        = 20
+	MinPinZoom     = 0.1
+	MaxPinZoom     = 8.0
+	MinPinOpacity  = 0.1
+	pinZoomStep    = 1.1  // Zoom factor per wheel notch
+	pinOpacityStep = 0.05 // Opacity change per Ctrl+wheel notch
+	maxPinPixels   = 64 * 1024 * 1024
+	pinFitFraction = 0.9 // New pins are zoomed out to fit this much of the work area
+)
+
+// MONITORINFO for GetMonitorInfoW
+type MONITORINFO struct {
+	CbSize    uint32
+	RcMonitor RECT
+	RcWork    RECT
+	DwFlags   uint32
+}
+
+// PinInfo describes a pinned screenshot
+type PinInfo struct {
+	ID           int     `json:"id"`
+	X            int     `json:"x"`
+	Y            int     `json:"y"`
+	Width        int     `json:"width"`  // Current on-screen size (after zoom)
+	Height       int     `json:"height"` // Current on-screen size (after zoom)
+	Zoom         float64 `json:"zoom"`
+	Opacity      float64 `json:"opacity"`
+	ClickThrough bool    `json:"clickThrough"`
+}
+
+// pin is one floating always-on-top window. Its fields are only touched on the
+// PinManager's window thread, except info, which is guarded by PinManager.mu.
+type pin struct {
+	id      int
+	hwnd    uintptr
+	source  *image.RGBA
+	scaled  *image.RGBA // source at the current zoom, cached between redraws
+	drawCtx *DrawContext
+	active  bool
+	info    PinInfo
+}
+
+type pinCmdType int
+
+const (
+	pinCmdCreate pinCmdType = iota
+	pinCmdClose
+	pinCmdCloseAll
+	pinCmdZoom
+	pinCmdOpacity
+	pinCmdClickThrough
+	pinCmdStop
+)
+
+type pinCmd struct {
+	Type    pinCmdType
+	ID      int
+	Image   *image.RGBA
+	X, Y    int
+	AtMouse bool
+	Value   float64
+	Flag    bool
+	Reply   chan pinReply
+}
+
+type pinReply struct {
+	ID  int
+	Err error
+}
+
+// ErrPinNotFound is returned for an unknown pin ID
+var ErrPinNotFound = errors.New("pin not found")
+
+// errPinsStopped is returned for commands the stopped message loop never ran
+var errPinsStopped = errors.New("pin manager not running")
+
+// PinManager shows screenshots as borderless topmost windows. Pins are dragged
+// to move, zoomed with the mouse wheel, faded with Ctrl+wheel and closed with Esc.
+// All pin windows live on one OS thread that owns their message loop.
+type PinManager struct {
+	hInstance uintptr
+	className *uint16
+	cmdCh     chan pinCmd
+	pins      map[uintptr]*pin // By window handle; window thread only
+	nextID    int
+	onClosed  func(id int)
+
+	mu      sync.Mutex
+	infos   map[int]PinInfo
+	running bool
+	done    chan struct{} // Closed when the message loop exits
+}
+
+// Package-level callback (must survive GC)
+var pinWndProcCallback = syscall.NewCallback(pinWndProc)
+var pinManagerInstance *PinManager
+
+// NewPinManager creates a new pin manager
+func NewPinManager() *PinManager {
+	return &PinManager{
+		cmdCh: make(chan pinCmd, 10),
+		pins:  make(map[uintptr]*pin),
+		infos: make(map[int]PinInfo),
+	}
+}
+
+// SetOnClosed sets a callback for pins closed by the user (Esc)
+func (pm *PinManager) SetOnClosed(cb func(id int)) {
+	pm.onClosed = cb
+}
+
+// Start registers the pin window class and starts the message loop
+func (pm *PinManager) Start() error {
+	pm.mu.Lock()
+	if pm.running {
+		pm.mu.Unlock()
+		return nil
+	}
+	pm.running = true
+	done := make(chan struct{})
+	pm.done = done
+	pm.mu.Unlock()
+
+	pinManagerInstance = pm
+	readyCh := make(chan error, 1)
+	go func() {
+		defer close(done)
+		pm.messageLoop(readyCh)
+	}()
+
+	if err := <-readyCh; err != nil {
+		pm.mu.Lock()
+		pm.running = false
+		pm.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Stop closes all pins and stops the message loop
+func (pm *PinManager) Stop() {
+	pm.mu.Lock()
+	if !pm.running {
+		pm.mu.Unlock()
+		return
+	}
+	pm.running = false
+	done := pm.done
+	pm.mu.Unlock()
+
+	select {
+	case pm.cmdCh <- pinCmd{Type: pinCmdStop}:
+	case <-done:
+	}
+}
+
+// Pin shows img with its top-left corner at (x, y) in virtual screen coordinates
+// and returns the new pin's ID
+func (pm *PinManager) Pin(img *image.RGBA, x, y int) (int, error) {
+	return pm.send(pinCmd{Type: pinCmdCreate, Image: img, X: x, Y: y})
+}
+
+// PinAtCursor shows img centred on the mouse cursor, zoomed out if needed to fit
+// the monitor's work area
+func (pm *PinManager) PinAtCursor(img *image.RGBA) (int, error) {
+	return pm.send(pinCmd{Type: pinCmdCreate, Image: img, AtMouse: true})
+}
+
+// Close closes one pin
+func (pm *PinManager) Close(id int) error {
+	_, err := pm.send(pinCmd{Type: pinCmdClose, ID: id})
+	return err
+}
+
+// CloseAll closes every pin
+func (pm *PinManager) CloseAll() {
+	pm.send(pinCmd{Type: pinCmdCloseAll})
+}
+
+// SetZoom sets a pin's zoom factor (1 = actual size), keeping its centre in place
+func (pm *PinManager) SetZoom(id int, zoom float64) error {
+	_, err := pm.send(pinCmd{Type: pinCmdZoom, ID: id, Value: zoom})
+	return err
+}
+
+// SetOpacity sets a pin's opacity from MinPinOpacity to 1
+func (pm *PinManager) SetOpacity(id int, opacity float64) error {
+	_, err := pm.send(pinCmd{Type: pinCmdOpacity, ID: id, Value: opacity})
+	return err
+}
+
+// SetClickThrough makes a pin ignore the mouse so clicks reach the windows below it
+func (pm *PinManager) SetClickThrough(id int, enabled bool) error {
+	_, err := pm.send(pinCmd{Type: pinCmdClickThrough, ID: id, Flag: enabled})
+	return err
+}
+
+// List returns the open pins in creation order
+func (pm *PinManager) List() []PinInfo {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	result := make([]PinInfo, 0, len(pm.infos))
+	for _, info := range pm.infos {
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// send runs a command on the window thread and waits for its reply
+func (pm *PinManager) send(cmd pinCmd) (int, error) {
+	pm.mu.Lock()
+	running, done := pm.running, pm.done
+	pm.mu.Unlock()
+	if !running {
+		return 0, errPinsStopped
+	}
+
+	// A concurrent Stop may end the loop before it gets to cmd
+	cmd.Reply = make(chan pinReply, 1)
+	select {
+	case pm.cmdCh <- cmd:
+	case <-done:
+		return 0, errPinsStopped
+	}
+	select {
+	case reply := <-cmd.Reply:
+		return reply.ID, reply.Err
+	case <-done:
+		select {
+		case reply := <-cmd.Reply:
+			return reply.ID, reply.Err
+		default:
+			return 0, errPinsStopped
+		}
+	}
+}
+
+func (pm *PinManager) messageLoop(readyCh chan<- error) {
+	// CRITICAL: Windows must be created and pumped on the same OS thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	pm.hInstance, _, _ = procGetModuleHandleW.Call(0)
+	className, _ := syscall.UTF16PtrFromString("WinShotPin")
+	pm.className = className
+
+	wc := WNDCLASSEXW{
+		CbSize:        uint32(unsafe.Sizeof(WNDCLASSEXW{})),
+		LpfnWndProc:   pinWndProcCallback,
+		HInstance:     pm.hInstance,
+		HCursor:       loadCursor(IDC_SIZEALL),
+		LpszClassName: className,
+	}
+	if ret, _, _ := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); ret == 0 {
+		readyCh <- errors.New("failed to register pin window class")
+		return
+	}
+	readyCh <- nil
+
+	var msg MSG
+	for {
+		// Block while there is nothing on screen to pump messages for
+		if len(pm.pins) == 0 {
+			if !pm.handleCmd(<-pm.cmdCh) {
+				return
+			}
+			continue
+		}
+
+		select {
+		case cmd := <-pm.cmdCh:
+			if !pm.handleCmd(cmd) {
+				return
+			}
+		default:
+			ret, _, _ := procPeekMessageW.Call(uintptr(unsafe.Pointer(&msg)), 0, 0, 0, PM_REMOVE)
+			if ret != 0 {
+				procTranslateMessage.Call(uintptr(unsafe.Pointer(&msg)))
+				procDispatchMessageW.Call(uintptr(unsafe.Pointer(&msg)))
+				continue
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+}
+
+// handleCmd runs one command on the window thread; false stops the loop
+func (pm *PinManager) handleCmd(cmd pinCmd) bool {
+	reply := pinReply{ID: cmd.ID}
+	switch cmd.Type {
+	case pinCmdCreate:
+		reply.ID, reply.Err = pm.create(cmd)
+	case pinCmdCloseAll, pinCmdStop:
+		for _, p := range pm.pins {
+			pm.destroy(p)
+		}
+	default:
+		p := pm.byID(cmd.ID)
+		if p == nil {
+			reply.Err = ErrPinNotFound
+			break
+		}
+		switch cmd.Type {
+		case pinCmdClose:
+			pm.destroy(p)
+		case pinCmdZoom:
+			cx := p.info.X + p.info.Width/2
+			cy := p.info.Y + p.info.Height/2
+			reply.Err = pm.zoomAround(p, cmd.Value, cx, cy)
+		case pinCmdOpacity:
+			p.info.Opacity = clampFloat(cmd.Value, MinPinOpacity, 1)
+			reply.Err = pm.render(p)
+		case pinCmdClickThrough:
+			pm.setClickThrough(p, cmd.Flag)
+		}
+	}
+
+	if cmd.Reply != nil {
+		cmd.Reply <- reply
+	}
+	if cmd.Type == pinCmdStop {
+		procUnregisterClassW.Call(uintptr(unsafe.Pointer(pm.className)), pm.hInstance)
+		return false
+	}
+	return true
+}
+
+func (pm *PinManager) byID(id int) *pin {
+	for _, p := range pm.pins {
+		if p.id == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// create opens a pin window for cmd.Image
+func (pm *PinManager) create(cmd pinCmd) (int, error) {
+	if cmd.Image == nil || cmd.Image.Bounds().Empty() {
+		return 0, errors.New("empty image")
+	}
+	if len(pm.pins) >= MaxPins {
+		return 0, fmt.Errorf("too many pins (max %d)", MaxPins)
+	}
+
+	width, height := cmd.Image.Bounds().Dx(), cmd.Image.Bounds().Dy()
+	zoom := 1.0
+	x, y := cmd.X, cmd.Y
+	if cmd.AtMouse {
+		var pt POINT
+		procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+		work := workAreaAt(pt)
+		workW, workH := int(work.Right-work.Left), int(work.Bottom-work.Top)
+
+		zoom = math.Min(1, pinFitFraction*math.Min(float64(workW)/float64(width), float64(workH)/float64(height)))
+		zoom = clampFloat(zoom, MinPinZoom, 1)
+		w, h := scaledSize(width, height, zoom)
+		x = clampInt(int(pt.X)-w/2, int(work.Left), maxInt(int(work.Left), int(work.Right)-w))
+		y = clampInt(int(pt.Y)-h/2, int(work.Top), maxInt(int(work.Top), int(work.Bottom)-h))
+	}
+	zoom = limitZoom(width, height, zoom)
+
+	hwnd, _, _ := procCreateWindowExW.Call(
+		WS_EX_LAYERED|WS_EX_TOPMOST|WS_EX_TOOLWINDOW,
+		uintptr(unsafe.Pointer(pm.className)),
+		0, // No title
+		WS_POPUP,
+		uintptr(x), uintptr(y), 1, 1, // Sized by render
+		0, 0, pm.hInstance, 0,
+	)
+	if hwnd == 0 {
+		return 0, errors.New("failed to create pin window")
+	}
+
+	pm.nextID++
+	p := &pin{
+		id:     pm.nextID,
+		hwnd:   hwnd,
+		source: cmd.Image,
+		info: PinInfo{
+			ID:      pm.nextID,
+			X:       x,
+			Y:       y,
+			Zoom:    zoom,
+			Opacity: 1,
+		},
+	}
+	pm.pins[hwnd] = p
+
+	if err := pm.render(p); err != nil {
+		pm.destroy(p)
+		return 0, err
+	}
+	procShowWindow.Call(hwnd, SW_SHOWNOACTIVATE)
+	return p.id, nil
+}
+
+// destroy closes a pin window and releases its bitmap
+func (pm *PinManager) destroy(p *pin) {
+	delete(pm.pins, p.hwnd)
+	procDestroyWindow.Call(p.hwnd)
+	if p.drawCtx != nil {
+		p.drawCtx.Cleanup()
+		p.drawCtx = nil
+	}
+
+	pm.mu.Lock()
+	delete(pm.infos, p.id)
+	pm.mu.Unlock()
+}
+
+// zoomAround changes a pin's zoom keeping the screen point (cx, cy) fixed
+func (pm *PinManager) zoomAround(p *pin, zoom float64, cx, cy int) error {
+	width, height := p.source.Bounds().Dx(), p.source.Bounds().Dy()
+	zoom = limitZoom(width, height, clampFloat(zoom, MinPinZoom, MaxPinZoom))
+	if zoom == p.info.Zoom {
+		return nil
+	}
+
+	oldW, oldH := maxInt(p.info.Width, 1), maxInt(p.info.Height, 1)
+	newW, newH := scaledSize(width, height, zoom)
+	p.info.X = cx - int(math.Round(float64(cx-p.info.X)*float64(newW)/float64(oldW)))
+	p.info.Y = cy - int(math.Round(float64(cy-p.info.Y)*float64(newH)/float64(oldH)))
+	p.info.Zoom = zoom
+	return pm.render(p)
+}
+
+// setClickThrough toggles WS_EX_TRANSPARENT so mouse input passes through the pin
+func (pm *PinManager) setClickThrough(p *pin, enabled bool) {
+	style, _, _ := procGetWindowLongPtrW.Call(p.hwnd, GWL_EXSTYLE)
+	if enabled {
+		style |= WS_EX_TRANSPARENT
+	} else {
+		style &^= WS_EX_TRANSPARENT
+	}
+	procSetWindowLongPtrW.Call(p.hwnd, GWL_EXSTYLE, style)
+	p.info.ClickThrough = enabled
+	pm.publish(p)
+}
+
+// render draws the pin at its zoom and opacity and updates the layered window
+func (pm *PinManager) render(p *pin) error {
+	width, height := scaledSize(p.source.Bounds().Dx(), p.source.Bounds().Dy(), p.info.Zoom)
+	if p.scaled == nil || p.scaled.Bounds().Dx() != width || p.scaled.Bounds().Dy() != height {
+		p.scaled = scalePinImage(p.source, width, height, p.info.Zoom)
+	}
+
+	if p.drawCtx == nil || p.drawCtx.width != width || p.drawCtx.height != height {
+		if p.drawCtx != nil {
+			p.drawCtx.Cleanup()
+			p.drawCtx = nil
+		}
+		hScreenDC, _, _ := procGetDC.Call(0)
+		drawCtx, err := NewDrawContext(hScreenDC, width, height)
+		procReleaseDC.Call(0, hScreenDC)
+		if err != nil {
+			return err
+		}
+		p.drawCtx = drawCtx
+	}
+
+	p.drawCtx.drawImage(p.scaled)
+	if p.active {
+		// Highlight the pin that receives the keyboard (Esc)
+		p.drawCtx.drawSelectionBorder(0, 0, width, height)
+	}
+
+	ptSrc := POINT{0, 0}
+	ptDst := POINT{int32(p.info.X), int32(p.info.Y)}
+	size := SIZE{int32(width), int32(height)}
+	blend := BLENDFUNCTION{AC_SRC_OVER, 0, byte(math.Round(p.info.Opacity * 255)), AC_SRC_ALPHA}
+	ret, _, _ := procUpdateLayeredWindow.Call(
+		p.hwnd,
+		0,
+		uintptr(unsafe.Pointer(&ptDst)),
+		uintptr(unsafe.Pointer(&size)),
+		p.drawCtx.HMemDC,
+		uintptr(unsafe.Pointer(&ptSrc)),
+		0,
+		uintptr(unsafe.Pointer(&blend)),
+		ULW_ALPHA,
+	)
+	if ret == 0 {
+		return errors.New("failed to update pin window")
+	}
+
+	p.info.Width, p.info.Height = width, height
+	pm.publish(p)
+	return nil
+}
+
+// publish stores a pin's state for List
+func (pm *PinManager) publish(p *pin) {
+	pm.mu.Lock()
+	pm.infos[p.id] = p.info
+	pm.mu.Unlock()
+}
+
+// pinWndProc handles messages for all pin windows (always on the window thread)
+func pinWndProc(hwnd, msg, wParam, lParam uintptr) uintptr {
+	pm := pinManagerInstance
+	if pm == nil {
+		return defWindowProc(hwnd, msg, wParam, lParam)
+	}
+	p := pm.pins[hwnd]
+	if p == nil {
+		return defWindowProc(hwnd, msg, wParam, lParam)
+	}
+
+	switch msg {
+	case WM_NCHITTEST:
+		return HTCAPTION // Drag anywhere to move
+
+	case WM_MOVE:
+		p.info.X = int(int16(lParam & 0xFFFF))
+		p.info.Y = int(int16((lParam >> 16) & 0xFFFF))
+		pm.publish(p)
+		return 0
+
+	case WM_ACTIVATE:
+		p.active = wParam&0xFFFF != 0
+		pm.render(p)
+		return 0
+
+	case WM_MOUSEWHEEL:
+		notches := float64(int16(wParam>>16)) / WHEEL_DELTA
+		if ctrl, _, _ := procGetKeyState.Call(VK_CONTROL); ctrl&0x8000 != 0 {
+			p.info.Opacity = clampFloat(p.info.Opacity+notches*pinOpacityStep, MinPinOpacity, 1)
+			pm.render(p)
+		} else {
+			cx := int(int16(lParam & 0xFFFF))
+			cy := int(int16((lParam >> 16) & 0xFFFF))
+			pm.zoomAround(p, p.info.Zoom*math.Pow(pinZoomStep, notches), cx, cy)
+		}
+		return 0
+
+	case WM_NCLBUTTONDBLCLK:
+		// Double-click restores actual size
+		pm.zoomAround(p, 1, p.info.X+p.info.Width/2, p.info.Y+p.info.Height/2)
+		return 0
+
+	case WM_KEYDOWN:
+		if wParam == VK_ESCAPE {
+			id := p.id
+			pm.destroy(p)
+			if pm.onClosed != nil {
+				go pm.onClosed(id)
+			}
+		}
+		return 0
+
+	case WM_CLOSE:
+		return 0 // Pins close through Esc or the manager only
+	}
+
+	return defWindowProc(hwnd, msg, wParam, lParam)
+}
+
+// workAreaAt returns the work area of the monitor nearest pt
+func workAreaAt(pt POINT) RECT {
+	hMonitor, _, _ := procMonitorFromPoint.Call(uintptr(*(*uint64)(unsafe.Pointer(&pt))), MONITOR_NEAREST)
+	info := MONITORINFO{CbSize: uint32(unsafe.Sizeof(MONITORINFO{}))}
+	if ret, _, _ := procGetMonitorInfoW.Call(hMonitor, uintptr(unsafe.Pointer(&info))); ret == 0 {
+		return RECT{0, 0, 1920, 1080}
+	}
+	return info.RcWork
+}
+
+// scaledSize returns the on-screen size of a width x height image at zoom
+func scaledSize(width, height int, zoom float64) (int, int) {
+	return maxInt(1, int(math.Round(float64(width)*zoom))), maxInt(1, int(math.Round(float64(height)*zoom)))
+}
+
+// limitZoom lowers zoom so the scaled bitmap stays within maxPinPixels
+func limitZoom(width, height int, zoom float64) float64 {
+	if limit := math.Sqrt(float64(maxPinPixels) / float64(width*height)); zoom > limit {
+		return limit
+	}
+	return zoom
+}
+
+// scalePinImage resizes src; enlargements use nearest-neighbour to keep pixels crisp
+func scalePinImage(src *image.RGBA, width, height int, zoom float64) *image.RGBA {
+	if zoom == 1 && src.Bounds().Min == (image.Point{}) {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	var scaler draw.Scaler = draw.ApproxBiLinear
+	if zoom > 1 {
+		scaler = draw.NearestNeighbor
+	}
+	scaler.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+	return dst
+}
+
+func clampFloat(val, min, max float64) float64 {
+	return math.Max(min, math.Min(max, val))
+}
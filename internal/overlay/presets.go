@@ -0,0 +1,110 @@
+package overlay
+
+import "fmt"
+
+// SizePreset is a fixed selection size in physical pixels
+type SizePreset struct {
+	Width, Height int
+}
+
+// SizePresets are the fixed sizes offered in the overlay's right-click menu
+var SizePresets = []SizePreset{
+	{640, 480},
+	{800, 600},
+	{1024, 768},
+	{1280, 720},
+	{1920, 1080},
+}
+
+// AspectPreset is a width:height ratio drags can be locked to
+type AspectPreset struct {
+	Width, Height int
+}
+
+// AspectPresets are the ratios offered in the overlay's right-click menu
+var AspectPresets = []AspectPreset{
+	{1, 1},
+	{4, 3},
+	{3, 2},
+	{16, 9},
+}
+
+func (a AspectPreset) ratio() float64 {
+	return float64(a.Width) / float64(a.Height)
+}
+
+// Right-click menu command IDs
+const (
+	menuCapture = iota + 1
+	menuCancel
+	menuFreeAspect
+	menuLockAspect
+	menuAspectFirst = 100 // + index into AspectPresets
+	menuSizeFirst   = 200 // + index into SizePresets
+)
+
+// menuItem is an entry of the overlay's right-click menu; an ID of 0 is a separator
+type menuItem struct {
+	id      int
+	label   string
+	checked bool
+	enabled bool
+}
+
+// contextMenu returns the right-click menu for the selection. Sizes are shown
+// in physical pixels, which scaleRatio converts overlay pixels to.
+func contextMenu(sel *Selection, scaleRatio float64) []menuItem {
+	r := sel.Rect()
+	hasSel := sel.Pending && !r.Empty()
+
+	items := []menuItem{
+		{id: menuCapture, label: "Capture\tEnter", enabled: hasSel},
+		{id: menuCancel, label: "Cancel\tEsc", enabled: true},
+		{},
+		{id: menuFreeAspect, label: "Free Aspect Ratio", checked: sel.Aspect == 0, enabled: true},
+		{id: menuLockAspect, label: "Lock Current Aspect Ratio", enabled: hasSel},
+	}
+	for i, a := range AspectPresets {
+		items = append(items, menuItem{
+			id:      menuAspectFirst + i,
+			label:   fmt.Sprintf("%d:%d", a.Width, a.Height),
+			checked: sel.Aspect == a.ratio(),
+			enabled: true,
+		})
+	}
+	items = append(items, menuItem{})
+	for i, p := range SizePresets {
+		w, h := logicalSize(p, scaleRatio)
+		items = append(items, menuItem{
+			id:      menuSizeFirst + i,
+			label:   fmt.Sprintf("%d × %d", p.Width, p.Height),
+			checked: hasSel && r.Dx() == w && r.Dy() == h,
+			enabled: true,
+		})
+	}
+	return items
+}
+
+// applyMenu carries out a preset chosen from the right-click menu within a
+// w x h overlay. Capture and Cancel are left to the caller.
+func (s *Selection) applyMenu(id, w, h int, scaleRatio float64) {
+	switch {
+	case id == menuFreeAspect:
+		s.SetAspect(0, w, h)
+	case id == menuLockAspect:
+		s.LockAspect()
+	case id >= menuAspectFirst && id < menuAspectFirst+len(AspectPresets):
+		s.SetAspect(AspectPresets[id-menuAspectFirst].ratio(), w, h)
+	case id >= menuSizeFirst && id < menuSizeFirst+len(SizePresets):
+		sw, sh := logicalSize(SizePresets[id-menuSizeFirst], scaleRatio)
+		s.SetSize(sw, sh, w, h)
+	}
+}
+
+// logicalSize converts a preset to overlay pixels
+func logicalSize(p SizePreset, scaleRatio float64) (int, int) {
+	if scaleRatio <= 0 {
+		scaleRatio = 1
+	}
+	return round(float64(p.Width) / scaleRatio), round(float64(p.Height) / scaleRatio)
+}
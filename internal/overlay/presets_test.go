@@ -0,0 +1,47 @@
+package overlay
+
+import (
+	"image"
+	"testing"
+)
+
+func TestContextMenu_Checked(t *testing.T) {
+	sel := Selection{StartX: 0, StartY: 0, EndX: 1024, EndY: 576, Pending: true, Aspect: 16.0 / 9}
+	checked := map[string]bool{}
+	for _, item := range contextMenu(&sel, 1.25) {
+		if item.checked {
+			checked[item.label] = true
+		}
+	}
+	// 1024 x 576 overlay pixels at 125% are 1280 x 720 physical ones
+	if len(checked) != 2 || !checked["16:9"] || !checked["1280 × 720"] {
+		t.Errorf("checked items = %v, want 16:9 and 1280 × 720", checked)
+	}
+}
+
+func TestContextMenu_CaptureNeedsSelection(t *testing.T) {
+	var sel Selection
+	for _, item := range contextMenu(&sel, 1) {
+		if item.id == menuCapture && item.enabled {
+			t.Error("Capture is enabled without a selection")
+		}
+	}
+}
+
+func TestSelection_ApplyMenu(t *testing.T) {
+	s := Selection{CursorX: 400, CursorY: 300}
+	s.applyMenu(menuSizeFirst+3, 800, 600, 2) // 1280 x 720 at 200%
+	if got, want := s.Rect(), image.Rect(80, 120, 720, 480); got != want {
+		t.Errorf("size preset: Rect() = %v, want %v", got, want)
+	}
+
+	s.applyMenu(menuAspectFirst, 800, 600, 2) // 1:1
+	if s.Aspect != 1 || s.Rect().Dx() != s.Rect().Dy() {
+		t.Errorf("aspect preset: Aspect %v, Rect() %v", s.Aspect, s.Rect())
+	}
+
+	s.applyMenu(menuFreeAspect, 800, 600, 2)
+	if s.Aspect != 0 {
+		t.Errorf("free aspect: Aspect = %v", s.Aspect)
+	}
+}
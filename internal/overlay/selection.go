@@ -0,0 +1,509 @@
+package overlay
+
+import (
+	"image"
+	"math"
+)
+
+// Mode is what the overlay is shown for
+type Mode int
+
+const (
+	ModeRegion    Mode = iota // Select a region to capture
+	ModePickColor             // Pick the color of a pixel
+	ModeMeasure               // Measure distances between points and the gaps between edges
+)
+
+// Edge is the part of a pending selection that arrow keys move
+type Edge int
+
+const (
+	EdgeNone Edge = iota // The whole selection moves
+	EdgeLeft
+	EdgeTop
+	EdgeRight
+	EdgeBottom
+	edgeCount
+)
+
+// Handle is the part of a selection the mouse drags: a set of edges to resize,
+// two of them for a corner, or the inside to move it
+type Handle int
+
+const HandleNone Handle = 0
+
+const (
+	HandleLeft Handle = 1 << iota
+	HandleTop
+	HandleRight
+	HandleBottom
+	HandleMove
+)
+
+const (
+	// minDragSize is how large a new mouse selection must be to keep on
+	// release; anything smaller is taken as a stray click
+	minDragSize = 10
+
+	// handleReach is how far from an edge of a pending selection the mouse
+	// grabs it
+	handleReach = 6
+)
+
+// Rect returns the selection with positive width and height
+func (s *Selection) Rect() image.Rectangle {
+	return image.Rect(s.StartX, s.StartY, s.EndX, s.EndY)
+}
+
+// Visible reports whether there is a selection to draw
+func (s *Selection) Visible() bool {
+	return s.IsDragging || s.Pending
+}
+
+func (s *Selection) setRect(r image.Rectangle) {
+	s.StartX, s.StartY, s.EndX, s.EndY = r.Min.X, r.Min.Y, r.Max.X, r.Max.Y
+}
+
+// HandleAt returns the handle of the pending selection at x, y
+func (s *Selection) HandleAt(x, y int) Handle {
+	r := s.Rect()
+	if !s.Pending || s.Mode != ModeRegion || !image.Pt(x, y).In(r.Inset(-handleReach)) {
+		return HandleNone
+	}
+
+	var h Handle
+	if left, right := absInt(x-r.Min.X), absInt(x-r.Max.X); minInt(left, right) <= handleReach {
+		if left <= right {
+			h |= HandleLeft
+		} else {
+			h |= HandleRight
+		}
+	}
+	if top, bottom := absInt(y-r.Min.Y), absInt(y-r.Max.Y); minInt(top, bottom) <= handleReach {
+		if top <= bottom {
+			h |= HandleTop
+		} else {
+			h |= HandleBottom
+		}
+	}
+	if h == HandleNone {
+		return HandleMove
+	}
+	return h
+}
+
+// Press handles the mouse button going down: on a pending selection it grabs
+// the handle there, anywhere else it starts a new selection
+func (s *Selection) Press(x, y int) {
+	s.CursorX, s.CursorY = x, y
+	if s.Mode == ModeMeasure {
+		s.pressLine(x, y)
+		return
+	}
+	if h := s.HandleAt(x, y); h != HandleNone {
+		s.ActiveEdge = EdgeNone
+		s.startGrab(h, x, y)
+		return
+	}
+
+	*s = Selection{
+		StartX:    x,
+		StartY:    y,
+		EndX:      x,
+		EndY:      y,
+		SpaceHeld: s.SpaceHeld,
+		CursorX:   x,
+		CursorY:   y,
+		Aspect:    s.Aspect,
+		Mode:      s.Mode,
+	}
+	s.startGrab(HandleRight|HandleBottom, x, y)
+}
+
+// startGrab starts dragging handle h from the pointer at x, y
+func (s *Selection) startGrab(h Handle, x, y int) {
+	s.IsDragging = true
+	s.Grab = h
+	s.grabRect = s.Rect()
+	s.grabX, s.grabY = x, y
+}
+
+// Move follows the cursor within a w x h overlay. While dragging, the grabbed
+// handle follows it, or the whole selection does if moveAll (Space is held).
+func (s *Selection) Move(x, y, w, h int, moveAll bool) {
+	prevX, prevY := s.CursorX, s.CursorY
+	s.CursorX, s.CursorY = x, y
+	if !s.IsDragging {
+		return
+	}
+	if s.Mode == ModeMeasure {
+		s.drag(x, y, w, h)
+		return
+	}
+
+	if moveAll && s.Grab != HandleMove {
+		s.SpaceHeld = true
+		d := image.Pt(x-prevX, y-prevY)
+		s.grabRect = s.grabRect.Add(d)
+		s.grabX += d.X
+		s.grabY += d.Y
+	} else if s.SpaceHeld {
+		s.SpaceHeld = false
+	}
+	s.drag(x, y, w, h)
+}
+
+// drag applies the pointer at x, y to the grabbed handle
+func (s *Selection) drag(x, y, w, h int) {
+	if s.Mode == ModeMeasure {
+		s.EndX, s.EndY = x, y
+		return
+	}
+
+	d := image.Pt(x-s.grabX, y-s.grabY)
+	r := s.grabRect
+	if s.Grab == HandleMove {
+		s.setRect(moveWithin(r, d, w, h))
+		return
+	}
+
+	if s.Grab&HandleLeft != 0 {
+		r.Min.X += d.X
+	}
+	if s.Grab&HandleRight != 0 {
+		r.Max.X += d.X
+	}
+	if s.Grab&HandleTop != 0 {
+		r.Min.Y += d.Y
+	}
+	if s.Grab&HandleBottom != 0 {
+		r.Max.Y += d.Y
+	}
+	r = clampRect(r.Canon(), w, h)
+	if s.Aspect > 0 {
+		r = fitAspect(r, s.grabRect, s.Grab, s.Aspect, w, h)
+	}
+	s.setRect(r)
+}
+
+// Release ends a mouse drag. The selection stays pending for its handles, Enter
+// or a double-click, unless it was a stray click.
+func (s *Selection) Release() {
+	if !s.IsDragging {
+		return
+	}
+	creating := !s.Pending
+	s.IsDragging = false
+	s.SpaceHeld = false
+	s.Grab = HandleNone
+	if s.Mode == ModeMeasure {
+		s.Pending = s.StartX != s.EndX || s.StartY != s.EndY
+		return
+	}
+
+	r := s.Rect()
+	if creating && (r.Dx() <= minDragSize || r.Dy() <= minDragSize) {
+		*s = Selection{CursorX: s.CursorX, CursorY: s.CursorY, Aspect: s.Aspect, Mode: s.Mode}
+		return
+	}
+	s.Pending = !r.Empty()
+}
+
+// DoubleClick returns the pending selection to confirm if x, y is inside it
+func (s *Selection) DoubleClick(x, y int) (image.Rectangle, bool) {
+	r := s.Rect()
+	if !s.Pending || s.IsDragging || s.Mode != ModeRegion || !image.Pt(x, y).In(r) {
+		return image.Rectangle{}, false
+	}
+	return r, true
+}
+
+// Enter anchors a selection at the cursor if there is none, to be stretched
+// with the mouse or arrow keys. Otherwise it returns the selection to confirm,
+// unless it is empty.
+func (s *Selection) Enter() (image.Rectangle, bool) {
+	if !s.Visible() {
+		s.StartX, s.StartY = s.CursorX, s.CursorY
+		s.EndX, s.EndY = s.CursorX, s.CursorY
+		s.startGrab(HandleRight|HandleBottom, s.CursorX, s.CursorY)
+		return image.Rectangle{}, false
+	}
+	r := s.Rect()
+	return r, !r.Empty()
+}
+
+// Nudge handles an arrow key within a w x h overlay. While dragging, the
+// cursor moves and drags the handle with it; a pending selection moves, or
+// only its active edge does; otherwise, as for a measured line, just the
+// cursor moves. Reports whether the cursor moved, so the mouse pointer can
+// follow.
+func (s *Selection) Nudge(dx, dy, w, h int) bool {
+	switch {
+	case s.IsDragging:
+		s.CursorX = clampInt(s.CursorX+dx, 0, w)
+		s.CursorY = clampInt(s.CursorY+dy, 0, h)
+		s.drag(s.CursorX, s.CursorY, w, h)
+	case s.Pending && s.Mode == ModeRegion:
+		s.nudgePending(dx, dy, w, h)
+		return false
+	default:
+		s.CursorX = clampInt(s.CursorX+dx, 0, w)
+		s.CursorY = clampInt(s.CursorY+dy, 0, h)
+	}
+	return true
+}
+
+// nudgePending moves a pending selection or its active edge, keeping it inside
+// the overlay and at least a pixel wide and high
+func (s *Selection) nudgePending(dx, dy, w, h int) {
+	r := s.Rect()
+	switch s.ActiveEdge {
+	case EdgeNone:
+		r = moveWithin(r, image.Pt(dx, dy), w, h)
+	case EdgeLeft:
+		r.Min.X = clampInt(r.Min.X+dx, 0, r.Max.X-1)
+	case EdgeTop:
+		r.Min.Y = clampInt(r.Min.Y+dy, 0, r.Max.Y-1)
+	case EdgeRight:
+		r.Max.X = clampInt(r.Max.X+dx, r.Min.X+1, w)
+	case EdgeBottom:
+		r.Max.Y = clampInt(r.Max.Y+dy, r.Min.Y+1, h)
+	}
+	s.setRect(r)
+}
+
+// CycleEdge handles Tab: arrow keys move the next edge, or the previous one if
+// reverse, and after the last edge the whole selection again. A selection being
+// dragged stops following the cursor and becomes pending first.
+func (s *Selection) CycleEdge(reverse bool) {
+	if s.Mode != ModeRegion {
+		return
+	}
+	if s.IsDragging {
+		if s.Rect().Empty() {
+			return
+		}
+		s.IsDragging = false
+		s.SpaceHeld = false
+		s.Grab = HandleNone
+		s.Pending = true
+	}
+	if !s.Pending {
+		return
+	}
+
+	step := 1
+	if reverse {
+		step = int(edgeCount) - 1
+	}
+	s.ActiveEdge = Edge((int(s.ActiveEdge) + step) % int(edgeCount))
+}
+
+// SetAspect locks drags to width/height ratio, or unlocks them if ratio is 0.
+// A pending selection is reshaped around its center, keeping its width where
+// the w x h overlay allows.
+func (s *Selection) SetAspect(ratio float64, w, h int) {
+	s.Aspect = ratio
+	r := s.Rect()
+	if ratio <= 0 || !s.Pending || r.Empty() {
+		return
+	}
+	sw, sh := float64(r.Dx()), float64(r.Dx())/ratio
+	if sh > float64(h) {
+		sw, sh = float64(h)*ratio, float64(h)
+	}
+	s.setRect(centerWithin(r, round(sw), round(sh), w, h))
+}
+
+// LockAspect locks drags to the pending selection's current shape
+func (s *Selection) LockAspect() {
+	if r := s.Rect(); s.Pending && !r.Empty() {
+		s.Aspect = float64(r.Dx()) / float64(r.Dy())
+	}
+}
+
+// SetSize makes the selection a pending sw x sh one within a w x h overlay,
+// centered where the selection or, without one, the cursor is
+func (s *Selection) SetSize(sw, sh, w, h int) {
+	r := s.Rect()
+	if !s.Visible() {
+		r = image.Rect(s.CursorX, s.CursorY, s.CursorX, s.CursorY)
+	}
+	s.setRect(centerWithin(r, sw, sh, w, h))
+	s.IsDragging = false
+	s.SpaceHeld = false
+	s.Grab = HandleNone
+	s.Pending = true
+	s.ActiveEdge = EdgeNone
+}
+
+// moveWithin moves r by d, stopping at the edges of a w x h overlay
+func moveWithin(r image.Rectangle, d image.Point, w, h int) image.Rectangle {
+	d.X = clampInt(d.X, -r.Min.X, w-r.Max.X)
+	d.Y = clampInt(d.Y, -r.Min.Y, h-r.Max.Y)
+	return r.Add(d)
+}
+
+// clampRect clamps each corner of r into a w x h overlay
+func clampRect(r image.Rectangle, w, h int) image.Rectangle {
+	return image.Rect(
+		clampInt(r.Min.X, 0, w), clampInt(r.Min.Y, 0, h),
+		clampInt(r.Max.X, 0, w), clampInt(r.Max.Y, 0, h),
+	)
+}
+
+// centerWithin returns an sw x sh rectangle centered on r, shrunk and moved as
+// needed to fit a w x h overlay
+func centerWithin(r image.Rectangle, sw, sh, w, h int) image.Rectangle {
+	sw, sh = minInt(sw, w), minInt(sh, h)
+	cx, cy := (r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2
+	c := image.Rect(cx-sw/2, cy-sh/2, cx-sw/2+sw, cy-sh/2+sh)
+	return moveWithin(c, image.Point{}, w, h) // Moving by nothing still pulls it inside
+}
+
+// fitAspect reshapes r, the result of dragging handle g of orig, to ratio. The
+// edges opposite the dragged ones stay put, as does the center of an axis that
+// isn't being dragged, and the result is shrunk to fit a w x h overlay.
+func fitAspect(r, orig image.Rectangle, g Handle, ratio float64, w, h int) image.Rectangle {
+	horiz := g&(HandleLeft|HandleRight) != 0
+	vert := g&(HandleTop|HandleBottom) != 0
+
+	sw, sh := float64(r.Dx()), float64(r.Dy())
+	switch {
+	case horiz && vert:
+		// Follow whichever way the pointer went further
+		if sw < sh*ratio {
+			sw = sh * ratio
+		} else {
+			sh = sw / ratio
+		}
+	case horiz:
+		sh = sw / ratio
+	default:
+		sw = sh * ratio
+	}
+
+	fx, dirX := aspectAnchor(g&HandleLeft != 0, g&HandleRight != 0, orig.Min.X, orig.Max.X, r.Min.X, r.Max.X)
+	fy, dirY := aspectAnchor(g&HandleTop != 0, g&HandleBottom != 0, orig.Min.Y, orig.Max.Y, r.Min.Y, r.Max.Y)
+
+	// Shrink both sides alike to the room beside the anchors
+	scale := math.Min(1, math.Min(room(fx, dirX, w)/sw, room(fy, dirY, h)/sh))
+	if math.IsNaN(scale) {
+		scale = 1
+	}
+	sw, sh = sw*scale, sh*scale
+
+	minX, maxX := place(fx, round(sw), dirX)
+	minY, maxY := place(fy, round(sh), dirY)
+	return image.Rect(minX, minY, maxX, maxY)
+}
+
+// aspectAnchor returns the coordinate a drag along one axis keeps fixed and
+// which way the selection extends from it: -1 toward lower coordinates, 1
+// toward higher, or 0 centered on it when neither edge is dragged
+func aspectAnchor(lowDragged, highDragged bool, origMin, origMax, min, max int) (int, int) {
+	switch {
+	case lowDragged:
+		if min < origMax {
+			return origMax, -1
+		}
+		return origMax, 1
+	case highDragged:
+		if max > origMin {
+			return origMin, 1
+		}
+		return origMin, -1
+	default:
+		return (origMin + origMax) / 2, 0
+	}
+}
+
+// room returns how large a selection may get extending dir from fixed on an
+// axis of length size
+func room(fixed, dir, size int) float64 {
+	switch dir {
+	case -1:
+		return float64(fixed)
+	case 1:
+		return float64(size - fixed)
+	default:
+		return float64(2 * minInt(fixed, size-fixed))
+	}
+}
+
+// place returns the span of length extending dir from fixed
+func place(fixed, length, dir int) (int, int) {
+	switch dir {
+	case -1:
+		return fixed - length, fixed
+	case 1:
+		return fixed, fixed + length
+	default:
+		return fixed - length/2, fixed - length/2 + length
+	}
+}
+
+func round(f float64) int {
+	return int(math.Round(f))
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func clampInt(val, min, max int) int {
+	if val < min {
+		return min
+	}
+	if val > max {
+		return max
+	}
+	return val
+}
+
+// edgeNames names each Edge in the instructions
+var edgeNames = [...]string{
+	EdgeLeft:   "left edge",
+	EdgeTop:    "top edge",
+	EdgeRight:  "right edge",
+	EdgeBottom: "bottom edge",
+}
+
+// instructionText returns the keys that apply to the selection's state
+func instructionText(sel *Selection) string {
+	switch {
+	case sel.Mode == ModePickColor:
+		return "Click or Enter to pick a color. Arrows move (Shift 10px). ESC cancel"
+	case sel.Mode == ModeMeasure && sel.IsDragging:
+		return "Release to keep the line. Arrows move the end (Shift 10px). ESC cancel"
+	case sel.Mode == ModeMeasure:
+		return "Drag to measure, or point at a gap. Enter copy. ESC cancel"
+	case sel.IsDragging && sel.SpaceHeld:
+		return "Hold Space + Drag to reposition"
+	case sel.Pending && sel.ActiveEdge != EdgeNone:
+		return "Arrows move the " + edgeNames[sel.ActiveEdge] + ". Tab next edge. Enter confirm. ESC cancel"
+	case sel.Pending && !sel.IsDragging:
+		return "Drag handles to adjust. Enter or double-click to capture. Right-click for presets. ESC cancel"
+	case sel.IsDragging:
+		return "Arrows nudge (Shift 10px). Tab adjust edges. Enter confirm. ESC cancel"
+	default:
+		return "Drag to select. Space to move. Arrows nudge, Enter start. Right-click for presets. ESC cancel"
+	}
+}
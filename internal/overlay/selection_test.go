@@ -0,0 +1,301 @@
+package overlay
+
+import (
+	"image"
+	"testing"
+)
+
+func TestSelection_Release(t *testing.T) {
+	tests := []struct {
+		name        string
+		x, y        int
+		wantPending bool
+	}{
+		{"drag", 120, 80, true},
+		{"stray click", 25, 25, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Selection
+			s.Press(20, 20)
+			s.Move(tt.x, tt.y, 800, 600, false)
+			s.Release()
+			if s.Pending != tt.wantPending || s.Visible() != tt.wantPending {
+				t.Fatalf("after Release: Pending %v, Visible %v; want %v", s.Pending, s.Visible(), tt.wantPending)
+			}
+			if s.IsDragging {
+				t.Error("still dragging after Release")
+			}
+		})
+	}
+}
+
+func TestSelection_MoveAll(t *testing.T) {
+	var s Selection
+	s.Press(10, 10)
+	s.Move(50, 40, 800, 600, false)
+	s.Move(60, 45, 800, 600, true)
+	if got, want := s.Rect(), image.Rect(20, 15, 60, 45); got != want {
+		t.Errorf("Rect() = %v, want %v", got, want)
+	}
+	if !s.SpaceHeld {
+		t.Error("SpaceHeld = false while moving the selection")
+	}
+}
+
+func TestSelection_HandleAt(t *testing.T) {
+	s := Selection{StartX: 100, StartY: 100, EndX: 200, EndY: 160, Pending: true}
+	tests := []struct {
+		x, y int
+		want Handle
+	}{
+		{100, 100, HandleLeft | HandleTop},
+		{203, 158, HandleRight | HandleBottom},
+		{150, 97, HandleTop},
+		{99, 130, HandleLeft},
+		{150, 130, HandleMove},
+		{150, 170, HandleNone},
+		{50, 50, HandleNone},
+	}
+	for _, tt := range tests {
+		if got := s.HandleAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("HandleAt(%d, %d) = %d, want %d", tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	s.Pending = false
+	if got := s.HandleAt(150, 130); got != HandleNone {
+		t.Errorf("HandleAt without a pending selection = %d, want none", got)
+	}
+}
+
+func TestSelection_DragHandles(t *testing.T) {
+	tests := []struct {
+		name   string
+		aspect float64
+		press  image.Point
+		to     image.Point
+		want   image.Rectangle
+	}{
+		{"move", 0, image.Pt(150, 130), image.Pt(160, 120), image.Rect(110, 90, 210, 150)},
+		{"move stops at overlay edge", 0, image.Pt(150, 130), image.Pt(0, 130), image.Rect(0, 100, 100, 160)},
+		{"right edge", 0, image.Pt(200, 130), image.Pt(250, 300), image.Rect(100, 100, 250, 160)},
+		{"top-left corner", 0, image.Pt(100, 100), image.Pt(90, 120), image.Rect(90, 120, 200, 160)},
+		{"left edge past the right flips", 0, image.Pt(100, 130), image.Pt(230, 130), image.Rect(200, 100, 230, 160)},
+		{"corner clamped to overlay", 0, image.Pt(200, 160), image.Pt(900, 900), image.Rect(100, 100, 800, 600)},
+		{"corner with 2:1 aspect", 2, image.Pt(200, 160), image.Pt(300, 170), image.Rect(100, 100, 300, 200)},
+		{"edge with 2:1 aspect keeps center", 2, image.Pt(200, 130), image.Pt(240, 130), image.Rect(100, 95, 240, 165)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Selection{StartX: 100, StartY: 100, EndX: 200, EndY: 160, Pending: true, Aspect: tt.aspect}
+			s.Press(tt.press.X, tt.press.Y)
+			if !s.Pending || !s.IsDragging {
+				t.Fatalf("Press on the selection did not grab it: %+v", s)
+			}
+			s.Move(tt.to.X, tt.to.Y, 800, 600, false)
+			if got := s.Rect(); got != tt.want {
+				t.Errorf("Rect() = %v, want %v", got, tt.want)
+			}
+			s.Release()
+			if !s.Pending || s.IsDragging || s.Grab != HandleNone {
+				t.Errorf("after Release: %+v", s)
+			}
+		})
+	}
+}
+
+func TestSelection_NewDragWithAspect(t *testing.T) {
+	s := Selection{Aspect: 16.0 / 9}
+	s.Press(100, 100)
+	s.Move(50, 300, 800, 600, false)
+	if got, want := s.Rect(), image.Rect(0, 100, 100, 156); got != want {
+		t.Errorf("Rect() = %v, want %v", got, want)
+	}
+}
+
+func TestSelection_DoubleClick(t *testing.T) {
+	s := Selection{StartX: 100, StartY: 100, EndX: 200, EndY: 160, Pending: true}
+	if r, ok := s.DoubleClick(150, 130); !ok || r != image.Rect(100, 100, 200, 160) {
+		t.Errorf("DoubleClick inside = %v, %v", r, ok)
+	}
+	if _, ok := s.DoubleClick(50, 50); ok {
+		t.Error("DoubleClick outside confirmed")
+	}
+}
+
+func TestSelection_Enter(t *testing.T) {
+	s := Selection{CursorX: 30, CursorY: 40}
+
+	// The first Enter anchors a selection at the cursor
+	if _, confirm := s.Enter(); confirm {
+		t.Fatal("Enter() confirmed without a selection")
+	}
+	if !s.IsDragging || s.StartX != 30 || s.StartY != 40 {
+		t.Fatalf("Enter() did not anchor at the cursor: %+v", s)
+	}
+	if _, confirm := s.Enter(); confirm {
+		t.Fatal("Enter() confirmed an empty selection")
+	}
+
+	s.Nudge(10, 0, 800, 600)
+	s.Nudge(0, 5, 800, 600)
+	r, confirm := s.Enter()
+	if want := image.Rect(30, 40, 40, 45); !confirm || r != want {
+		t.Errorf("Enter() = %v, %v; want %v, true", r, confirm, want)
+	}
+}
+
+func TestSelection_SetSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		sel    Selection
+		sw, sh int
+		want   image.Rectangle
+	}{
+		{"centered on selection", Selection{StartX: 100, StartY: 100, EndX: 200, EndY: 200, Pending: true}, 40, 20, image.Rect(130, 140, 170, 160)},
+		{"at cursor", Selection{CursorX: 400, CursorY: 300}, 100, 50, image.Rect(350, 275, 450, 325)},
+		{"kept inside", Selection{CursorX: 10, CursorY: 590}, 100, 50, image.Rect(0, 550, 100, 600)},
+		{"shrunk to overlay", Selection{CursorX: 10, CursorY: 10}, 1000, 50, image.Rect(0, 0, 800, 50)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.sel
+			s.SetSize(tt.sw, tt.sh, 800, 600)
+			if got := s.Rect(); got != tt.want {
+				t.Errorf("Rect() = %v, want %v", got, tt.want)
+			}
+			if !s.Pending {
+				t.Error("sized selection is not pending")
+			}
+		})
+	}
+}
+
+func TestSelection_SetAspect(t *testing.T) {
+	s := Selection{StartX: 100, StartY: 100, EndX: 260, EndY: 200, Pending: true}
+	s.SetAspect(16.0/9, 800, 600)
+	if got, want := s.Rect(), image.Rect(100, 105, 260, 195); got != want {
+		t.Errorf("Rect() = %v, want %v", got, want)
+	}
+
+	s.SetAspect(0, 800, 600)
+	if s.Aspect != 0 || s.Rect() != image.Rect(100, 105, 260, 195) {
+		t.Errorf("unlocking changed the selection: %+v", s)
+	}
+}
+
+func TestSelection_Nudge(t *testing.T) {
+	tests := []struct {
+		name      string
+		sel       Selection
+		dx, dy    int
+		wantRect  image.Rectangle
+		wantX     int
+		wantY     int
+		wantMoved bool
+	}{
+		{
+			name:      "cursor",
+			sel:       Selection{CursorX: 5, CursorY: 5},
+			dx:        -10,
+			wantX:     0,
+			wantY:     5,
+			wantMoved: true,
+		},
+		{
+			name:      "drag end point",
+			sel:       dragging(10, 10, 50, 50),
+			dx:        10,
+			dy:        -1,
+			wantRect:  image.Rect(10, 10, 60, 49),
+			wantX:     60,
+			wantY:     49,
+			wantMoved: true,
+		},
+		{
+			name:     "pending clamped to overlay",
+			sel:      Selection{StartX: 10, StartY: 10, EndX: 50, EndY: 50, Pending: true},
+			dx:       -20,
+			dy:       1,
+			wantRect: image.Rect(0, 11, 40, 51),
+		},
+		{
+			name:     "left edge",
+			sel:      Selection{StartX: 10, StartY: 10, EndX: 50, EndY: 50, Pending: true, ActiveEdge: EdgeLeft},
+			dx:       10,
+			wantRect: image.Rect(20, 10, 50, 50),
+		},
+		{
+			name:     "left edge stops short of right",
+			sel:      Selection{StartX: 10, StartY: 10, EndX: 50, EndY: 50, Pending: true, ActiveEdge: EdgeLeft},
+			dx:       100,
+			wantRect: image.Rect(49, 10, 50, 50),
+		},
+		{
+			name:     "bottom edge clamped to overlay",
+			sel:      Selection{StartX: 10, StartY: 10, EndX: 50, EndY: 95, Pending: true, ActiveEdge: EdgeBottom},
+			dy:       10,
+			wantRect: image.Rect(10, 10, 50, 100),
+		},
+		{
+			name:     "vertical edge ignores horizontal nudge",
+			sel:      Selection{StartX: 10, StartY: 10, EndX: 50, EndY: 50, Pending: true, ActiveEdge: EdgeTop},
+			dx:       10,
+			wantRect: image.Rect(10, 10, 50, 50),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.sel
+			moved := s.Nudge(tt.dx, tt.dy, 100, 100)
+			if moved != tt.wantMoved {
+				t.Errorf("Nudge() = %v, want %v", moved, tt.wantMoved)
+			}
+			if s.Visible() {
+				if got := s.Rect(); got != tt.wantRect {
+					t.Errorf("Rect() = %v, want %v", got, tt.wantRect)
+				}
+			}
+			if tt.wantMoved && (s.CursorX != tt.wantX || s.CursorY != tt.wantY) {
+				t.Errorf("cursor = %d,%d, want %d,%d", s.CursorX, s.CursorY, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestSelection_CycleEdge(t *testing.T) {
+	s := dragging(10, 10, 50, 50)
+
+	// Tab during a drag makes the selection pending on its left edge
+	s.CycleEdge(false)
+	if s.IsDragging || !s.Pending || s.ActiveEdge != EdgeLeft {
+		t.Fatalf("after Tab: %+v", s)
+	}
+
+	for _, want := range []Edge{EdgeTop, EdgeRight, EdgeBottom, EdgeNone, EdgeLeft} {
+		s.CycleEdge(false)
+		if s.ActiveEdge != want {
+			t.Fatalf("ActiveEdge = %d, want %d", s.ActiveEdge, want)
+		}
+	}
+	s.CycleEdge(true)
+	if s.ActiveEdge != EdgeNone {
+		t.Errorf("Shift+Tab: ActiveEdge = %d, want %d", s.ActiveEdge, EdgeNone)
+	}
+
+	// Nothing to adjust without a selection
+	var empty Selection
+	empty.CycleEdge(false)
+	if empty.Pending || empty.ActiveEdge != EdgeNone {
+		t.Errorf("CycleEdge without a selection: %+v", empty)
+	}
+}
+
+// dragging returns a new selection being drawn from x1, y1 to x2, y2
+func dragging(x1, y1, x2, y2 int) Selection {
+	var s Selection
+	s.Press(x1, y1)
+	s.Move(x2, y2, 100, 100, false)
+	return s
+}
@@ -1,5 +1,10 @@
 package overlay
 
+import (
+	"image"
+	"image/color"
+)
+
 // Window style constants
 const (
 	WS_POPUP         = 0x80000000
@@ -24,10 +29,34 @@ const (
 	WM_SETCURSOR   = 0x0020
 	VK_ESCAPE      = 0x1B
 	VK_SPACE       = 0x20
+	VK_TAB         = 0x09
+	VK_RETURN      = 0x0D
+	VK_SHIFT       = 0x10
+	VK_LEFT        = 0x25
+	VK_UP          = 0x26
+	VK_RIGHT       = 0x27
+	VK_DOWN        = 0x28
 	HTCLIENT       = 1
 	PM_REMOVE      = 0x0001
 )
 
+// Selection editing and right-click menu constants
+const (
+	WM_LBUTTONDBLCLK = 0x0203
+	WM_RBUTTONUP     = 0x0205
+	CS_DBLCLKS       = 0x0008
+	IDC_SIZENWSE     = 32642
+	IDC_SIZENESW     = 32643
+	IDC_SIZEWE       = 32644
+	IDC_SIZENS       = 32645
+	MF_STRING        = 0x00000000
+	MF_GRAYED        = 0x00000001
+	MF_CHECKED       = 0x00000008
+	MF_SEPARATOR     = 0x00000800
+	TPM_RIGHTBUTTON  = 0x0002
+	TPM_RETURNCMD    = 0x0100
+)
+
 // GDI constants
 const (
 	DIB_RGB_COLORS = 0
@@ -83,6 +112,17 @@ type Selection struct {
 	EndX, EndY     int
 	IsDragging     bool
 	SpaceHeld      bool // For repositioning selection
+
+	CursorX, CursorY int  // Pointer position, for the crosshair and magnifier
+	Pending          bool // Selection finished but not confirmed; arrows and Tab adjust it until Enter
+	ActiveEdge       Edge // Edge arrow keys move in a pending selection
+
+	Grab         Handle          // Handle being dragged; the bottom-right corner while drawing a new selection
+	grabRect     image.Rectangle // Selection when the drag started
+	grabX, grabY int             // Pointer when the drag started
+	Aspect       float64         // Locked width/height ratio, or 0 for any shape
+
+	Mode Mode // What the overlay is shown for
 }
 
 // Result represents the final selection result
@@ -90,6 +130,8 @@ type Result struct {
 	X, Y          int
 	Width, Height int
 	Cancelled     bool
+	Color         color.RGBA  // Color pick mode: the pixel at X, Y
+	Measurement   Measurement // Measure mode: the line or gap measured
 }
 
 // WNDCLASSEXW for RegisterClassExW
@@ -15,6 +15,9 @@ type CaptureResult struct {
 	Width  int    `json:"width"`
 	Height int    `json:"height"`
 	Data   string `json:"data"` // Base64 encoded PNG
+	// MimeType is set when Data is not PNG; "image/svg+xml" markup must be
+	// rasterized by the frontend
+	MimeType string `json:"mimeType,omitempty"`
 }
 
 // CaptureFullscreen captures the display where the cursor is currently located
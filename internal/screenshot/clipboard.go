@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"image"
 	_ "image/gif"  // Register GIF decoder
 	_ "image/jpeg" // Register JPEG decoder
@@ -14,7 +15,11 @@ import (
 	"strings"
 	"unsafe"
 
+	_ "golang.org/x/image/bmp"  // Register BMP decoder
+	_ "golang.org/x/image/webp" // Register WebP decoder
 	"golang.org/x/sys/windows"
+
+	"winshot/internal/clipformat"
 )
 
 var (
@@ -29,9 +34,6 @@ var (
 	procGlobalLock   = kernel32Clip.NewProc("GlobalLock")
 	procGlobalUnlock = kernel32Clip.NewProc("GlobalUnlock")
 	procGlobalSize   = kernel32Clip.NewProc("GlobalSize")
-
-	shell32          = windows.NewLazySystemDLL("shell32.dll")
-	procDragQueryFile = shell32.NewProc("DragQueryFileW")
 )
 
 const (
@@ -60,158 +62,42 @@ func getPNGClipboardFormat() uintptr {
 	return cfPNG
 }
 
-// readImageFromHDROP reads the first image file from a CF_HDROP clipboard handle.
-// CF_HDROP is used when files are copied from File Explorer.
-func readImageFromHDROP(hDrop uintptr) (*CaptureResult, error) {
-	// Get number of files (pass 0xFFFFFFFF as index)
-	count, _, _ := procDragQueryFile.Call(hDrop, 0xFFFFFFFF, 0, 0)
-	if count == 0 {
-		return nil, errors.New("no files in clipboard")
-	}
-
-	// Try each file until we find an image
-	for i := uintptr(0); i < count; i++ {
-		// Get required buffer size for filename
-		size, _, _ := procDragQueryFile.Call(hDrop, i, 0, 0)
-		if size == 0 {
-			continue
-		}
-
-		// Allocate buffer and get filename (size+1 for null terminator)
-		buf := make([]uint16, size+1)
-		procDragQueryFile.Call(hDrop, i, uintptr(unsafe.Pointer(&buf[0])), size+1)
-
-		// Convert to Go string
-		filePath := windows.UTF16ToString(buf)
-		if filePath == "" {
-			continue
-		}
-
-		// Check if it's a supported image file
-		ext := strings.ToLower(filepath.Ext(filePath))
-		if !supportedImageExtensions[ext] {
-			continue
-		}
-
-		// Read and decode the image file
-		return readImageFile(filePath)
-	}
-
-	return nil, errors.New("no image files in clipboard")
-}
-
-// readImageFile reads an image file from disk and returns CaptureResult.
-func readImageFile(filePath string) (*CaptureResult, error) {
-	// Read file
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, errors.New("failed to read image file")
-	}
-
-	if len(data) > maxClipboardSize {
-		return nil, errors.New("image file too large")
-	}
-
-	// Decode image (supports PNG, JPEG, GIF via registered decoders)
-	img, _, err := image.Decode(bytes.NewReader(data))
-	if err != nil {
-		return nil, errors.New("failed to decode image file")
-	}
-
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
-
-	// Re-encode as PNG for consistent output
-	var buf bytes.Buffer
-	if err := png.Encode(&buf, img); err != nil {
-		return nil, err
-	}
-
-	return &CaptureResult{
-		Width:  width,
-		Height: height,
-		Data:   base64.StdEncoding.EncodeToString(buf.Bytes()),
-	}, nil
-}
-
-// BITMAPINFOHEADER represents the Windows BITMAPINFOHEADER structure
-type BITMAPINFOHEADER struct {
-	BiSize          uint32
-	BiWidth         int32
-	BiHeight        int32
-	BiPlanes        uint16
-	BiBitCount      uint16
-	BiCompression   uint32
-	BiSizeImage     uint32
-	BiXPelsPerMeter int32
-	BiYPelsPerMeter int32
-	BiClrUsed       uint32
-	BiClrImportant  uint32
-}
+// maxClipboardImages caps how many files GetClipboardImages decodes from one file list
+const maxClipboardImages = 20
 
 // ErrNoImageInClipboard is returned when clipboard has no image
 var ErrNoImageInClipboard = errors.New("no image in clipboard")
 
-// readPNGFromClipboard reads PNG data from clipboard handle and returns CaptureResult.
-// PNG format contains raw PNG file bytes, which we decode and re-encode to ensure valid output.
-func readPNGFromClipboard(hData uintptr) (*CaptureResult, error) {
-	// Lock global memory to get pointer to data
-	ptr, _, _ := procGlobalLock.Call(hData)
-	if ptr == 0 {
-		return nil, errors.New("failed to lock clipboard data")
-	}
-	defer procGlobalUnlock.Call(hData)
-
-	// Get size of clipboard data
-	size, _, _ := procGlobalSize.Call(hData)
-	if size == 0 {
-		return nil, errors.New("failed to get clipboard data size")
-	}
-	if size > maxClipboardSize {
-		return nil, errors.New("clipboard image too large")
-	}
-
-	// Copy PNG data from clipboard memory
-	pngData := make([]byte, size)
-	for i := uintptr(0); i < size; i++ {
-		pngData[i] = *(*byte)(unsafe.Pointer(ptr + i))
-	}
-
-	// Decode PNG to get dimensions and validate data
-	img, err := png.Decode(bytes.NewReader(pngData))
+// GetClipboardImage reads image from Windows clipboard.
+// When several files are on the clipboard only the first image is returned.
+func GetClipboardImage() (*CaptureResult, error) {
+	results, err := readClipboardImages(1)
 	if err != nil {
-		return nil, errors.New("invalid PNG data in clipboard")
-	}
-
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
-
-	// Re-encode to ensure consistent PNG output
-	var buf bytes.Buffer
-	if err := png.Encode(&buf, img); err != nil {
 		return nil, err
 	}
+	return results[0], nil
+}
 
-	return &CaptureResult{
-		Width:  width,
-		Height: height,
-		Data:   base64.StdEncoding.EncodeToString(buf.Bytes()),
-	}, nil
+// GetClipboardImages reads every image from the Windows clipboard. A file list
+// copied from File Explorer yields one result per image file (up to
+// maxClipboardImages); any other format yields a single result.
+func GetClipboardImages() ([]*CaptureResult, error) {
+	return readClipboardImages(maxClipboardImages)
 }
 
-// GetClipboardImage reads image from Windows clipboard
-func GetClipboardImage() (*CaptureResult, error) {
+// readClipboardImages tries each clipboard format in priority order and returns the
+// images from the first one that decodes
+func readClipboardImages(limit int) ([]*CaptureResult, error) {
 	// CRITICAL: Lock OS thread because Windows clipboard API requires
 	// OpenClipboard and CloseClipboard to be called on the same thread.
 	// Go's goroutine scheduler can switch threads between calls otherwise.
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 
-	// Get PNG clipboard format (registered format used by Chrome, Firefox, etc.)
-	// Must get this ONCE and reuse - each call returns the same ID
+	// Get registered formats ONCE and reuse - each call returns the same ID
 	cfPNG := getPNGClipboardFormat()
+	cfSVG := registerClipboardFormat(clipformat.SVGFormatName)
+	cfHTML := registerClipboardFormat(clipformat.HTMLFormatName)
 
 	// Open clipboard FIRST, then check formats
 	// This ensures consistent format detection
@@ -221,43 +107,65 @@ func GetClipboardImage() (*CaptureResult, error) {
 	}
 	defer procCloseClipboard.Call()
 
-	// Check formats in priority order: PNG (modern) → DIBV5 (transparency) → DIB (legacy) → HDROP (files)
-	var selectedFormat uintptr
-	formats := []uintptr{cfPNG, CF_DIBV5, CF_DIB, CF_HDROP}
+	// Priority: PNG (modern) → DIBV5 (transparency) → DIB (legacy) → SVG (vector) →
+	// HTML (<img> from browsers) → HDROP (files). A format that fails to decode
+	// falls through to the next one.
+	formats := []uintptr{cfPNG, CF_DIBV5, CF_DIB, cfSVG, cfHTML, CF_HDROP}
+	var lastErr error
 	for _, format := range formats {
 		if format == 0 {
-			continue // Skip invalid formats (e.g., if PNG registration failed)
+			continue // Skip invalid formats (e.g., if registration failed)
 		}
-		available, _, _ := procIsClipboardFormatAvailable.Call(format)
-		if available != 0 {
-			selectedFormat = format
-			break
+		if available, _, _ := procIsClipboardFormatAvailable.Call(format); available == 0 {
+			continue
+		}
+		data, err := readClipboardData(format)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var results []*CaptureResult
+		switch format {
+		case cfPNG:
+			results, err = single(decodeImageBytes(data))
+		case CF_DIBV5, CF_DIB:
+			results, err = single(decodeDIB(data))
+		case cfSVG:
+			results, err = single(svgResult(data))
+		case cfHTML:
+			results, err = single(readImageFromHTML(data))
+		case CF_HDROP:
+			results, err = readImagesFromHDROP(data, limit)
 		}
+		if err == nil {
+			return results, nil
+		}
+		lastErr = err
 	}
-	if selectedFormat == 0 {
-		return nil, ErrNoImageInClipboard
+
+	if lastErr != nil {
+		return nil, lastErr
 	}
+	return nil, ErrNoImageInClipboard
+}
 
-	// Handle CF_HDROP (file list from File Explorer)
-	if selectedFormat == CF_HDROP {
-		hDrop, _, _ := procGetClipboardData.Call(CF_HDROP)
-		if hDrop == 0 {
-			return nil, ErrNoImageInClipboard
-		}
-		return readImageFromHDROP(hDrop)
+// single wraps a one-image result for readClipboardImages
+func single(result *CaptureResult, err error) ([]*CaptureResult, error) {
+	if err != nil {
+		return nil, err
 	}
+	return []*CaptureResult{result}, nil
+}
 
-	// Get clipboard data handle for selected format
-	hData, _, _ := procGetClipboardData.Call(selectedFormat)
+// readClipboardData copies the raw bytes of a clipboard format.
+// The clipboard must be open.
+func readClipboardData(format uintptr) ([]byte, error) {
+	hData, _, _ := procGetClipboardData.Call(format)
 	if hData == 0 {
 		return nil, ErrNoImageInClipboard
 	}
 
-	// Handle PNG format (raw PNG bytes from modern apps)
-	if selectedFormat == cfPNG {
-		return readPNGFromClipboard(hData)
-	}
-
 	// Lock global memory to get pointer to data
 	ptr, _, _ := procGlobalLock.Call(hData)
 	if ptr == 0 {
@@ -277,108 +185,148 @@ func GetClipboardImage() (*CaptureResult, error) {
 		return nil, errors.New("clipboard image too large")
 	}
 
-	// Parse BITMAPINFOHEADER
-	header := (*BITMAPINFOHEADER)(unsafe.Pointer(ptr))
-
-	width := int(header.BiWidth)
-	height := int(header.BiHeight)
-	bitCount := int(header.BiBitCount)
+	data := make([]byte, size)
+	copy(data, unsafe.Slice((*byte)(unsafe.Pointer(ptr)), size))
+	return data, nil
+}
 
-	// Height can be negative (top-down DIB) or positive (bottom-up DIB)
-	bottomUp := height > 0
-	if height < 0 {
-		height = -height
+// decodeDIB converts CF_DIB / CF_DIBV5 data to a CaptureResult
+func decodeDIB(data []byte) (*CaptureResult, error) {
+	img, err := clipformat.DecodeDIB(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bitmap in clipboard: %w", err)
 	}
+	return encodePNG(img)
+}
 
-	if width <= 0 || height <= 0 {
-		return nil, errors.New("invalid image dimensions in clipboard")
+// decodeImageBytes decodes an encoded image (PNG, JPEG, GIF, BMP) and re-encodes it
+// as PNG for consistent output
+func decodeImageBytes(data []byte) (*CaptureResult, error) {
+	if len(data) > maxClipboardSize {
+		return nil, errors.New("image too large")
 	}
-
-	// Calculate pixel data offset (header + color table if applicable)
-	pixelOffset := uintptr(header.BiSize)
-	if bitCount <= 8 {
-		// For 8-bit or less, there's a color table
-		colorTableSize := uintptr(1 << bitCount * 4) // RGBQUAD entries
-		if header.BiClrUsed > 0 {
-			colorTableSize = uintptr(header.BiClrUsed * 4)
-		}
-		pixelOffset += colorTableSize
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("failed to decode image data")
 	}
+	return encodePNG(img)
+}
 
-	// Get pixel data pointer
-	pixelPtr := ptr + pixelOffset
-
-	// Create RGBA image
-	img := image.NewRGBA(image.Rect(0, 0, width, height))
+// encodePNG encodes img as a base64 PNG CaptureResult
+func encodePNG(img image.Image) (*CaptureResult, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
 
-	// Calculate row stride (must be aligned to 4 bytes)
-	rowSize := ((width*bitCount + 31) / 32) * 4
+	bounds := img.Bounds()
+	return &CaptureResult{
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+		Data:   base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
 
-	// Validate pixel data bounds to prevent buffer overflow
-	expectedSize := uintptr(rowSize * height)
-	dataSize := size - pixelOffset
-	if dataSize < expectedSize {
-		return nil, errors.New("invalid clipboard data: pixel data smaller than expected")
+// svgResult returns SVG markup as-is; the frontend rasterizes it, since Go has no
+// SVG renderer. Documents without a usable size get the CSS default of 300x150.
+func svgResult(data []byte) (*CaptureResult, error) {
+	data = bytes.TrimRight(data, "\x00") // Clipboard memory may be padded
+	width, height, ok := clipformat.SVGSize(data)
+	if !ok {
+		if !bytes.Contains(data, []byte("<svg")) {
+			return nil, errors.New("invalid SVG data in clipboard")
+		}
+		width, height = 300, 150
 	}
+	return &CaptureResult{
+		Width:    width,
+		Height:   height,
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MimeType: clipformat.SVGFormatName,
+	}, nil
+}
 
-	// Convert DIB to RGBA
-	switch bitCount {
-	case 24:
-		// 24-bit BGR
-		for y := 0; y < height; y++ {
-			srcY := y
-			if bottomUp {
-				srcY = height - 1 - y
+// readImageFromHTML returns the first <img> in an "HTML Format" fragment that is
+// either an inline data: URI or a local file. Remote URLs are not fetched.
+func readImageFromHTML(data []byte) (*CaptureResult, error) {
+	for _, src := range clipformat.HTMLImageSources(data) {
+		if strings.HasPrefix(src, "data:") {
+			payload, mediaType, err := clipformat.DecodeDataURI(src)
+			if err != nil {
+				continue
 			}
-			rowPtr := pixelPtr + uintptr(srcY*rowSize)
-			for x := 0; x < width; x++ {
-				pixelAddr := rowPtr + uintptr(x*3)
-				b := *(*byte)(unsafe.Pointer(pixelAddr))
-				g := *(*byte)(unsafe.Pointer(pixelAddr + 1))
-				r := *(*byte)(unsafe.Pointer(pixelAddr + 2))
-				img.Pix[(y*width+x)*4+0] = r
-				img.Pix[(y*width+x)*4+1] = g
-				img.Pix[(y*width+x)*4+2] = b
-				img.Pix[(y*width+x)*4+3] = 255
+			if mediaType == clipformat.SVGFormatName {
+				return svgResult(payload)
 			}
-		}
-	case 32:
-		// 32-bit BGRA
-		for y := 0; y < height; y++ {
-			srcY := y
-			if bottomUp {
-				srcY = height - 1 - y
+			if result, err := decodeImageBytes(payload); err == nil {
+				return result, nil
 			}
-			rowPtr := pixelPtr + uintptr(srcY*rowSize)
-			for x := 0; x < width; x++ {
-				pixelAddr := rowPtr + uintptr(x*4)
-				b := *(*byte)(unsafe.Pointer(pixelAddr))
-				g := *(*byte)(unsafe.Pointer(pixelAddr + 1))
-				r := *(*byte)(unsafe.Pointer(pixelAddr + 2))
-				a := *(*byte)(unsafe.Pointer(pixelAddr + 3))
-				// Some apps set alpha to 0 for opaque pixels, handle this
-				if a == 0 {
-					a = 255
-				}
-				img.Pix[(y*width+x)*4+0] = r
-				img.Pix[(y*width+x)*4+1] = g
-				img.Pix[(y*width+x)*4+2] = b
-				img.Pix[(y*width+x)*4+3] = a
+			continue
+		}
+		if path, ok := clipformat.FilePathFromURL(src); ok && isSupportedImageFile(path) {
+			if result, err := readImageFile(path); err == nil {
+				return result, nil
 			}
 		}
-	default:
-		return nil, errors.New("unsupported bit depth: only 24-bit and 32-bit images are supported")
 	}
+	return nil, errors.New("no usable image in clipboard HTML")
+}
 
-	// Encode to PNG and return as CaptureResult
-	var buf bytes.Buffer
-	if err := png.Encode(&buf, img); err != nil {
+// readImagesFromHDROP reads up to limit image files from CF_HDROP data.
+// CF_HDROP is used when files are copied from File Explorer.
+func readImagesFromHDROP(data []byte, limit int) ([]*CaptureResult, error) {
+	paths, err := clipformat.DecodeDropFiles(data)
+	if err != nil {
 		return nil, err
 	}
+	if len(paths) == 0 {
+		return nil, errors.New("no files in clipboard")
+	}
 
-	return &CaptureResult{
-		Width:  width,
-		Height: height,
-		Data:   base64.StdEncoding.EncodeToString(buf.Bytes()),
-	}, nil
+	var results []*CaptureResult
+	for _, filePath := range paths {
+		if !isSupportedImageFile(filePath) {
+			continue
+		}
+		result, err := readImageFile(filePath)
+		if err != nil {
+			println("Warning: skipping clipboard file", filePath+":", err.Error())
+			continue
+		}
+		results = append(results, result)
+		if len(results) >= limit {
+			break
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, errors.New("no image files in clipboard")
+	}
+	return results, nil
+}
+
+// isSupportedImageFile reports whether path has a supported image extension
+func isSupportedImageFile(path string) bool {
+	return supportedImageExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// readImageFile reads an image file from disk and returns CaptureResult.
+func readImageFile(filePath string) (*CaptureResult, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, errors.New("failed to read image file")
+	}
+	if info.Size() > maxClipboardSize {
+		return nil, errors.New("image file too large")
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.New("failed to read image file")
+	}
+	result, err := decodeImageBytes(data)
+	if err != nil {
+		return nil, errors.New("failed to decode image file")
+	}
+	return result, nil
 }
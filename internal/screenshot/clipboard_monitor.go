@@ -0,0 +1,280 @@
+package screenshot
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	procAddClipboardFormatListener    = user32Clip.NewProc("AddClipboardFormatListener")
+	procRemoveClipboardFormatListener = user32Clip.NewProc("RemoveClipboardFormatListener")
+	procGetClipboardSequenceNumber    = user32Clip.NewProc("GetClipboardSequenceNumber")
+	procRegisterClassExW              = user32Clip.NewProc("RegisterClassExW")
+	procCreateWindowExW               = user32Clip.NewProc("CreateWindowExW")
+	procDestroyWindow                 = user32Clip.NewProc("DestroyWindow")
+	procDefWindowProcW                = user32Clip.NewProc("DefWindowProcW")
+	procGetMessageW                   = user32Clip.NewProc("GetMessageW")
+	procDispatchMessageW              = user32Clip.NewProc("DispatchMessageW")
+	procPostMessageW                  = user32Clip.NewProc("PostMessageW")
+	procGetModuleHandleW              = kernel32Clip.NewProc("GetModuleHandleW")
+)
+
+const (
+	WM_CLIPBOARDUPDATE = 0x031D
+	WM_QUIT            = 0x0012
+
+	// hwndMessage is HWND_MESSAGE: the parent of message-only windows
+	hwndMessage = ^uintptr(2) // (HWND)-3
+
+	// clipboardDebounce coalesces the bursts of updates some apps send for one copy
+	clipboardDebounce = 300 * time.Millisecond
+)
+
+// ownClipboardSequence is the clipboard sequence number after WinShot's last write,
+// so the monitor can ignore images WinShot put there itself
+var ownClipboardSequence atomic.Uint32
+
+// recordOwnClipboardWrite remembers the current clipboard sequence number.
+// Called after the clipboard is closed following a write.
+func recordOwnClipboardWrite() {
+	seq, _, _ := procGetClipboardSequenceNumber.Call()
+	ownClipboardSequence.Store(uint32(seq))
+}
+
+// wndClassEx mirrors WNDCLASSEXW
+type wndClassEx struct {
+	CbSize        uint32
+	Style         uint32
+	LpfnWndProc   uintptr
+	CbClsExtra    int32
+	CbWndExtra    int32
+	HInstance     uintptr
+	HIcon         uintptr
+	HCursor       uintptr
+	HbrBackground uintptr
+	LpszMenuName  *uint16
+	LpszClassName *uint16
+	HIconSm       uintptr
+}
+
+// msg mirrors MSG
+type msg struct {
+	HWnd    uintptr
+	Message uint32
+	WParam  uintptr
+	LParam  uintptr
+	Time    uint32
+	Pt      struct{ X, Y int32 }
+}
+
+var (
+	// Window procedures are process-wide, so monitors are looked up by window handle
+	monitorsMu sync.Mutex
+	monitors   = map[uintptr]*ClipboardMonitor{}
+
+	registerMonitorClass sync.Once
+	monitorClassName     = windows.StringToUTF16Ptr("WinShotClipboardMonitor")
+	monitorClassErr      error
+)
+
+// ClipboardMonitor reports clipboard changes that contain a bitmap image.
+// It listens for WM_CLIPBOARDUPDATE on a hidden message-only window.
+type ClipboardMonitor struct {
+	onImage func()
+	callMu  sync.Mutex // Serializes onImage calls
+
+	mu      sync.Mutex
+	hwnd    uintptr
+	done    chan struct{}
+	timer   *time.Timer
+	lastSeq uint32
+}
+
+// NewClipboardMonitor creates a monitor that calls onImage (on its own goroutine)
+// when another application copies an image
+func NewClipboardMonitor(onImage func()) *ClipboardMonitor {
+	return &ClipboardMonitor{onImage: onImage}
+}
+
+// Running reports whether the monitor is listening
+func (m *ClipboardMonitor) Running() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hwnd != 0
+}
+
+// Start begins listening for clipboard changes
+func (m *ClipboardMonitor) Start() error {
+	m.mu.Lock()
+	if m.hwnd != 0 {
+		m.mu.Unlock()
+		return nil
+	}
+	m.mu.Unlock()
+
+	started := make(chan error, 1)
+	go m.run(started)
+	return <-started
+}
+
+// Stop stops listening and waits for the message loop to exit
+func (m *ClipboardMonitor) Stop() {
+	m.mu.Lock()
+	hwnd, done := m.hwnd, m.done
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	m.mu.Unlock()
+
+	if hwnd == 0 {
+		return
+	}
+	procPostMessageW.Call(hwnd, WM_QUIT, 0, 0)
+	<-done
+}
+
+// run owns the listener window; window messages must be pumped on the creating thread
+func (m *ClipboardMonitor) run(started chan<- error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	hInstance, _, _ := procGetModuleHandleW.Call(0)
+	registerMonitorClass.Do(func() {
+		class := wndClassEx{
+			LpfnWndProc:   windows.NewCallback(monitorWndProc),
+			HInstance:     hInstance,
+			LpszClassName: monitorClassName,
+		}
+		class.CbSize = uint32(unsafe.Sizeof(class))
+		if atom, _, err := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&class))); atom == 0 {
+			monitorClassErr = err
+		}
+	})
+	if monitorClassErr != nil {
+		started <- errors.New("failed to register clipboard monitor window class")
+		return
+	}
+
+	hwnd, _, _ := procCreateWindowExW.Call(
+		0,
+		uintptr(unsafe.Pointer(monitorClassName)),
+		0,
+		0,
+		0, 0, 0, 0,
+		hwndMessage, 0,
+		hInstance,
+		0,
+	)
+	if hwnd == 0 {
+		started <- errors.New("failed to create clipboard monitor window")
+		return
+	}
+
+	monitorsMu.Lock()
+	monitors[hwnd] = m
+	monitorsMu.Unlock()
+
+	if ret, _, _ := procAddClipboardFormatListener.Call(hwnd); ret == 0 {
+		monitorsMu.Lock()
+		delete(monitors, hwnd)
+		monitorsMu.Unlock()
+		procDestroyWindow.Call(hwnd)
+		started <- errors.New("failed to add clipboard listener")
+		return
+	}
+
+	done := make(chan struct{})
+	m.mu.Lock()
+	m.hwnd, m.done = hwnd, done
+	m.mu.Unlock()
+	started <- nil
+
+	// GetMessage dispatches sent messages such as WM_CLIPBOARDUPDATE to monitorWndProc
+	// and returns 0 once Stop posts WM_QUIT
+	var message msg
+	for {
+		ret, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&message)), hwnd, 0, 0)
+		if ret == 0 || int32(ret) == -1 || message.Message == WM_QUIT {
+			break
+		}
+		procDispatchMessageW.Call(uintptr(unsafe.Pointer(&message)))
+	}
+
+	procRemoveClipboardFormatListener.Call(hwnd)
+	monitorsMu.Lock()
+	delete(monitors, hwnd)
+	monitorsMu.Unlock()
+	procDestroyWindow.Call(hwnd)
+
+	m.mu.Lock()
+	m.hwnd = 0
+	m.mu.Unlock()
+	close(done)
+}
+
+func monitorWndProc(hwnd uintptr, message uint32, wParam, lParam uintptr) uintptr {
+	if message == WM_CLIPBOARDUPDATE {
+		monitorsMu.Lock()
+		m := monitors[hwnd]
+		monitorsMu.Unlock()
+		if m != nil {
+			m.schedule()
+		}
+		return 0
+	}
+
+	ret, _, _ := procDefWindowProcW.Call(hwnd, uintptr(message), wParam, lParam)
+	return ret
+}
+
+// schedule (re)starts the debounce timer for a clipboard update
+func (m *ClipboardMonitor) schedule() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	m.timer = time.AfterFunc(clipboardDebounce, m.check)
+}
+
+// check calls onImage if the settled clipboard holds a new bitmap that WinShot did not write
+func (m *ClipboardMonitor) check() {
+	seq, _, _ := procGetClipboardSequenceNumber.Call()
+
+	m.mu.Lock()
+	if m.hwnd == 0 || uint32(seq) == m.lastSeq {
+		m.mu.Unlock()
+		return
+	}
+	m.lastSeq = uint32(seq)
+	m.mu.Unlock()
+
+	if uint32(seq) == ownClipboardSequence.Load() || !HasClipboardBitmap() {
+		return
+	}
+	m.callMu.Lock()
+	defer m.callMu.Unlock()
+	m.onImage()
+}
+
+// HasClipboardBitmap reports whether the clipboard holds a bitmap image (PNG or DIB).
+// Copied files, HTML and SVG are ignored. The clipboard does not need to be open.
+func HasClipboardBitmap() bool {
+	for _, format := range []uintptr{getPNGClipboardFormat(), CF_DIBV5, CF_DIB} {
+		if format == 0 {
+			continue
+		}
+		if available, _, _ := procIsClipboardFormatAvailable.Call(format); available != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package screenshot
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"winshot/internal/clipformat"
+)
+
+var (
+	procEmptyClipboard   = user32Clip.NewProc("EmptyClipboard")
+	procSetClipboardData = user32Clip.NewProc("SetClipboardData")
+	procGlobalAlloc      = kernel32Clip.NewProc("GlobalAlloc")
+	procGlobalFree       = kernel32Clip.NewProc("GlobalFree")
+)
+
+const (
+	GMEM_MOVEABLE = 0x0002
+
+	// maxHTMLDataURI caps the size of images inlined as data: URIs in the HTML format
+	maxHTMLDataURI = 2 * 1024 * 1024
+
+	openClipboardRetries = 10
+)
+
+// ClipboardImageOptions selects the optional formats written with an image
+type ClipboardImageOptions struct {
+	FilePath string // Also place the saved file as CF_HDROP and reference it from the HTML fragment
+	HTML     bool   // Also write an "HTML Format" fragment with an <img> element
+	Alt      string // Alt text for the HTML <img>
+}
+
+// clipboardItem is one format/data pair written to the clipboard
+type clipboardItem struct {
+	format uintptr
+	data   []byte
+}
+
+// SetClipboardImage places img on the clipboard as "PNG", CF_DIBV5 (with alpha)
+// and CF_DIB at the same time, so both modern and legacy applications can paste it
+func SetClipboardImage(img image.Image, opts ClipboardImageOptions) error {
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, img); err != nil {
+		return fmt.Errorf("failed to encode PNG: %w", err)
+	}
+
+	items := []clipboardItem{
+		{format: getPNGClipboardFormat(), data: pngBuf.Bytes()},
+		{format: CF_DIBV5, data: clipformat.EncodeDIBV5(img)},
+		{format: CF_DIB, data: clipformat.EncodeDIB(img)},
+	}
+
+	if opts.FilePath != "" {
+		items = append(items, clipboardItem{format: CF_HDROP, data: clipformat.EncodeDropFiles([]string{opts.FilePath})})
+	}
+
+	if opts.HTML {
+		// Prefer referencing the saved file; inline small images otherwise
+		var src string
+		switch {
+		case opts.FilePath != "":
+			src = clipformat.FileURL(opts.FilePath)
+		case pngBuf.Len() <= maxHTMLDataURI:
+			src = "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngBuf.Bytes())
+		}
+		if src != "" {
+			bounds := img.Bounds()
+			fragment := clipformat.ImageFragment(src, bounds.Dx(), bounds.Dy(), opts.Alt)
+			items = append(items, clipboardItem{
+				format: registerClipboardFormat(clipformat.HTMLFormatName),
+				data:   clipformat.EncodeHTML(fragment, ""),
+			})
+		}
+	}
+
+	return setClipboard(items)
+}
+
+// SetClipboardFiles places a file list (CF_HDROP) on the clipboard, as File
+// Explorer does when copying files
+func SetClipboardFiles(paths []string) error {
+	if len(paths) == 0 {
+		return errors.New("no files to copy")
+	}
+	return setClipboard([]clipboardItem{{format: CF_HDROP, data: clipformat.EncodeDropFiles(paths)}})
+}
+
+// registerClipboardFormat returns the ID of a named clipboard format
+func registerClipboardFormat(name string) uintptr {
+	namePtr, _ := windows.UTF16PtrFromString(name)
+	id, _, _ := procRegisterClipboardFormat.Call(uintptr(unsafe.Pointer(namePtr)))
+	return id
+}
+
+// setClipboard replaces the clipboard contents with items
+func setClipboard(items []clipboardItem) error {
+	// Clipboard calls must stay on one OS thread (see GetClipboardImage)
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// Another application may briefly hold the clipboard open
+	opened := false
+	for i := 0; i < openClipboardRetries; i++ {
+		if ret, _, _ := procOpenClipboard.Call(0); ret != 0 {
+			opened = true
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !opened {
+		return errors.New("failed to open clipboard")
+	}
+	// Deferred first so it runs after the clipboard is closed, letting the
+	// clipboard monitor skip this write
+	defer recordOwnClipboardWrite()
+	defer procCloseClipboard.Call()
+
+	if ret, _, _ := procEmptyClipboard.Call(); ret == 0 {
+		return errors.New("failed to empty clipboard")
+	}
+
+	written := 0
+	for _, item := range items {
+		if item.format == 0 || len(item.data) == 0 {
+			continue // Format registration failed
+		}
+		if err := setClipboardData(item.format, item.data); err != nil {
+			println("Warning: clipboard format", item.format, "not written:", err.Error())
+			continue
+		}
+		written++
+	}
+	if written == 0 {
+		return errors.New("failed to write clipboard data")
+	}
+	return nil
+}
+
+// setClipboardData copies data into global memory and hands it to the clipboard.
+// The clipboard must be open.
+func setClipboardData(format uintptr, data []byte) error {
+	hMem, _, _ := procGlobalAlloc.Call(GMEM_MOVEABLE, uintptr(len(data)))
+	if hMem == 0 {
+		return errors.New("GlobalAlloc failed")
+	}
+
+	ptr, _, _ := procGlobalLock.Call(hMem)
+	if ptr == 0 {
+		procGlobalFree.Call(hMem)
+		return errors.New("GlobalLock failed")
+	}
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(ptr)), len(data)), data)
+	procGlobalUnlock.Call(hMem)
+
+	// On success the system owns the memory; free it only on failure
+	if ret, _, _ := procSetClipboardData.Call(format, hMem); ret == 0 {
+		procGlobalFree.Call(hMem)
+		return errors.New("SetClipboardData failed")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package screenshot
 
 import (
+	"image"
 	"time"
 	"unsafe"
 
@@ -21,12 +22,16 @@ var (
 	procShowWindow             = user32Win.NewProc("ShowWindow")
 	procIsIconic               = user32Win.NewProc("IsIconic")
 	procGetCursorPos           = user32Win.NewProc("GetCursorPos")
+	procMonitorFromPoint       = user32Win.NewProc("MonitorFromPoint")
+	procGetDpiForMonitor       = shcore.NewProc("GetDpiForMonitor")
 )
 
 const (
 	DWMWA_EXTENDED_FRAME_BOUNDS   = 9
 	PROCESS_PER_MONITOR_DPI_AWARE = 2
 	SW_RESTORE                    = 9
+	MONITOR_DEFAULTTONEAREST      = 2
+	MDT_EFFECTIVE_DPI             = 0
 )
 
 type RECT struct {
@@ -132,3 +137,38 @@ func GetMonitorAtCursor() int {
 	// Fallback to primary display
 	return 0
 }
+
+// GetMonitorAt returns the display index containing the point (x, y)
+// Returns 0 (primary display) if no display contains it
+func GetMonitorAt(x, y int) int {
+	numDisplays := GetDisplayCount()
+	for i := 0; i < numDisplays; i++ {
+		if (image.Point{x, y}).In(GetDisplayBounds(i)) {
+			return i
+		}
+	}
+	return 0
+}
+
+// GetScaleAt returns the DPI scale factor (1.0 = 96 DPI) of the monitor nearest to (x, y)
+// Returns 1.0 if the scale cannot be determined (Windows 8 and earlier)
+func GetScaleAt(x, y int) float64 {
+	if shcore.Load() != nil || procGetDpiForMonitor.Find() != nil {
+		return 1.0
+	}
+
+	// MonitorFromPoint takes the POINT struct by value, packed into one 64-bit argument
+	pt := uintptr(uint32(int32(x))) | uintptr(uint32(int32(y)))<<32
+	monitor, _, _ := procMonitorFromPoint.Call(pt, MONITOR_DEFAULTTONEAREST)
+	if monitor == 0 {
+		return 1.0
+	}
+
+	var dpiX, dpiY uint32
+	ret, _, _ := procGetDpiForMonitor.Call(monitor, MDT_EFFECTIVE_DPI,
+		uintptr(unsafe.Pointer(&dpiX)), uintptr(unsafe.Pointer(&dpiY)))
+	if ret != 0 || dpiX == 0 {
+		return 1.0
+	}
+	return float64(dpiX) / 96.0
+}
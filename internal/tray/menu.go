@@ -0,0 +1,226 @@
+package tray
+
+// Menu item IDs
+const (
+	MenuShow       = 1001
+	MenuFullscreen = 1002
+	MenuRegion     = 1003
+	MenuWindow     = 1004
+	MenuSettings   = 1005 // Shown as "Fix Hotkeys..." while some hotkeys failed to register
+	MenuQuit       = 1006
+	MenuLibrary    = 1007 // Library window trigger (left-click on tray)
+	MenuClipboard  = 1008 // Toggle the clipboard history watcher
+)
+
+// Menu item IDs of the menu's dynamic parts. The actions of recent capture i
+// are MenuRecentFirst + i*menuRecentStride + its RecentAction, upload target
+// i is MenuUploadFirst + i and notification action i is MenuActionFirst + i.
+const (
+	MenuPauseHotkeys = 1009 // Toggle all hotkeys off and on
+	MenuRecentFirst  = 2000
+	MenuUploadFirst  = 3000
+	MenuActionFirst  = 4000
+
+	menuRecentStride = 10
+	menuBlock        = 1000 // IDs reserved for each dynamic part
+)
+
+// MaxRecentCaptures is how many recent captures the menu lists
+const MaxRecentCaptures = 8
+
+// maxLabelLength is how many characters of a capture's name the menu shows
+const maxLabelLength = 40
+
+// RecentAction is what a recent capture's submenu does with it
+type RecentAction int
+
+const (
+	RecentOpen    RecentAction = iota // Open it in the editor
+	RecentCopy                        // Copy the image
+	RecentCopyURL                     // Copy its link, uploading it to the selected target first if needed
+	recentActionCount
+)
+
+// RecentCapture is a saved capture listed in the menu
+type RecentCapture struct {
+	Path string // Saved file
+	Name string // Shown in the menu
+	URL  string // Public link if it was uploaded
+}
+
+// UploadTarget is an upload destination the menu can select
+type UploadTarget struct {
+	ID         string // e.g. "r2"
+	Name       string // Shown in the menu
+	Configured bool   // Targets without credentials can't be selected
+}
+
+// MenuState is everything the tray menu shows that changes
+type MenuState struct {
+	Recent         []RecentCapture // Newest first
+	UploadTargets  []UploadTarget
+	UploadTarget   string // ID of the selected upload target
+	ClipboardWatch bool
+	HotkeysPaused  bool
+	HotkeyWarning  string // Why some hotkeys don't work; empty if all registered
+}
+
+// MenuItem is an entry of the tray menu. An item with Items opens a submenu
+// and has no ID; one with neither an ID nor a label is a separator.
+type MenuItem struct {
+	ID       int
+	Label    string
+	Checked  bool
+	Disabled bool
+	Items    []MenuItem
+}
+
+// Separator reports whether the item is a separator line
+func (m MenuItem) Separator() bool {
+	return m.ID == 0 && m.Label == "" && m.Items == nil
+}
+
+// BuildMenu returns the tray menu for state
+func BuildMenu(state MenuState) []MenuItem {
+	items := []MenuItem{
+		{ID: MenuShow, Label: "Show WinShot"},
+		{},
+		{ID: MenuFullscreen, Label: "Capture Fullscreen"},
+		{ID: MenuRegion, Label: "Capture Region"},
+		{ID: MenuWindow, Label: "Capture Window"},
+		{},
+		{Label: "Recent Captures", Items: recentItems(state)},
+	}
+	if len(state.UploadTargets) > 0 {
+		items = append(items, MenuItem{Label: "Upload To", Items: uploadItems(state)})
+	}
+
+	settings := MenuItem{ID: MenuSettings, Label: "Settings..."}
+	if state.HotkeyWarning != "" {
+		settings.Label = "Fix Hotkeys..."
+	}
+	return append(items,
+		MenuItem{},
+		MenuItem{ID: MenuClipboard, Label: "Watch Clipboard", Checked: state.ClipboardWatch},
+		MenuItem{ID: MenuPauseHotkeys, Label: "Pause Hotkeys", Checked: state.HotkeysPaused},
+		MenuItem{},
+		MenuItem{ID: MenuLibrary, Label: "Library..."},
+		settings,
+		MenuItem{},
+		MenuItem{ID: MenuQuit, Label: "Quit"},
+	)
+}
+
+// recentItems returns a submenu of actions for each recent capture
+func recentItems(state MenuState) []MenuItem {
+	if len(state.Recent) == 0 {
+		return []MenuItem{{Label: "No recent captures", Disabled: true}}
+	}
+
+	uploadable := canUpload(state)
+	var items []MenuItem
+	for i, capture := range state.Recent {
+		if i == MaxRecentCaptures {
+			break
+		}
+		copyURL := MenuItem{ID: RecentID(i, RecentCopyURL), Label: "Copy Link"}
+		if capture.URL == "" {
+			copyURL.Label = "Upload & Copy Link"
+			copyURL.Disabled = !uploadable
+		}
+		items = append(items, MenuItem{
+			Label: shorten(capture.Name, maxLabelLength),
+			Items: []MenuItem{
+				{ID: RecentID(i, RecentOpen), Label: "Open in Editor"},
+				{ID: RecentID(i, RecentCopy), Label: "Copy Image"},
+				copyURL,
+			},
+		})
+	}
+	return items
+}
+
+// uploadItems returns the upload targets to choose from, the selected one checked
+func uploadItems(state MenuState) []MenuItem {
+	items := make([]MenuItem, len(state.UploadTargets))
+	for i, target := range state.UploadTargets {
+		items[i] = MenuItem{
+			ID:       MenuUploadFirst + i,
+			Label:    target.Name,
+			Checked:  target.ID == state.UploadTarget,
+			Disabled: !target.Configured,
+		}
+		if !target.Configured {
+			items[i].Label += " (not set up)"
+		}
+	}
+	return items
+}
+
+// canUpload reports whether the selected upload target is set up
+func canUpload(state MenuState) bool {
+	for _, target := range state.UploadTargets {
+		if target.ID == state.UploadTarget {
+			return target.Configured
+		}
+	}
+	return false
+}
+
+// RecentID returns the menu ID of an action on recent capture i
+func RecentID(i int, action RecentAction) int {
+	return MenuRecentFirst + i*menuRecentStride + int(action)
+}
+
+// RecentItem returns the recent capture, as an index into MenuState.Recent,
+// and the action a menu ID is for
+func RecentItem(id int) (int, RecentAction, bool) {
+	n := id - MenuRecentFirst
+	if n < 0 || n >= MaxRecentCaptures*menuRecentStride {
+		return 0, 0, false
+	}
+	action := RecentAction(n % menuRecentStride)
+	if action >= recentActionCount {
+		return 0, 0, false
+	}
+	return n / menuRecentStride, action, true
+}
+
+// UploadItem returns the upload target, as an index into
+// MenuState.UploadTargets, a menu ID selects
+func UploadItem(id int) (int, bool) {
+	n := id - MenuUploadFirst
+	if n < 0 || n >= menuBlock {
+		return 0, false
+	}
+	return n, true
+}
+
+// ActionMenu returns the menu shown when a notification is clicked, one item
+// per action label
+func ActionMenu(labels []string) []MenuItem {
+	items := make([]MenuItem, len(labels))
+	for i, label := range labels {
+		items[i] = MenuItem{ID: MenuActionFirst + i, Label: label}
+	}
+	return items
+}
+
+// ActionItem returns the action, as an index into ActionMenu's labels, a menu
+// ID picks
+func ActionItem(id int) (int, bool) {
+	n := id - MenuActionFirst
+	if n < 0 || n >= menuBlock {
+		return 0, false
+	}
+	return n, true
+}
+
+// shorten cuts s to max characters, ending it with "..." if it was longer
+func shorten(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-3]) + "..."
+}
@@ -0,0 +1,174 @@
+package tray
+
+import (
+	"strings"
+	"testing"
+)
+
+// find returns the item labeled label at the top level of items
+func find(t *testing.T, items []MenuItem, label string) MenuItem {
+	t.Helper()
+	for _, item := range items {
+		if item.Label == label {
+			return item
+		}
+	}
+	t.Fatalf("no %q item", label)
+	return MenuItem{}
+}
+
+func TestBuildMenu_Fixed(t *testing.T) {
+	items := BuildMenu(MenuState{ClipboardWatch: true})
+
+	if items[0].ID != MenuShow || items[len(items)-1].ID != MenuQuit {
+		t.Errorf("menu runs from %q to %q, want Show to Quit", items[0].Label, items[len(items)-1].Label)
+	}
+	if !find(t, items, "Watch Clipboard").Checked {
+		t.Error("Watch Clipboard not checked")
+	}
+	if find(t, items, "Pause Hotkeys").Checked {
+		t.Error("Pause Hotkeys checked")
+	}
+	if find(t, items, "Settings...").ID != MenuSettings || find(t, items, "Library...").ID != MenuLibrary {
+		t.Error("Settings and Library have the wrong IDs")
+	}
+	for _, item := range items {
+		if item.Label == "Upload To" {
+			t.Error("Upload To shown without upload targets")
+		}
+	}
+
+	// Separators never sit next to each other or at the ends
+	for i, item := range items {
+		if item.Separator() && (i == 0 || i == len(items)-1 || items[i-1].Separator()) {
+			t.Errorf("stray separator at %d", i)
+		}
+	}
+}
+
+func TestBuildMenu_HotkeyWarning(t *testing.T) {
+	items := BuildMenu(MenuState{HotkeyWarning: "PrintScreen is taken", HotkeysPaused: true})
+	if find(t, items, "Fix Hotkeys...").ID != MenuSettings {
+		t.Error("Fix Hotkeys... doesn't open settings")
+	}
+	if !find(t, items, "Pause Hotkeys").Checked {
+		t.Error("Pause Hotkeys not checked")
+	}
+}
+
+func TestBuildMenu_Recent(t *testing.T) {
+	state := MenuState{
+		Recent: []RecentCapture{
+			{Path: `C:\shots\a.png`, Name: "a.png", URL: "https://example.com/a.png"},
+			{Path: `C:\shots\b.png`, Name: "b.png"},
+		},
+		UploadTargets: []UploadTarget{{ID: "r2", Name: "Cloudflare R2", Configured: true}},
+		UploadTarget:  "r2",
+	}
+
+	recent := find(t, BuildMenu(state), "Recent Captures").Items
+	if len(recent) != 2 || recent[0].Label != "a.png" || recent[1].Label != "b.png" {
+		t.Fatalf("recent captures = %+v", recent)
+	}
+	for i, capture := range recent {
+		for action, item := range capture.Items {
+			index, gotAction, ok := RecentItem(item.ID)
+			if !ok || index != i || gotAction != RecentAction(action) {
+				t.Errorf("%s %q: RecentItem(%d) = %d, %d, %v", capture.Label, item.Label, item.ID, index, gotAction, ok)
+			}
+		}
+	}
+	if got := recent[0].Items[2]; got.Label != "Copy Link" || got.Disabled {
+		t.Errorf("uploaded capture's link item = %+v", got)
+	}
+	if got := recent[1].Items[2]; got.Label != "Upload & Copy Link" || got.Disabled {
+		t.Errorf("capture to upload's link item = %+v", got)
+	}
+
+	// Without a usable target there's nowhere to upload to
+	state.UploadTargets[0].Configured = false
+	recent = find(t, BuildMenu(state), "Recent Captures").Items
+	if !recent[1].Items[2].Disabled || recent[0].Items[2].Disabled {
+		t.Error("link items not disabled for the capture that needs uploading only")
+	}
+}
+
+func TestBuildMenu_RecentLimits(t *testing.T) {
+	recent := find(t, BuildMenu(MenuState{}), "Recent Captures").Items
+	if len(recent) != 1 || !recent[0].Disabled || recent[0].ID != 0 {
+		t.Errorf("empty recent captures = %+v", recent)
+	}
+
+	var state MenuState
+	for i := 0; i < MaxRecentCaptures+3; i++ {
+		state.Recent = append(state.Recent, RecentCapture{Name: strings.Repeat("x", 60)})
+	}
+	recent = find(t, BuildMenu(state), "Recent Captures").Items
+	if len(recent) != MaxRecentCaptures {
+		t.Errorf("listed %d recent captures, want %d", len(recent), MaxRecentCaptures)
+	}
+	if got := recent[0].Label; len(got) != maxLabelLength || !strings.HasSuffix(got, "...") {
+		t.Errorf("long name shown as %q", got)
+	}
+}
+
+func TestBuildMenu_UploadTargets(t *testing.T) {
+	state := MenuState{
+		UploadTargets: []UploadTarget{
+			{ID: "r2", Name: "Cloudflare R2", Configured: true},
+			{ID: "gdrive", Name: "Google Drive"},
+		},
+		UploadTarget: "r2",
+	}
+	targets := find(t, BuildMenu(state), "Upload To").Items
+	if len(targets) != 2 {
+		t.Fatalf("upload targets = %+v", targets)
+	}
+	if !targets[0].Checked || targets[0].Disabled {
+		t.Errorf("selected target = %+v", targets[0])
+	}
+	if targets[1].Checked || !targets[1].Disabled || targets[1].Label != "Google Drive (not set up)" {
+		t.Errorf("unconfigured target = %+v", targets[1])
+	}
+	for i, target := range targets {
+		if index, ok := UploadItem(target.ID); !ok || index != i {
+			t.Errorf("UploadItem(%d) = %d, %v; want %d", target.ID, index, ok, i)
+		}
+	}
+}
+
+func TestMenuIDs_DontOverlap(t *testing.T) {
+	fixed := []int{MenuShow, MenuFullscreen, MenuRegion, MenuWindow, MenuSettings, MenuQuit, MenuLibrary, MenuClipboard, MenuPauseHotkeys}
+	for _, id := range fixed {
+		if _, _, ok := RecentItem(id); ok {
+			t.Errorf("RecentItem(%d) matched a fixed item", id)
+		}
+		if _, ok := UploadItem(id); ok {
+			t.Errorf("UploadItem(%d) matched a fixed item", id)
+		}
+		if _, ok := ActionItem(id); ok {
+			t.Errorf("ActionItem(%d) matched a fixed item", id)
+		}
+	}
+	if _, _, ok := RecentItem(RecentID(0, recentActionCount)); ok {
+		t.Error("RecentItem matched an ID between two captures")
+	}
+	if _, ok := UploadItem(RecentID(MaxRecentCaptures-1, RecentCopyURL)); ok {
+		t.Error("UploadItem matched a recent capture")
+	}
+}
+
+func TestActionMenu(t *testing.T) {
+	items := ActionMenu([]string{"Open", "Show in folder"})
+	if len(items) != 2 || items[1].Label != "Show in folder" {
+		t.Fatalf("ActionMenu() = %+v", items)
+	}
+	for i, item := range items {
+		if index, ok := ActionItem(item.ID); !ok || index != i {
+			t.Errorf("ActionItem(%d) = %d, %v; want %d", item.ID, index, ok, i)
+		}
+	}
+	if _, ok := ActionItem(MenuUploadFirst); ok {
+		t.Error("ActionItem matched an upload target")
+	}
+}
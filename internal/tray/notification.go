@@ -0,0 +1,182 @@
+//go:build windows
+
+package tray
+
+import (
+	"errors"
+	"image"
+	"syscall"
+	"unsafe"
+
+	"winshot/internal/notify"
+)
+
+var (
+	gdi32                  = syscall.NewLazyDLL("gdi32.dll")
+	procCreateDIBSection   = gdi32.NewProc("CreateDIBSection")
+	procCreateBitmap       = gdi32.NewProc("CreateBitmap")
+	procDeleteObject       = gdi32.NewProc("DeleteObject")
+	procCreateIconIndirect = user32.NewProc("CreateIconIndirect")
+	procGetSystemMetrics   = user32.NewProc("GetSystemMetrics")
+)
+
+const (
+	NIIF_USER       = 0x00000004
+	NIIF_LARGE_ICON = 0x00000020
+
+	NIN_BALLOONUSERCLICK = WM_USER + 5
+
+	SM_CXICON      = 11
+	BI_RGB         = 0
+	DIB_RGB_COLORS = 0
+)
+
+// BITMAPINFOHEADER for the thumbnail DIB
+type BITMAPINFOHEADER struct {
+	BiSize          uint32
+	BiWidth         int32
+	BiHeight        int32
+	BiPlanes        uint16
+	BiBitCount      uint16
+	BiCompression   uint32
+	BiSizeImage     uint32
+	BiXPelsPerMeter int32
+	BiYPelsPerMeter int32
+	BiClrUsed       uint32
+	BiClrImportant  uint32
+}
+
+// ICONINFO for CreateIconIndirect
+type ICONINFO struct {
+	FIcon    int32
+	XHotspot uint32
+	YHotspot uint32
+	HbmMask  uintptr
+	HbmColor uintptr
+}
+
+// Notifications returns a notify backend showing notifications as balloons
+// from the tray icon, which Windows 10 and later show as toasts. Balloons have
+// no buttons, so clicking one lists its actions in a menu instead.
+func (t *TrayIcon) Notifications() notify.Backend {
+	return balloons{t}
+}
+
+// SetNotificationHandler sets fn to run the action picked from a clicked notification
+func (t *TrayIcon) SetNotificationHandler(fn func(id int, action string)) {
+	t.notifyMu.Lock()
+	t.onNotification = fn
+	t.notifyMu.Unlock()
+}
+
+// balloons is the notify backend of a tray icon
+type balloons struct {
+	t *TrayIcon
+}
+
+// Show shows n as a balloon with its thumbnail as the large icon
+func (b balloons) Show(n notify.Notification) error {
+	t := b.t
+	nid, visible := t.iconData()
+	if !visible {
+		return errors.New("tray icon not shown")
+	}
+
+	nid.UFlags |= NIF_INFO
+	copyUTF16(nid.SzInfoTitle[:], n.Title)
+	copyUTF16(nid.SzInfo[:], n.Message)
+	nid.DwInfoFlags = NIIF_INFO
+	var hIcon uintptr
+	switch {
+	case n.Failed:
+		nid.DwInfoFlags = NIIF_ERROR
+	case n.Thumbnail != nil:
+		if hIcon = createIcon(notify.Thumbnail(n.Thumbnail, iconSize())); hIcon != 0 {
+			nid.HBalloonIcon = hIcon
+			nid.DwInfoFlags = NIIF_USER | NIIF_LARGE_ICON
+		}
+	}
+
+	t.notifyMu.Lock()
+	oldIcon := t.balloonIcon
+	t.notification, t.balloonIcon = n, hIcon
+	t.notifyMu.Unlock()
+
+	ret, _, _ := procShell_NotifyIconW.Call(NIM_MODIFY, uintptr(unsafe.Pointer(&nid)))
+	if oldIcon != 0 {
+		procDestroyIcon.Call(oldIcon)
+	}
+	if ret == 0 {
+		return errors.New("failed to show notification")
+	}
+	return nil
+}
+
+// notificationClicked runs the action of the last notification, picked from a
+// menu at the cursor if it has more than one
+func (t *TrayIcon) notificationClicked() {
+	t.notifyMu.Lock()
+	n, handler := t.notification, t.onNotification
+	t.notifyMu.Unlock()
+	if handler == nil || len(n.Actions) == 0 {
+		return
+	}
+	if len(n.Actions) == 1 {
+		handler(n.ID, n.Actions[0].ID)
+		return
+	}
+
+	labels := make([]string, len(n.Actions))
+	for i, action := range n.Actions {
+		labels[i] = action.Label
+	}
+	if i, ok := ActionItem(t.trackMenu(ActionMenu(labels))); ok && i < len(n.Actions) {
+		handler(n.ID, n.Actions[i].ID)
+	}
+}
+
+// iconSize returns the size of a large balloon icon
+func iconSize() int {
+	size, _, _ := procGetSystemMetrics.Call(SM_CXICON)
+	return max(int(size), 32)
+}
+
+// createIcon converts img to an icon with an alpha channel, or returns 0 on failure
+func createIcon(img *image.RGBA) uintptr {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	bi := BITMAPINFOHEADER{
+		BiSize:        uint32(unsafe.Sizeof(BITMAPINFOHEADER{})),
+		BiWidth:       int32(w),
+		BiHeight:      -int32(h), // Top-down
+		BiPlanes:      1,
+		BiBitCount:    32,
+		BiCompression: BI_RGB,
+	}
+	var bits unsafe.Pointer
+	hbmColor, _, _ := procCreateDIBSection.Call(0, uintptr(unsafe.Pointer(&bi)), DIB_RGB_COLORS, uintptr(unsafe.Pointer(&bits)), 0, 0)
+	if hbmColor == 0 {
+		return 0
+	}
+	defer procDeleteObject.Call(hbmColor)
+
+	// RGBA to BGRA
+	pix := unsafe.Slice((*byte)(bits), w*h*4)
+	for y := 0; y < h; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+w*4]
+		for x := 0; x < w*4; x += 4 {
+			i := y*w*4 + x
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = row[x+2], row[x+1], row[x], row[x+3]
+		}
+	}
+
+	// An all-zero mask leaves transparency to the alpha channel
+	hbmMask, _, _ := procCreateBitmap.Call(uintptr(w), uintptr(h), 1, 1, 0)
+	if hbmMask == 0 {
+		return 0
+	}
+	defer procDeleteObject.Call(hbmMask)
+
+	info := ICONINFO{FIcon: 1, HbmMask: hbmMask, HbmColor: hbmColor}
+	hIcon, _, _ := procCreateIconIndirect.Call(uintptr(unsafe.Pointer(&info)))
+	return hIcon
+}
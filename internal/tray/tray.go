@@ -1,9 +1,15 @@
+//go:build windows
+
 package tray
 
 import (
 	"os"
+	"strings"
+	"sync"
 	"syscall"
 	"unsafe"
+
+	"winshot/internal/notify"
 )
 
 var (
@@ -43,6 +49,9 @@ const (
 	NIF_TIP     = 0x00000004
 	NIF_INFO    = 0x00000010
 
+	NIIF_INFO  = 0x00000001
+	NIIF_ERROR = 0x00000003
+
 	WM_USER          = 0x0400
 	WM_TRAYICON      = WM_USER + 1
 	WM_LBUTTONUP     = 0x0202
@@ -52,6 +61,9 @@ const (
 	WM_QUIT          = 0x0012
 
 	MF_STRING    = 0x00000000
+	MF_GRAYED    = 0x00000001
+	MF_CHECKED   = 0x00000008
+	MF_POPUP     = 0x00000010
 	MF_SEPARATOR = 0x00000800
 
 	TPM_LEFTALIGN   = 0x0000
@@ -71,17 +83,6 @@ const (
 	PM_REMOVE = 0x0001
 )
 
-// Menu item IDs
-const (
-	MenuShow       = 1001
-	MenuFullscreen = 1002
-	MenuRegion     = 1003
-	MenuWindow     = 1004
-	MenuSettings   = 1005
-	MenuQuit       = 1006
-	MenuLibrary    = 1007 // Library window trigger (left-click on tray)
-)
-
 // NOTIFYICONDATAW structure
 type NOTIFYICONDATAW struct {
 	CbSize           uint32
@@ -138,14 +139,26 @@ type TrayMenuCallback func(menuID int)
 // TrayIcon represents the system tray icon
 type TrayIcon struct {
 	hwnd     uintptr
-	nid      NOTIFYICONDATAW
+	nid      NOTIFYICONDATAW // Protected by nidMu
 	hIcon    uintptr
 	tooltip  string
-	visible  bool
+	visible  bool // Protected by nidMu
+	nidMu    sync.Mutex
 	callback TrayMenuCallback
 	onShow   func()
 	running  bool
 	stopCh   chan struct{}
+
+	clipboardWatch bool   // Check state of the clipboard watcher menu item
+	hotkeyWarning  string // Why some hotkeys don't work; empty if all registered
+	hotkeysPaused  bool   // Check state of the pause hotkeys menu item
+
+	menuSource func(state *MenuState) // Fills in recent captures and upload targets
+
+	notifyMu       sync.Mutex
+	notification   notify.Notification         // Last balloon shown, whose actions a click lists
+	balloonIcon    uintptr                     // Thumbnail icon of the last balloon
+	onNotification func(id int, action string) // Runs the action picked from a clicked balloon
 }
 
 // Global tray instance for window proc callback
@@ -172,6 +185,31 @@ func (t *TrayIcon) SetOnShow(cb func()) {
 	t.onShow = cb
 }
 
+// SetClipboardWatch sets the check mark of the "Watch Clipboard" menu item
+func (t *TrayIcon) SetClipboardWatch(enabled bool) {
+	t.clipboardWatch = enabled
+}
+
+// SetHotkeysPaused sets the check mark of the "Pause Hotkeys" menu item
+func (t *TrayIcon) SetHotkeysPaused(paused bool) {
+	t.hotkeysPaused = paused
+}
+
+// SetMenuSource sets fn to fill in the recent captures and upload targets each
+// time the menu opens
+func (t *TrayIcon) SetMenuSource(fn func(state *MenuState)) {
+	t.menuSource = fn
+}
+
+// SetHotkeyWarning reports hotkeys that failed to register with a balloon and a
+// "Fix Hotkeys..." menu item. An empty message clears the warning.
+func (t *TrayIcon) SetHotkeyWarning(message string) {
+	t.hotkeyWarning = message
+	if message != "" {
+		t.ShowBalloon("Some hotkeys aren't working", message, true)
+	}
+}
+
 // Start initializes and shows the tray icon
 func (t *TrayIcon) Start() error {
 	go t.run()
@@ -234,6 +272,7 @@ func (t *TrayIcon) run() {
 	}
 
 	// Initialize NOTIFYICONDATAW
+	t.nidMu.Lock()
 	t.nid = NOTIFYICONDATAW{
 		CbSize:           uint32(unsafe.Sizeof(NOTIFYICONDATAW{})),
 		HWnd:             t.hwnd,
@@ -251,12 +290,18 @@ func (t *TrayIcon) run() {
 
 	// Add tray icon
 	ret, _, _ := procShell_NotifyIconW.Call(NIM_ADD, uintptr(unsafe.Pointer(&t.nid)))
+	t.visible = ret != 0
+	t.nidMu.Unlock()
 	if ret == 0 {
 		return
 	}
-	t.visible = true
 	t.running = true
 
+	// Hotkeys are registered before the icon exists; report failures now
+	if t.hotkeyWarning != "" {
+		t.ShowBalloon("Some hotkeys aren't working", t.hotkeyWarning, true)
+	}
+
 	// Message loop - simple blocking loop without select
 	var msg MSG
 	for t.running {
@@ -305,6 +350,11 @@ func trayWndProc(hwnd uintptr, msg uint32, wParam, lParam uintptr) uintptr {
 			if globalTray != nil && globalTray.onShow != nil {
 				globalTray.onShow()
 			}
+		case NIN_BALLOONUSERCLICK:
+			// Clicked notification - list its actions
+			if globalTray != nil {
+				globalTray.notificationClicked()
+			}
 		}
 		return 0
 	}
@@ -314,20 +364,38 @@ func trayWndProc(hwnd uintptr, msg uint32, wParam, lParam uintptr) uintptr {
 }
 
 func (t *TrayIcon) showMenu() {
-	// Create popup menu
+	state := MenuState{
+		ClipboardWatch: t.clipboardWatch,
+		HotkeysPaused:  t.hotkeysPaused,
+		HotkeyWarning:  t.hotkeyWarning,
+	}
+	if t.menuSource != nil {
+		t.menuSource(&state)
+	}
+
+	// Handle menu selection
+	switch cmd := t.trackMenu(BuildMenu(state)); cmd {
+	case 0:
+	case MenuShow:
+		if t.onShow != nil {
+			t.onShow()
+		}
+	default:
+		if t.callback != nil {
+			t.callback(cmd)
+		}
+	}
+}
+
+// trackMenu shows items as a popup menu at the cursor and returns the ID of
+// the picked item, or 0 if the menu was dismissed
+func (t *TrayIcon) trackMenu(items []MenuItem) int {
 	hMenu, _, _ := procCreatePopupMenu.Call()
 	if hMenu == 0 {
-		return
+		return 0
 	}
-
-	// Add menu items
-	appendMenu(hMenu, MF_STRING, MenuShow, "Show WinShot")
-	appendMenu(hMenu, MF_SEPARATOR, 0, "")
-	appendMenu(hMenu, MF_STRING, MenuFullscreen, "Capture Fullscreen")
-	appendMenu(hMenu, MF_STRING, MenuRegion, "Capture Region")
-	appendMenu(hMenu, MF_STRING, MenuWindow, "Capture Window")
-	appendMenu(hMenu, MF_SEPARATOR, 0, "")
-	appendMenu(hMenu, MF_STRING, MenuQuit, "Quit")
+	defer procDestroyMenu.Call(hMenu)
+	appendItems(hMenu, items)
 
 	// Get cursor position
 	var pt POINT
@@ -336,7 +404,6 @@ func (t *TrayIcon) showMenu() {
 	// Set foreground window (required for menu to work properly)
 	procSetForegroundWindow.Call(t.hwnd)
 
-	// Show menu and get selection
 	cmd, _, _ := procTrackPopupMenu.Call(
 		hMenu,
 		TPM_RETURNCMD|TPM_RIGHTBUTTON,
@@ -346,36 +413,50 @@ func (t *TrayIcon) showMenu() {
 		t.hwnd,
 		0,
 	)
+	return int(cmd)
+}
 
-	// Handle menu selection
-	if cmd != 0 {
-		switch int(cmd) {
-		case MenuShow:
-			if t.onShow != nil {
-				t.onShow()
-			}
-		case MenuQuit:
-			if t.callback != nil {
-				t.callback(MenuQuit)
+// appendItems adds items to hMenu, creating their submenus. Destroying hMenu
+// destroys the submenus too.
+func appendItems(hMenu uintptr, items []MenuItem) {
+	for _, item := range items {
+		switch {
+		case item.Separator():
+			appendMenu(hMenu, MF_SEPARATOR, 0, "")
+		case item.Items != nil:
+			hSubMenu, _, _ := procCreatePopupMenu.Call()
+			if hSubMenu == 0 {
+				continue
 			}
+			appendItems(hSubMenu, item.Items)
+			procAppendMenuW.Call(hMenu, MF_POPUP, hSubMenu, uintptr(unsafe.Pointer(menuText(item.Label))))
 		default:
-			if t.callback != nil {
-				t.callback(int(cmd))
+			flags := MF_STRING
+			if item.Checked {
+				flags |= MF_CHECKED
+			}
+			if item.Disabled {
+				flags |= MF_GRAYED
 			}
+			appendMenu(hMenu, flags, item.ID, item.Label)
 		}
 	}
-
-	// Destroy menu
-	procDestroyMenu.Call(hMenu)
 }
 
 func appendMenu(hMenu uintptr, flags, id int, text string) {
-	textPtr := syscall.StringToUTF16Ptr(text)
-	procAppendMenuW.Call(hMenu, uintptr(flags), uintptr(id), uintptr(unsafe.Pointer(textPtr)))
+	procAppendMenuW.Call(hMenu, uintptr(flags), uintptr(id), uintptr(unsafe.Pointer(menuText(text))))
+}
+
+// menuText converts a label for a menu, doubling "&" so it isn't taken as a
+// keyboard shortcut marker
+func menuText(label string) *uint16 {
+	return syscall.StringToUTF16Ptr(strings.ReplaceAll(label, "&", "&&"))
 }
 
 // Hide hides the tray icon
 func (t *TrayIcon) Hide() error {
+	t.nidMu.Lock()
+	defer t.nidMu.Unlock()
 	if !t.visible {
 		return nil
 	}
@@ -386,6 +467,8 @@ func (t *TrayIcon) Hide() error {
 
 // Show shows the tray icon
 func (t *TrayIcon) Show() error {
+	t.nidMu.Lock()
+	defer t.nidMu.Unlock()
 	if t.visible {
 		return nil
 	}
@@ -396,6 +479,8 @@ func (t *TrayIcon) Show() error {
 
 // SetTooltip updates the tooltip text
 func (t *TrayIcon) SetTooltip(tooltip string) {
+	t.nidMu.Lock()
+	defer t.nidMu.Unlock()
 	t.tooltip = tooltip
 	tip := syscall.StringToUTF16(tooltip)
 	for i := 0; i < len(tip) && i < 127; i++ {
@@ -406,11 +491,57 @@ func (t *TrayIcon) SetTooltip(tooltip string) {
 	}
 }
 
+// ShowBalloon shows a notification balloon (a toast on Windows 10+) from the tray icon
+func (t *TrayIcon) ShowBalloon(title, message string, isError bool) {
+	nid, visible := t.iconData()
+	if !visible {
+		return
+	}
+	nid.UFlags |= NIF_INFO
+	copyUTF16(nid.SzInfoTitle[:], title)
+	copyUTF16(nid.SzInfo[:], message)
+	nid.DwInfoFlags = NIIF_INFO
+	if isError {
+		nid.DwInfoFlags = NIIF_ERROR
+	}
+
+	// A click on this balloon has no actions to list
+	t.notifyMu.Lock()
+	t.notification = notify.Notification{}
+	t.notifyMu.Unlock()
+
+	procShell_NotifyIconW.Call(NIM_MODIFY, uintptr(unsafe.Pointer(&nid)))
+}
+
+// iconData returns a copy of the icon's data to show a balloon with, and
+// whether the icon is shown
+func (t *TrayIcon) iconData() (NOTIFYICONDATAW, bool) {
+	t.nidMu.Lock()
+	defer t.nidMu.Unlock()
+	return t.nid, t.visible
+}
+
+// copyUTF16 copies s into a fixed-size, NUL-terminated buffer, truncating if needed
+func copyUTF16(buf []uint16, s string) {
+	text := syscall.StringToUTF16(s)
+	if len(text) > len(buf) {
+		text = text[:len(buf)]
+		text[len(text)-1] = 0
+	}
+	copy(buf, text)
+}
+
 // Stop removes the tray icon and stops the message loop
 func (t *TrayIcon) Stop() error {
 	if t.running {
 		t.Hide()
 		t.running = false
+		t.notifyMu.Lock()
+		if t.balloonIcon != 0 {
+			procDestroyIcon.Call(t.balloonIcon)
+			t.balloonIcon = 0
+		}
+		t.notifyMu.Unlock()
 		// Signal stop - non-blocking send
 		select {
 		case t.stopCh <- struct{}{}:
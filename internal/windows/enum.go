@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"image"
 	"image/png"
+	"path/filepath"
 	"syscall"
 	"unsafe"
 
@@ -27,6 +28,7 @@ var (
 	procGetWindowDC          = user32.NewProc("GetWindowDC")
 	procReleaseDC            = user32.NewProc("ReleaseDC")
 	procGetDC                = user32.NewProc("GetDC")
+	procGetForegroundWindow  = user32.NewProc("GetForegroundWindow")
 
 	procCreateCompatibleDC     = gdi32.NewProc("CreateCompatibleDC")
 	procCreateCompatibleBitmap = gdi32.NewProc("CreateCompatibleBitmap")
@@ -194,6 +196,35 @@ func GetWindowInfo(hwnd uintptr) (*WindowInfo, error) {
 	}, nil
 }
 
+// GetForegroundWindow returns the handle of the window the user is working in, or 0
+func GetForegroundWindow() uintptr {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	return hwnd
+}
+
+// GetWindowProcessName returns the executable name (e.g. "chrome.exe") of the process owning a window
+// Returns an empty string if the process can't be queried (e.g. elevated processes)
+func GetWindowProcessName(hwnd uintptr) string {
+	var pid uint32
+	if _, err := windows.GetWindowThreadProcessId(windows.HWND(hwnd), &pid); err != nil || pid == 0 {
+		return ""
+	}
+
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(handle)
+
+	buf := make([]uint16, windows.MAX_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(handle, 0, &buf[0], &size); err != nil {
+		return ""
+	}
+
+	return filepath.Base(windows.UTF16ToString(buf[:size]))
+}
+
 // CaptureWindowThumbnail captures a thumbnail of a window
 // Returns base64 encoded PNG image scaled to specified max dimensions
 func CaptureWindowThumbnail(hwnd uintptr, maxWidth, maxHeight int) string {
@@ -0,0 +1,415 @@
+package workflow
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // Background images
+	_ "image/png"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+
+	"winshot/internal/clipformat"
+)
+
+// Style is the editor look applied by beautify steps. It mirrors the editor
+// canvas so a headless capture comes out as it would be exported from the editor.
+type Style struct {
+	Padding         int
+	CornerRadius    int
+	ShadowSize      int
+	Background      string // CSS color, linear-gradient(...) or url(data:...)
+	AutoBackground  bool   // Use the screenshot's dominant edge color instead of Background
+	OutputRatio     string // "auto" or "W:H"
+	Inset           int    // 0-50 percent the screenshot is scaled down within its frame
+	InsetBackground string // Color revealed around an inset screenshot
+	BorderEnabled   bool
+	BorderWeight    int
+	BorderColor     string
+	BorderOpacity   int    // 0-100
+	BorderType      string // "outside", "center" or "inside"
+}
+
+// defaultBackground is the fallback when a background can't be parsed, as in the editor
+var defaultBackground = color.NRGBA{0x1a, 0x1a, 0x2e, 0xff}
+
+// Beautify draws img on a background with the style's padding, rounded corners,
+// drop shadow and border
+func Beautify(img image.Image, style Style) *image.RGBA {
+	srcBounds := img.Bounds()
+	w, h := srcBounds.Dx(), srcBounds.Dy()
+	padding := max(style.Padding, 0)
+	totalW, totalH := OutputSize(w, h, padding, style.OutputRatio)
+
+	dst := image.NewRGBA(image.Rect(0, 0, totalW, totalH))
+	insetBackground := parseColor(style.InsetBackground, defaultBackground)
+	if style.AutoBackground {
+		// The editor uses the edge color for the inset background too
+		insetBackground = EdgeColor(img)
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(insetBackground), image.Point{}, draw.Src)
+	} else {
+		fillBackground(dst, style.Background)
+	}
+
+	// Screenshot frame, centered; inset scales the screenshot (and its shadow,
+	// corners and border) down within it
+	scale := 1 - float64(min(max(style.Inset, 0), 50))/100
+	frameX := float64(totalW-w) / 2
+	frameY := float64(totalH-h) / 2
+	shot := rectF{
+		x: frameX + float64(w)*(1-scale)/2,
+		y: frameY + float64(h)*(1-scale)/2,
+		w: float64(w) * scale,
+		h: float64(h) * scale,
+	}
+	radius := math.Min(float64(max(style.CornerRadius, 0))*scale, math.Min(shot.w, shot.h)/2)
+
+	if style.Inset > 0 && (style.InsetBackground != "" || style.AutoBackground) {
+		frame := rectF{x: frameX, y: frameY, w: float64(w), h: float64(h)}
+		fillMask(dst, roundedRectMask(dst.Bounds(), frame, float64(style.CornerRadius)), insetBackground)
+	}
+
+	if style.ShadowSize > 0 {
+		// Konva's shadow: 50% black, blurred by ShadowSize, offset down by a quarter of it
+		blur := float64(style.ShadowSize) * scale
+		offset := shot
+		offset.y += blur / 4
+		mask := roundedRectMask(dst.Bounds(), offset, radius)
+		blurAlpha(mask, blur/2)
+		fillMask(dst, mask, color.NRGBA{0, 0, 0, 0x80})
+	}
+
+	// Screenshot, scaled if inset and clipped to the rounded rect
+	scaled := image.NewRGBA(image.Rect(0, 0, totalW, totalH))
+	target := image.Rect(int(math.Round(shot.x)), int(math.Round(shot.y)),
+		int(math.Round(shot.x+shot.w)), int(math.Round(shot.y+shot.h)))
+	if scale == 1 {
+		draw.Draw(scaled, target, img, srcBounds.Min, draw.Src)
+	} else {
+		xdraw.CatmullRom.Scale(scaled, target, img, srcBounds, draw.Src, nil)
+	}
+	draw.DrawMask(dst, dst.Bounds(), scaled, image.Point{}, roundedRectMask(dst.Bounds(), shot, radius), image.Point{}, draw.Over)
+
+	if style.BorderEnabled && style.BorderWeight > 0 {
+		weight := float64(style.BorderWeight) * scale
+		var depth float64 // Stroke center, measured inward from the screenshot edge
+		switch style.BorderType {
+		case "outside":
+			depth = -weight / 2
+		case "inside":
+			depth = weight / 2
+		}
+		border := parseColor(style.BorderColor, color.NRGBA{0, 0, 0, 0xff})
+		opacity := float64(min(max(style.BorderOpacity, 0), 100)) / 100
+		border.A = uint8(float64(border.A)*opacity + 0.5)
+		fillMask(dst, strokeMask(dst.Bounds(), shot, radius, depth, weight), border)
+	}
+
+	return dst
+}
+
+// OutputSize returns the canvas size for a w x h screenshot: the screenshot
+// plus padding on every side, widened or heightened to match ratio ("W:H")
+func OutputSize(w, h, padding int, ratio string) (int, int) {
+	minW, minH := w+padding*2, h+padding*2
+	target, ok := parseRatio(ratio)
+	if !ok {
+		return minW, minH
+	}
+	if target > float64(minW)/float64(minH) {
+		return int(math.Round(float64(minH) * target)), minH
+	}
+	return minW, int(math.Round(float64(minW) / target))
+}
+
+// parseRatio parses "W:H"; "auto" and invalid ratios report false
+func parseRatio(ratio string) (float64, bool) {
+	ws, hs, ok := strings.Cut(ratio, ":")
+	if !ok {
+		return 0, false
+	}
+	w, errW := strconv.ParseFloat(ws, 64)
+	h, errH := strconv.ParseFloat(hs, 64)
+	if errW != nil || errH != nil || w <= 0 || h <= 0 {
+		return 0, false
+	}
+	return w / h, true
+}
+
+// rectF is a rectangle with fractional coordinates
+type rectF struct {
+	x, y, w, h float64
+}
+
+// roundedRectDistance is the signed distance from (px, py) to the edge of a
+// rounded rect: negative inside, positive outside
+func roundedRectDistance(r rectF, radius, px, py float64) float64 {
+	cx, cy := r.x+r.w/2, r.y+r.h/2
+	qx := math.Abs(px-cx) - r.w/2 + radius
+	qy := math.Abs(py-cy) - r.h/2 + radius
+	outside := math.Hypot(math.Max(qx, 0), math.Max(qy, 0))
+	return outside + math.Min(math.Max(qx, qy), 0) - radius
+}
+
+// roundedRectMask returns the antialiased coverage of a rounded rect
+func roundedRectMask(bounds image.Rectangle, r rectF, radius float64) *image.Alpha {
+	mask := image.NewAlpha(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			d := roundedRectDistance(r, radius, float64(x)+0.5, float64(y)+0.5)
+			mask.Pix[mask.PixOffset(x, y)] = coverage(0.5 - d)
+		}
+	}
+	return mask
+}
+
+// strokeMask returns the coverage of a stroke of width weight whose center line
+// lies depth pixels inside the rounded rect's edge
+func strokeMask(bounds image.Rectangle, r rectF, radius, depth, weight float64) *image.Alpha {
+	mask := image.NewAlpha(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			inside := -roundedRectDistance(r, radius, float64(x)+0.5, float64(y)+0.5)
+			mask.Pix[mask.PixOffset(x, y)] = coverage(weight/2 + 0.5 - math.Abs(inside-depth))
+		}
+	}
+	return mask
+}
+
+// coverage converts a 0-1 coverage fraction to an alpha value
+func coverage(c float64) uint8 {
+	return uint8(math.Min(math.Max(c, 0), 1)*255 + 0.5)
+}
+
+// fillMask paints c through mask
+func fillMask(dst *image.RGBA, mask *image.Alpha, c color.NRGBA) {
+	draw.DrawMask(dst, dst.Bounds(), image.NewUniform(c), image.Point{}, mask, mask.Bounds().Min, draw.Over)
+}
+
+// blurAlpha approximates a gaussian blur of standard deviation sigma with
+// three box blur passes in each direction
+func blurAlpha(mask *image.Alpha, sigma float64) {
+	if sigma < 0.5 {
+		return
+	}
+	// Box width giving the same variance over three passes
+	radius := int(math.Round((math.Sqrt(4*sigma*sigma+1) - 1) / 2))
+	if radius < 1 {
+		return
+	}
+	w, h := mask.Rect.Dx(), mask.Rect.Dy()
+	line := make([]uint8, max(w, h))
+	for pass := 0; pass < 3; pass++ {
+		for y := 0; y < h; y++ {
+			row := mask.Pix[y*mask.Stride : y*mask.Stride+w]
+			boxBlur(row, line[:w], 1, radius)
+		}
+		for x := 0; x < w; x++ {
+			col := mask.Pix[x : (h-1)*mask.Stride+x+1]
+			boxBlur(col, line[:h], mask.Stride, radius)
+		}
+	}
+}
+
+// boxBlur blurs every stride-th value of data in place, treating values
+// beyond either end as zero. tmp holds one value per element.
+func boxBlur(data, tmp []uint8, stride, radius int) {
+	n := len(tmp)
+	for i := range tmp {
+		tmp[i] = data[i*stride]
+	}
+	window := 2*radius + 1
+	sum := 0
+	for i := 0; i < radius && i < n; i++ {
+		sum += int(tmp[i])
+	}
+	for i := 0; i < n; i++ {
+		if j := i + radius; j < n {
+			sum += int(tmp[j])
+		}
+		if j := i - radius - 1; j >= 0 {
+			sum -= int(tmp[j])
+		}
+		data[i*stride] = uint8((sum + window/2) / window)
+	}
+}
+
+// fillBackground paints a CSS-style background over all of dst
+func fillBackground(dst *image.RGBA, background string) {
+	background = strings.TrimSpace(background)
+	switch {
+	case strings.HasPrefix(background, "url("):
+		if bg := decodeURLImage(background); bg != nil {
+			xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), bg, bg.Bounds(), draw.Src, nil)
+			return
+		}
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(defaultBackground), image.Point{}, draw.Src)
+	case strings.Contains(background, "gradient"):
+		fillGradient(dst, parseGradientStops(background))
+	default:
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(parseColor(background, defaultBackground)), image.Point{}, draw.Src)
+	}
+}
+
+// decodeURLImage decodes the data: URI in a CSS url(...) value
+func decodeURLImage(value string) image.Image {
+	uri := strings.Trim(strings.TrimSuffix(strings.TrimPrefix(value, "url("), ")"), `"'`)
+	data, _, err := clipformat.DecodeDataURI(uri)
+	if err != nil {
+		return nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// gradientStop is a color at a position from 0 to 1
+type gradientStop struct {
+	pos   float64
+	color color.NRGBA
+}
+
+// gradientStopPattern matches "color [position%]" like the editor's parser
+var gradientStopPattern = regexp.MustCompile(`(#[0-9a-fA-F]{6}|#[0-9a-fA-F]{3}|rgba?\([^)]+\))\s*(\d+)?%?`)
+
+// parseGradientStops reads the color stops of a linear-gradient(...) value.
+// Like the editor, the gradient angle is ignored: it always runs corner to corner.
+func parseGradientStops(gradient string) []gradientStop {
+	var stops []gradientStop
+	for _, m := range gradientStopPattern.FindAllStringSubmatch(gradient, -1) {
+		pos := 1.0
+		if m[2] != "" {
+			n, _ := strconv.Atoi(m[2])
+			pos = float64(n) / 100
+		} else if len(stops) == 0 {
+			pos = 0
+		}
+		stops = append(stops, gradientStop{pos: pos, color: parseColor(m[1], defaultBackground)})
+	}
+	if len(stops) == 0 {
+		stops = []gradientStop{
+			{0, color.NRGBA{0x66, 0x7e, 0xea, 0xff}},
+			{1, color.NRGBA{0x76, 0x4b, 0xa2, 0xff}},
+		}
+	}
+	return stops
+}
+
+// fillGradient paints a linear gradient from the top-left to the bottom-right corner
+func fillGradient(dst *image.RGBA, stops []gradientStop) {
+	w, h := float64(dst.Rect.Dx()), float64(dst.Rect.Dy())
+	length := w*w + h*h
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		for x := 0; x < dst.Rect.Dx(); x++ {
+			// Project the pixel onto the diagonal
+			t := ((float64(x)+0.5)*w + (float64(y)+0.5)*h) / length
+			c := gradientAt(stops, t)
+			dst.Set(dst.Rect.Min.X+x, dst.Rect.Min.Y+y, c)
+		}
+	}
+}
+
+// gradientAt interpolates the stops at position t
+func gradientAt(stops []gradientStop, t float64) color.NRGBA {
+	if t <= stops[0].pos {
+		return stops[0].color
+	}
+	for i := 1; i < len(stops); i++ {
+		a, b := stops[i-1], stops[i]
+		if t <= b.pos {
+			f := 0.0
+			if b.pos > a.pos {
+				f = (t - a.pos) / (b.pos - a.pos)
+			}
+			lerp := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*f + 0.5) }
+			return color.NRGBA{lerp(a.color.R, b.color.R), lerp(a.color.G, b.color.G), lerp(a.color.B, b.color.B), lerp(a.color.A, b.color.A)}
+		}
+	}
+	return stops[len(stops)-1].color
+}
+
+// parseColor parses #rgb, #rrggbb, #rrggbbaa, rgb() and rgba() colors,
+// returning fallback for anything else
+func parseColor(value string, fallback color.NRGBA) color.NRGBA {
+	value = strings.TrimSpace(value)
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) == 6 {
+			hex += "ff"
+		}
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if len(hex) != 8 || err != nil {
+			return fallback
+		}
+		return color.NRGBA{uint8(n >> 24), uint8(n >> 16), uint8(n >> 8), uint8(n)}
+	}
+
+	lower := strings.ToLower(value)
+	if !strings.HasPrefix(lower, "rgb") || !strings.HasSuffix(lower, ")") {
+		return fallback
+	}
+	open := strings.Index(lower, "(")
+	parts := strings.Split(lower[open+1:len(lower)-1], ",")
+	if len(parts) != 3 && len(parts) != 4 {
+		return fallback
+	}
+	var channels [4]float64
+	channels[3] = 1
+	for i, part := range parts {
+		n, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return fallback
+		}
+		channels[i] = n
+	}
+	clamp := func(v, limit float64) uint8 { return uint8(math.Min(math.Max(v, 0), limit)*255/limit + 0.5) }
+	return color.NRGBA{clamp(channels[0], 255), clamp(channels[1], 255), clamp(channels[2], 255), clamp(channels[3], 1)}
+}
+
+// EdgeColor returns the most common color along the image's edges, quantized
+// like the editor's auto background
+func EdgeColor(img image.Image) color.NRGBA {
+	const step = 32
+	b := img.Bounds()
+	if b.Empty() {
+		return defaultBackground
+	}
+
+	// Sample about every tenth pixel, as the editor does
+	counts := make(map[color.NRGBA]int)
+	var best color.NRGBA
+	sample := func(x, y int) {
+		c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+		if c.A < 128 {
+			return
+		}
+		q := func(v uint8) uint8 { return uint8(min(int(math.Round(float64(v)/step))*step, 255)) }
+		key := color.NRGBA{q(c.R), q(c.G), q(c.B), 0xff}
+		counts[key]++
+		if counts[key] > counts[best] {
+			best = key
+		}
+	}
+	for x := b.Min.X; x < b.Max.X; x += 10 {
+		sample(x, b.Min.Y)
+		sample(x, b.Max.Y-1)
+	}
+	for y := b.Min.Y + 1; y < b.Max.Y-1; y += 10 {
+		sample(b.Min.X, y)
+		sample(b.Max.X-1, y)
+	}
+
+	if len(counts) == 0 {
+		return defaultBackground
+	}
+	return best
+}
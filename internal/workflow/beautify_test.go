@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestOutputSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		w, h, padding int
+		ratio         string
+		wantW, wantH  int
+	}{
+		{"auto", 100, 50, 10, "auto", 120, 70},
+		{"no padding", 100, 50, 0, "auto", 100, 50},
+		{"wider ratio", 100, 100, 0, "16:9", 178, 100},
+		{"taller ratio", 100, 50, 0, "1:1", 100, 100},
+		{"invalid ratio", 100, 50, 5, "wide", 110, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h := OutputSize(tt.w, tt.h, tt.padding, tt.ratio)
+			if w != tt.wantW || h != tt.wantH {
+				t.Errorf("OutputSize = %dx%d, want %dx%d", w, h, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	fallback := color.NRGBA{1, 2, 3, 4}
+	tests := []struct {
+		in   string
+		want color.NRGBA
+	}{
+		{"#ff0000", color.NRGBA{255, 0, 0, 255}},
+		{"#0f0", color.NRGBA{0, 255, 0, 255}},
+		{"#00000080", color.NRGBA{0, 0, 0, 128}},
+		{"rgb(10, 20, 30)", color.NRGBA{10, 20, 30, 255}},
+		{"rgba(10,20,30,0.5)", color.NRGBA{10, 20, 30, 128}},
+		{"#zzzzzz", fallback},
+		{"red", fallback},
+		{"rgb(1,2)", fallback},
+	}
+	for _, tt := range tests {
+		if got := parseColor(tt.in, fallback); got != tt.want {
+			t.Errorf("parseColor(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseGradientStops(t *testing.T) {
+	stops := parseGradientStops("linear-gradient(135deg, #667eea 0%, #764ba2 100%)")
+	if len(stops) != 2 || stops[0].pos != 0 || stops[1].pos != 1 {
+		t.Fatalf("stops = %+v", stops)
+	}
+	if mid := gradientAt(stops, 0.5); mid.R != 0x6e || mid.B != 0xc6 {
+		t.Errorf("midpoint = %v", mid)
+	}
+}
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func TestBeautify_PadsAndKeepsScreenshot(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	out := Beautify(solid(40, 20, red), Style{Padding: 10, Background: "#0000ff", OutputRatio: "auto"})
+
+	if out.Bounds().Dx() != 60 || out.Bounds().Dy() != 40 {
+		t.Fatalf("size = %v, want 60x40", out.Bounds())
+	}
+	if got := out.RGBAAt(2, 2); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("padding = %v, want the background", got)
+	}
+	if got := out.RGBAAt(30, 20); got != red {
+		t.Errorf("center = %v, want the screenshot", got)
+	}
+	if got := out.RGBAAt(10, 10); got != red {
+		t.Errorf("screenshot corner = %v, want square corners without a radius", got)
+	}
+}
+
+func TestBeautify_RoundsCornersAndDrawsBorder(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	out := Beautify(solid(40, 40, red), Style{
+		Padding:       10,
+		CornerRadius:  10,
+		Background:    "#ffffff",
+		BorderEnabled: true,
+		BorderWeight:  2,
+		BorderColor:   "#00ff00",
+		BorderOpacity: 100,
+		BorderType:    "inside",
+	})
+
+	if got := out.RGBAAt(10, 10); got != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("rounded corner = %v, want the background", got)
+	}
+	if got := out.RGBAAt(30, 10); got.G != 255 || got.R != 0 {
+		t.Errorf("top edge = %v, want the border", got)
+	}
+	if got := out.RGBAAt(30, 30); got != red {
+		t.Errorf("center = %v, want the screenshot", got)
+	}
+}
+
+func TestBeautify_ShadowDarkensBelow(t *testing.T) {
+	out := Beautify(solid(20, 20, color.White), Style{Padding: 20, ShadowSize: 12, Background: "#ffffff"})
+	below := out.RGBAAt(30, 42)
+	above := out.RGBAAt(30, 17)
+	if below.R >= 255 || below.R >= above.R {
+		t.Errorf("shadow below = %v, above = %v; want darker below", below, above)
+	}
+}
+
+func TestBeautify_AutoBackground(t *testing.T) {
+	gray := color.RGBA{128, 128, 128, 255}
+	out := Beautify(solid(30, 30, gray), Style{Padding: 5, Background: "#000000", AutoBackground: true})
+	if got := out.RGBAAt(1, 1); got != gray {
+		t.Errorf("background = %v, want the edge color %v", got, gray)
+	}
+}
@@ -0,0 +1,99 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Placeholders replaced in command arguments
+const (
+	PlaceholderFile = "{file}" // Path of the saved capture (a temporary PNG if unsaved)
+	PlaceholderURL  = "{url}"  // URL of the last upload
+)
+
+// maxCommandOutput caps how much command output is kept in the step result
+const maxCommandOutput = 500
+
+// ExpandArgs replaces the placeholders in args
+func ExpandArgs(args []string, file, url string) []string {
+	replacer := strings.NewReplacer(PlaceholderFile, file, PlaceholderURL, url)
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		expanded[i] = replacer.Replace(arg)
+	}
+	return expanded
+}
+
+// runCommand runs a command step and returns its trimmed output
+func (r *Runner) runCommand(ctx context.Context, img image.Image, step Step, result *Result) (string, error) {
+	if step.Command == "" {
+		return "", errors.New("no command set")
+	}
+
+	file := result.Path
+	if file == "" && usesPlaceholder(step.Args, PlaceholderFile) {
+		// Left in place: the command may hand the file to another process
+		var err error
+		if file, err = writeTempPNG(img); err != nil {
+			return "", err
+		}
+	}
+
+	timeout := r.CommandTimeout
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, step.Command, ExpandArgs(step.Args, file, result.URL)...)
+	hideConsoleWindow(cmd)
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if len(output) > maxCommandOutput {
+		output = output[:maxCommandOutput] + "..."
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return output, fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if output != "" {
+			return output, fmt.Errorf("%w: %s", err, output)
+		}
+		return output, err
+	}
+	return output, nil
+}
+
+// usesPlaceholder reports whether any argument contains placeholder
+func usesPlaceholder(args []string, placeholder string) bool {
+	for _, arg := range args {
+		if strings.Contains(arg, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTempPNG saves img to a new file in the temp directory
+func writeTempPNG(img image.Image) (string, error) {
+	file, err := os.CreateTemp("", "winshot_"+time.Now().Format("2006-01-02_15-04-05")+"_*.png")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	return file.Name(), nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package workflow
+
+import "os/exec"
+
+// hideConsoleWindow is a no-op outside Windows
+func hideConsoleWindow(cmd *exec.Cmd) {}
@@ -0,0 +1,15 @@
+package workflow
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// createNoWindow keeps console programs from opening a console window
+const createNoWindow = 0x08000000
+
+// hideConsoleWindow configures cmd so console programs run without a visible
+// console. GUI programs still show their windows.
+func hideConsoleWindow(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: createNoWindow}
+}
@@ -0,0 +1,174 @@
+// Package workflow runs the after-capture steps bound to a hotkey: beautify,
+// save, copy, upload, notify, run a command, or hand the capture to the editor
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Step types
+const (
+	StepEditor   = "editor"   // Hand the capture and the remaining steps to the editor
+	StepBeautify = "beautify" // Apply the editor's background, padding, shadow and border
+	StepSave     = "save"     // Save to the QuickSave folder
+	StepCopy     = "copy"     // Copy the image to the clipboard
+	StepUpload   = "upload"   // Upload to Step.Provider
+	StepCopyURL  = "copyUrl"  // Copy the URL of the last upload to the clipboard
+	StepNotify   = "notify"   // Show a notification summarizing the results so far
+	StepCommand  = "command"  // Run Step.Command with Step.Args
+)
+
+// DefaultCommandTimeout bounds how long a command step may run
+const DefaultCommandTimeout = 60 * time.Second
+
+// Step is one action of a workflow
+type Step struct {
+	Type     string
+	Provider string   // Upload: "r2" or "gdrive"
+	Command  string   // Command: executable to run
+	Args     []string // Command: arguments; see ExpandArgs for placeholders
+}
+
+// StepResult is the outcome of one step
+type StepResult struct {
+	Type   string `json:"type"`
+	Output string `json:"output,omitempty"` // e.g. the saved path or uploaded URL
+	Error  string `json:"error,omitempty"`
+}
+
+// Result is the outcome of a workflow run
+type Result struct {
+	Steps    []StepResult `json:"steps"`
+	Path     string       `json:"path,omitempty"`     // Where the capture was saved
+	URL      string       `json:"url,omitempty"`      // Where the capture was uploaded
+	Handoff  bool         `json:"handoff,omitempty"`  // An editor step took over the remaining steps
+	Failures int          `json:"failures,omitempty"` // Steps that returned an error
+}
+
+// Runner runs workflows. Each handler performs one kind of step; a step whose
+// handler is nil fails without stopping the workflow.
+type Runner struct {
+	Style Style // Used by beautify steps
+
+	Save       func(img image.Image) (string, error)                        // Returns the saved path
+	CopyImage  func(img image.Image, path string) error                     // path is empty if not saved
+	Upload     func(img image.Image, provider, path string) (string, error) // Returns the public URL
+	CopyText   func(text string) error
+	Notify     func(img image.Image, result Result) error // Shows the results so far
+	OpenEditor func(img image.Image, remaining []Step) error
+
+	CommandTimeout time.Duration // Zero means DefaultCommandTimeout
+}
+
+// errNoHandler is returned for steps the runner has no handler for
+var errNoHandler = errors.New("not supported")
+
+// Run executes steps in order on img. A failing step is recorded and the next
+// step still runs; an editor step ends the run, handing over the steps after it.
+func (r *Runner) Run(ctx context.Context, img image.Image, steps []Step) Result {
+	var result Result
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			break
+		}
+
+		var output string
+		var err error
+		switch step.Type {
+		case StepEditor:
+			if r.OpenEditor == nil {
+				err = errNoHandler
+				break
+			}
+			if err = r.OpenEditor(img, steps[i+1:]); err == nil {
+				result.Handoff = true
+			}
+		case StepBeautify:
+			img = Beautify(img, r.Style)
+		case StepSave:
+			if r.Save == nil {
+				err = errNoHandler
+				break
+			}
+			if output, err = r.Save(img); err == nil {
+				result.Path = output
+			}
+		case StepCopy:
+			if r.CopyImage == nil {
+				err = errNoHandler
+				break
+			}
+			err = r.CopyImage(img, result.Path)
+		case StepUpload:
+			if r.Upload == nil {
+				err = errNoHandler
+				break
+			}
+			if output, err = r.Upload(img, step.Provider, result.Path); err == nil {
+				result.URL = output
+			}
+		case StepCopyURL:
+			switch {
+			case result.URL == "":
+				err = errors.New("nothing was uploaded")
+			case r.CopyText == nil:
+				err = errNoHandler
+			default:
+				output = result.URL
+				err = r.CopyText(result.URL)
+			}
+		case StepNotify:
+			if r.Notify == nil {
+				err = errNoHandler
+				break
+			}
+			err = r.Notify(img, result)
+		case StepCommand:
+			output, err = r.runCommand(ctx, img, step, &result)
+		default:
+			err = fmt.Errorf("unknown step %q", step.Type)
+		}
+
+		stepResult := StepResult{Type: step.Type, Output: output}
+		if err != nil {
+			stepResult.Error = err.Error()
+			result.Failures++
+		}
+		result.Steps = append(result.Steps, stepResult)
+
+		if result.Handoff {
+			break
+		}
+	}
+	return result
+}
+
+// Summary describes the results so far for a notification
+func (r *Result) Summary() (title, message string) {
+	switch {
+	case r.URL != "":
+		title, message = "Screenshot uploaded", r.URL
+	case r.Path != "":
+		title, message = "Screenshot saved", filepath.Base(r.Path)
+	default:
+		title, message = "Screenshot captured", ""
+	}
+
+	if r.Failures > 0 {
+		var failed []string
+		for _, step := range r.Steps {
+			if step.Error != "" {
+				failed = append(failed, step.Type+": "+step.Error)
+			}
+		}
+		title = "Screenshot workflow failed"
+		message = strings.TrimSpace(message + "\n" + strings.Join(failed, "\n"))
+	}
+	return title, message
+}
@@ -0,0 +1,166 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recorder is a Runner whose handlers log their calls
+type recorder struct {
+	calls []string
+}
+
+func (rec *recorder) runner() *Runner {
+	return &Runner{
+		Save: func(img image.Image) (string, error) {
+			rec.calls = append(rec.calls, "save")
+			return `C:\shots\winshot_1.png`, nil
+		},
+		CopyImage: func(img image.Image, path string) error {
+			rec.calls = append(rec.calls, "copy "+path)
+			return nil
+		},
+		Upload: func(img image.Image, provider, path string) (string, error) {
+			rec.calls = append(rec.calls, "upload "+provider)
+			if provider == "bad" {
+				return "", errors.New("not configured")
+			}
+			return "https://cdn.example/winshot_1.png", nil
+		},
+		CopyText: func(text string) error {
+			rec.calls = append(rec.calls, "text "+text)
+			return nil
+		},
+		Notify: func(img image.Image, result Result) error {
+			title, _ := result.Summary()
+			rec.calls = append(rec.calls, "notify "+title)
+			return nil
+		},
+		OpenEditor: func(img image.Image, remaining []Step) error {
+			var types []string
+			for _, step := range remaining {
+				types = append(types, step.Type)
+			}
+			rec.calls = append(rec.calls, "editor "+strings.Join(types, ","))
+			return nil
+		},
+	}
+}
+
+func testImage() image.Image {
+	return image.NewRGBA(image.Rect(0, 0, 4, 3))
+}
+
+func TestRunner_RunsStepsInOrder(t *testing.T) {
+	rec := &recorder{}
+	result := rec.runner().Run(context.Background(), testImage(), []Step{
+		{Type: StepSave},
+		{Type: StepCopy},
+		{Type: StepUpload, Provider: "r2"},
+		{Type: StepCopyURL},
+		{Type: StepNotify},
+	})
+
+	want := []string{
+		"save",
+		`copy C:\shots\winshot_1.png`,
+		"upload r2",
+		"text https://cdn.example/winshot_1.png",
+		"notify Screenshot uploaded",
+	}
+	if !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("calls = %q, want %q", rec.calls, want)
+	}
+	if result.Failures != 0 || len(result.Steps) != 5 {
+		t.Errorf("result = %+v, want 5 successful steps", result)
+	}
+	if result.Path != `C:\shots\winshot_1.png` || result.URL != "https://cdn.example/winshot_1.png" {
+		t.Errorf("path/url = %q/%q", result.Path, result.URL)
+	}
+	if result.Steps[2].Output != result.URL {
+		t.Errorf("upload output = %q, want the URL", result.Steps[2].Output)
+	}
+}
+
+func TestRunner_ContinuesAfterFailedStep(t *testing.T) {
+	rec := &recorder{}
+	result := rec.runner().Run(context.Background(), testImage(), []Step{
+		{Type: StepUpload, Provider: "bad"},
+		{Type: StepCopyURL},
+		{Type: StepNotify},
+		{Type: "teleport"},
+	})
+
+	if result.Failures != 3 {
+		t.Fatalf("Failures = %d, want 3: %+v", result.Failures, result.Steps)
+	}
+	if result.Steps[0].Error != "not configured" {
+		t.Errorf("upload error = %q", result.Steps[0].Error)
+	}
+	if result.Steps[1].Error != "nothing was uploaded" {
+		t.Errorf("copyUrl error = %q", result.Steps[1].Error)
+	}
+	if result.Steps[2].Error != "" || rec.calls[len(rec.calls)-1] != "notify Screenshot workflow failed" {
+		t.Errorf("notify should run and report the failures, calls = %q", rec.calls)
+	}
+	if !strings.Contains(result.Steps[3].Error, "unknown step") {
+		t.Errorf("unknown step error = %q", result.Steps[3].Error)
+	}
+}
+
+func TestRunner_EditorHandsOffRemainingSteps(t *testing.T) {
+	rec := &recorder{}
+	result := rec.runner().Run(context.Background(), testImage(), []Step{
+		{Type: StepSave},
+		{Type: StepEditor},
+		{Type: StepCopy},
+		{Type: StepUpload, Provider: "r2"},
+	})
+
+	want := []string{"save", "editor copy,upload"}
+	if !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("calls = %q, want %q", rec.calls, want)
+	}
+	if !result.Handoff || len(result.Steps) != 2 {
+		t.Errorf("result = %+v, want a handoff after 2 steps", result)
+	}
+}
+
+func TestRunner_MissingHandler(t *testing.T) {
+	result := (&Runner{}).Run(context.Background(), testImage(), []Step{{Type: StepSave}, {Type: StepBeautify}})
+	if result.Steps[0].Error != errNoHandler.Error() {
+		t.Errorf("save error = %q, want %q", result.Steps[0].Error, errNoHandler)
+	}
+	if result.Steps[1].Error != "" {
+		t.Errorf("beautify needs no handler, got %q", result.Steps[1].Error)
+	}
+}
+
+func TestRunner_StopsWhenCancelled(t *testing.T) {
+	rec := &recorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := rec.runner().Run(ctx, testImage(), []Step{{Type: StepSave}})
+	if len(rec.calls) != 0 || len(result.Steps) != 0 {
+		t.Errorf("cancelled run executed %q", rec.calls)
+	}
+}
+
+func TestRunner_CommandStep(t *testing.T) {
+	result := (&Runner{}).Run(context.Background(), testImage(), []Step{{Type: StepCommand}})
+	if result.Steps[0].Error != "no command set" {
+		t.Errorf("error = %q, want %q", result.Steps[0].Error, "no command set")
+	}
+}
+
+func TestExpandArgs(t *testing.T) {
+	got := ExpandArgs([]string{"--file={file}", "{url}", "plain"}, `C:\a.png`, "https://x/a.png")
+	want := []string{`--file=C:\a.png`, "https://x/a.png", "plain"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandArgs = %q, want %q", got, want)
+	}
+}